@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/gateway/simulator"
+)
+
+var (
+	flagAddr  = flag.String("http", "localhost:8090", "Simulator server address")
+	flagRules = flag.String("rules", "", "Location of the JSON file with the simulator rules")
+)
+
+func main() {
+	flag.Parse()
+
+	flags := log.Ldate | log.Ltime | log.Lmicroseconds | log.LUTC
+	logger := log.New(os.Stderr, "", flags)
+
+	var rules []simulator.Rule
+	if *flagRules != "" {
+		f, err := os.Open(*flagRules)
+		if err != nil {
+			logger.Fatalf("unable to open rules: %v", err)
+		}
+		rules, err = simulator.LoadRules(f)
+		f.Close()
+		if err != nil {
+			logger.Fatalf("unable to load rules: %v", err)
+		}
+	}
+
+	server := &http.Server{
+		Addr:    *flagAddr,
+		Handler: simulator.New(rules...).Handler(),
+	}
+
+	// Graceful shutdown
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-sigCh
+		logger.Printf("simulator shutting down: signal received: %v", sig)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := server.Shutdown(ctx)
+		if err != nil {
+			logger.Printf("simulator shutdown failed: %v", err)
+		}
+	}()
+
+	logger.Printf("simulator starting up: %s (%d rules)", server.Addr, len(rules))
+	err := server.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		logger.Fatalf("simulator startup failed: %v", err)
+	}
+}
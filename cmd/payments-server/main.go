@@ -7,23 +7,44 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 
 	"github.com/michaljemala/payments-sample/internal/doc"
 	"github.com/michaljemala/payments-sample/internal/migrate/postgres"
+	"github.com/michaljemala/payments-sample/internal/migrate/sqlite3"
+	"github.com/michaljemala/payments-sample/pkg/events"
+	"github.com/michaljemala/payments-sample/pkg/gateway"
+	"github.com/michaljemala/payments-sample/pkg/gateway/simulator"
 	"github.com/michaljemala/payments-sample/pkg/payments"
 )
 
 var (
-	flagAddr         = flag.String("http", "localhost:8080", "API server address")
-	flagDsn          = flag.String("database", "", "Database server connect string")
-	flagMigrationDir = flag.String("migrations", "", "Location of the migration files")
-	flagDocs         = flag.Bool("docs", true, "")
+	flagAddr           = flag.String("http", "localhost:8080", "API server address")
+	flagDriver         = flag.String("driver", "postgres", "Store driver, one of postgres, sqlite3 or memory")
+	flagDsn            = flag.String("database", "", "Database server connect string")
+	flagMigrationDir   = flag.String("migrations", "", "Location of the migration files")
+	flagIdempotencyTTL = flag.Duration("idempotency-ttl", 24*time.Hour, "Expiration of the payment idempotency keys")
+	flagEnumCacheTTL   = flag.Duration("enum-cache-ttl", time.Minute, "Expiration of the cached enumerations")
+	flagWorkerInterval = flag.Duration("worker-interval", 10*time.Second, "Polling interval of the scheduled payment worker and the dispatcher")
+	flagWorkerRetries  = flag.Int("worker-max-attempts", 5, "Execution attempts of a scheduled payment and send attempts of a submitted one")
+	flagWorkerBackoff  = flag.Duration("worker-backoff", time.Minute, "Initial delay between the attempts")
+	flagGateway        = flag.String("gateway", "simulator", "Scheme gateway, either simulator or the URL of a remote gateway")
+	flagGatewaySchemes = flag.String("gateway-schemes", "SEPA,SWIFT", "Schemes the payments are sent to the gateway for")
+	flagGatewayTimeout = flag.Duration("gateway-timeout", 30*time.Second, "Timeout of sending a payment to the gateway")
+	flagSimulatorRules = flag.String("simulator-rules", "", "Location of the JSON file with the rules of the in-process simulator")
+	flagEventSinks     = flag.String("event-sinks", "log", "Sinks the payment events are published to, a comma separated list of log or the URLs the events are posted to")
+	flagEventTimeout   = flag.Duration("event-timeout", 10*time.Second, "Timeout of publishing a payment event to the sinks or a webhook")
+	flagFeedInterval   = flag.Duration("changes-interval", time.Second, "Polling interval of the streams of the payment changes")
+	flagFileMode       = flag.String("payment-file-mode", "all-or-nothing", "Import of the payment files, either all-or-nothing or partial")
+	flagDocs           = flag.Bool("docs", true, "")
 )
 
 func main() {
@@ -32,24 +53,51 @@ func main() {
 	logger := initLogging()
 
 	if *flagMigrationDir != "" {
-		m := postgres.NewMigration(postgres.Config{
-			DSN:          *flagDsn,
-			DatabaseName: "payments",
-			SourceURL:    *flagMigrationDir,
-			Logger:       logger,
-		})
-		err := m.Do()
-		if err != nil {
-			logger.Fatalf("unable to migrate database: %v", err)
-		}
+		migrateDatabase(logger)
 	}
 
 	api, close := initPaymentAPI(logger)
 	defer close()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	for _, w := range []*payments.Worker{api.Worker(), api.Dispatcher(), api.Relay(), api.Webhooks()} {
+		workers.Add(1)
+		go func(w *payments.Worker) {
+			defer workers.Done()
+			w.Run(ctx)
+		}(w)
+	}
+
 	router := initRouter(api.Prefix(), api, logger, *flagDocs)
 
-	initAndStartServer(router, logger)
+	initAndStartServer(router, cancel, api.Shutdown, logger)
+
+	// Let the workers finish the payments in progress before closing the store.
+	cancel()
+	workers.Wait()
+}
+
+func migrateDatabase(logger *log.Logger) {
+	var err error
+	switch *flagDriver {
+	case "postgres":
+		err = postgres.NewMigration(postgres.Config{
+			DSN:          *flagDsn,
+			DatabaseName: "payments",
+			SourceURL:    *flagMigrationDir,
+			Logger:       logger,
+		}).Do()
+	case "sqlite3":
+		err = sqlite3.NewMigration(sqlite3.Config{
+			DSN:       *flagDsn,
+			SourceURL: *flagMigrationDir,
+			Logger:    logger,
+		}).Do()
+	}
+	if err != nil {
+		logger.Fatalf("unable to migrate database: %v", err)
+	}
 }
 
 func initLogging() *log.Logger {
@@ -60,10 +108,21 @@ func initLogging() *log.Logger {
 
 func initPaymentAPI(logger *log.Logger) (*payments.API, func()) {
 	api, err := payments.NewAPI(payments.Config{
-		Prefix: "/",
-		Driver: "postgres",
-		DSN:    *flagDsn,
-		Logger: logger,
+		Prefix:            "/",
+		Driver:            *flagDriver,
+		DSN:               *flagDsn,
+		IdempotencyKeyTTL: *flagIdempotencyTTL,
+		EnumCacheTTL:      *flagEnumCacheTTL,
+		WorkerInterval:    *flagWorkerInterval,
+		WorkerMaxAttempts: *flagWorkerRetries,
+		WorkerBackoff:     *flagWorkerBackoff,
+		Gateways:          initGateways(logger),
+		GatewayTimeout:    *flagGatewayTimeout,
+		EventSinks:        initEventSinks(logger),
+		EventTimeout:      *flagEventTimeout,
+		ChangesInterval:   *flagFeedInterval,
+		PaymentFileMode:   *flagFileMode,
+		Logger:            logger,
 	})
 	if err != nil {
 		logger.Fatalf("unable to initialize payment API: %v", err)
@@ -76,6 +135,49 @@ func initPaymentAPI(logger *log.Logger) (*payments.API, func()) {
 	}
 }
 
+func initGateways(logger *log.Logger) map[string]gateway.Gateway {
+	var gw gateway.Gateway
+	if *flagGateway == "simulator" {
+		var rules []simulator.Rule
+		if *flagSimulatorRules != "" {
+			f, err := os.Open(*flagSimulatorRules)
+			if err != nil {
+				logger.Fatalf("unable to open simulator rules: %v", err)
+			}
+			defer f.Close()
+			rules, err = simulator.LoadRules(f)
+			if err != nil {
+				logger.Fatalf("unable to load simulator rules: %v", err)
+			}
+		}
+		gw = simulator.New(rules...)
+	} else {
+		gw = gateway.NewHTTP(*flagGateway)
+	}
+
+	gateways := make(map[string]gateway.Gateway)
+	for _, scheme := range strings.Split(*flagGatewaySchemes, ",") {
+		if scheme = strings.TrimSpace(scheme); scheme != "" {
+			gateways[scheme] = gw
+		}
+	}
+	return gateways
+}
+
+func initEventSinks(logger *log.Logger) []events.Sink {
+	var sinks []events.Sink
+	for _, sink := range strings.Split(*flagEventSinks, ",") {
+		switch sink = strings.TrimSpace(sink); sink {
+		case "":
+		case "log":
+			sinks = append(sinks, events.NewLog(logger))
+		default:
+			sinks = append(sinks, events.NewHTTP(sink))
+		}
+	}
+	return sinks
+}
+
 func initRouter(pattern string, handler http.Handler, logger *log.Logger, withDocs bool) http.Handler {
 	router := chi.NewRouter()
 	router.Use(
@@ -91,11 +193,14 @@ func initRouter(pattern string, handler http.Handler, logger *log.Logger, withDo
 	return router
 }
 
-func initAndStartServer(router http.Handler, logger *log.Logger) {
+func initAndStartServer(router http.Handler, stopWorkers, closeStreams func(), logger *log.Logger) {
 	server := &http.Server{
 		Addr:    *flagAddr,
 		Handler: router,
 	}
+	// Shutdown waits for the active connections, the long-lived streams
+	// have to be ended explicitly.
+	server.RegisterOnShutdown(closeStreams)
 
 	// Graceful shutdown
 	sigCh := make(chan os.Signal, 1)
@@ -104,6 +209,8 @@ func initAndStartServer(router http.Handler, logger *log.Logger) {
 		sig := <-sigCh
 		logger.Printf("server shutting down: signal received: %v", sig)
 
+		stopWorkers()
+
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 		err := server.Shutdown(ctx)
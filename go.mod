@@ -20,7 +20,7 @@ require (
 	github.com/manyminds/api2go v0.0.0-20190524072506-70a1ce7752ec
 	github.com/mattes/migrate v3.0.1+incompatible
 	github.com/mattn/go-colorable v0.1.2 // indirect
-	github.com/mattn/go-sqlite3 v1.10.0 // indirect
+	github.com/mattn/go-sqlite3 v1.10.0
 	github.com/michaljemala/pqerror v0.1.0
 	github.com/opencontainers/go-digest v1.0.0-rc1 // indirect
 	github.com/pkg/errors v0.8.1 // indirect
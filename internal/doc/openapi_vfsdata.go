@@ -19,14 +19,14 @@ var openapi = func() http.FileSystem {
 	fs := vfsgen۰FS{
 		"/": &vfsgen۰DirInfo{
 			name:    "/",
-			modTime: time.Date(2019, 6, 12, 12, 14, 19, 717849885, time.UTC),
+			modTime: time.Date(2026, 10, 17, 6, 17, 45, 635079941, time.UTC),
 		},
 		"/openapi.yaml": &vfsgen۰CompressedFileInfo{
 			name:             "openapi.yaml",
-			modTime:          time.Date(2019, 6, 12, 12, 14, 19, 717337229, time.UTC),
-			uncompressedSize: 11599,
+			modTime:          time.Date(2026, 10, 17, 6, 45, 57, 470460256, time.UTC),
+			uncompressedSize: 80565,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5a\x5f\x73\xe3\xb6\x11\x7f\xd7\xa7\xd8\x99\x66\x46\x77\x8d\x4c\xc9\x8e\x9b\x26\x7c\xe9\xe8\x6c\x37\xd1\x4c\x9b\xd3\xd8\xbe\xe9\x83\x4f\xf5\x40\xc4\x52\x42\x42\x02\x34\x00\xca\x56\xd3\x7c\xf7\x0e\x48\xf0\x9f\x44\x51\xa4\x23\xf5\x7c\x17\xf3\xc9\x22\x17\x8b\xfd\xed\x9f\xdf\x82\x4b\x8b\x08\x39\x89\x98\x0b\xdf\x38\x23\xe7\xac\xc7\xb8\x2f\xdc\x1e\x80\x66\x3a\x40\x17\xa6\x64\x1d\x22\xd7\x0a\xc6\xd3\x49\x0f\x80\xa2\xf2\x24\x8b\x34\x13\xdc\x85\x71\xf9\x27\x08\x1f\x14\x0b\xa3\x00\x21\xca\xd6\x5c\x5f\xdd\xdc\x9a\x85\x4e\x0f\x60\x85\x52\x25\xab\x46\xce\xc8\x39\xed\x29\x94\xe6\x8e\xd9\xe9\x04\x62\x19\xb8\xd0\x5f\x6a\x1d\xb9\xc3\x61\x20\x3c\x12\x2c\x85\xd2\xee\x77\xa3\xef\x46\xc3\x7e\x2f\x22\x7a\x99\x08\x0e\x33\xc5\xe6\x07\xc0\x02\x75\xfa\x07\x80\x8a\xc3\x90\xc8\xb5\x0b\xd7\xa8\x25\xc3\x15\x82\x27\x82\x00\xbd\xcc\xb0\x6c\xa1\x63\xe5\x45\x84\x92\x98\x87\x13\xea\x82\xcf\x38\xcd\x60\xda\xe7\x11\x91\x24\x44\x6d\x0d\x4c\xaf\x13\xe0\x24\x44\x17\xfa\x3e\x0b\x34\xca\x3b\x46\x67\xfd\xfc\xe1\x86\x67\x72\x33\x04\x0f\xd6\x85\x3f\x96\x64\xc5\xf8\x02\xf4\x12\x41\x45\xe8\x31\x9f\x21\x05\x46\x9d\x92\x1a\xc6\x5d\x78\x88\x51\xae\x4b\xf7\x24\x3e\xc4\x4c\xa2\x31\x95\x04\x0a\x4b\x4f\x94\xb7\xc4\x90\xb8\xa5\x3b\x00\x7a\x1d\xa1\x0b\x4a\x4b\xc6\x17\x3b\x8d\xa7\x38\xd7\x42\x3a\xc4\xf3\x44\xcc\xf5\x3d\x8f\xc3\x39\xca\xce\x78\x42\x42\x11\x7c\x29\x42\x20\x25\x40\x56\x29\xa4\x4a\x3f\x01\x38\x4f\x22\x65\x87\x82\xa7\xc5\x0b\x01\x17\x91\x05\xde\xed\x43\xd2\xaf\x42\x29\xec\x36\xab\x9d\xfe\x11\xcc\x65\x5c\xe3\x02\x65\xe5\x49\xc8\x38\x0b\xe3\xd0\x85\xd3\x1d\x30\x14\xfb\x0f\x3e\x03\x44\x8a\xde\x14\x34\xd3\x18\x2a\x10\x1c\xc8\x27\x47\x96\xdc\x26\x4f\xe9\xed\xbf\x8c\x46\xbd\x6c\x47\x15\x09\xae\xb0\xc4\x20\xfd\xb3\xd1\xa8\xef\xee\x42\x7d\x13\x7b\x1e\x2a\xe5\xc7\xc1\x1a\xa4\x75\x00\xcd\x92\xb1\xc4\x67\xe5\x9c\xf3\x04\xd7\xc8\x75\xd5\x7a\x12\x45\x01\xf3\x12\x7a\x1b\xae\x38\x75\x48\xc4\xbe\xfe\x59\x09\x5e\x95\xaa\x47\x6e\xae\xaf\x24\xfa\x2e\xf4\xff\x34\xf4\x44\x18\x09\x6e\x4a\x61\x98\xca\xaa\xa1\xe5\xc9\x8b\xdc\x9a\x6b\x0b\xb3\x08\x40\xff\xbc\x09\xe5\x84\xaf\x48\xc0\x68\x1a\xa0\x12\xcf\x1e\x1d\x55\x1a\x53\x22\x25\x59\x6f\x3d\x4b\xb2\x69\x7b\x49\xb3\x2b\xae\xa4\x14\x32\x85\x1d\x99\x6e\xb5\xd9\x8a\x2e\x24\x12\x8d\x40\x80\xe3\x63\x16\xc6\xda\xfe\xe3\x25\x82\xd6\xb3\x79\xf6\x3c\xc4\xa8\xf4\x3b\x41\xd7\x85\x5d\x45\x12\x6b\x19\x17\x39\x5c\xe3\xae\x76\xce\xaa\x77\x55\x9b\xf0\x27\x16\x5f\xa7\x36\xf6\x1b\x13\xfe\x74\x77\x2a\xfc\x54\xf8\x05\x54\x9e\xfc\xc1\xda\x3a\x84\x76\x4d\x89\xaf\x8f\x91\xe8\x16\x69\xb7\x24\xff\xc0\xc9\x3c\x48\x5a\x47\x0a\x25\x87\x49\xe3\xe4\x2e\xb3\x45\xc0\x78\x14\xeb\xa3\xc3\x3c\x66\xe6\x5b\x5f\x7c\xff\x7c\x5f\x48\x54\x22\x96\x1e\x02\xd1\x5a\xb2\x79\xac\x51\x19\x37\xf8\x01\xf3\x3e\x67\xd7\xe4\x67\xd5\xe1\xaf\xf6\xaf\x7b\x46\x7f\x6b\x71\x70\x25\x1c\xf0\x89\x29\x6d\x0e\x8a\x4d\xac\xb1\x40\x6d\x73\xf4\xdd\x7a\x42\x5b\x9c\x5b\x0b\x33\x36\x7a\xa5\x39\x5f\xef\x8e\x1e\x7b\x88\x8b\x98\x31\x8a\x5c\x9b\x5e\x5c\x39\xf9\xec\xe0\xa5\x7a\xb7\x37\x79\x6f\x72\xd9\x4c\x26\x0d\x25\x37\xad\x23\x92\xbc\x8d\xbe\x84\x9e\xf9\x03\xea\x5a\x1e\x39\xdf\x0f\x8a\x0b\x0d\xbe\x88\xf9\xf1\x71\x1c\xb5\x4b\x12\xed\x2d\xb7\x12\xff\x8a\x32\xdd\x3a\xe9\xcd\xb9\xbe\xda\x28\xbf\xa0\x8c\x3f\x64\xc7\xdf\xc1\x84\xcf\xee\xf8\x26\x4a\xad\xfa\x7d\xd7\x12\x35\x11\x7d\x19\xf5\x99\x42\xfc\x23\x17\x28\xc5\x00\x35\x6e\x55\xe8\x65\x72\xbb\x75\x8d\xa6\x5a\xbe\xd8\x2a\xad\x4b\xfa\x86\x0c\x19\x6f\x7b\xad\x5a\x00\xa9\xbb\xa8\xd3\x2b\xf6\x35\xca\xec\xd6\xa9\xde\x24\x46\xd9\x16\x9b\xea\xc7\xd3\x09\xa0\x11\xc8\xa0\xa7\x29\x22\xe6\x3f\xa3\x57\x7e\x9d\x48\x3d\x71\xa7\x34\xd1\xb1\x1a\x80\x27\x28\x0e\xd2\x29\xdf\x2c\x8b\x92\x34\x81\xd4\xac\x8c\x2e\x15\x2f\xc3\xab\x9d\x52\x6c\x98\xf5\xe3\xed\xed\xd4\x2e\x4d\x36\x72\x4a\xd4\x45\xb1\xab\xb6\x31\x2f\x97\xcd\x89\x9d\x07\x78\x29\xea\x0d\xfd\xe9\xd8\xb2\xeb\x06\xb0\x8c\x43\xc2\x4f\x24\x12\x9a\x9c\x56\x6d\xee\x83\xf0\x93\x61\x5d\x24\xc5\x3c\xc0\xb0\xd8\x85\xa2\x26\x2c\xd8\x1d\xf4\x4d\x7d\xf8\x14\x05\x84\x93\x6c\x1c\x59\xab\xb3\x26\x70\x93\xcb\xda\xa0\xff\x10\x88\x39\x31\xb9\x13\xa7\x55\x50\x64\xbf\x51\x4e\xf2\x23\xb5\x03\x8c\x83\x2f\x64\x68\x6e\x7f\xf8\x30\xb9\x5c\x9d\x57\x73\xa4\xe2\x15\x23\x48\xb4\x0b\x71\x6c\x2b\xf1\x22\x96\x12\xb9\xb7\xbe\x28\x85\xac\x62\x47\x26\x90\x84\x00\x88\x02\x8a\x3e\xe3\x68\xde\x6b\xe0\x6e\x72\xf3\x1e\xce\xcf\x4e\xff\x3a\x7b\x63\xe7\xba\x8f\x8f\x8f\x0e\x53\xc2\x11\x72\x31\x64\x4a\x0c\x97\x22\xc4\xa1\xd2\x84\x53\x22\xa9\x1a\x7a\x56\xd9\xbd\x51\xa6\x9c\xa5\x0e\xdf\xee\x34\xf6\x9f\x82\xa3\x36\xdc\x54\x67\xd5\x35\x46\x12\x55\x32\xc0\x23\x10\x5a\x49\x20\xa1\x88\x0b\xc2\xaa\x29\x91\xba\xe4\x5f\x91\x20\x6e\x93\x4c\x11\xd1\x1a\x25\x77\xa1\xff\xef\x37\xa3\xff\xde\x9d\x9e\x7c\x3f\xfb\x48\xff\xfc\xf6\xcd\x47\xe7\x23\xfd\xf5\xec\xb7\xb7\x7f\xfb\xaa\x68\x28\x19\xce\xb2\xda\x26\xee\x29\x47\x21\xd5\x32\xa6\x54\xa2\xca\xcd\x6c\x89\x25\x60\x1c\x4f\xf7\x62\x31\x52\x67\x7b\xa5\x3c\xa6\xd7\x7b\x85\x24\x2e\x58\xb5\xaf\xd5\x8a\x45\x42\x69\x12\xdc\xb7\xe2\x85\x64\xf4\x2a\xd7\x5b\xc2\xd5\x09\xd3\xcd\x7b\xf8\xe6\xf4\xdb\x6f\x6d\x42\x67\x8b\x36\x78\xa2\x66\x07\xdb\xb2\x6e\x8c\xe3\xd1\xdd\x5d\x28\xc8\xe3\xd0\x85\xbb\x9b\x7f\x4d\xfe\x7e\x3b\x80\x9b\xab\xe9\x78\x56\x5e\x3f\x25\xb2\xf0\x4e\xcb\xe0\x24\x9d\x70\x17\xa0\x9f\x48\x88\x39\x6b\xd8\x16\x12\x99\x4d\x1a\xd1\x98\x2b\x9f\x7f\xb7\x51\x3f\x47\x8e\x3e\xf3\x58\x52\x2d\x0a\x16\x6c\x85\x1c\x1e\x99\x5e\x66\x6a\x5a\xef\x96\x4c\x69\x77\xee\xf7\xae\xbc\x4f\x65\x9a\xde\x76\x83\x48\x8a\x15\xa3\xd5\x2d\x9a\x4a\x68\x9c\xae\x9b\xda\x65\x45\x2d\x92\x6a\x25\xed\xd5\x93\x8a\xdb\x2a\xac\x2a\xed\x18\xf0\xc6\x0c\xb6\xaa\x21\xc3\xb9\x3f\x75\xf7\xe6\xd0\x8f\xb6\x1f\xa5\xed\x88\x97\x32\x8a\x6c\x6c\xd6\xa6\x44\xb6\xc7\xbf\xb5\x4c\x3c\xdd\x39\xbb\x6e\x3e\xa2\x50\xa2\x49\xd3\x99\xc4\x3c\xdf\xe6\x8a\x8a\xb6\x0d\x8d\x8c\x0e\x12\xa9\x41\x69\xca\x34\x2b\x13\x78\xcd\x2e\xe6\x62\xb4\xcb\xbb\x52\x71\x4c\x2c\xd9\xb5\xb1\x7e\x47\x07\x81\x82\x57\xb2\xc1\xd1\xac\xfa\xde\x90\xdb\x5d\xaf\x71\x0b\x7d\x13\xac\x44\x61\xd2\x10\xbb\xbe\x2d\x64\x9d\xb7\xbf\xb5\x2e\xfd\xa2\xd8\x55\x5f\x99\x33\xb7\x75\x66\x1f\xf2\x0e\xab\x55\x55\x08\xbe\xa3\xce\xb4\x3b\xf4\xcb\x0d\xf3\x97\x9a\xe3\xf1\x56\x38\x36\x0a\x63\xc1\xec\x69\x30\x59\xef\xb4\x48\x45\x9f\x49\xb5\x15\xae\x8a\xd6\x7f\x30\xfe\x0b\x68\x91\x54\x75\x22\x9d\x7e\x1a\x6b\x9b\x80\x01\xe9\xa0\xdf\x08\x77\x53\xcf\xf1\xa9\xbd\x7a\x23\xdc\x4d\x7d\x24\x71\xd5\x5a\xbd\x11\x66\x22\x56\xed\xb6\xa8\xfb\xe8\xd1\xc8\x77\xf9\xf9\xfb\x95\xed\x0e\xce\x76\x25\x98\x29\x83\x0d\x2c\xf3\x0c\x72\xb6\x18\xd8\x0a\x9f\x1d\x84\x0d\x49\x10\xbc\xf7\xeb\x1e\x00\x9c\x3c\x93\x2a\x2b\x28\x92\x77\x8c\x41\xfe\x5a\xb0\x69\x74\x13\xb1\x3e\xdb\xb4\x66\x7e\xac\x3a\xb9\xfa\x2f\x14\x9d\x28\xfa\x25\xd8\x77\x30\xb2\xaf\xfd\x20\xf8\x7b\x69\xe0\x77\xd4\xfb\xe7\x5c\xc4\xaf\xe7\x92\xa3\x9e\x4b\xb6\x3f\x38\xed\x7c\x3b\xd9\x1e\xef\x77\x7c\x91\x79\xed\x4e\xe5\xeb\x35\xb1\xff\x1f\x89\x5d\xfe\x52\xf3\xca\xc0\xaf\x89\x7a\x30\xad\x87\x48\xd4\xff\x05\x00\x00\xff\xff\x4f\x2a\xa4\x7f\x4f\x2d\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x7d\x6b\x73\xdb\xc6\x92\xe8\x77\xfd\x8a\xa9\xbd\xa7\x4a\xc9\x86\xa4\x28\x59\xf6\x89\xf9\x61\xb7\x64\x59\x3e\xd1\x39\xb6\xa2\x92\xe4\xe4\xd6\xf5\x51\xc4\x21\xd0\x24\x67\x0d\xcc\x30\x33\x03\x49\x4c\x6e\xfe\xfb\x56\xcf\x03\x0f\x12\x20\x01\x8a\xb4\x2d\x89\x91\x53\x12\x89\x79\x74\xf7\xf4\xbb\x1b\x80\x98\x00\xa7\x13\xd6\x23\x2f\x3a\xdd\xce\xc1\x0e\xe3\x43\xd1\xdb\x21\x44\x33\x1d\x41\x8f\x9c\xd3\x69\x0c\x5c\x2b\x72\x74\x7e\xba\x43\x48\x08\x2a\x90\x6c\xa2\x99\xe0\x3d\x72\x94\xff\x48\xc4\x90\x28\x16\x4f\x22\x20\x13\x3f\xe7\xe2\xe4\xf2\x0a\x27\x76\x76\x08\xb9\x05\xa9\xcc\xac\x6e\xa7\xdb\xd9\xdf\x51\x20\xf1\x1b\xdc\xa9\x4d\x12\x19\xf5\xc8\xee\x58\xeb\x49\x6f\x6f\x2f\x12\x01\x8d\xc6\x42\xe9\xde\x8f\xdd\x1f\xbb\x7b\xbb\x3b\x13\xaa\xc7\x66\xe0\x9e\x5f\x18\x3f\x10\x32\x02\x6d\xff\x20\x44\x25\x71\x4c\xe5\xb4\x47\x2e\x40\x4b\x06\xb7\x40\x02\x11\x45\x10\x78\xc0\xfc\xc4\x8e\x99\x48\x88\x98\x80\xa4\x78\xf1\x34\xec\x91\x21\xe3\xa1\x47\xd3\x5d\x9f\x50\x49\x63\xd0\x0e\x40\xf3\x15\x69\x13\x4e\x63\xe8\x91\xdd\x21\x8b\x34\xc8\x4f\x2c\xbc\xde\x4d\x2f\xce\x50\x26\x05\x43\xf0\x68\x9a\xd1\x63\x4c\x6f\x19\x1f\x11\x3d\x06\xa2\x26\x10\xb0\x21\x83\x90\xb0\xd0\x43\x85\x3f\x8c\xf7\xc8\xef\x09\xc8\x69\xee\x3b\x09\xbf\x27\x4c\x02\x82\x4a\x23\x05\xb9\x2b\x2a\x18\x43\x4c\x33\x18\xf1\x47\x4f\x27\xd0\x23\x4a\x4b\xc6\x47\x95\xc0\x87\x30\xd0\x42\x76\x68\x10\x88\x84\xeb\x1b\x9e\xc4\x03\x90\x8d\xf1\x89\x69\x08\x64\x28\x45\x4c\x68\x0e\x21\xb7\x28\xb1\x8b\x7e\x05\xe4\x02\x09\x21\x5b\x17\x7a\x5a\x7c\x5b\xc8\xd1\x18\xf7\xef\x04\x89\x94\xc0\x83\x69\x63\xa4\x18\x27\x94\x4f\x51\x28\x8a\x6c\x18\x88\x38\xa6\x44\x01\xb2\xbe\x86\x90\xb8\x0d\x18\xa8\xaf\x80\xa4\x39\x7a\x68\x8c\x9b\x18\xd6\xc3\xcd\x2e\xff\x35\x10\x03\x1e\xde\x68\x71\x83\xbf\x24\x0c\x01\x8f\xb0\x39\x9a\x77\x4c\x8f\xcb\x11\x05\x1e\xb6\xb5\x68\x03\x0f\x49\xba\xfc\xd7\x40\x93\x27\x31\x48\x16\x6c\x04\x47\xb7\xf6\xd7\x45\x50\x42\xcc\xb4\xa6\x3c\x80\x1b\x34\x98\x32\x36\xd6\xa4\xa3\xb4\x4c\x02\x9d\x48\x08\xd7\x88\xb0\x57\x67\x5f\x1b\xe3\x95\x8f\x72\x2c\x14\xe4\x58\xb3\x95\x1e\xa1\x90\x25\xc8\x11\xa6\xea\x49\xb1\xe0\xa0\xbe\x9e\x02\xbe\xa5\x51\x02\xd7\x9f\x46\x7a\xc5\x93\x36\xab\x90\x91\x04\xaa\x41\x12\x3d\xa6\x7c\x06\x5d\xb3\xc1\x37\x80\x1f\xac\x0f\x41\x21\x09\xfc\x9e\xd0\x88\x68\xf1\x4d\x22\x1b\x3d\xec\x30\x23\x50\xea\xdb\x3d\xc9\x48\xc3\x9a\xb0\xfb\xf6\x8e\xd1\xb9\xb3\xf8\xe5\xf5\xa7\x89\x84\x21\xbb\x6f\x8c\xab\x71\x66\x07\x53\x42\x89\x5d\xcd\xe9\x2d\x5c\x93\x28\x4d\xa5\x27\x47\x09\xc6\x2d\xc2\x46\x5c\x20\xa3\x91\x80\xaa\xaf\x41\x00\xaf\x46\xd7\x40\x02\xe3\xf0\xa6\x6a\xf9\x31\x11\x21\x84\x08\x74\x2d\xd3\x8b\x67\xe8\x46\x67\xd8\x33\xae\x34\xd0\xd0\x1b\x9e\x88\x19\xfa\x80\x6a\x11\x1a\x45\xe2\x0e\x42\xe4\x77\x1a\xc6\x8c\x2b\x43\xb7\x4d\x60\x38\x10\x22\x02\xca\x2b\x51\x0c\x8c\xbd\x08\x6f\xa8\x5e\xc9\xf4\xb8\xe9\x84\x0e\xad\x4e\xce\x1f\xa2\x66\xf1\x97\x38\x34\xfc\xb1\x0e\x53\x8f\x84\x54\x43\x1b\xf7\xad\x89\x2f\xac\x8e\xb0\x26\x42\x3e\x4e\xb4\x23\xbd\x32\xd6\x03\x18\x0a\x09\x8f\x0f\xe1\x87\x9e\xf3\xe3\xc1\x3b\x99\x84\x0f\x91\xe7\x88\x2a\x4d\x82\x31\xe5\xa3\xc7\x24\xd4\x45\xa4\xe1\x81\x58\x3f\x2e\xc9\xce\xe3\x1e\xe9\x87\xa1\xfe\x38\xd9\x3c\x5a\xcf\x89\x3f\x1e\xe4\xd1\x0f\x00\x85\xe8\xc3\x3d\x04\x09\x9e\xef\x0d\xce\x5a\x49\xe2\xd3\xc5\xd0\x19\x19\x00\xb1\x4b\x56\x48\x3f\xee\xf2\x15\xc8\xb1\x12\x25\x60\x7d\xa4\x10\xbc\x4a\x25\x3c\x1e\x82\x44\x7a\x7d\xf4\x28\x15\x95\xc7\x44\x8a\xb5\xf3\xc6\xb7\x4d\x11\x25\xa4\xae\x44\xf8\xbf\xda\xb9\x2b\x84\x1c\xcf\x24\xc5\x86\x0c\xa2\x50\x21\x07\xe0\x2a\x06\xc3\x94\x28\x83\x69\x8b\x50\x3b\x82\xd8\x08\x11\x42\x1b\xd3\xf6\xdb\x7d\x4c\xbb\xe1\x14\xac\x48\x71\xc3\x6f\xc0\x43\x8c\x68\x85\x0c\x8b\x85\x0e\x42\x2e\x93\xc9\x44\xc8\xdc\x76\x54\x02\xe9\xb3\xb0\xdf\x22\xfd\x7c\xd2\x21\xf7\xd9\xd7\x2b\xf0\x2b\xc3\x3c\x60\xfe\xd2\x54\x27\x0a\xff\x2a\x04\xb0\xfd\x56\x61\xbb\x7e\x45\x41\x07\xe7\xe5\x22\xff\xdc\xc7\xf9\x71\x99\x83\x89\x9f\x32\x7b\xd4\x27\x94\x87\xc5\xdd\xaa\x38\xb1\x4f\xbe\x4b\x49\x89\x54\x13\x89\xa5\x2f\x5e\x23\x81\x88\xc1\x58\xe7\xef\xbf\x08\x0b\xc1\x3d\xc5\x52\x6b\x8f\xec\xb6\xf3\x04\x6f\x15\xc8\xb8\x3b\xcf\x5a\x13\x3a\x82\x4f\xcb\xca\x61\xbb\x45\xa1\xca\x24\x04\x67\x77\x76\x37\x80\x1f\xe3\x1a\x46\x20\x0b\x57\x62\xc6\x59\x9c\xc4\x3d\xb2\x5f\x81\x86\x62\x7f\xc0\x0a\x48\x58\xec\x31\xca\x67\x1a\x62\x0c\xe5\x09\xfd\xea\x98\xe1\xbf\x98\xde\x5b\x84\x5f\x76\xbb\x15\x28\x1b\x9b\x76\x5d\x57\x37\xa4\x14\x40\x2e\xc5\xf9\x64\x28\x30\x93\xe1\x6b\xd0\x41\x22\x95\x90\x2d\xf7\xdb\x4a\xb1\x98\xd0\xdf\x13\xb0\x19\x1d\x45\x34\xfd\x0c\xdc\x56\x78\x71\x42\x9f\xc3\xbd\xee\x93\x88\xf1\xcf\x45\x85\x70\x4c\x39\xe1\x42\xa3\xa6\x0d\x44\x3c\x60\x3c\x55\x2c\x79\x86\xeb\xa3\x59\xb6\xdf\x58\x05\x7c\xdd\xff\x02\xc2\x52\xa4\xa0\xdb\x78\x75\x12\x4e\x24\x04\x10\xae\x4e\xc2\x89\x84\xdb\xb5\x90\xd0\xf2\xc2\xe6\x29\x28\x41\x4d\x04\x57\x90\x6b\x85\xd8\x3d\xe8\x76\x77\x7b\x55\x24\xbc\x4c\x82\x00\x94\x1a\x26\xd1\x94\x48\x47\xbf\xd0\x9b\xe6\x5c\x63\x46\x1e\xf2\x40\x70\x0d\x3c\xed\xe7\xb0\xff\xe8\x64\x12\xb1\xc0\x54\xd6\xf6\x6e\x79\xd8\xa1\x13\xf6\xc3\xff\x28\xc1\x8b\xa3\xca\x11\xc1\x9f\xbf\x49\x18\xf6\xc8\xee\xff\xd9\x0b\x44\x3c\x11\x1c\x15\xf7\x9e\x1d\xab\xf6\x5c\xc3\xc7\x71\x0a\xcd\x85\x43\x33\xe3\x8c\xdd\xc3\x45\x58\x9e\xf2\x5b\x1a\xb1\xd0\xd2\x3b\xd7\x30\xb2\x71\xac\x2c\x93\x53\x29\x69\xfe\x98\x1d\x03\xa0\x46\x9b\x9f\xb2\x98\x14\x27\x52\x0a\x59\x40\xfb\x45\x35\xda\x6f\x67\xb3\xa6\x99\xa7\x65\x72\xe7\x5c\xf0\xb6\xc9\x91\x12\x1a\xa0\xc5\x7e\xcc\xd4\x98\x60\x13\xd2\x6c\x87\xd1\xb1\x71\x24\x10\x53\xb8\xf3\x54\x28\x6d\x2b\xb2\x1e\x87\xe3\xb3\x1a\x7d\x45\xa7\x21\xc4\x13\xa1\xd1\x49\x6a\xff\x0b\xf2\xd8\xa0\x62\x1c\x03\x0d\x41\x56\x9d\xca\xbf\x60\x4a\x12\xce\x50\xed\x4c\x40\x5a\xd2\x93\x98\x7e\x46\x35\x65\x45\x50\xf9\xb4\xb6\x3b\x2f\xa2\xe8\x10\x3a\xeb\xd4\x13\xa9\x11\x7b\x0f\x7c\xa4\xc7\x3d\x72\xf0\xf2\xa5\xbb\xe4\xf6\x7c\x23\xc2\x69\x6f\x67\x7e\x43\x2d\x13\xd8\x59\xc0\x25\xf5\x78\xa4\x9c\x43\xea\xe8\x00\x73\x50\x17\x16\xc6\xdd\x85\x5a\x6f\xbf\x5a\x30\xce\x32\x76\x20\x2a\xd5\x80\xd1\xd4\xa7\x26\x9b\x4a\xc2\x0f\x4d\x25\xa1\x01\xa6\xcd\x34\xdd\x47\x4e\x07\x91\xa9\x0b\x59\x54\x52\x34\xc3\xc4\x7c\xcb\x9c\x26\x64\x7c\x92\xe8\x16\xa1\x9c\x00\xca\x10\x06\x14\x12\x7c\x9c\x80\x35\xc3\x5b\x90\xd3\x74\xb4\x89\x1c\x36\x4e\x94\x2f\xa0\x2c\x5f\xaf\x4e\x39\x09\x4a\x24\x32\x00\x42\xb5\x96\x6c\x90\x68\x50\xa8\x25\x87\x11\x0b\xf4\x13\x20\xcd\xc1\x41\x35\x69\x72\xda\x8e\x7c\x86\x29\x19\x53\x45\x68\x24\x81\x86\x53\x32\x00\xe0\x24\x51\x8e\x6d\x28\x09\xd9\xd0\xf4\x86\x68\xaf\xbc\x1e\x31\x6d\xd2\x1e\xd6\x3d\x9b\xc5\x5d\xd0\xcb\x7a\xa9\x25\xd0\x38\x1f\xc2\xa3\x08\xa1\xcd\xa5\x8a\x5c\x9a\xfe\xd9\xf6\x25\x7e\x7b\x72\x9b\xef\x6d\xad\x72\x67\x8f\x82\x00\x26\x1a\x1b\x14\x80\x28\x2c\x6a\xf7\x6d\x56\xae\x9f\xb3\x4a\x84\x2a\xd2\xff\xc7\xc9\x55\xd6\x6a\xdb\x27\x70\x8f\xf3\x48\x7f\xa6\xc8\xda\x6f\xe1\x4a\x53\xe3\xf1\xc6\x54\x07\x63\xc8\xc2\x68\x3a\xa2\x58\x4c\x2d\x80\x1e\x50\x29\x31\xfc\x1a\x4c\x09\xd0\x60\x6c\x51\xe9\x90\x13\xa3\x14\xcc\x07\x54\x18\x0a\x7f\x1b\xb7\x97\x69\x45\x26\x42\x31\x3c\x43\xcc\x48\xe0\x5a\x43\x80\xb0\xe5\x3f\x98\xbc\x84\xd9\xc2\x51\xe5\x0e\xa4\x09\x3f\xb0\x33\x0b\xc7\x51\x8b\xab\xc9\x4b\xa4\xa0\xe1\xb2\xc8\xf9\xe9\x55\x33\xd9\x64\x01\xcc\xe8\x7f\x5e\xfe\x7c\x46\x80\x07\x22\x84\xd0\xc2\x98\x8e\x0c\xa9\xa6\xfd\xa2\xde\x2a\x18\x7c\x65\xce\xcb\xab\x5a\x7b\xba\x35\xec\xfe\x7b\xaa\x74\xdb\x1c\x61\xfb\xf4\x6d\x23\xab\x7f\xee\xc9\xe3\x2b\xd6\x58\x1c\xc0\xf0\x84\xdd\x7a\xe0\xcd\x21\xa1\x9d\x46\x4e\x92\xa0\x92\x18\x14\x91\x6c\x34\xd6\x2e\x3f\xca\x74\x87\xfc\xea\x92\x19\x4c\xe7\x47\xcf\x96\xfb\x1d\x95\xd1\x0c\x88\x62\x96\x7d\xc5\xe8\xb7\xbb\xd0\xd4\x2e\x30\x48\x4e\x30\xc4\xb0\xc0\x60\x16\xbe\x16\x36\x9a\x99\x3c\x5f\xe0\xf2\x7b\x46\x50\x88\x1a\x27\x5a\x91\x50\xdc\xf1\xa5\xca\x43\xc3\xbd\xde\x33\xab\xb5\x2d\x29\x9a\x69\x8d\x19\xa7\x68\x99\x6d\x55\xb9\x5c\x1a\x0a\x18\xc6\xc8\xde\x46\x16\x58\xa3\xa9\xd2\xeb\x7c\x8b\x4a\xef\x4f\xf7\xd7\x0d\x0b\xff\xaa\xd1\xc5\x8f\xbe\xc4\x3d\x53\x1a\xfd\x58\x37\xb3\x54\xf4\x46\xa0\x9d\xdc\xbd\x99\x9e\x86\x35\x84\x2e\x03\x23\xbd\x64\xfd\x6c\xbc\xd9\xa0\xfa\xb0\xac\x87\xed\x18\x8e\x85\xc0\x35\xe6\x94\x64\xb9\x1f\x5d\x70\x6b\xcb\xc9\xbe\x88\x7a\xa7\x6f\x77\x57\x15\x90\xf3\x32\x3f\x34\x0d\xc5\xf3\xd0\xda\xb0\x22\xb7\x30\xfe\x3b\xb9\xa2\xa3\xe2\x37\x33\xeb\x1f\x9b\x64\xae\xf6\xf7\x74\x78\xfd\xe3\x08\xd3\x69\xc4\x6f\x73\x31\xc4\x46\x78\x7b\x11\xa1\x1d\xb5\xfe\x01\xba\xd4\x33\x3e\x5c\x4e\x67\xcc\xdb\x0c\x45\xc2\xc3\xce\xa6\xf1\xd8\x9c\x8c\xa2\xbc\xe8\x60\x3c\x27\x8b\x27\x21\xd3\xb5\xe5\x10\x93\xcf\x8e\x28\x4f\x4d\x08\x33\xb0\x4f\x87\xed\x0f\xe8\xf0\x34\xb3\xd5\x20\xb1\xe8\x63\x4c\x52\x4a\x32\x9b\x9a\x66\x45\x3b\xe6\x85\xca\x3a\x55\xd6\xf7\x98\x48\x71\xcb\xd0\x23\x41\xd1\x5c\x6b\xd4\xbe\xde\xd0\xbc\xc2\xd1\x2e\x83\x65\x31\xdd\x1d\x13\x21\xf3\xd5\x0a\xcc\x9b\x2a\x43\x64\x54\xd8\xbc\xb8\xd6\x46\xf1\x39\xeb\x9d\xe5\x71\xb4\xc7\x97\x29\x53\x65\xc0\xc3\x33\x81\xb5\x70\xf5\x7d\x53\x45\x24\x5a\x52\xee\xe3\x05\x3b\xd0\xf5\x6d\x3e\x01\xea\xec\x1f\x2c\xa7\x0e\x86\xd0\x26\x74\x8e\x45\xe8\x6e\x2d\xb4\x91\x52\x0c\x94\xcf\x76\xc6\x3c\x3a\x3a\xd8\x76\xdd\x39\xf3\x64\xf3\xd1\xb5\x0d\x94\x5d\xc5\x51\x6c\x6b\xa2\x1e\x89\x89\x2a\xd3\xf8\x0b\xd4\xe3\xd1\x3c\x33\x14\xb5\xbf\xcb\x61\x74\x56\x55\xb7\x18\xa3\xf9\x64\xd5\xdc\x5a\x4f\x5a\x03\xcf\xa5\xe9\x54\x32\xb0\xf9\x96\xf4\x66\x10\x84\x1f\xb6\x2a\xf7\xd1\xab\xdc\xf2\xa0\x7d\xef\x4f\x6a\xca\xa5\x7f\xf5\xaa\x4b\x64\x57\x99\x21\x2e\xd1\xcb\xc8\x28\x94\x0b\x3d\x06\x49\x22\x36\x84\x60\x1a\x44\xde\x86\x97\xea\xec\xcc\xae\x3b\xb2\x3f\x5d\xbd\x6d\x69\xdb\x00\xe4\xf7\x29\x01\xed\x54\xd7\xee\x36\xb1\xaa\x1c\xc2\x95\x21\x2f\xd1\xc3\xae\xf3\x88\x63\x8b\x8a\x6f\xd7\x6b\xd3\x09\xc6\x26\x34\x6a\x39\x4d\xd0\xc2\x3b\xe4\x61\xa2\x5b\x44\x81\xd6\x11\xb4\x88\x84\xff\x81\x40\xb7\x48\x80\x77\xcb\x46\xf8\x59\x27\x92\x5f\x2f\x54\xee\x4d\xdd\xf9\x8c\x45\x20\xdc\xb8\xc8\x2d\x3a\xd4\x6d\x2e\xc1\xe6\x12\x96\x5b\x94\x9c\x92\xc8\xb9\xea\x59\xa3\x4c\xe0\x72\x4c\x5e\x1a\x8b\x0a\xe2\xe9\xe8\x53\x09\x4a\x0b\x09\x0b\xd4\xe9\x85\x1d\x41\xe8\xec\x2d\x6b\xcb\x6e\x4c\x2b\x68\x51\xb7\x8f\x63\xb3\xa7\xa6\x42\xd7\xa3\x46\x1c\x8d\xbe\x69\x15\xb2\xa0\x37\xc7\x33\xca\x13\x6e\xc9\x59\xae\x47\x67\x1a\x94\x9e\x84\x3e\xad\x50\x1d\x63\x86\xe7\x3d\xad\x51\x47\x31\x0a\xd5\x54\x24\x89\x9b\x84\x39\x7b\xea\x89\x84\x35\xd5\x20\x4a\x4c\x57\x63\xa6\x65\x04\x87\x52\x4d\x92\x15\x5b\x7e\xb2\x6b\x6d\x95\x89\x53\x26\x9e\xb6\xc0\xb1\xd4\xa2\x7c\x30\x60\x2b\xd5\x62\xe8\x8e\x60\xf3\x46\x6c\x11\x9a\xc5\xa3\x7b\xce\x5e\x8a\x97\xaa\xf6\x90\x45\xa0\x16\x18\xe0\xd3\x78\x32\x77\x2f\x85\x13\x1f\xc6\x3b\xdd\xee\x3e\x09\x12\xa5\x45\x0c\xfe\x71\x26\x36\x15\x39\xc4\xf2\x3a\x67\x9a\xd1\x7c\xb7\xeb\xb2\xf6\x0c\xbf\xa6\xfd\xff\x85\x69\x4c\x28\x7e\xf7\x9a\x84\x22\x48\x0c\x18\x1d\x72\x82\xbd\x14\xfd\xe3\x50\x5f\xc9\xe1\xd5\xfd\x29\x1f\x9a\x1b\x39\x5c\xcf\x19\xb6\x2e\xa4\x42\x9e\xee\xe4\xaa\x75\x97\x27\xe7\x47\x2e\x5a\x27\x0c\x9b\xe1\xb1\xfb\x42\xde\xb2\x00\x48\x04\xb7\x10\xe1\x3a\x7d\x1c\xd4\x6f\xf9\x02\xdf\xe5\xaf\xa7\xef\xae\xfc\x1c\x13\xc1\xdd\x31\x05\x1d\xf2\x16\x26\xfe\x66\x11\x93\x71\x4c\xb7\xea\xb7\xdd\xe6\x86\xc6\xed\x58\x84\xd0\xf7\x8b\xe1\x66\xae\x81\x03\x2f\xe2\x76\x2c\x76\xa5\x70\x60\xb8\x38\xba\x37\x98\x6a\xc1\x60\x11\x55\x93\x90\xa8\x63\x34\xa3\x51\x85\x8f\x63\xe7\x3b\x1e\x7d\xc7\x22\xaf\x0e\xd6\x58\xe4\xb8\x8f\xa3\xde\xce\x72\xbe\xad\x9d\xc6\x5a\xd0\x51\x78\x89\xb7\x94\x38\x62\x39\x32\x66\x24\xda\xb8\xdc\xd5\xd0\x21\x48\xe1\x0b\x98\x14\xee\x56\x5a\xdc\xee\xf0\x81\x46\x36\x2e\xc5\x63\x4d\x72\xbd\x0f\x9e\xa3\x5b\x78\x01\x59\x31\xbb\x4b\xc3\xc8\x92\x8d\x6e\x15\x5e\x45\x6c\xa5\x88\xd0\x3f\x26\xa1\x30\xf6\x9d\x86\x21\x49\x26\x8f\x58\x15\xd5\xe9\x98\x3b\x13\xfc\x31\xb1\xc3\x5e\x40\x23\xe0\x21\x95\x6a\xef\x4f\x83\x2f\xfc\xb5\x37\x48\x14\xe3\xa0\x54\x3b\xa4\x53\x55\xd3\x6d\xf1\x73\x08\xce\x41\xfc\xa9\x53\x40\xa5\x1a\x60\x04\xfa\x8d\x9b\xf0\x96\x4e\xeb\xb4\x5f\xd9\xc5\x1a\x78\x25\x97\x66\x02\xc1\x0e\xb1\x16\x81\xce\xa8\x43\x50\x49\xae\xec\x92\x94\x26\x5a\x3c\x70\xbe\xe7\x2e\xbd\x47\x76\xc9\xcd\x1e\x05\x40\xdf\x31\xa9\x34\x92\xcd\x73\x8d\x44\x07\xb0\x45\xb4\xc0\xef\x9c\x6f\x82\x75\x21\xf2\x47\x8e\xb5\x9c\x76\x1f\x60\x66\x7b\x48\x93\x48\x77\x56\x41\x60\xe9\x4d\x8e\x05\xcc\xa2\x86\x98\xbd\xa7\xa5\x88\xbd\xe8\xe2\x97\x2a\x77\xe7\xef\xd0\x90\x40\xf0\x02\x3e\xe4\xca\x4f\x21\x6a\x42\xb9\x22\x54\x93\x58\x28\x4d\x5e\xbc\x7a\x65\x16\x58\x37\xc6\x65\xb2\x93\xb1\xe4\xde\xe9\x10\x45\xdb\xb4\x14\xec\x2e\xb4\x15\x0b\x14\xab\x67\x7a\x03\xbf\xe9\xd9\x73\xe7\x6b\x48\x83\x8e\x28\xad\xbe\xa1\xb3\x76\x13\x50\x19\x22\x6e\xf2\xde\xb1\x75\xfe\xb0\xfa\xb3\xfb\x35\x95\x51\x5e\xfc\x4b\xbc\xdb\x17\x8b\xbc\xdb\xab\x39\x7d\x33\xa6\xb7\x60\x4c\x8c\x7f\xf8\x80\x62\xbe\xb1\x70\xc4\x6e\x81\xcf\x54\xbb\xea\xdd\x32\x84\x02\x61\x19\xb0\xb3\x69\x4a\x6d\xde\x64\x2d\xa2\xa7\x53\x95\xfe\x66\x59\x4a\xbc\x4d\x78\xc4\x78\xef\xb9\x87\x87\xd6\x30\x5f\x99\x7b\x33\xf3\xc0\xd1\x59\x93\x65\xe9\xb4\xd8\x5a\x2d\x51\x22\x27\x3c\x89\x8f\x45\x08\xef\x8c\x5e\xdd\x6d\x36\xf1\x8c\xc6\xab\x4d\x3c\x0a\x34\xbb\x6d\x3e\x75\x1d\x1a\xef\x72\x96\xb8\x56\xaf\xd9\x0e\x73\x34\xce\x8f\x5a\xc3\x59\xb9\x15\x03\x2c\x9d\xcc\x5d\xcc\xfc\x0b\xb4\x9f\x34\x6f\x3a\x1d\x07\x49\xe4\x30\xcd\xf2\xd4\xcc\xfe\xc3\x39\x65\xdf\x2f\x96\x9a\x85\x92\xb3\x4c\x7a\x2c\x83\x67\x54\x5b\xae\x86\xfd\xa1\xae\x55\x01\xcf\x77\x5b\x77\xbe\xcc\x41\x6e\x42\x11\xd5\xb9\x3f\x11\xc3\xa6\x5b\x4f\xcc\x46\xe5\x02\x9b\x41\xb8\xcc\x3b\xc7\x99\x00\xf7\xea\x8b\xfa\x11\xa6\x9c\x2f\x44\x04\x6a\x77\x51\x30\x5e\x42\xfb\x7a\x94\x2f\xa7\xfb\x02\xf1\x59\x22\x3c\x8b\x44\xa7\x4a\x70\xea\x73\x7e\xe3\x1c\xc0\xb1\x4b\xe4\x14\x43\x9e\xad\x4a\xab\xad\xd2\xea\x9f\x4d\x5d\xef\x6d\xfe\x28\x1e\x9d\xe2\x58\x5e\x4a\x3a\xc3\xac\x0a\xb7\x5a\xe2\xa9\x17\xa2\xb3\xa0\xd7\x77\x36\x99\xee\x31\xf5\x88\xf1\xf6\x4e\xea\xde\x9f\xe8\x09\xf9\x5e\x9d\x39\xfd\xed\x83\x71\x1c\xb4\x53\x99\xff\x28\x50\x0b\x7d\xcc\x62\xaa\xc0\x25\x41\x6c\xaa\xb8\xb3\x33\x2f\xd1\x85\x24\xc8\x3c\x2d\xe6\xa2\xe9\x85\x3e\x35\x9d\xf3\xaa\x4b\xcd\x57\xea\x54\xbb\x8b\x2b\xd9\xae\x4d\xb8\xa9\x5b\x15\xbe\x59\x15\x5e\xd3\xb1\x34\xf7\x1b\x37\x72\x2b\x0f\xeb\xb9\x95\xf3\xa7\xfc\xa4\xee\x03\xf2\xe4\xc3\x06\x58\xf4\x2d\xd1\xd5\xc4\xfb\x83\xf0\x86\xdf\x46\xfe\x25\x96\xc9\xb6\xde\xe5\x97\xf0\x2e\x17\x28\x27\xbc\xbd\x66\xeb\x5c\x6e\x9d\xcb\xad\x73\xf9\x20\xe7\xb2\x9e\xc5\x79\x0a\x2d\x13\x0b\x6e\xc3\x49\xcd\x01\x5d\x96\x6e\x20\x57\xf9\x0a\xa6\x79\xef\x0b\xfa\x7e\xd8\xba\xcc\x4c\x9f\xe7\x14\x1f\x3f\xc7\xc2\x52\xbb\x11\xa6\x1b\x6d\xc6\x7a\x94\x69\xd0\x7a\xe7\x9b\xde\x0b\x90\x81\x18\x76\xb6\x12\xf1\xc4\x25\x62\xcf\x3c\x68\x54\xb2\x86\x05\x81\x74\x56\x29\x93\x8f\x40\x1f\xfb\x01\x0f\x61\xf0\x67\x5c\x14\x48\x09\xbc\x2d\x0b\x7c\xbb\x65\x01\xcb\xe4\xd3\x26\xe1\x5b\x76\xae\xdb\xca\xc0\x1a\x2a\x03\x96\x9c\xd3\x46\xa1\x9b\x2d\x0d\xb8\xb3\x73\x03\x32\x39\xee\xd5\x97\xf8\xe7\x1e\xbd\xcd\xb0\xff\xca\xc5\x01\x77\x88\x5b\xcd\xd6\x58\xb3\x35\x38\x9d\xba\x11\x5c\xc9\x61\x3c\x3a\xf5\xf1\xfc\x1c\xd6\x25\xf5\x01\x77\xa8\x4f\xa8\x40\x90\xda\xd1\xcd\x96\x08\x1c\xe1\xd2\x1a\xc1\xbf\xbe\x6c\x85\xc0\x6d\x5f\x6a\xc6\x52\x27\xdb\xd3\x76\x25\x1b\xb6\x09\xaf\x75\xab\xc9\x37\xad\xc9\x6b\xba\x99\xd3\x8d\x95\x09\x4a\x0e\xfa\x69\xd5\x09\x3c\x01\xd7\x52\x28\xd8\xfa\x9a\x5f\xc6\xd7\x5c\x5e\x2a\xd8\x2a\xa8\x2f\xa3\xa0\xb6\xae\xe6\x93\x75\x35\x6b\x5a\x9e\xe7\x53\x2e\x58\x96\x83\x58\x53\xbd\xc0\x09\xd9\xba\x8d\x48\x99\x1e\xad\x79\xc4\xdb\x8a\x41\xed\x8a\xc1\x53\x92\x8a\x3d\xf7\x2e\xb4\xc6\x35\x83\x74\x5a\x29\xa7\x63\x3c\x93\x8e\x78\x08\x97\x3f\xe7\xaa\x41\x4a\xc0\x6d\xd9\xe0\x1b\x2e\x1b\xb8\x77\x09\x36\x0a\xe8\xb2\x93\xdd\x16\x0e\xd6\x51\x38\x70\x67\xb0\x4a\xe5\xc0\x4d\x75\x23\x32\x61\xee\xd5\x17\xfb\x67\x1f\xce\xcd\x88\xc0\xea\xb5\x03\xb7\xd0\x56\xbf\x35\xd6\x6f\x4d\xce\xa7\x76\x48\x57\x72\x1c\x8f\x4e\x89\x3c\x3f\xef\x75\x59\xf9\xc0\x9d\xea\x53\xaa\x1f\xa4\xf6\x74\xc3\x05\x04\x47\x3a\x5f\x41\x38\xf9\x78\xf1\x85\x4b\x08\x0e\x80\x52\x83\x96\xf9\xdc\x9e\xc0\x2b\x59\xb3\x8d\x38\xb1\x5b\x95\xbe\x71\x95\x5e\xd7\xeb\xdc\x60\x1d\xa1\xe4\xac\x9f\x58\x21\xc1\x93\x70\x3d\x95\x04\xb7\xda\x43\xa4\x75\xeb\x7b\xd6\xf1\x3d\x6b\xd4\x12\x4a\x78\x77\xeb\x7a\x6e\x5d\xcf\xad\xeb\xd9\xc4\xf5\xac\x6b\x81\x9e\x51\x3d\x61\x59\x6a\x62\x5d\x05\x05\xb7\xcf\xba\x6d\x49\x99\x36\xad\x7b\xca\xdb\x92\x42\xfd\x92\xc2\x53\x92\x8c\xbd\x3b\x18\x8c\x85\xf8\xdc\x56\xc9\x20\xc5\x53\xf5\x96\x87\x3a\xe8\x82\xba\xb9\xa4\x30\xb7\x91\x6b\x35\x02\xfd\xab\x5d\xe4\x32\xbf\xc6\x97\x90\x8c\x05\x96\xed\xd7\x32\xbc\x8a\x0f\xe5\x35\xaf\xb0\xb5\x2f\x8e\x5d\xed\x35\xdd\x8f\xc4\x61\x58\xc4\x79\x0b\xb9\x6f\x19\x07\x96\x1c\xfb\xee\x73\xd3\x37\xa5\x49\x73\x47\x91\x01\x60\xf3\x02\xf0\x70\x22\x18\xd7\xfe\x25\x39\xc5\xf7\x39\x37\x12\x35\xdb\x7d\x5f\x42\xf6\x75\x0b\xdb\x73\x0a\x69\x16\x70\xf1\xca\x99\x75\x57\x28\xc9\x2b\x1f\x42\x23\xc1\x47\xd9\xfb\xad\x15\x04\x12\xdc\x3b\x9a\xf1\xc0\xed\xd3\x38\x91\x41\xec\x15\x7c\x26\xb2\x7d\x5d\xca\x93\x55\x4c\x2b\x9e\x4a\xdd\xa0\x26\x4f\xfd\x47\xad\x63\x9e\x91\x3a\xad\x97\x53\x9f\x91\xab\xa7\x92\x57\x2f\xf5\xe3\xf6\xfe\xcc\x7f\xcc\xde\x9d\x5d\x9d\x6d\x9f\x19\x5f\x33\xf1\xee\x5e\xb8\x90\x9f\x5c\xfa\xd6\x85\xda\x69\xf7\x3a\xef\x5b\x58\x92\x89\x2f\x73\x4e\xd7\xe0\x9b\xba\x91\x6b\xb3\x96\x6b\x70\x4d\xd3\xe7\xa4\x66\xa6\xe1\x0b\x71\xf2\x63\x52\xfa\xdb\x00\xd6\x07\xb0\x05\xde\x79\x1a\xe9\x9d\x45\xaf\x20\x2f\x0d\x53\x5b\x64\x42\x13\x65\x0a\x02\x42\x12\x09\x93\x88\x06\x50\xf0\xad\x1a\x68\x0a\x7c\x26\x51\x09\xfb\xad\x5b\x55\x6c\x1d\xeb\x05\x8e\xf5\xf2\xb2\xc1\x56\x65\x36\x55\x99\x5b\x3f\xf9\x29\xfb\xc9\xcf\xcf\x4a\x54\x16\x01\xf0\xeb\x0a\x43\x41\x3e\x03\x4c\x4c\x92\x7f\x0c\x24\x12\x23\xec\x2f\x41\x33\x11\x42\xc4\x6e\x01\x1f\xb4\xd2\xc8\x54\x58\x10\x4a\x04\x6f\xdd\xc6\xa2\x4c\x47\x36\x39\xf1\x5c\x41\x60\xfe\x9d\xd7\x5b\x29\x79\xb2\x52\x92\x06\x92\x19\x83\xd7\xac\x06\x38\xe1\xc8\x17\x06\x56\x14\x92\x2c\xf2\x7a\x9b\x2e\xb0\x5e\xf9\xc8\x02\xde\x5d\xf7\x42\x99\xfc\x41\xde\xb0\xf0\x7a\xb7\xc9\x7b\x65\x8e\x45\x1c\x53\xa2\x00\x63\xbd\x39\x57\x23\x0b\x84\x15\xa6\x71\x63\x74\x55\x09\xe5\x53\x22\x86\x9d\x9d\xc5\x47\x3d\xd7\x7d\x56\x06\xb9\xcb\x09\x3f\x18\x68\x9f\x5b\xde\x34\xbc\x26\x77\x7d\x83\xa8\x3d\x0c\x5e\xb3\x8e\xd9\x72\x33\x70\xda\x17\x07\x3f\x0c\x46\x27\x00\x53\xf7\x16\xe2\x75\x43\x3a\xa1\x23\xf8\x64\xdf\x78\x76\xbd\x5b\x05\xd3\x6e\x2a\xa5\x28\x70\x44\x4d\x20\xc0\xb4\x0c\xbe\xe9\x74\x04\x9d\x65\xe8\x65\x8e\xe9\x90\x46\x0a\x6a\x81\xcb\xb8\x86\x11\xc8\xc2\x95\x98\x71\x16\xe3\x1b\xc0\xf7\x2b\xd0\x50\xec\x0f\x58\x01\x89\xec\x7d\x6f\x46\xe1\xe3\x8b\x04\xe9\x57\xc7\x0c\x7f\x62\x7a\x6f\xbf\x7e\xd9\xed\x2e\xb4\xca\x0b\xbc\xec\x5f\xe7\xf4\x68\x55\x15\x12\x0f\x23\x4c\xa2\x27\x9b\xee\x5f\x64\xf0\x16\x1a\xbd\x65\x86\xaf\x68\x68\xa6\xbb\xcf\xf3\xf6\x9d\x67\xe3\xd7\x95\xb8\x37\x7b\x7f\xba\xbf\xa7\x59\x82\xbc\x66\x6e\xd9\x4f\x7c\x98\x77\x33\xdd\x94\x6f\x93\xc3\x2b\xbd\x56\x92\xca\x9f\x63\x6d\x93\xcc\xf7\x93\x4b\x13\xf9\x95\xa9\xfc\xf2\x33\xad\x93\xce\x7f\xb8\x7a\x9c\x7e\x21\xbe\xfc\x06\x53\x38\xa5\xea\x6b\x1b\xa5\xf9\x28\x2d\xe5\xe5\x27\x1a\xa1\x15\x55\xd8\x9e\x04\xf7\xb1\x57\xdd\x66\x72\x9e\xa8\x71\x89\x26\xb3\xed\xf1\x26\x3d\x8a\x3a\xcd\x04\x76\x54\x6b\x88\x27\x0d\xdb\x4d\x52\x18\x1c\x87\x6e\x75\xdc\x6a\x3a\xce\x4b\x76\xe6\xe1\xd9\x23\xfa\x42\x0c\xbc\xd5\x75\x5b\x5d\xf7\x15\x75\xdd\x4a\x4d\x1d\x2e\x09\x95\x12\xa2\x3a\x95\xf9\xc8\xe8\x91\x5d\xe9\xed\xcc\xab\xd2\xe2\xe3\x37\xfc\x16\x85\xb7\x26\xe3\x0d\x97\xd7\x3b\xe5\x21\xf1\xc2\x3c\x06\x4e\xac\xcc\x5d\xcc\x92\x61\x2e\x67\x51\x7c\xbe\x47\x29\x64\xf8\xe1\xfa\xd3\x44\xc2\x90\xdd\xd7\x83\x90\x2a\x68\x33\xae\x80\x2b\x66\xfa\xe5\x70\x05\x62\x17\x68\x04\x58\xfe\xf9\x21\xa5\xa0\xd9\x7e\xbc\x5a\x40\xfd\x3a\x06\x3d\x06\x99\x11\xca\x98\x4f\x33\x1f\xdf\x40\x8f\x9f\x72\xfd\xf5\x04\xfc\x93\xc6\x73\x46\xb4\x1c\xe6\x81\x10\x11\x50\x6e\xc6\x64\xd6\xb0\x08\xee\xff\x6d\x9b\x2b\x6d\x73\xc9\x5d\x41\x68\xed\xad\x51\x65\xe0\xce\x9e\xb2\xc4\x99\x3e\x87\x4b\x71\x31\xdb\x62\xd8\x37\xba\xb3\x6f\xae\xdb\xd6\x42\x6b\x17\x6b\xd3\x39\x77\x9f\x6a\x11\xe6\xd3\x61\x1b\xaf\xb4\xcd\xa5\x5a\x30\xe3\xcd\x59\x08\x22\xc5\xb3\xbe\x65\x22\x51\xa9\x4d\x6d\xa1\x9a\x4f\xb8\xbf\x55\x52\x82\x12\x89\x0c\x00\xaf\x27\x91\x36\xa9\x93\xfe\x8b\xee\xa1\x31\x08\x1f\x44\x88\x21\x4d\xd8\xaf\x89\x43\xe1\xfe\xb2\xdc\x7d\x62\xbd\x32\x18\x2f\xb5\xc4\x76\x4d\xd3\x49\x48\xb5\x90\x04\x05\x37\x41\x0a\x0f\xa5\x88\xdd\xf3\x4d\xcd\x12\x9e\xd8\x1e\x85\x9a\xd0\x38\xbd\xe0\xe4\x1e\x2d\x44\x29\x1c\x47\x9c\x1c\x9d\x9f\x12\xc0\x01\x9d\x9d\x4a\xb3\x9e\x33\xe6\x36\x4d\xd9\x72\x2f\xa9\xd7\x4c\x47\x29\xe3\x97\x59\x74\x3b\x3c\xfb\x3c\x07\x28\x21\x25\x60\xfd\x74\x75\x75\xee\xa6\xce\x3c\x22\x07\x3f\x35\x5d\xed\x88\xe7\xf5\x75\xdb\x65\x06\x03\x8b\xf5\xcc\xfa\x06\xa1\xc6\x1b\x90\x71\x12\x53\xde\xc6\x4e\x41\x3a\x88\xc0\xfb\xd0\xfe\xec\x26\x52\x0c\x22\x88\xb3\x5d\x42\xd0\x94\x45\xbd\xda\xeb\xc1\xfd\x24\xa2\x9c\xe6\x6d\xd7\xdc\x9a\xa5\x07\x47\x88\xe5\xf0\xca\xad\x2e\xf0\x1d\x29\x60\xee\x18\xb6\xfd\xe3\x4e\x22\x1a\xee\x52\xed\xce\x99\xe6\xf4\x4c\x6f\x96\x02\xf1\xcf\xcb\x9f\xcf\xfc\x40\x0f\x87\x6b\x66\x21\xa1\x08\x12\xbc\x9b\x0a\xef\x9b\x4a\x80\xdc\x8d\x59\x30\x26\x01\x76\xe6\x84\x55\x10\x96\x1e\xdb\xe9\xdb\xde\x4e\xc9\xd6\xff\x88\xc4\x80\x46\xd1\x94\x24\xb6\x41\x31\x73\xf3\xf1\xf0\x68\xaa\x22\x3a\xa8\x1b\x86\x42\xc6\xf8\xf5\xc7\x8f\xa7\x6f\x6f\x0f\x3b\x3b\x15\x5b\x65\x6f\xeb\x4f\x12\x17\x73\xf8\x1b\xba\x8e\x73\xec\x5b\x80\xc3\x0f\x30\xec\x48\x28\x56\x8f\x87\x8c\x43\x88\xdb\x7e\x3a\xbd\xfc\x99\x1c\x1e\xec\xff\xfd\xfa\xbb\xb1\xd6\x93\xde\xde\xde\xdd\xdd\x5d\x87\x29\xd1\x11\x72\xb4\xc7\x94\xd8\x1b\x8b\x18\xf6\x94\xa6\xf8\x02\xf4\x50\xf9\x27\x28\x4c\x6f\x70\x31\xd5\x19\xeb\xf8\xfb\x4a\x60\x3f\x08\x0e\x1a\xe3\xbd\x32\xa8\x2e\x60\x22\x41\xa1\x3b\x41\x28\x89\xdd\x48\x42\x63\x7c\x64\x5a\x67\xa7\x92\x1f\xca\x78\xc1\x1c\x5f\xf6\x71\x66\xa3\xff\x6a\xe7\xae\x10\x72\x2e\x9c\xc9\x0e\x21\x60\x31\x8d\xdc\x96\x04\x38\x62\x14\x22\x7d\xa8\x43\xa2\x43\x4e\x35\x89\x13\xa5\x8d\x37\x6b\x1e\xc0\x14\x0b\x09\x64\x28\xd1\x8a\x0a\x4e\x42\x36\xc2\x6a\xbc\x1e\x53\x93\x17\x2f\xec\xe3\x09\x4b\x62\xc6\x85\x44\x1e\xd0\xa9\x75\x4b\x6f\xe2\x32\x21\x6d\x8b\xe0\x00\xb8\x0f\x00\x6f\xfa\x1b\x83\x4f\xde\x7b\xc8\x66\x26\x79\xe2\xd8\x9f\x23\x33\x46\x11\x2a\x21\x6d\xba\xf7\x69\x7a\x85\x2f\xae\xf7\xd3\x73\x60\xf8\xa7\x52\xec\x77\xbb\x9d\x6e\xb7\x4f\x4e\x3e\x5e\xa0\x83\xd0\xdf\xc7\x0f\x3f\x7d\x7c\x97\xdf\xa1\x84\x03\x5d\xcb\x9b\x06\x89\xa5\x91\xdf\xbe\xeb\xfe\xff\x4f\xfb\xed\xd7\xd7\xff\x0e\xff\xf3\xfb\xef\xfe\xdd\xf9\x77\xf8\xc3\xf7\xff\xfd\xb7\xcc\x7d\xf6\x60\xf7\x76\xea\x79\x9b\x79\x76\xb6\xab\x1c\x85\xa1\x04\xa5\x7a\xcd\x98\x22\x62\x1c\xf6\x7b\xcb\x30\xc1\x51\x07\x4b\x47\x05\x4c\x4f\x97\x0e\x92\x30\x62\x82\x2f\x1d\x86\xf9\x10\x1a\xdd\xd4\x32\x36\xee\xf9\x81\x73\x83\x0b\xfc\x8d\x8c\xf6\x62\xff\xd5\x2b\xa7\x19\xd2\xe7\x34\x16\x8d\x4f\xc9\x0e\xe7\xb6\xe4\x6a\xdf\x48\xd5\xdb\xa9\x18\x45\x08\x70\x2c\x24\x7d\xba\xfc\xf5\xf4\xdd\x55\x8b\xe0\x0b\x53\xaf\xf3\xf3\x3f\x40\x16\x4e\x17\x00\x73\xd7\x49\x0c\x9a\x62\xcc\xdd\x69\x76\x80\xb7\x20\xd5\x0c\x41\x0b\xcb\xff\x62\xaf\x7b\xfe\x76\x05\xe4\x16\x61\x3c\x90\x80\x88\x41\x88\xf5\x38\x30\x51\x98\x75\xcb\x3a\x3b\xcb\x4b\x6a\x25\x05\x35\x17\xb9\xdd\x50\x5d\x09\xcc\x15\x8b\x53\x49\x33\xc3\x6d\x97\xa7\xd5\x70\x44\xa4\xd1\x9f\x07\xb3\xe8\x76\x57\x10\x3e\xaf\xee\x43\xaa\xa1\x8d\x37\xda\xa4\xd7\xe0\x1e\x82\x44\xcf\x50\x68\x91\x64\xb9\xf3\x38\xf1\xf3\x76\xf3\xa7\x78\x32\xbb\x5a\x01\xbd\x77\x94\x45\xee\x95\x8b\xa6\xce\x97\x6d\x9e\xcb\xcf\x65\xd8\xba\x87\x84\x64\x83\x8a\x67\x64\x9e\x2b\x32\x34\x4b\x36\xe4\x09\xbf\x59\xe5\x39\x9c\xa5\x05\x59\xe4\x09\xbb\x47\x0a\xe2\x8a\xc7\xcf\xe1\x5e\xdf\xb8\x35\xea\xf2\x00\xce\xf1\xfb\x3e\xe8\x94\x23\xaa\xf4\x0d\xe4\x9d\xec\xb9\x7d\x2f\x80\xaa\x8c\xc6\x38\x61\x06\xf1\x85\x00\x9c\xf0\xf0\x4a\x9c\xf0\x30\xf5\xd6\x7a\x3b\x25\x7b\xe4\x8c\x68\xe6\xd6\x39\x87\x66\xea\x1b\xd4\xfc\xf1\xfa\xd4\xed\x1d\x9d\x7a\x97\x2b\x90\xd8\xa6\x8c\x21\x1d\xd5\x24\x16\x4a\x93\x17\x2f\xf1\x49\x86\x68\x49\xb1\xd5\x63\x28\xa4\xd1\x2c\x84\xf2\x90\xec\x1b\x5d\x46\x8c\xc2\xc9\x60\xcf\xdb\x62\xa5\xa9\xd4\x68\xb3\x80\x87\x2e\x5d\x4c\x54\x44\xd5\xd8\x98\x52\x4c\xab\x50\xb4\x81\x77\x02\x43\x1d\x65\xb8\x10\x6f\x6a\xc3\x11\xd9\x73\x48\x4b\xce\x22\x35\x6b\xff\xf1\xdb\xa7\xa3\xf6\xff\xa3\xed\x3f\xba\xed\xd7\x7b\xff\xdd\xfb\xee\xfb\x4e\x6b\xf7\x07\xd2\xbe\xfe\xcf\xbf\xfd\x87\x1b\x1a\xd3\xfb\xf7\xc0\x47\x7a\xdc\x23\x2f\x5e\xba\xef\xe0\x9e\xc6\x93\x08\x9b\x0a\x4e\xcf\x7e\x69\x1f\x74\xf7\x5f\xef\x75\xbb\x87\x07\x56\xd0\xce\x92\x18\x24\x0b\x16\xd3\x39\x23\x6e\x9e\x6a\x44\x42\x20\x78\xc0\x30\x40\x36\xf9\x57\xa5\x0b\x84\x74\x7e\xc8\x52\x22\x2e\xc2\x78\xf7\xb7\x4f\xdd\xf6\xeb\xeb\x1f\xfe\xb6\x5b\x0b\xc1\xfd\x6e\xf7\xa0\xdb\xdd\x2f\xe8\x90\xf3\x44\x4e\x84\x5a\xca\x40\x6e\xd8\x8c\x52\x68\x11\x4a\x0e\x49\x04\x78\x00\xc6\xa6\x1d\x74\xbb\x07\x07\x64\xe2\x06\xa3\x35\x2b\x72\xc9\x02\x46\xaa\x8b\xf3\x43\x4f\xf9\xf2\xe3\xf9\xb9\xa5\xc0\x05\xc4\x4c\x6b\xca\x03\x38\xe5\x56\x65\x57\xa9\xd2\xdc\x75\xa2\x21\x8a\xbc\xf0\xa4\x67\x7d\x37\xa6\xba\x20\x4e\xcc\x60\xd5\x22\xc0\x4c\x7a\x47\x69\x99\x04\x3a\x91\x68\xde\xd0\xa1\xcb\x3e\x37\x54\xa6\xf9\xa9\xd9\xb7\x33\xe0\xbe\x93\x00\x44\xa3\x36\x13\xc3\x94\xe4\xfb\x87\xdd\x1c\xcd\xfd\xb6\x15\xd4\x6e\x48\xf1\x19\xaa\xef\x1f\xfa\xfe\x15\x42\x6a\x80\x8b\x8c\xb3\xbf\xff\xea\xf0\x75\x5e\x76\xbc\x48\x31\x4e\x20\x82\x00\xf3\x23\x2c\x70\x3a\xb7\x95\x7b\x68\xda\x60\x6a\xb9\xab\xa6\x69\x4e\x91\xda\xfd\xed\xe2\x9d\x11\x9e\x3f\x0f\xfe\x42\x86\x32\x7f\xee\xb7\x0e\xf6\xff\xca\xf9\xc1\x79\xbe\xb9\x78\xb7\xff\xe3\xcb\x17\xaf\xbb\xdd\xbf\xbf\x3c\xfc\x7b\xf7\xc5\xa1\x1d\x95\x9a\xe0\xb7\x34\xeb\x13\x2e\x60\x87\x17\x66\x59\xc3\x05\xb3\x18\x3a\x08\x32\xf0\x46\x17\x99\x83\xb7\x32\x85\x39\x00\x1f\x14\x4c\xd0\x3e\xdc\x8d\x81\xcf\x2e\xe4\x1e\x25\x90\xa6\x0a\x71\x2f\xfc\xda\x78\x4e\x33\xbc\x55\xa0\x45\xde\x78\xed\xcc\xa2\x8a\x4a\xb0\xdd\x7d\xd5\xde\x77\x48\xfe\x82\xb1\x5a\x25\x82\x39\x2d\xf1\x26\x51\x8c\x83\x52\x24\xa4\x53\xaf\x2a\xdc\x1b\x38\x67\x00\x2f\x62\x4c\x39\xc5\xfc\xdb\x60\x6a\x67\x80\xbc\x05\x69\x02\x39\xa6\xf2\xc1\x7f\xde\x87\x49\xf7\x44\x0c\x32\xfc\xa7\x05\x0a\xdd\x51\xa4\x75\x00\xec\x16\xc2\x16\x89\xc5\xad\xa5\x78\x6a\xec\x07\x79\x78\xd9\xb0\x72\x2e\xa1\x43\x9d\xf3\x38\x70\x58\x90\xe8\xb6\x18\x0e\xed\x7d\xd4\xb9\xed\x19\x86\xa2\x77\x00\x9f\xd1\xca\xe1\xb2\xf8\x30\x31\x32\x16\x11\x9b\xa3\x49\xb3\xe3\xc1\x64\xd2\xcf\x3c\x9a\xe6\x4a\x8b\x36\x0a\x68\x62\x9c\xdc\x61\x50\xa5\xd8\x88\x67\xc4\xf0\x38\x1b\x2f\x10\xef\x80\x0a\x02\x98\x20\x5f\x31\x5d\x75\x3a\xd5\xb0\x97\x00\x9a\x63\xae\xcb\xd3\x0f\xed\x43\x80\x17\xf4\xc7\xf0\xc7\x76\x40\xff\x3e\x68\x1f\x1e\xbc\xee\xb6\xe9\xcb\x83\xa0\x1d\x86\x2f\x07\xaf\xf6\x5f\xbd\x84\xe0\xf0\x85\xd3\xd0\xa8\x0e\x99\xe0\xd6\x5d\xaa\x40\x10\x2f\xe5\xb1\x1b\x61\xd0\x8f\x36\x45\xda\xe9\x45\x4f\xa7\xe5\x4f\xcb\x38\x5d\xc6\x4b\x2b\xf3\x7e\x9d\x1b\x66\x83\x74\x93\x5d\x4a\x26\xeb\xa6\xc5\x47\xfe\x99\x8b\x3b\x9e\xaa\xbd\xd9\xeb\x73\x82\xe8\x6e\xf8\x3f\xd2\xa5\x94\xb8\xf2\xb7\xf4\xfb\xe3\xbc\xa3\xa9\x86\x68\x0e\x7a\x95\x7b\x5b\x82\xd2\xc7\x49\xd8\x14\x2c\x43\x7c\xa7\xa6\x36\x0a\x9b\x73\x73\x2e\x0b\x79\xe7\x02\x7c\x6e\x04\x89\xd8\x10\x82\x69\x80\xe9\x5a\x33\xb8\x43\x8e\x52\x90\x73\xdc\x85\x71\x10\xc7\x00\x95\x50\xae\xf0\x79\x2e\x21\x6a\xf4\x31\x44\x26\x0d\xf5\xf1\xec\xf8\xe7\xb3\x77\xa7\x17\x1f\x4e\xde\xa2\x10\x63\x9a\x8c\xf2\x84\x46\x04\x4b\x0f\x70\xd7\x59\x1a\xb1\xbf\xbd\x38\xc2\x88\xfd\xfc\xe4\xec\xed\xe9\xd9\x3f\x6e\x8e\xce\xcf\x2f\x7e\xfe\xe5\xe8\x7d\x8b\x5c\x7e\x7c\xf3\xe1\xf4\xea\xea\xe4\x6d\x8b\x1c\x1d\x1f\x9f\x9c\x9b\xbf\x2e\x4f\xae\xae\xde\xe3\x1f\x17\x27\xff\x3c\x39\x36\x5f\x1d\x1f\x9d\x1d\x9f\xbc\x77\x5f\x5e\x7d\xbc\x38\xc3\xbf\x72\x60\x15\xf2\x00\xe7\x54\x66\x59\x92\x9a\x2e\x88\xa9\xc3\xa4\x9f\x66\x88\x89\x45\x3b\xaf\xe5\x3c\xf1\x26\xb8\x89\xc7\xbc\x02\x7b\x82\x3a\x07\x93\x20\x37\xb5\x96\x1f\x00\x87\x21\x0b\x98\x49\x3f\x2a\xf7\x64\x4b\x1b\x53\xd8\x65\x6a\xef\x66\x62\xcf\xca\xfd\xde\xe4\xf7\xb1\x2b\xbb\xfe\x61\x53\x3a\x3a\x7d\x73\x74\x56\xea\xa1\xe0\x2f\xc3\xca\xc6\x37\x99\x7f\xd5\xfe\x42\x98\x26\x52\xdc\xb2\x10\x64\xdd\x1c\xc1\x91\x9d\x77\xee\xa6\x65\xee\x0b\x2d\x26\xe1\x96\xae\x63\x87\xbb\x04\x5e\x71\xd1\x86\x3c\xb2\x30\xf9\xe5\xe0\x25\x1e\x4f\x57\x3b\xa2\xe4\xcd\xe9\x71\x91\x70\x18\x32\x98\x60\xc8\x09\x9f\xea\x90\x0b\x57\x7a\x32\x03\x07\x42\x8f\xdd\x00\x64\xb2\xdc\xfb\x2a\x2a\x89\xbc\x90\xbd\x7e\x72\x65\x16\x5b\x65\xe1\x39\x5e\xa6\x33\x30\x2f\xdc\xc7\x09\xd7\xb1\x88\x22\x6f\xbe\x6c\xb1\xae\xb7\x53\xb2\xa9\x1b\x4d\x82\x74\x78\xa7\x9a\xd8\x15\x6d\x34\x65\x67\x30\xdb\x32\x53\xb2\xda\xcc\x8a\x2c\x6c\x19\x69\xc1\x38\x55\x4b\x36\x48\x34\x28\xbf\x43\xd5\x2e\xf8\xc3\x0a\xbe\x7d\xfd\xd6\xa6\x1c\x5c\x33\xf3\x4b\x8f\xae\xa0\x27\x9d\x72\x29\xc0\x47\x4c\xda\xb2\x09\x2c\x8e\xf6\x98\x0e\x2d\x02\x95\x11\x60\x76\xb9\x0a\x32\x2e\xa2\x0f\xfe\xd8\xba\xc0\xfc\xf7\x8b\xe1\xf3\xd5\x98\x22\x70\xf8\x13\xc2\x40\x17\xf3\x4b\x75\xd6\x73\xf8\x1a\xb5\x3f\xbf\xa6\xf7\x43\xd6\xbb\xaa\x2a\xe4\xaa\x1b\xae\x69\x5d\xdc\x92\x45\xe7\xea\xc8\x4d\x16\x35\x93\xe7\x17\x4d\xc3\x8c\x9b\xd4\x0f\xbc\x09\x73\x61\x4f\xdd\x6d\x0a\x41\xe1\xfc\x36\xa6\xf4\xb5\xd2\xc2\x69\x20\x36\xbf\xa8\xd9\x1b\x6e\xd2\xc0\xb9\xe9\xd2\x33\xb1\xc4\xfc\x06\xce\x99\x16\xfc\x46\x16\x9c\xf1\xba\x1b\xcc\xf8\xf2\xf3\x1b\x00\x0f\x6f\xb4\xb8\xc1\x5f\x2b\x63\x31\x97\x18\x9d\xdf\x86\xdb\x94\xde\xea\x7b\xcc\xe6\x04\xe7\xb7\x70\xba\xe9\xc6\xe5\xc1\x56\xe4\x52\x97\x72\x9b\x5f\x5e\xa6\x79\xab\x1b\x36\x9f\xb8\xaa\xbb\x4b\x69\xf6\x6b\x7e\x33\x17\x3f\xcc\xe4\xd0\xeb\x6c\x90\x06\x2b\xf3\x8b\x26\x93\x70\xc5\x45\xd3\x50\x23\x5b\x34\x62\xfc\xb3\xaa\x61\xe8\x66\x8c\xee\x88\xb9\x06\x0a\x33\xbf\xb3\xb3\x5c\x8d\x0f\x99\xcc\xda\xa2\x4b\x57\x7d\xcf\xf8\x67\x1f\x53\x9b\xd1\xf6\xbe\xb2\xba\xc6\x2d\xa2\x0d\xd6\x8f\x68\xd3\xe5\x39\xdc\xd7\x5f\x1e\x07\x37\x5b\x1e\xbb\xab\x6a\x2f\x9f\xb6\x62\xd5\xda\xc2\x49\x84\xe5\x28\xf4\x00\x21\x23\x54\x61\x0b\x37\x30\x6b\xd3\xd8\xa9\x64\x89\xad\x27\xb5\xd0\x93\xaa\x76\x80\x72\x68\x5a\xa7\xa6\xe5\x9c\x91\x56\xea\x40\xb4\x9c\x39\x2a\x2e\xb9\xaa\x83\x44\xa3\xe8\xe7\x61\xd9\x85\xaa\x1b\x01\x96\x7b\x4f\x05\x2c\x8c\x3d\x6e\xa5\x4d\x0f\xd7\x0d\x7c\xad\x95\x41\x5b\xec\x32\x15\x89\x5c\x08\x55\xaf\x1b\x79\x6d\xdf\x02\x7c\x5b\xff\x6f\xeb\xff\x6d\xfd\xbf\xc7\xe4\xff\xcd\x98\xdb\x1a\xb9\x8b\x1a\xf6\xf6\x01\x86\xf5\xdb\xb7\x96\x5f\x20\xef\xb0\x9a\xed\x5c\xcd\x3c\x6e\x26\xb9\xb0\x4d\x2e\x6c\x93\x0b\xdb\xe4\xc2\x36\xb9\xf0\x58\x92\x0b\x8e\x54\xff\x00\x3d\x6b\x03\xe7\xec\x94\x1b\x8a\x8f\x0f\x9d\x09\x4f\x4b\x4c\xda\x03\x2c\xe1\x33\x09\x31\xb7\xb6\x6e\x6b\xeb\xb6\xb6\x6e\x6b\xeb\x1e\xbb\xad\x73\x00\x58\xb3\xb0\x0d\xa3\xb6\x61\xd4\xb3\x0a\xa3\xb6\x56\x60\x6b\x05\x9e\xb9\x15\x30\x56\xe0\xd1\x45\x3c\x97\x9c\x4e\xd4\x58\xe8\x52\x5b\x75\x2c\xb0\x39\x55\xdb\x2e\x49\x70\x0f\x56\x70\xf6\xcb\xdd\x04\xa1\x73\x37\x55\x15\x6f\xe2\xab\x69\xd0\x8a\x16\xa9\xae\x35\x2a\xb9\xf9\xf0\xc1\x37\x0c\x56\x58\xb2\xaa\x06\xd4\x32\x1b\xd2\xcc\x76\xcc\xdb\x8c\x3a\xbc\x5d\xd4\xea\x65\x36\xa2\xf9\x2a\xf3\x36\x61\x05\x5b\x30\x1f\x5e\xac\x10\x56\xd4\x31\x24\x2b\x18\x90\x72\xc3\xd1\xd0\x60\x2c\x32\x14\x2b\x19\x88\x45\x86\x61\x25\x83\xb0\xcc\x10\xac\x68\x00\x16\x2a\xfe\xd5\x14\xfe\x02\x45\x5f\x83\x6b\xe6\x14\xfc\x72\xc5\xfe\x00\x85\x5e\xae\xc8\x1b\x2a\xf0\x72\xc5\xdd\x40\x61\xfb\x1b\x6e\xde\xd2\xa9\x5a\x18\x61\xe4\xef\xcc\x51\xa8\x9b\xa9\x93\xef\x05\x9a\xf9\xc1\x1d\x12\xb3\xcf\xb3\x2a\x79\x92\x55\xc9\xb6\x8d\x33\x5d\xe5\x20\x95\x59\x92\x12\xc2\xe0\xb3\xc0\xf2\xb7\x01\x75\x76\x0a\x63\xab\x4d\xc0\xbc\x21\x98\xb9\x58\x16\x18\x2d\x59\xcd\x05\x47\x1e\x9e\x76\x48\xa7\x33\x98\x2e\x0a\x6d\x16\x50\x73\x31\x91\xdc\x09\x96\x40\xbb\x14\xe2\x25\x34\xc0\x7f\x41\xa2\x6f\xc4\xb0\xa2\x0b\xa1\x70\x16\x4e\x90\x73\xf7\x5d\x25\x5c\xb3\x68\xfe\x7e\x2b\x2a\x0b\xf7\xcc\xf9\x9b\xaf\x3a\x0f\x87\x3f\x33\xe6\x0e\x98\x9f\x98\xd2\x42\x4e\x6b\x85\xef\x63\x3b\xb6\xb3\x53\x79\x1a\x4f\x55\xa4\xea\xba\x68\x39\x08\x77\x1a\x9d\x53\x31\x6d\x70\xe3\x28\xfd\x85\x64\xc3\xef\x5a\x86\x79\x73\xec\x0b\x0f\x3e\xed\xad\xc6\xb2\x8e\x1c\xc7\x17\x27\x47\x57\x27\x2d\xf2\xf1\xfc\xad\xf9\xfd\xf6\xe4\xfd\x09\xfe\xbe\x38\xb9\xbc\xfa\xf9\xe2\x64\x96\x3c\xf8\x63\x1e\xd2\x56\x43\x16\x3f\x2a\x90\xe4\x6e\x8c\x8f\xa5\x0b\xdd\x8d\xed\xc6\x93\x6f\x11\x4d\x3f\x03\xcf\x9e\x4b\xe6\x1e\x22\xe7\x1e\xc0\xb6\x92\x08\x3a\xff\xae\x92\xbe\x05\xc0\x4e\x0b\x4f\x64\xca\xdd\x1d\xea\x9e\x05\x35\x03\xef\x4a\x00\xa1\x12\x50\x9a\xc6\x93\xde\x2a\xb3\xab\x34\x4a\xf1\xbf\x01\x0c\x85\x84\xe6\x0c\x35\x13\xa3\x95\x71\x97\xb9\x5b\x75\x4d\x2b\xbb\x2f\xdf\xb1\x08\x2e\x00\xef\xb9\xee\xed\x94\x1c\xca\xcf\x89\x0e\x44\x16\xf4\xb1\x18\x47\xe2\x27\xa0\xc1\x38\x0d\x0f\x8d\xdb\x31\x64\x11\xb4\xfc\x8d\xcd\xf6\xa5\x04\x6e\x16\x5e\xe9\xec\x54\x4a\xeb\x83\x75\xe7\x9c\xec\x37\x50\x88\x55\x0a\x62\x9e\x65\x9b\x28\x83\x32\x45\xb8\x80\xb9\x8a\x4a\xb0\x8d\xf4\x9a\xd1\xda\xd5\x0a\xb0\x82\x04\x8b\x70\xc3\x9f\x18\x94\xa2\x23\xa8\x10\xcd\x02\x0f\xa0\x27\xd5\xff\xa0\x46\xa7\x61\xbf\xec\x44\x6b\xe2\x48\x48\x3c\x73\xef\x58\xad\x49\x29\x71\x68\x14\xb5\x85\x6c\x73\xa1\xc7\x8c\x8f\xf0\xa5\x8d\x52\x33\x1a\x15\xc9\x84\x3f\x96\x47\x8b\x8f\x25\x58\x96\x36\xf0\x6c\x83\x44\x5c\x6d\xa6\x79\xae\x64\xf5\xc4\x59\xf3\xbe\xc0\xcc\xd7\x38\xd8\xe5\xc7\xeb\x46\x78\xfb\x96\x05\x3a\x95\xba\xb8\xc6\x49\xcc\x04\x9d\x0f\x5c\xc9\x71\xfb\x42\x80\x0a\x7c\xf8\x16\x24\xbb\xcd\x3f\x39\x13\xb9\xd0\x3e\x96\x54\xe1\x1d\x7d\xf8\x31\x3d\x7d\xf7\xf4\xf2\x29\x83\x28\x54\xd9\x18\x16\x16\xee\x24\xac\xdd\x54\x5b\xa7\xb5\xb6\xdc\x23\x58\x5c\x8b\x2d\x41\xf3\x08\x13\xdf\x2c\x9c\x57\xae\x39\xdc\xa2\x08\x6f\x76\x77\xb2\x80\xb7\x29\x9f\xfd\x7c\x75\x73\xfa\xe1\xfc\xe7\x8b\xab\x93\xb7\xf6\xae\x77\xf3\x5a\x29\xf3\x8c\x12\xf3\x98\x56\xe4\xa2\xec\xa1\x24\x2b\x1d\x58\x2a\x8a\x7e\xa3\xfc\xdd\xc9\x79\x00\xae\x77\x2a\xa6\xe3\x3d\xf9\x0b\xc8\xb0\x58\x54\x96\x0a\x4c\x4d\xb1\xa9\x2b\x3c\x15\xcf\x09\x5d\x99\x78\xe5\xcf\x03\x7d\xd0\x72\x15\xcf\xdc\x5c\xc8\x5e\x65\xcf\xe0\x4c\xad\x8b\xd7\xef\x9e\xf7\xf0\x6f\xff\xf4\x54\x1e\x80\xe4\xaa\xd3\x14\xf6\xe2\x13\xed\x0a\xa0\x5c\xa6\x0f\x7b\xf1\xfb\x35\x49\x5d\x2c\xb2\xec\x65\xa7\x5b\xd4\x33\x75\xfc\xa5\x99\x94\xe7\xac\x51\xaf\x38\x26\x27\x24\xee\xc6\xe6\x4c\x16\x32\x28\x7b\x3b\x4b\xd9\xb5\x8a\x3d\x67\xef\x72\x5e\x00\x87\x09\x09\xd8\xed\xdc\xf0\xd2\xc7\x54\x73\xb8\xf3\x87\xae\x48\x80\x0f\x4f\x56\xee\xc1\x19\xf8\xf8\xc2\xd9\x43\x9f\x7f\x12\xf5\x31\x26\xc7\xe5\x74\xc9\x31\xbb\xa7\x21\x7e\x89\xf3\x2d\x40\xe0\xa0\x2b\x7b\xe6\xaa\x7f\x60\x63\x7b\x9f\xd0\x68\x32\xa6\xed\x83\xce\xce\x12\xd2\x36\xe3\x03\x8b\x33\x7b\x3e\x9c\xe0\xee\xb6\xe9\xed\x94\x6c\x92\x63\x05\x37\xac\xb3\x53\x89\xfd\x17\x91\xf5\xf9\x27\x9c\xa6\xd0\xec\x2c\x25\xac\x3f\x62\xbb\xc6\x57\x3e\x63\xf3\x4c\xd9\x1b\xf3\x4c\xd9\x85\x07\x9d\x3d\x95\x71\xf6\xd1\xb9\xfe\x9d\x2e\x8c\xbb\x6a\xd7\xdc\x33\x72\x3b\x3b\x75\xbd\xe2\x98\xde\xdf\x94\xb7\x5d\x14\x80\xf9\x30\xf7\x74\x5d\x4a\x14\xe3\xa3\x28\xb5\x41\x2d\xc2\x86\x24\x62\x31\x2b\x71\x5f\xbe\x05\x7e\x77\xc6\xe2\x04\x5f\x41\x79\x95\x63\x9b\x12\xd8\x1c\xbb\xb8\xdd\x3a\xae\xd2\xd0\xf2\xdb\x77\x5c\xd5\x20\xfb\xc2\xfa\x8d\x37\xe9\xe3\x74\xfc\xf7\xae\xa4\x99\x7d\x21\x01\xd3\x66\x10\x5a\x1d\x53\xf2\x4e\xe1\xde\x4e\x09\x09\x4e\x78\x68\x3c\x88\x82\xc9\x37\xef\xd2\xb4\x4f\x47\x9a\x24\x6a\x6c\x9e\x25\xd5\xd9\xa9\x64\xdf\x2f\x22\xa4\xa7\x6f\x57\x15\x4d\xf7\x52\xa3\x76\xfe\xad\x20\xab\x4a\x69\x0e\x55\x4d\xe5\x08\xf4\x4d\x22\xa3\xeb\x1a\x62\x9c\x8d\x5e\xc8\x91\x47\x03\x25\x22\x74\xc2\xf0\xe1\xdf\xe8\xdf\xe3\x6f\x45\x3e\x5e\xbc\x37\x07\x94\x3f\x18\xa1\x74\xe1\x60\x96\x10\x23\x9f\xba\x4a\x24\x2b\x5c\xc9\xde\xc1\xaa\x16\x42\x87\xbc\x9d\xea\x03\x07\x8b\x16\x86\x47\x5a\xe6\xfd\x50\xf6\x52\x8c\xe2\x8a\x0f\x5a\xad\x10\x9d\x32\xef\xbe\xc2\xa7\xaf\xe1\xa2\xa5\x52\x97\xf1\x07\xfe\xd8\xd7\xf8\x2f\x44\x27\xf7\x70\x3b\xff\xf3\x2f\x48\x9f\xe3\xf6\xd3\x87\xa3\xe3\xf6\xe5\x4f\x47\x07\x2f\x5f\x11\x7c\xa2\x1a\xc5\x47\x3c\xe2\x5b\xaf\x34\x89\x00\xef\xcf\xde\x7f\x95\x7f\xce\x65\x24\xf8\xa8\x43\x7e\x95\x4c\x43\x1b\x9f\x57\xd8\x9a\x5b\x9b\x92\x11\x70\x4c\x0d\x9b\xba\x86\x7b\x75\x86\x7b\x40\x38\xce\xc8\x1e\x02\x58\x78\x7f\x4d\xf6\x24\xc0\x06\x27\x1d\x33\x9e\x3e\xb0\xf1\xd5\xaa\x6a\x71\x96\xe3\xbc\x2a\x70\xaa\xd1\x2a\x8e\xe5\xda\xd1\xff\x57\x5e\x55\x5d\xa1\xb2\xba\xb8\x2d\xa6\x41\x85\x75\xe6\x15\x52\xbd\x9d\x12\x62\x98\x37\xa3\x15\x1a\x61\xdc\x9b\x86\x45\xee\x7d\x69\xf9\x03\xeb\xec\x54\x6a\x90\x47\xa2\x28\xdd\xab\x92\xd6\xef\xcb\xe4\xc9\x54\x92\xf3\xa9\x8b\x55\xa6\xb2\xd6\xb1\xc6\x2c\x8d\x1e\xa6\x76\x1c\x93\x3c\x10\xb2\x09\x9d\x46\x82\x86\x0b\xa5\xf4\x6a\x9c\x8a\xa4\x41\xa4\x5c\x10\xe7\xce\xa6\x2a\x13\xb5\x40\x99\x38\xf6\x70\x8f\xad\x6b\x91\xb7\x27\xef\x4f\x7f\x39\xb9\xc0\x94\xcf\xdb\x93\xa3\xb7\x37\xef\x4f\xae\xae\x4e\x2e\x32\x5e\xa9\x7a\x30\xf8\x02\x37\x34\xff\x62\x41\x5b\x95\x52\x82\x0c\xa9\xec\x94\x42\x59\xe6\x6c\x2e\x78\x28\x78\xed\x07\x83\xbb\x3c\x9b\x7d\x56\xb7\x7f\x61\x58\xa7\x3e\xa1\x16\x97\x86\xca\x9f\x1c\x3e\x07\x9c\x79\xbb\x57\xcd\x87\x87\x2f\x84\xc7\xbf\x45\xe8\xa6\xfc\xc0\x2b\x5f\xc2\x83\xfb\xba\x22\x7d\xee\x39\x88\x29\x34\x9e\x56\xf8\x44\xbe\xfa\xc7\xb3\x56\xe5\xef\x8e\xa6\x74\xb9\x46\xc7\xf3\xbf\x03\x00\xdf\x4b\xb2\xb0\xb5\x3a\x01\x00"),
 		},
 	}
 	fs["/"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
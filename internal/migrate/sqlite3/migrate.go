@@ -0,0 +1,146 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	driverName      = "sqlite3"
+	sourceURLScheme = "file://"
+	upSuffix        = ".up.sql"
+)
+
+type Config struct {
+	DSN       string
+	SourceURL string
+	Logger    *log.Logger
+}
+
+// Migration applies the up migrations of the source directory which have not
+// been applied yet. The migrate library does not ship a SQLite driver, so the
+// applied versions are tracked in the schema_migrations table the same way.
+type Migration struct {
+	config Config
+}
+
+func NewMigration(c Config) *Migration {
+	return &Migration{config: c}
+}
+
+func (m *Migration) Do() error {
+	m.config.Logger.Printf("database migration started: %s", m.config.DSN)
+
+	if !strings.HasPrefix(m.config.SourceURL, sourceURLScheme) {
+		return fmt.Errorf("migration failed: unsupported source: %s", m.config.SourceURL)
+	}
+	files, err := m.sourceFiles(strings.TrimPrefix(m.config.SourceURL, sourceURLScheme))
+	if err != nil {
+		return fmt.Errorf("migration failed: unable to read source: %v", err)
+	}
+
+	db, err := sql.Open(driverName, m.config.DSN)
+	if err != nil {
+		return fmt.Errorf("migration failed: unable to open database: %v", err)
+	}
+	defer db.Close()
+	m.config.Logger.Print("database opened")
+
+	// Schema changes are done with foreign keys disabled as recommended by
+	// SQLite, the pragma applies per connection hence a single one is used.
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`PRAGMA foreign_keys = OFF`)
+	if err != nil {
+		return fmt.Errorf("migration failed: unable to disable foreign keys: %v", err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`)
+	if err != nil {
+		return fmt.Errorf("migration failed: unable to create version table: %v", err)
+	}
+
+	var current int64 = -1
+	err = db.QueryRow(`SELECT coalesce(max(version), -1) FROM schema_migrations`).Scan(&current)
+	if err != nil {
+		return fmt.Errorf("migration failed: unable to read version: %v", err)
+	}
+
+	for _, f := range files {
+		if f.version <= current {
+			continue
+		}
+		err = m.apply(db, f)
+		if err != nil {
+			return fmt.Errorf("unable to migrate: %s: %v", filepath.Base(f.path), err)
+		}
+	}
+
+	m.config.Logger.Print("database successfully migrated")
+
+	return nil
+}
+
+type sourceFile struct {
+	version int64
+	path    string
+}
+
+func (m *Migration) sourceFiles(dir string) ([]sourceFile, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []sourceFile
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, upSuffix) {
+			continue
+		}
+		i := strings.Index(name, "_")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid migration name: %s", name)
+		}
+		version, err := strconv.ParseInt(name[:i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", name)
+		}
+		files = append(files, sourceFile{version: version, path: filepath.Join(dir, name)})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].version < files[j].version
+	})
+
+	return files, nil
+}
+
+func (m *Migration) apply(db *sql.DB, f sourceFile) error {
+	script, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(string(script))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, f.version)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+)
+
+const cutOffLayout = "15:04"
+
+// Calendar gives the business days of a payment scheme. Weekends and the days
+// of the holiday calendar the scheme follows, e.g. TARGET2 or the holidays of
+// a country, are not business days. Payments received after the cut-off time
+// of a business day are processed on the next one.
+//
+// The recurring holidays are computed by the rules of the holiday calendar,
+// the Holidays add the ones no rule gives. A holiday named as a rule replaces
+// the rule in its year, e.g. when a bank holiday is moved.
+type Calendar struct {
+	Scheme          string
+	HolidayCalendar string
+	Location        *time.Location
+	CutOff          time.Duration
+	Holidays        map[Date]string
+}
+
+// NewCalendar returns the calendar of the scheme whose cut-off time, e.g.
+// 16:00, is given in the named time zone.
+func NewCalendar(scheme, holidayCalendar, timeZone, cutOff string) (*Calendar, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, errors.Generic(
+			errors.ErrCodeGenericInternal,
+			"invalid calendar",
+			fmt.Sprintf("scheme %s: unknown time zone %q", scheme, timeZone),
+		)
+	}
+	t, err := time.Parse(cutOffLayout, cutOff)
+	if err != nil {
+		return nil, errors.Generic(
+			errors.ErrCodeGenericInternal,
+			"invalid calendar",
+			fmt.Sprintf("scheme %s: cut-off %q is not in the HH:MM format", scheme, cutOff),
+		)
+	}
+	return &Calendar{
+		Scheme:          scheme,
+		HolidayCalendar: holidayCalendar,
+		Location:        location,
+		CutOff:          time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute,
+		Holidays:        make(map[Date]string),
+	}, nil
+}
+
+func (c Calendar) IsBusinessDay(d Date) bool {
+	if isWeekend(d) {
+		return false
+	}
+	_, holiday := c.Holiday(d)
+	return !holiday
+}
+
+// Holiday returns the name of the holiday falling on the day, if any.
+func (c Calendar) Holiday(d Date) (string, bool) {
+	if name, ok := c.Holidays[d]; ok {
+		return name, true
+	}
+	name, ok := c.ruleHolidays(d.Year())[d]
+	return name, ok
+}
+
+// ruleHolidays computes the holidays of the year given by the rules of the
+// holiday calendar.
+func (c Calendar) ruleHolidays(year int) map[Date]string {
+	replaced := make(map[string]bool)
+	for d, name := range c.Holidays {
+		if d.Year() == year {
+			replaced[name] = true
+		}
+	}
+
+	calendar := holidayCalendars[c.HolidayCalendar]
+	holidays := make(map[Date]string, len(calendar.rules))
+	taken := func(d Date) bool {
+		_, stored := c.Holidays[d]
+		_, computed := holidays[d]
+		return stored || computed
+	}
+	var substituted []holidayRule
+	for _, rule := range calendar.rules {
+		if replaced[rule.name] {
+			continue
+		}
+		d := rule.date(year)
+		if calendar.substitute && isWeekend(d) {
+			substituted = append(substituted, rule)
+			continue
+		}
+		holidays[d] = rule.name
+	}
+	// The holidays are substituted once all the weekday ones are known, so
+	// the Christmas Day falling on Sunday moves past the Boxing Day.
+	for _, rule := range substituted {
+		d := rule.date(year)
+		for isWeekend(d) || taken(d) {
+			d = d.AddDays(1)
+		}
+		holidays[d] = rule.name
+	}
+	return holidays
+}
+
+// NextBusinessDay returns the day itself if it is a business day, otherwise
+// the first business day following it.
+func (c Calendar) NextBusinessDay(d Date) Date {
+	for !c.IsBusinessDay(d) {
+		d = d.AddDays(1)
+	}
+	return d
+}
+
+// CutOffAt returns the cut-off time of the day in the time zone of the
+// calendar. It is a wall clock time, so it does not move on the days the
+// daylight saving time changes.
+func (c Calendar) CutOffAt(d Date) time.Time {
+	year, month, day := d.Date()
+	hour, minute := int(c.CutOff/time.Hour), int(c.CutOff%time.Hour/time.Minute)
+	return time.Date(year, month, day, hour, minute, 0, 0, c.Location)
+}
+
+// ValueDate returns the business day a payment received at the given time is
+// executed on. Without the requested execution date the payment is executed
+// as soon as possible.
+func (c Calendar) ValueDate(requested *Date, received time.Time) Date {
+	local := received.In(c.Location)
+	earliest := DateOf(local)
+	if !local.Before(c.CutOffAt(earliest)) {
+		earliest = earliest.AddDays(1)
+	}
+	if requested != nil && earliest.Before(*requested) {
+		earliest = *requested
+	}
+	return c.NextBusinessDay(earliest)
+}
+
+// BusinessDays lists the business days within the range, both bounds included.
+func (c Calendar) BusinessDays(from, to Date) []BusinessDay {
+	days := []BusinessDay{}
+	for d := from; !to.Before(d); d = d.AddDays(1) {
+		if c.IsBusinessDay(d) {
+			days = append(days, BusinessDay{Date: d, CutOff: c.CutOffAt(d)})
+		}
+	}
+	return days
+}
+
+// BusinessDay is a day on which the payments of a scheme are executed, the
+// ones received until its cut-off time are executed on the day.
+type BusinessDay struct {
+	Date   Date      `json:"date"`
+	CutOff time.Time `json:"cut_off"`
+}
+
+func (d BusinessDay) GetID() string   { return d.Date.String() }
+func (d BusinessDay) GetName() string { return "business-days" }
+
+type BusinessDaySearchRequest struct {
+	resource.SearchFilter
+}
+
+// DateRange returns the requested range, the unset bounds are left to the
+// caller.
+func (r BusinessDaySearchRequest) DateRange() DateRange {
+	var dateRange DateRange
+	if r.SearchFilter == nil {
+		return dateRange
+	}
+	bound := func(op resource.FilterOperator) *Date {
+		d, ok := r.SearchFilter[resource.FilterKey("date", op)].(Date)
+		if !ok {
+			return nil
+		}
+		return &d
+	}
+	dateRange.Gte = bound(resource.FilterOperatorGte)
+	dateRange.Lte = bound(resource.FilterOperatorLte)
+	return dateRange
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testCalendar(t *testing.T) *Calendar {
+	t.Helper()
+
+	c, err := NewCalendar("SEPA", "TARGET2", "Europe/Berlin", "16:00")
+	if err != nil {
+		t.Fatalf("unable to create calendar: %v", err)
+	}
+	return c
+}
+
+func TestNewCalendar(t *testing.T) {
+	testCases := []struct {
+		name     string
+		timeZone string
+		cutOff   string
+		valid    bool
+	}{
+		{name: "Valid calendar", timeZone: "Europe/London", cutOff: "14:30", valid: true},
+		{name: "Unknown time zone", timeZone: "Europe/Atlantis", cutOff: "14:30"},
+		{name: "Invalid cut-off", timeZone: "Europe/London", cutOff: "2pm"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewCalendar("SWIFT", "GB", tc.timeZone, tc.cutOff)
+			if !tc.valid {
+				if err == nil {
+					t.Fatalf("expected error, have <nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, have := 14*time.Hour+30*time.Minute, c.CutOff; want != have {
+				t.Fatalf("invalid cut-off: want %v, have %v", want, have)
+			}
+		})
+	}
+}
+
+func TestCalendar_Holiday(t *testing.T) {
+	testCases := []struct {
+		name            string
+		holidayCalendar string
+		holidays        map[string]string
+		date            string
+		out             string
+	}{
+		{name: "Good Friday", holidayCalendar: "TARGET2", date: "2026-04-03", out: "Good Friday"},
+		{name: "Easter Monday", holidayCalendar: "TARGET2", date: "2026-04-06", out: "Easter Monday"},
+		{name: "Labour Day", holidayCalendar: "TARGET2", date: "2026-05-01", out: "Labour Day"},
+		{name: "Christmas Holiday", holidayCalendar: "TARGET2", date: "2026-12-26", out: "Christmas Holiday"},
+		{name: "Working day", holidayCalendar: "TARGET2", date: "2026-04-07"},
+		{name: "Early May bank holiday", holidayCalendar: "GB", date: "2026-05-04", out: "Early May bank holiday"},
+		{name: "Spring bank holiday", holidayCalendar: "GB", date: "2026-05-25", out: "Spring bank holiday"},
+		{name: "Summer bank holiday", holidayCalendar: "GB", date: "2026-08-31", out: "Summer bank holiday"},
+		{name: "Boxing Day on Saturday", holidayCalendar: "GB", date: "2026-12-28", out: "Boxing Day"},
+		{name: "Christmas Day on Saturday", holidayCalendar: "GB", date: "2027-12-27", out: "Christmas Day"},
+		{name: "Boxing Day after Christmas Day on Saturday", holidayCalendar: "GB", date: "2027-12-28", out: "Boxing Day"},
+		{name: "Christmas Day on Sunday", holidayCalendar: "GB", date: "2022-12-27", out: "Christmas Day"},
+		{name: "New Year's Day on Saturday", holidayCalendar: "GB", date: "2028-01-03", out: "New Year's Day"},
+		{
+			name:            "Moved holiday",
+			holidayCalendar: "GB",
+			holidays:        map[string]string{"2020-05-08": "Early May bank holiday"},
+			date:            "2020-05-08",
+			out:             "Early May bank holiday",
+		},
+		{
+			name:            "Day of moved holiday",
+			holidayCalendar: "GB",
+			holidays:        map[string]string{"2020-05-08": "Early May bank holiday"},
+			date:            "2020-05-04",
+		},
+		{
+			name:            "Additional holiday",
+			holidayCalendar: "GB",
+			holidays:        map[string]string{"2023-05-08": "Coronation of King Charles III"},
+			date:            "2023-05-08",
+			out:             "Coronation of King Charles III",
+		},
+		{name: "Unknown holiday calendar", holidayCalendar: "XX", date: "2026-12-25"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewCalendar("SWIFT", tc.holidayCalendar, "UTC", "14:00")
+			if err != nil {
+				t.Fatalf("unable to create calendar: %v", err)
+			}
+			for date, name := range tc.holidays {
+				c.Holidays[MustDateFrom(date)] = name
+			}
+
+			name, ok := c.Holiday(MustDateFrom(tc.date))
+			if want, have := tc.out != "", ok; want != have {
+				t.Fatalf("invalid holiday: want %v, have %v", want, have)
+			}
+			if want, have := tc.out, name; want != have {
+				t.Fatalf("invalid holiday name: want %q, have %q", want, have)
+			}
+		})
+	}
+}
+
+func TestCalendar_ValueDate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		requested string
+		received  string
+		out       string
+	}{
+		{name: "Before cut-off", received: "2019-06-12T13:59:59Z", out: "2019-06-12"},
+		{name: "At cut-off", received: "2019-06-12T14:00:00Z", out: "2019-06-13"},
+		{name: "After cut-off on Friday", received: "2019-06-14T15:00:00Z", out: "2019-06-17"},
+		{name: "Saturday", received: "2019-06-15T08:00:00Z", out: "2019-06-17"},
+		{name: "Local day differs from UTC", received: "2019-06-11T22:30:00Z", out: "2019-06-12"},
+		{name: "Before cut-off on Thursday before Easter", received: "2019-04-18T10:00:00Z", out: "2019-04-18"},
+		{name: "After cut-off on Thursday before Easter", received: "2019-04-18T15:00:00Z", out: "2019-04-23"},
+		{name: "Requested business day", requested: "2019-06-20", received: "2019-06-12T15:00:00Z", out: "2019-06-20"},
+		{name: "Requested holiday", requested: "2019-04-22", received: "2019-04-01T10:00:00Z", out: "2019-04-23"},
+		{name: "Requested today after cut-off", requested: "2019-06-12", received: "2019-06-12T15:00:00Z", out: "2019-06-13"},
+	}
+
+	c := testCalendar(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			received, err := time.Parse(time.RFC3339, tc.received)
+			if err != nil {
+				t.Fatalf("unable to parse time: %v", err)
+			}
+			var requested *Date
+			if tc.requested != "" {
+				d := MustDateFrom(tc.requested)
+				requested = &d
+			}
+			if want, have := tc.out, c.ValueDate(requested, received).String(); want != have {
+				t.Fatalf("invalid value date: want %s, have %s", want, have)
+			}
+		})
+	}
+}
+
+func TestCalendar_BusinessDays(t *testing.T) {
+	c := testCalendar(t)
+
+	var have []string
+	for _, d := range c.BusinessDays(MustDateFrom("2019-04-18"), MustDateFrom("2019-04-24")) {
+		have = append(have, d.Date.String()+" "+d.CutOff.UTC().Format(time.RFC3339))
+	}
+	want := []string{
+		"2019-04-18 2019-04-18T14:00:00Z",
+		"2019-04-23 2019-04-23T14:00:00Z",
+		"2019-04-24 2019-04-24T14:00:00Z",
+	}
+	if !cmp.Equal(want, have) {
+		t.Fatalf("unexpected business days: %v", cmp.Diff(want, have))
+	}
+}
+
+func TestCalendar_CutOffAt(t *testing.T) {
+	c := testCalendar(t)
+
+	// The daylight saving time starts on 2019-03-31 in Europe.
+	for date, cutOff := range map[string]string{
+		"2019-03-29": "2019-03-29T15:00:00Z",
+		"2019-03-31": "2019-03-31T14:00:00Z",
+	} {
+		if want, have := cutOff, c.CutOffAt(MustDateFrom(date)).UTC().Format(time.RFC3339); want != have {
+			t.Errorf("invalid cut-off of %s: want %s, have %s", date, want, have)
+		}
+	}
+}
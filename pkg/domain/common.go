@@ -3,6 +3,9 @@ package domain
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/shopspring/decimal"
@@ -12,6 +15,10 @@ import (
 
 type ID uuid.UUID
 
+func NewID() ID {
+	return ID(uuid.Must(uuid.NewV4()))
+}
+
 func MustIDFrom(s string) ID {
 	id, err := IDFrom(s)
 	if err != nil {
@@ -91,15 +98,55 @@ func DecimalFrom(s string) (Decimal, error) {
 			err.Error(),
 		)
 	}
-	return Decimal(d), nil
+	return keepScale(d, s), nil
+}
+
+// keepScale restores the scale of the parsed decimal, which the decimal library
+// drops along with the trailing zeros, e.g. 100.00 is not turned into 100.
+func keepScale(d decimal.Decimal, s string) Decimal {
+	s = strings.Trim(s, `"`)
+	if i := strings.IndexByte(s, '.'); i >= 0 && !strings.ContainsAny(s, "Ee") {
+		d = d.Round(int32(len(s) - i - 1))
+	}
+	return Decimal(d)
 }
 
 func (d Decimal) String() string {
 	return decimal.Decimal(d).String()
 }
 
+func (d Decimal) Cmp(o Decimal) int {
+	return decimal.Decimal(d).Cmp(decimal.Decimal(o))
+}
+
+func (d Decimal) Sign() int {
+	return decimal.Decimal(d).Sign()
+}
+
+// Scale returns the number of fraction digits the decimal is represented
+// with, including the trailing zeros.
+func (d Decimal) Scale() int32 {
+	if exp := decimal.Decimal(d).Exponent(); exp < 0 {
+		return -exp
+	}
+	return 0
+}
+
+// Round rounds the decimal to the given number of fraction digits and
+// represents it with exactly that scale.
+func (d Decimal) Round(places int32) Decimal {
+	return Decimal(decimal.Decimal(d).Round(places))
+}
+
+// canonical formats the decimal keeping its scale, e.g. 100.00 stays 100.00.
+func (d Decimal) canonical() string {
+	return decimal.Decimal(d).StringFixed(d.Scale())
+}
+
+// MarshalJSON encodes the decimal as a string, so no precision gets lost in
+// clients which parse JSON numbers as floats.
 func (d Decimal) MarshalJSON() ([]byte, error) {
-	return json.Marshal(decimal.Decimal(d))
+	return json.Marshal(d.canonical())
 }
 
 func (d *Decimal) UnmarshalJSON(data []byte) error {
@@ -112,12 +159,12 @@ func (d *Decimal) UnmarshalJSON(data []byte) error {
 			err.Error(),
 		)
 	}
-	*d = Decimal(dec)
+	*d = keepScale(dec, string(data))
 	return nil
 }
 
 func (d Decimal) Value() (driver.Value, error) {
-	return decimal.Decimal(d).Value()
+	return d.canonical(), nil
 }
 func (d *Decimal) Scan(src interface{}) error {
 	dec := new(decimal.Decimal)
@@ -125,10 +172,170 @@ func (d *Decimal) Scan(src interface{}) error {
 	if err != nil {
 		return err
 	}
-	*d = Decimal(*dec)
+	switch src := src.(type) {
+	case string:
+		*d = keepScale(*dec, src)
+	case []byte:
+		*d = keepScale(*dec, string(src))
+	default:
+		*d = Decimal(*dec)
+	}
 	return nil
 }
 
+// DecimalRange bounds decimal values, only the set bounds are applied.
+type DecimalRange struct {
+	Gt, Gte, Lt, Lte *Decimal
+}
+
+func (r DecimalRange) IsEmpty() bool {
+	return r.Gt == nil && r.Gte == nil && r.Lt == nil && r.Lte == nil
+}
+
+func (r DecimalRange) Contains(d Decimal) bool {
+	switch {
+	case r.Gt != nil && d.Cmp(*r.Gt) <= 0:
+		return false
+	case r.Gte != nil && d.Cmp(*r.Gte) < 0:
+		return false
+	case r.Lt != nil && d.Cmp(*r.Lt) >= 0:
+		return false
+	case r.Lte != nil && d.Cmp(*r.Lte) > 0:
+		return false
+	}
+	return true
+}
+
+// TimeRange bounds points in time, only the set bounds are applied.
+type TimeRange struct {
+	Gt, Gte, Lt, Lte *time.Time
+}
+
+func (r TimeRange) IsEmpty() bool {
+	return r.Gt == nil && r.Gte == nil && r.Lt == nil && r.Lte == nil
+}
+
+func (r TimeRange) Contains(t time.Time) bool {
+	switch {
+	case r.Gt != nil && !t.After(*r.Gt):
+		return false
+	case r.Gte != nil && t.Before(*r.Gte):
+		return false
+	case r.Lt != nil && !t.Before(*r.Lt):
+		return false
+	case r.Lte != nil && t.After(*r.Lte):
+		return false
+	}
+	return true
+}
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar day without a time of day, encoded as YYYY-MM-DD. It is
+// represented by the midnight of the day in UTC.
+type Date struct {
+	time.Time
+}
+
+// DateOf returns the day of the time in its own location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func MustDateFrom(s string) Date {
+	d, err := DateFrom(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func DateFrom(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid format of date",
+			fmt.Sprintf("%q is not a date in the YYYY-MM-DD format", s),
+		)
+	}
+	return Date{t}, nil
+}
+
+func (d Date) String() string {
+	return d.Format(dateLayout)
+}
+
+func (d Date) Before(o Date) bool {
+	return d.Time.Before(o.Time)
+}
+
+// AddDays returns the day the given number of days later.
+func (d Date) AddDays(n int) Date {
+	return Date{d.AddDate(0, 0, n)}
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid format of date",
+			err.Error(),
+		)
+	}
+	*d, err = DateFrom(s)
+	return err
+}
+
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Date) Scan(src interface{}) error {
+	var err error
+	switch src := src.(type) {
+	case time.Time:
+		*d = DateOf(src)
+	case string:
+		*d, err = DateFrom(src)
+	case []byte:
+		*d, err = DateFrom(string(src))
+	default:
+		err = fmt.Errorf("unable to scan %T into date", src)
+	}
+	return err
+}
+
+// DateRange bounds calendar days, only the set bounds are applied.
+type DateRange struct {
+	Gt, Gte, Lt, Lte *Date
+}
+
+func (r DateRange) IsEmpty() bool {
+	return r.Gt == nil && r.Gte == nil && r.Lt == nil && r.Lte == nil
+}
+
+func (r DateRange) Contains(d Date) bool {
+	switch {
+	case r.Gt != nil && !r.Gt.Before(d):
+		return false
+	case r.Gte != nil && d.Before(*r.Gte):
+		return false
+	case r.Lt != nil && !d.Before(*r.Lt):
+		return false
+	case r.Lte != nil && r.Lte.Before(d):
+		return false
+	}
+	return true
+}
+
 type Address struct {
 	Line1       string  `json:"line1"`
 	Line2       *string `json:"line2,omitempty"`
@@ -158,5 +365,3 @@ func (o *BaseObject) SetID(s string) error {
 	o.ID = id
 	return nil
 }
-
-type EnumName string
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 
@@ -216,6 +217,206 @@ func TestID_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestDecimal_MarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   Decimal
+		out  string
+	}{
+		{name: "Integer", in: MustDecimalFrom("100"), out: `"100"`},
+		{name: "Trailing zeros", in: MustDecimalFrom("100.00"), out: `"100.00"`},
+		{name: "Rounded", in: MustDecimalFrom("100").Round(2), out: `"100.00"`},
+		{name: "Large", in: MustDecimalFrom("12345678901234567890.12"), out: `"12345678901234567890.12"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := tc.in.MarshalJSON()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, have := tc.out, string(b); want != have {
+				t.Fatalf("invalid JSON marshaled decimal: want %v, have %v", want, have)
+			}
+		})
+	}
+}
+
+func TestDecimal_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name  string
+		in    string
+		scale int32
+	}{
+		{name: "String", in: `"100.10"`, scale: 2},
+		{name: "Number", in: `100.100`, scale: 3},
+		{name: "Integer", in: `"100"`, scale: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var d Decimal
+			err := d.UnmarshalJSON([]byte(tc.in))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, have := tc.scale, d.Scale(); want != have {
+				t.Fatalf("invalid scale: want %d, have %d", want, have)
+			}
+		})
+	}
+}
+
+func TestDecimal_Scan(t *testing.T) {
+	for _, src := range []interface{}{"2500.00", []byte("2500.00")} {
+		var d Decimal
+		err := d.Scan(src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want, have := "2500.00", d.canonical(); want != have {
+			t.Fatalf("invalid scanned decimal: want %s, have %s", want, have)
+		}
+	}
+}
+
+func TestDecimalRange_Contains(t *testing.T) {
+	decimal := func(s string) *Decimal {
+		d := MustDecimalFrom(s)
+		return &d
+	}
+
+	testCases := []struct {
+		name string
+		in   DecimalRange
+		out  bool
+	}{
+		{name: "No bounds", in: DecimalRange{}, out: true},
+		{name: "Greater than", in: DecimalRange{Gt: decimal("99.99")}, out: true},
+		{name: "Not greater than", in: DecimalRange{Gt: decimal("100")}, out: false},
+		{name: "Greater than or equal", in: DecimalRange{Gte: decimal("100.000")}, out: true},
+		{name: "Less than", in: DecimalRange{Lt: decimal("100.01")}, out: true},
+		{name: "Not less than", in: DecimalRange{Lt: decimal("100")}, out: false},
+		{name: "Less than or equal", in: DecimalRange{Lte: decimal("100")}, out: true},
+		{name: "Within bounds", in: DecimalRange{Gte: decimal("50"), Lt: decimal("150")}, out: true},
+		{name: "Out of bounds", in: DecimalRange{Gte: decimal("150"), Lt: decimal("250")}, out: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if want, have := tc.out, tc.in.Contains(MustDecimalFrom("100.00")); want != have {
+				t.Fatalf("unexpected result: want %t, have %t", want, have)
+			}
+		})
+	}
+}
+
+func TestTimeRange_Contains(t *testing.T) {
+	at := func(s string) *time.Time {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic(err)
+		}
+		return &t
+	}
+
+	testCases := []struct {
+		name string
+		in   TimeRange
+		out  bool
+	}{
+		{name: "No bounds", in: TimeRange{}, out: true},
+		{name: "Greater than", in: TimeRange{Gt: at("2019-06-12T11:59:59Z")}, out: true},
+		{name: "Not greater than", in: TimeRange{Gt: at("2019-06-12T12:00:00Z")}, out: false},
+		{name: "Greater than or equal", in: TimeRange{Gte: at("2019-06-12T14:00:00+02:00")}, out: true},
+		{name: "Not less than", in: TimeRange{Lt: at("2019-06-12T12:00:00Z")}, out: false},
+		{name: "Less than or equal", in: TimeRange{Lte: at("2019-06-12T12:00:00Z")}, out: true},
+		{name: "Out of bounds", in: TimeRange{Gte: at("2019-06-11T00:00:00Z"), Lt: at("2019-06-12T00:00:00Z")}, out: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if want, have := tc.out, tc.in.Contains(*at("2019-06-12T12:00:00Z")); want != have {
+				t.Fatalf("unexpected result: want %t, have %t", want, have)
+			}
+		})
+	}
+}
+
+func TestDate_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name  string
+		in    string
+		out   string
+		valid bool
+	}{
+		{name: "Date", in: `"2019-06-12"`, out: "2019-06-12", valid: true},
+		{name: "Date with time", in: `"2019-06-12T12:00:00Z"`},
+		{name: "Invalid day", in: `"2019-02-30"`},
+		{name: "Number", in: `20190612`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var d Date
+			err := d.UnmarshalJSON([]byte(tc.in))
+			if !tc.valid {
+				assertInvalidArgumentError(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, have := tc.out, d.String(); want != have {
+				t.Fatalf("invalid date: want %s, have %s", want, have)
+			}
+		})
+	}
+}
+
+func TestDate_Scan(t *testing.T) {
+	local := time.FixedZone("UTC+2", 2*60*60)
+	for _, src := range []interface{}{"2019-06-12", []byte("2019-06-12"), time.Date(2019, 6, 12, 0, 0, 0, 0, local)} {
+		var d Date
+		err := d.Scan(src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want, have := MustDateFrom("2019-06-12"), d; want != have {
+			t.Fatalf("invalid scanned date: want %s, have %s", want, have)
+		}
+	}
+}
+
+func TestDateRange_Contains(t *testing.T) {
+	date := func(s string) *Date {
+		d := MustDateFrom(s)
+		return &d
+	}
+
+	testCases := []struct {
+		name string
+		in   DateRange
+		out  bool
+	}{
+		{name: "No bounds", in: DateRange{}, out: true},
+		{name: "Greater than", in: DateRange{Gt: date("2019-06-11")}, out: true},
+		{name: "Not greater than", in: DateRange{Gt: date("2019-06-12")}, out: false},
+		{name: "Greater than or equal", in: DateRange{Gte: date("2019-06-12")}, out: true},
+		{name: "Not less than", in: DateRange{Lt: date("2019-06-12")}, out: false},
+		{name: "Less than or equal", in: DateRange{Lte: date("2019-06-12")}, out: true},
+		{name: "Within bounds", in: DateRange{Gte: date("2019-06-01"), Lt: date("2019-07-01")}, out: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if want, have := tc.out, tc.in.Contains(MustDateFrom("2019-06-12")); want != have {
+				t.Fatalf("unexpected result: want %t, have %t", want, have)
+			}
+		})
+	}
+}
+
 func toID(data ...byte) ID {
 	if len(data) != uuid.Size {
 		panic("invalid uuid length")
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/manyminds/api2go/jsonapi"
+
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+)
+
+type EnumName string
+
+// Enum is an entry of the reference data the payments are validated against,
+// it is identified by its code. Entries are never deleted as the payments keep
+// referring to them, they are deactivated instead so no new payments can use them.
+type Enum interface {
+	jsonapi.MarshalIdentifier
+	jsonapi.EntityNamer
+	IsActive() bool
+	SetActive(bool)
+	Validate() error
+}
+
+// MaxMinorUnits is the highest number of minor units defined by ISO 4217.
+const MaxMinorUnits = 4
+
+var (
+	schemeCodePattern   = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,34}$`)
+	countryCodePattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// Scheme is a payment scheme, e.g. SEPA.
+type Scheme struct {
+	Code   string `json:"-"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+func (s Scheme) GetID() string          { return s.Code }
+func (s *Scheme) SetID(id string) error { s.Code = id; return nil }
+func (s Scheme) GetName() string        { return "schemes" }
+func (s Scheme) IsActive() bool         { return s.Active }
+func (s *Scheme) SetActive(active bool) { s.Active = active }
+
+func (s Scheme) Validate() error {
+	return validateEnum(schemeCodePattern, s.Code, s.Name).Err()
+}
+
+// Country is an ISO 3166 country.
+type Country struct {
+	Code   string `json:"-"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+func (c Country) GetID() string          { return c.Code }
+func (c *Country) SetID(id string) error { c.Code = id; return nil }
+func (c Country) GetName() string        { return "countries" }
+func (c Country) IsActive() bool         { return c.Active }
+func (c *Country) SetActive(active bool) { c.Active = active }
+
+func (c Country) Validate() error {
+	return validateEnum(countryCodePattern, c.Code, c.Name).Err()
+}
+
+// Currency is an ISO 4217 currency. The minor units give the number of
+// fraction digits of its amounts, the maximum amount is unlimited if not set.
+type Currency struct {
+	Code       string   `json:"-"`
+	Name       string   `json:"name"`
+	MinorUnits int32    `json:"minor_units"`
+	MaxAmount  *Decimal `json:"max_amount,omitempty"`
+	Active     bool     `json:"active"`
+}
+
+func (c Currency) GetID() string          { return c.Code }
+func (c *Currency) SetID(id string) error { c.Code = id; return nil }
+func (c Currency) GetName() string        { return "currencies" }
+func (c Currency) IsActive() bool         { return c.Active }
+func (c *Currency) SetActive(active bool) { c.Active = active }
+
+func (c Currency) Validate() error {
+	violations := validateEnum(currencyCodePattern, c.Code, c.Name)
+	if c.MinorUnits < 0 || c.MinorUnits > MaxMinorUnits {
+		violations = append(violations, EnumViolation(
+			"/data/attributes/minor_units",
+			fmt.Sprintf("minor units must be between 0 and %d", MaxMinorUnits),
+		))
+	}
+	if c.MaxAmount != nil {
+		switch {
+		case c.MaxAmount.Sign() <= 0:
+			violations = append(violations, EnumViolation("/data/attributes/max_amount", "maximum amount must be positive"))
+		case c.MaxAmount.Round(c.MinorUnits).Cmp(*c.MaxAmount) != 0:
+			violations = append(violations, EnumViolation(
+				"/data/attributes/max_amount",
+				fmt.Sprintf("maximum amount must have at most %d fraction digits", c.MinorUnits),
+			))
+		}
+	}
+	return violations.Err()
+}
+
+// ValidateAmount checks the amount to be positive, to fit into the minor
+// units and not to exceed the maximum amount of the currency.
+func (c Currency) ValidateAmount(amount Decimal) error {
+	switch {
+	case amount.Sign() <= 0:
+		return errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid amount",
+			"amount must be positive",
+		)
+	case amount.Round(c.MinorUnits).Cmp(amount) != 0:
+		return errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid amount",
+			fmt.Sprintf("currency %s allows at most %d fraction digits", c.Code, c.MinorUnits),
+		)
+	case c.MaxAmount != nil && amount.Cmp(*c.MaxAmount) > 0:
+		return errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid amount",
+			fmt.Sprintf("amount must not exceed %s %s", c.MaxAmount, c.Code),
+		)
+	}
+	return nil
+}
+
+func validateEnum(codePattern *regexp.Regexp, code, name string) errors.Multi {
+	var violations errors.Multi
+	if !codePattern.MatchString(code) {
+		violations = append(violations, EnumViolation("/data/id", fmt.Sprintf("code %q does not match %s", code, codePattern)))
+	}
+	if name == "" {
+		violations = append(violations, EnumViolation("/data/attributes/name", "name must not be empty"))
+	}
+	return violations
+}
+
+// EnumViolation returns an invalid argument error caused by the enumeration
+// value the pointer refers to.
+func EnumViolation(pointer, detail string) errors.Error {
+	return errors.Generic(
+		errors.ErrCodeGenericInvalidArgument,
+		"invalid enum",
+		detail,
+		map[string]interface{}{errors.ExtraPointer: pointer},
+	)
+}
+
+type EnumSearchRequest struct {
+	resource.SearchFilter
+}
+
+func (r EnumSearchRequest) Codes() []string {
+	if r.SearchFilter == nil {
+		return nil
+	}
+	codes, ok := r.SearchFilter["code"].([]string)
+	if !ok {
+		return nil
+	}
+	return codes
+}
+
+func (r EnumSearchRequest) NamePrefix() string {
+	if r.SearchFilter == nil {
+		return ""
+	}
+	prefix, _ := r.SearchFilter[resource.FilterKey("name", resource.FilterOperatorPrefix)].(string)
+	return prefix
+}
+
+// Active returns the requested activity of the entries, ok is false if any
+// entries are requested.
+func (r EnumSearchRequest) Active() (active bool, ok bool) {
+	if r.SearchFilter == nil {
+		return false, false
+	}
+	active, ok = r.SearchFilter["active"].(bool)
+	return active, ok
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "testing"
+
+func TestCurrency_ValidateAmount(t *testing.T) {
+	max := MustDecimalFrom("1000")
+	eur := Currency{Code: "EUR", MinorUnits: 2, MaxAmount: &max}
+	huf := Currency{Code: "HUF"}
+
+	testCases := []struct {
+		name     string
+		currency Currency
+		in       string
+		valid    bool
+	}{
+		{name: "Valid amount", currency: eur, in: "100.10", valid: true},
+		{name: "Insignificant fraction digits", currency: eur, in: "100.100", valid: true},
+		{name: "Maximum amount", currency: eur, in: "1000", valid: true},
+		{name: "Zero amount", currency: eur, in: "0"},
+		{name: "Negative amount", currency: eur, in: "-1.00"},
+		{name: "Too many fraction digits", currency: eur, in: "100.123"},
+		{name: "Above maximum amount", currency: eur, in: "1000.01"},
+		{name: "Currency without minor units", currency: huf, in: "100", valid: true},
+		{name: "Fraction of currency without minor units", currency: huf, in: "100.5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.currency.ValidateAmount(MustDecimalFrom(tc.in))
+			if tc.valid {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			assertInvalidArgumentError(t, err)
+		})
+	}
+}
+
+func TestEnum_Validate(t *testing.T) {
+	max := MustDecimalFrom("1000.00")
+	negative := MustDecimalFrom("-1")
+	fractional := MustDecimalFrom("0.5")
+
+	testCases := []struct {
+		name  string
+		in    Enum
+		valid bool
+	}{
+		{name: "Valid scheme", in: &Scheme{Code: "FPS", Name: "Faster Payments"}, valid: true},
+		{name: "Scheme with lower case code", in: &Scheme{Code: "fps", Name: "Faster Payments"}},
+		{name: "Scheme without name", in: &Scheme{Code: "FPS"}},
+		{name: "Valid country", in: &Country{Code: "US", Name: "United States"}, valid: true},
+		{name: "Country with alpha-3 code", in: &Country{Code: "USA", Name: "United States"}},
+		{name: "Valid currency", in: &Currency{Code: "USD", Name: "US dollar", MinorUnits: 2, MaxAmount: &max}, valid: true},
+		{name: "Currency with invalid code", in: &Currency{Code: "US", Name: "US dollar"}},
+		{name: "Currency with too many minor units", in: &Currency{Code: "USD", Name: "US dollar", MinorUnits: 5}},
+		{name: "Currency with negative minor units", in: &Currency{Code: "USD", Name: "US dollar", MinorUnits: -1}},
+		{name: "Currency with negative maximum amount", in: &Currency{Code: "USD", Name: "US dollar", MaxAmount: &negative}},
+		{name: "Currency with fractional maximum amount", in: &Currency{Code: "HUF", Name: "Hungarian forint", MaxAmount: &fractional}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.in.Validate()
+			if tc.valid {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			assertInvalidArgumentError(t, err)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+)
+
+type PaymentEventType string
+
+const (
+	PaymentEventCreated       PaymentEventType = "payment.created"
+	PaymentEventUpdated       PaymentEventType = "payment.updated"
+	PaymentEventStatusChanged PaymentEventType = "payment.status_changed"
+	PaymentEventDeleted       PaymentEventType = "payment.deleted"
+	PaymentEventRestored      PaymentEventType = "payment.restored"
+)
+
+// PaymentEvent announces a single change of a payment to the parties outside
+// of the service. Events are published at least once, so the consumers should
+// use the event id to recognize the repeated ones.
+type PaymentEvent struct {
+	ID             ID               `json:"id"`
+	Type           PaymentEventType `json:"type"`
+	PaymentID      ID               `json:"payment_id"`
+	Actor          string           `json:"actor"`
+	OccurredAt     time.Time        `json:"occurred_at"`
+	PreviousStatus PaymentStatus    `json:"previous_status,omitempty"`
+	Payment        *PaymentSnapshot `json:"payment"`
+
+	// Sequence numbers the events in the order they were recorded, it is
+	// assigned by the store. Position numbers the events in the order they
+	// were committed, it is assigned to the committed events for the feed.
+	Sequence    int64                   `json:"-"`
+	Position    int64                   `json:"-"`
+	Publication PaymentEventPublication `json:"-"`
+}
+
+type PaymentEventPublicationStatus string
+
+// The events are pending until published, the relay never gives up on them.
+const (
+	PaymentEventPublicationPending   PaymentEventPublicationStatus = "PENDING"
+	PaymentEventPublicationPublished PaymentEventPublicationStatus = "PUBLISHED"
+)
+
+// PaymentEventPublication tracks the attempts of the relay to publish
+// an event.
+type PaymentEventPublication struct {
+	Status        PaymentEventPublicationStatus `json:"status"`
+	Attempts      int                           `json:"attempts"`
+	NextAttemptAt *time.Time                    `json:"next_attempt_at,omitempty"`
+	LastError     string                        `json:"last_error,omitempty"`
+	PublishedAt   *time.Time                    `json:"published_at,omitempty"`
+}
+
+// NewPaymentEvent returns the event announcing the change recorded by the
+// history entry. An update changing the status of the payment is announced
+// as the status change.
+func NewPaymentEvent(entry *PaymentHistory) *PaymentEvent {
+	event := &PaymentEvent{
+		ID:         NewID(),
+		PaymentID:  entry.PaymentID,
+		Actor:      entry.Actor,
+		OccurredAt: entry.Timestamp,
+		Payment:    entry.After,
+		Publication: PaymentEventPublication{
+			Status: PaymentEventPublicationPending,
+		},
+	}
+	switch entry.Operation {
+	case PaymentOperationCreate:
+		event.Type = PaymentEventCreated
+	case PaymentOperationUpdate:
+		event.Type = PaymentEventUpdated
+		if entry.Before.Status != entry.After.Status {
+			event.Type = PaymentEventStatusChanged
+			event.PreviousStatus = entry.Before.Status
+		}
+	case PaymentOperationDelete:
+		event.Type = PaymentEventDeleted
+	case PaymentOperationRestore:
+		event.Type = PaymentEventRestored
+	}
+	return event
+}
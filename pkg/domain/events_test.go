@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPaymentEvent(t *testing.T) {
+	id := MustIDFrom("276c8bbf-79ca-4ac2-b319-0f1c51463540")
+	draft := NewPaymentSnapshot(&Payment{BaseObject: BaseObject{ID: id}, Status: PaymentStatusDraft, Version: 1})
+	edited := NewPaymentSnapshot(&Payment{BaseObject: BaseObject{ID: id}, Status: PaymentStatusDraft, Version: 2})
+	submitted := NewPaymentSnapshot(&Payment{BaseObject: BaseObject{ID: id}, Status: PaymentStatusSubmitted, Version: 2})
+
+	testCases := []struct {
+		name           string
+		operation      PaymentOperation
+		before, after  *PaymentSnapshot
+		eventType      PaymentEventType
+		previousStatus PaymentStatus
+	}{
+		{
+			name:      "Create",
+			operation: PaymentOperationCreate,
+			after:     draft,
+			eventType: PaymentEventCreated,
+		},
+		{
+			name:      "Update",
+			operation: PaymentOperationUpdate,
+			before:    draft,
+			after:     edited,
+			eventType: PaymentEventUpdated,
+		},
+		{
+			name:           "Status change",
+			operation:      PaymentOperationUpdate,
+			before:         draft,
+			after:          submitted,
+			eventType:      PaymentEventStatusChanged,
+			previousStatus: PaymentStatusDraft,
+		},
+		{
+			name:      "Delete",
+			operation: PaymentOperationDelete,
+			before:    draft,
+			after:     edited,
+			eventType: PaymentEventDeleted,
+		},
+		{
+			name:      "Restore",
+			operation: PaymentOperationRestore,
+			before:    edited,
+			after:     edited,
+			eventType: PaymentEventRestored,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := &PaymentHistory{
+				PaymentID: id,
+				Operation: tc.operation,
+				Actor:     "jane.doe",
+				Timestamp: time.Date(2019, 6, 12, 12, 0, 0, 0, time.UTC),
+				Before:    tc.before,
+				After:     tc.after,
+			}
+			event := NewPaymentEvent(entry)
+			if want, have := tc.eventType, event.Type; want != have {
+				t.Errorf("unexpected event type: want %q, have %q", want, have)
+			}
+			if want, have := tc.previousStatus, event.PreviousStatus; want != have {
+				t.Errorf("unexpected previous status: want %q, have %q", want, have)
+			}
+			if event.PaymentID != id || event.Actor != entry.Actor || !event.OccurredAt.Equal(entry.Timestamp) || event.Payment != tc.after {
+				t.Errorf("unexpected event: %+v", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type PaymentOperation string
+
+const (
+	PaymentOperationCreate  PaymentOperation = "CREATE"
+	PaymentOperationUpdate  PaymentOperation = "UPDATE"
+	PaymentOperationDelete  PaymentOperation = "DELETE"
+	PaymentOperationRestore PaymentOperation = "RESTORE"
+)
+
+// PaymentHistory records a single change of a payment. History entries are
+// never modified nor deleted, not even along with the payment itself.
+type PaymentHistory struct {
+	BaseObject
+
+	PaymentID ID               `json:"payment_id"`
+	Operation PaymentOperation `json:"operation"`
+	Actor     string           `json:"actor"`
+	RequestID string           `json:"request_id,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+	Before    *PaymentSnapshot `json:"before,omitempty"`
+	After     *PaymentSnapshot `json:"after,omitempty"`
+}
+
+func (h PaymentHistory) GetName() string {
+	return "payment_history"
+}
+
+// PaymentSnapshot is the complete state of a payment, including the fields
+// which are not part of the payment attributes.
+type PaymentSnapshot struct {
+	ID        ID         `json:"id"`
+	Version   uint       `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	*Payment
+}
+
+func NewPaymentSnapshot(payment *Payment) *PaymentSnapshot {
+	p := *payment
+	return &PaymentSnapshot{ID: p.ID, Version: p.Version, DeletedAt: p.DeletedAt, Payment: &p}
+}
+
+func (s PaymentSnapshot) ToPayment() *Payment {
+	p := new(Payment)
+	if s.Payment != nil {
+		*p = *s.Payment
+	}
+	p.ID = s.ID
+	p.Version = s.Version
+	p.DeletedAt = s.DeletedAt
+	return p
+}
+
+// UnmarshalJSON restores the identity of the embedded payment, as it is
+// only kept in the snapshot fields.
+func (s *PaymentSnapshot) UnmarshalJSON(data []byte) error {
+	type snapshot PaymentSnapshot
+	err := json.Unmarshal(data, (*snapshot)(s))
+	if err != nil {
+		return err
+	}
+	if s.Payment != nil {
+		s.Payment.ID = s.ID
+		s.Payment.Version = s.Version
+		s.Payment.DeletedAt = s.DeletedAt
+	}
+	return nil
+}
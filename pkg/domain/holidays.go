@@ -0,0 +1,99 @@
+package domain
+
+import "time"
+
+// holidayRule gives the date of a recurring holiday in the given year.
+type holidayRule struct {
+	name string
+	date func(year int) Date
+}
+
+// holidayRules are the recurring holidays of a holiday calendar, listed in the
+// order they occur within a year.
+type holidayRules struct {
+	rules []holidayRule
+	// substitute moves the holidays falling on a weekend to the next weekday
+	// which is not a holiday already, as the UK bank holidays do.
+	substitute bool
+}
+
+// holidayCalendars are the rules of the supported holiday calendars. The
+// holidays which can not be given by a rule, e.g. a moved bank holiday, are
+// maintained as the holidays of the calendar.
+var holidayCalendars = map[string]holidayRules{
+	"TARGET2": {
+		rules: []holidayRule{
+			{"New Year's Day", fixedDay(time.January, 1)},
+			{"Good Friday", easterDay(-2)},
+			{"Easter Monday", easterDay(1)},
+			{"Labour Day", fixedDay(time.May, 1)},
+			{"Christmas Day", fixedDay(time.December, 25)},
+			{"Christmas Holiday", fixedDay(time.December, 26)},
+		},
+	},
+	"GB": {
+		rules: []holidayRule{
+			{"New Year's Day", fixedDay(time.January, 1)},
+			{"Good Friday", easterDay(-2)},
+			{"Easter Monday", easterDay(1)},
+			{"Early May bank holiday", firstMonday(time.May)},
+			{"Spring bank holiday", lastMonday(time.May)},
+			{"Summer bank holiday", lastMonday(time.August)},
+			{"Christmas Day", fixedDay(time.December, 25)},
+			{"Boxing Day", fixedDay(time.December, 26)},
+		},
+		substitute: true,
+	},
+}
+
+func fixedDay(month time.Month, day int) func(int) Date {
+	return func(year int) Date {
+		return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+	}
+}
+
+// easterDay gives the day the given number of days from the Easter Sunday.
+func easterDay(days int) func(int) Date {
+	return func(year int) Date {
+		return easterSunday(year).AddDays(days)
+	}
+}
+
+func firstMonday(month time.Month) func(int) Date {
+	return func(year int) Date {
+		d := Date{time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)}
+		return d.AddDays(int(time.Monday-d.Weekday()+7) % 7)
+	}
+}
+
+func lastMonday(month time.Month) func(int) Date {
+	return func(year int) Date {
+		d := Date{time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)}
+		return d.AddDays(-(int(d.Weekday()-time.Monday+7) % 7))
+	}
+}
+
+// easterSunday computes the Easter Sunday of the Gregorian calendar by the
+// anonymous Gregorian algorithm.
+func easterSunday(year int) Date {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return Date{time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)}
+}
+
+func isWeekend(d Date) bool {
+	switch d.Weekday() {
+	case time.Saturday, time.Sunday:
+		return true
+	}
+	return false
+}
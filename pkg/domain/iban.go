@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+)
+
+// ibanLengths holds the IBAN lengths of the countries in the IBAN registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22,
+	"DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18, "FO": 18, "FR": 27,
+	"GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24,
+	"ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "SA": 24, "SC": 31,
+	"SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28, "TL": 23, "TN": 24,
+	"TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+var (
+	ibanPattern = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]+$`)
+	bicPattern  = regexp.MustCompile(`^[A-Z]{4}[A-Z]{2}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+)
+
+// ValidateIBAN checks the IBAN in its electronic format, i.e. upper case
+// without any spaces.
+func ValidateIBAN(iban string) error {
+	if !ibanPattern.MatchString(iban) {
+		return fmt.Errorf("%q is not an IBAN in electronic format", iban)
+	}
+	country := IBANCountryCode(iban)
+	length, ok := ibanLengths[country]
+	if !ok {
+		return fmt.Errorf("country %s does not use IBAN", country)
+	}
+	if len(iban) != length {
+		return fmt.Errorf("IBAN of country %s must have %d characters", country, length)
+	}
+	if !hasValidMod97Checksum(iban) {
+		return fmt.Errorf("IBAN %q has invalid check digits", iban)
+	}
+	return nil
+}
+
+// IBANCountryCode returns the country prefix of the IBAN.
+func IBANCountryCode(iban string) string {
+	if len(iban) < 2 {
+		return ""
+	}
+	return iban[:2]
+}
+
+// hasValidMod97Checksum verifies the ISO 7064 mod 97-10 check digits which
+// follow the two letter prefix, as used by the IBANs and creditor references.
+func hasValidMod97Checksum(code string) bool {
+	rearranged := code[4:] + code[:4]
+	var digits []byte
+	for _, c := range []byte(rearranged) {
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		default:
+			digits = append(digits, []byte(fmt.Sprintf("%d", c-'A'+10))...)
+		}
+	}
+	n, ok := new(big.Int).SetString(string(digits), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// ValidateBIC checks the ISO 9362 business identifier code, either the
+// 8 character one or the 11 character one including the branch code.
+func ValidateBIC(bic string) error {
+	if !bicPattern.MatchString(bic) {
+		return fmt.Errorf("%q is not a valid BIC", bic)
+	}
+	return nil
+}
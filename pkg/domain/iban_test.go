@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestValidateIBAN(t *testing.T) {
+	testCases := []struct {
+		name  string
+		in    string
+		valid bool
+	}{
+		{name: "German IBAN", in: "DE89370400440532013000", valid: true},
+		{name: "Slovak IBAN", in: "SK3112000000198742637541", valid: true},
+		{name: "Norwegian IBAN", in: "NO9386011117947", valid: true},
+		{name: "Malta IBAN", in: "MT84MALT011000012345MTLCAST001S", valid: true},
+		{name: "Invalid check digits", in: "DE88370400440532013000"},
+		{name: "Invalid length", in: "DE8937040044053201300"},
+		{name: "Unknown country", in: "US64SVBKUS6S3300958879"},
+		{name: "Lower case", in: "de89370400440532013000"},
+		{name: "Spaces", in: "DE89 3704 0044 0532 0130 00"},
+		{name: "Empty", in: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateIBAN(tc.in)
+			if want, have := tc.valid, err == nil; want != have {
+				t.Fatalf("unexpected validity of %q: want %t, have %t (%v)", tc.in, want, have, err)
+			}
+		})
+	}
+}
+
+func TestValidateBIC(t *testing.T) {
+	testCases := []struct {
+		name  string
+		in    string
+		valid bool
+	}{
+		{name: "Primary office", in: "DEUTDEFF", valid: true},
+		{name: "Branch office", in: "DEUTDEFF500", valid: true},
+		{name: "Invalid length", in: "DEUTDEFF5"},
+		{name: "Invalid country", in: "DEUT12FF"},
+		{name: "Lower case", in: "deutdeff"},
+		{name: "Empty", in: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateBIC(tc.in)
+			if want, have := tc.valid, err == nil; want != have {
+				t.Fatalf("unexpected validity of %q: want %t, have %t (%v)", tc.in, want, have, err)
+			}
+		})
+	}
+}
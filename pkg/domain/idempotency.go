@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+type IdempotencyKey struct {
+	Actor          string
+	Key            string
+	Fingerprint    string
+	ResourceID     ID
+	ResponseStatus int
+	ResponseBody   []byte
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+func (k IdempotencyKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdempotencyKey_IsExpired(t *testing.T) {
+	expiresAt := time.Date(2019, 6, 12, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		now     time.Time
+		expired bool
+	}{
+		{name: "Before expiration", now: expiresAt.Add(-time.Second)},
+		{name: "At expiration", now: expiresAt, expired: true},
+		{name: "After expiration", now: expiresAt.Add(time.Second), expired: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			k := IdempotencyKey{ExpiresAt: expiresAt}
+			if want, have := tc.expired, k.IsExpired(tc.now); want != have {
+				t.Fatalf("invalid expiration: want %v, have %v", want, have)
+			}
+		})
+	}
+}
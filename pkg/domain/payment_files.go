@@ -0,0 +1,79 @@
+package domain
+
+// PaymentImportMode decides what happens to the valid payments of a file
+// once some of its payments are rejected.
+type PaymentImportMode string
+
+const (
+	// PaymentImportAllOrNothing imports either all the payments of a file
+	// or none of them.
+	PaymentImportAllOrNothing PaymentImportMode = "all-or-nothing"
+	// PaymentImportPartial imports the valid payments of a file regardless
+	// of the rejected ones.
+	PaymentImportPartial PaymentImportMode = "partial"
+)
+
+func (m PaymentImportMode) IsValid() bool {
+	switch m {
+	case PaymentImportAllOrNothing, PaymentImportPartial:
+		return true
+	}
+	return false
+}
+
+// PaymentFile is a bulk of payments initiated at once, e.g. by an ISO 20022
+// customer credit transfer initiation.
+type PaymentFile struct {
+	MessageID    string
+	Transactions []PaymentFileTransaction
+}
+
+// PaymentFileTransaction is a single payment of a file. The transaction which
+// could not be read as a payment carries the error instead.
+type PaymentFileTransaction struct {
+	PaymentInformationID string
+	EndToEndID           string
+	Payment              *Payment
+	Err                  error
+}
+
+type PaymentFileStatus string
+
+const (
+	PaymentFileStatusImported    PaymentFileStatus = "IMPORTED"
+	PaymentFileStatusRejected    PaymentFileStatus = "REJECTED"
+	PaymentFileStatusNotImported PaymentFileStatus = "NOT_IMPORTED"
+)
+
+// PaymentFileReport tells the outcome of the import of each payment of the
+// file, in the order of the file.
+type PaymentFileReport struct {
+	BaseObject
+
+	MessageID string              `json:"message_id"`
+	Mode      PaymentImportMode   `json:"mode"`
+	Imported  int                 `json:"imported"`
+	Rejected  int                 `json:"rejected"`
+	Results   []PaymentFileResult `json:"results"`
+}
+
+func (r PaymentFileReport) GetName() string {
+	return "payment-files"
+}
+
+// PaymentFileResult is the outcome of the import of a single payment. The
+// valid payment of a file imported all or nothing is not imported once any
+// other payment is rejected.
+type PaymentFileResult struct {
+	PaymentInformationID string             `json:"payment_information_id"`
+	EndToEndID           string             `json:"end_to_end_id,omitempty"`
+	PaymentID            ID                 `json:"payment_id"`
+	Status               PaymentFileStatus  `json:"status"`
+	Errors               []PaymentFileError `json:"errors,omitempty"`
+}
+
+type PaymentFileError struct {
+	Title   string `json:"title"`
+	Detail  string `json:"detail,omitempty"`
+	Pointer string `json:"pointer,omitempty"`
+}
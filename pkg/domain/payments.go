@@ -1,6 +1,13 @@
 package domain
 
 import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/shopspring/decimal"
+
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 	"github.com/michaljemala/payments-sample/pkg/internal/resource"
 )
@@ -8,49 +15,197 @@ import (
 type Payment struct {
 	BaseObject
 
-	Amount   Monetary     `json:"amount"`
-	Creditor PaymentParty `json:"creditor"`
-	Debtor   PaymentParty `json:"debtor"`
-	Scheme   string       `json:"scheme"`
+	Amount   Monetary      `json:"amount"`
+	Creditor PaymentParty  `json:"creditor"`
+	Debtor   PaymentParty  `json:"debtor"`
+	Scheme   string        `json:"scheme"`
+	Status   PaymentStatus `json:"status"`
+
+	// EndToEndReference identifies the payment all the way to the creditor,
+	// NumericReference is the reference the creditor reconciles against.
+	EndToEndReference     string                `json:"end_to_end_reference,omitempty"`
+	NumericReference      string                `json:"numeric_reference,omitempty"`
+	PaymentPurpose        string                `json:"payment_purpose,omitempty"`
+	RemittanceInformation RemittanceInformation `json:"remittance_information"`
+
+	// RequestedExecutionDate is the day the payment should be executed on,
+	// as soon as possible if not set.
+	RequestedExecutionDate *Date `json:"requested_execution_date,omitempty"`
+
+	// ValueDate is the business day of the scheme the payment is executed
+	// on, it is computed by the server.
+	ValueDate *Date `json:"value_date,omitempty"`
+
+	// SchemeReference is assigned by the scheme which has accepted the
+	// payment, RejectionReason is given by the scheme which has rejected it
+	// or is the last error once the execution attempts are used up.
+	SchemeReference string `json:"scheme_reference,omitempty"`
+	RejectionReason string `json:"rejection_reason,omitempty"`
+
+	// CreatedAt and UpdatedAt are managed by the server, the values sent by
+	// the clients are ignored.
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Version   uint             `json:"-"`
+	DeletedAt *time.Time       `json:"-"`
+	Execution PaymentExecution `json:"-"`
+}
+
+// PaymentExecution tracks the failed attempts of the worker to submit
+// a scheduled payment.
+type PaymentExecution struct {
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+func (p Payment) Meta() jsonapi.Meta {
+	meta := jsonapi.Meta{"version": p.Version}
+	if p.DeletedAt != nil {
+		meta["deleted_at"] = p.DeletedAt
+	}
+	if p.Execution.Attempts > 0 {
+		meta["execution"] = p.Execution
+	}
+	return meta
+}
+
+func (p Payment) IsDeleted() bool {
+	return p.DeletedAt != nil
 }
 
 func (p Payment) Validate() error {
+	var violations errors.Multi
 	if p.ID.IsNil() {
-		return errors.Generic(
-			errors.ErrCodeGenericInvalidArgument,
-			"invalid payment",
-			"payment id must not be nil",
-		)
+		violations = append(violations, PaymentViolation("/data/id", "payment id must not be nil"))
 	}
 	if p.Scheme == "" {
-		return errors.Generic(
-			errors.ErrCodeGenericInvalidArgument,
-			"invalid payment",
-			"invalid payment scheme",
-		)
+		violations = append(violations, PaymentViolation(PaymentPointer("scheme"), "invalid payment scheme"))
+	}
+	if p.Status != "" && !p.Status.IsValid() {
+		violations = append(violations, PaymentViolation(PaymentPointer("status"), "invalid payment status"))
 	}
 	if p.Amount.Currency == "" {
-		return errors.Generic(
-			errors.ErrCodeGenericInvalidArgument,
-			"invalid payment",
-			"invalid amount currency code",
-		)
+		violations = append(violations, PaymentViolation(PaymentPointer("amount", "currency"), "invalid amount currency code"))
 	}
 	if p.Creditor.AccountNumber == "" {
-		return errors.Generic(
-			errors.ErrCodeGenericInvalidArgument,
-			"invalid payment",
-			"invalid creditor account number",
-		)
+		violations = append(violations, PaymentViolation(PaymentPointer("creditor", "account_number"), "invalid creditor account number"))
 	}
 	if p.Debtor.AccountNumber == "" {
-		return errors.Generic(
-			errors.ErrCodeGenericInvalidArgument,
-			"invalid payment",
-			"invalid debtor account number",
-		)
+		violations = append(violations, PaymentViolation(PaymentPointer("debtor", "account_number"), "invalid debtor account number"))
+	}
+	return violations.Err()
+}
+
+// PaymentViolation returns an error of the payment document value given by
+// the JSON pointer.
+func PaymentViolation(pointer, detail string) errors.Error {
+	return errors.Generic(
+		errors.ErrCodeGenericInvalidArgument,
+		"invalid payment",
+		detail,
+		map[string]interface{}{errors.ExtraPointer: pointer},
+	)
+}
+
+// PaymentPointer returns the JSON pointer of the payment attribute given by
+// its path within the payment document.
+func PaymentPointer(path ...string) string {
+	return "/data/attributes/" + strings.Join(path, "/")
+}
+
+// HasSameContent reports whether both payments carry the same business data.
+// The lifecycle status and the answer of the scheme, version, timestamps, value
+// date, deletion and execution attempts are not considered to be a part of
+// the content.
+func (p Payment) HasSameContent(o Payment) bool {
+	if !decimal.Decimal(p.Amount.Value).Equal(decimal.Decimal(o.Amount.Value)) {
+		return false
+	}
+	p.Amount.Value, o.Amount.Value = Decimal{}, Decimal{}
+	p.Status, o.Status = "", ""
+	p.SchemeReference, o.SchemeReference = "", ""
+	p.RejectionReason, o.RejectionReason = "", ""
+	p.CreatedAt, o.CreatedAt = time.Time{}, time.Time{}
+	p.UpdatedAt, o.UpdatedAt = time.Time{}, time.Time{}
+	p.ValueDate, o.ValueDate = nil, nil
+	p.Version, o.Version = 0, 0
+	p.DeletedAt, o.DeletedAt = nil, nil
+	p.Execution, o.Execution = PaymentExecution{}, PaymentExecution{}
+	return reflect.DeepEqual(p, o)
+}
+
+const (
+	PaymentSchemeSEPA  = "SEPA"
+	PaymentSchemeSWIFT = "SWIFT"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusDraft           = PaymentStatus("DRAFT")
+	PaymentStatusPendingApproval = PaymentStatus("PENDING_APPROVAL")
+	PaymentStatusSubmitted       = PaymentStatus("SUBMITTED")
+	PaymentStatusAccepted        = PaymentStatus("ACCEPTED")
+	PaymentStatusSettled         = PaymentStatus("SETTLED")
+	PaymentStatusRejected        = PaymentStatus("REJECTED")
+	PaymentStatusCancelled       = PaymentStatus("CANCELLED")
+	PaymentStatusReturned        = PaymentStatus("RETURNED")
+	PaymentStatusUnconfirmed     = PaymentStatus("UNCONFIRMED")
+)
+
+// paymentStatusTransitions defines the payment lifecycle state machine,
+// statuses without any outgoing transition are final.
+var paymentStatusTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusDraft:           {PaymentStatusPendingApproval, PaymentStatusSubmitted, PaymentStatusCancelled},
+	PaymentStatusPendingApproval: {PaymentStatusDraft, PaymentStatusSubmitted, PaymentStatusRejected, PaymentStatusCancelled},
+	PaymentStatusSubmitted:       {PaymentStatusAccepted, PaymentStatusRejected},
+	PaymentStatusAccepted:        {PaymentStatusSettled, PaymentStatusRejected},
+	PaymentStatusSettled:         {PaymentStatusReturned},
+	PaymentStatusRejected:        nil,
+	PaymentStatusCancelled:       nil,
+	PaymentStatusReturned:        nil,
+	// The scheme has never answered the payment, it may or may not have
+	// been received, so it is left to be confirmed or sent again manually.
+	PaymentStatusUnconfirmed: {PaymentStatusAccepted, PaymentStatusRejected, PaymentStatusSubmitted},
+}
+
+func (s PaymentStatus) String() string {
+	return string(s)
+}
+
+func (s PaymentStatus) IsValid() bool {
+	_, ok := paymentStatusTransitions[s]
+	return ok
+}
+
+func (s PaymentStatus) IsFinal() bool {
+	return s.IsValid() && len(paymentStatusTransitions[s]) == 0
+}
+
+// IsEditable reports whether the payment content may still be changed.
+func (s PaymentStatus) IsEditable() bool {
+	return s == PaymentStatusDraft
+}
+
+// IsDeletable reports whether the payment may be deleted, i.e. it has not
+// been handed over to the scheme.
+func (s PaymentStatus) IsDeletable() bool {
+	switch s {
+	case PaymentStatusDraft, PaymentStatusPendingApproval, PaymentStatusCancelled:
+		return true
 	}
-	return nil
+	return false
+}
+
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
 }
 
 type PaymentParty struct {
@@ -61,6 +216,13 @@ type PaymentParty struct {
 	AccountProvider AccountProvider `json:"account_provider"`
 }
 
+// RemittanceInformation tells the creditor what the payment is for, either as
+// a free text or as an ISO 11649 creditor reference, but not both.
+type RemittanceInformation struct {
+	Unstructured string `json:"unstructured,omitempty"`
+	Structured   string `json:"structured,omitempty"`
+}
+
 type AccountProvider struct {
 	Code string  `json:"code"`
 	Name *string `json:"name,omitempty"`
@@ -73,7 +235,9 @@ type Monetary struct {
 
 type PaymentSearchRequest struct {
 	*resource.SearchPagination
+	*resource.SearchCursor
 	resource.SearchFilter
+	resource.SearchSort
 }
 
 func (r PaymentSearchRequest) IDs() []ID {
@@ -109,7 +273,153 @@ func (r PaymentSearchRequest) DebtorAccountNumbers() []string {
 	return numbers
 }
 
+func (r PaymentSearchRequest) Currencies() []string {
+	if r.SearchFilter == nil {
+		return nil
+	}
+	currencies, ok := r.SearchFilter["amount.currency"].([]string)
+	if !ok {
+		return nil
+	}
+	return currencies
+}
+
+func (r PaymentSearchRequest) Schemes() []string {
+	if r.SearchFilter == nil {
+		return nil
+	}
+	schemes, ok := r.SearchFilter["scheme"].([]string)
+	if !ok {
+		return nil
+	}
+	return schemes
+}
+
+func (r PaymentSearchRequest) EndToEndReferences() []string {
+	return r.stringValues("end_to_end_reference")
+}
+
+func (r PaymentSearchRequest) NumericReferences() []string {
+	return r.stringValues("numeric_reference")
+}
+
+func (r PaymentSearchRequest) CreditorReferences() []string {
+	return r.stringValues("remittance_information.structured")
+}
+
+// References returns the references matching any of the end-to-end, numeric
+// or creditor references of a payment, e.g. the one quoted by a customer.
+func (r PaymentSearchRequest) References() []string {
+	return r.stringValues("reference")
+}
+
+func (r PaymentSearchRequest) stringValues(field string) []string {
+	if r.SearchFilter == nil {
+		return nil
+	}
+	values, ok := r.SearchFilter[field].([]string)
+	if !ok {
+		return nil
+	}
+	return values
+}
+
+func (r PaymentSearchRequest) AmountRange() DecimalRange {
+	var amountRange DecimalRange
+	if r.SearchFilter == nil {
+		return amountRange
+	}
+	bound := func(op resource.FilterOperator) *Decimal {
+		d, ok := r.SearchFilter[resource.FilterKey("amount.value", op)].(Decimal)
+		if !ok {
+			return nil
+		}
+		return &d
+	}
+	amountRange.Gt = bound(resource.FilterOperatorGt)
+	amountRange.Gte = bound(resource.FilterOperatorGte)
+	amountRange.Lt = bound(resource.FilterOperatorLt)
+	amountRange.Lte = bound(resource.FilterOperatorLte)
+	return amountRange
+}
+
+func (r PaymentSearchRequest) CreatedAtRange() TimeRange {
+	return r.timeRange("created_at")
+}
+
+func (r PaymentSearchRequest) UpdatedAtRange() TimeRange {
+	return r.timeRange("updated_at")
+}
+
+func (r PaymentSearchRequest) timeRange(field string) TimeRange {
+	var timeRange TimeRange
+	if r.SearchFilter == nil {
+		return timeRange
+	}
+	bound := func(op resource.FilterOperator) *time.Time {
+		t, ok := r.SearchFilter[resource.FilterKey(field, op)].(time.Time)
+		if !ok {
+			return nil
+		}
+		return &t
+	}
+	timeRange.Gt = bound(resource.FilterOperatorGt)
+	timeRange.Gte = bound(resource.FilterOperatorGte)
+	timeRange.Lt = bound(resource.FilterOperatorLt)
+	timeRange.Lte = bound(resource.FilterOperatorLte)
+	return timeRange
+}
+
+func (r PaymentSearchRequest) RequestedExecutionDateRange() DateRange {
+	var dateRange DateRange
+	if r.SearchFilter == nil {
+		return dateRange
+	}
+	bound := func(op resource.FilterOperator) *Date {
+		d, ok := r.SearchFilter[resource.FilterKey("requested_execution_date", op)].(Date)
+		if !ok {
+			return nil
+		}
+		return &d
+	}
+	dateRange.Gt = bound(resource.FilterOperatorGt)
+	dateRange.Gte = bound(resource.FilterOperatorGte)
+	dateRange.Lt = bound(resource.FilterOperatorLt)
+	dateRange.Lte = bound(resource.FilterOperatorLte)
+	return dateRange
+}
+
+// Deleted reports whether the deleted payments are searched instead of the
+// live ones.
+func (r PaymentSearchRequest) Deleted() bool {
+	if r.SearchFilter == nil {
+		return false
+	}
+	deleted, ok := r.SearchFilter["deleted"].(bool)
+	if !ok {
+		return false
+	}
+	return deleted
+}
+
+func (r PaymentSearchRequest) CreditorNamePrefix() string {
+	return r.namePrefix("creditor.name")
+}
+
+func (r PaymentSearchRequest) DebtorNamePrefix() string {
+	return r.namePrefix("debtor.name")
+}
+
+func (r PaymentSearchRequest) namePrefix(field string) string {
+	if r.SearchFilter == nil {
+		return ""
+	}
+	prefix, _ := r.SearchFilter[resource.FilterKey(field, resource.FilterOperatorPrefix)].(string)
+	return prefix
+}
+
 type PaymentSearchResponse struct {
-	Data []*Payment
-	Size uint
+	Data    []*Payment
+	Size    uint
+	HasMore bool
 }
@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 )
@@ -93,6 +95,31 @@ func TestPayment_Validate(t *testing.T) {
 			},
 			errFunc: assertInvalidArgumentError,
 		},
+		{
+			name: "Multiple problems",
+			in: Payment{
+				BaseObject: BaseObject{ID: MustIDFrom("276c8bbf-79ca-4ac2-b319-0f1c51463540")},
+				Amount: Monetary{
+					Value: MustDecimalFrom("1000.0"),
+				},
+				Debtor: PaymentParty{
+					AccountNumber: "SK0809000000000123123123",
+				},
+				Scheme: "SWIFT",
+			},
+			errFunc: func(t *testing.T, err error) {
+				assertInvalidArgumentError(t, err)
+
+				var pointers []string
+				for _, e := range err.(errors.Multi) {
+					pointers = append(pointers, e.Extra[errors.ExtraPointer].(string))
+				}
+				want := []string{"/data/attributes/amount/currency", "/data/attributes/creditor/account_number"}
+				if have := pointers; !reflect.DeepEqual(want, have) {
+					t.Fatalf("invalid error pointers: want %v, have %v", want, have)
+				}
+			},
+		},
 		{
 			name: "Valid payment",
 			in: Payment{
@@ -115,22 +142,158 @@ func TestPayment_Validate(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			err := tc.in.Validate()
-			if err != nil {
-				if tc.errFunc == nil {
-					t.Fatalf("unexpected error: %v", err)
+			if tc.errFunc != nil {
+				if err == nil {
+					t.Fatalf("expected error")
 				}
+				tc.errFunc(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPaymentStatus_CanTransitionTo(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from, to PaymentStatus
+		allowed  bool
+	}{
+		{name: "Draft to submitted", from: PaymentStatusDraft, to: PaymentStatusSubmitted, allowed: true},
+		{name: "Draft to pending approval", from: PaymentStatusDraft, to: PaymentStatusPendingApproval, allowed: true},
+		{name: "Pending approval back to draft", from: PaymentStatusPendingApproval, to: PaymentStatusDraft, allowed: true},
+		{name: "Submitted to accepted", from: PaymentStatusSubmitted, to: PaymentStatusAccepted, allowed: true},
+		{name: "Accepted to settled", from: PaymentStatusAccepted, to: PaymentStatusSettled, allowed: true},
+		{name: "Settled to returned", from: PaymentStatusSettled, to: PaymentStatusReturned, allowed: true},
+		{name: "Unconfirmed sent again", from: PaymentStatusUnconfirmed, to: PaymentStatusSubmitted, allowed: true},
+		{name: "Unconfirmed to cancelled", from: PaymentStatusUnconfirmed, to: PaymentStatusCancelled},
+		{name: "Draft to settled", from: PaymentStatusDraft, to: PaymentStatusSettled},
+		{name: "Settled to draft", from: PaymentStatusSettled, to: PaymentStatusDraft},
+		{name: "Submitted to cancelled", from: PaymentStatusSubmitted, to: PaymentStatusCancelled},
+		{name: "Cancelled is final", from: PaymentStatusCancelled, to: PaymentStatusDraft},
+		{name: "Same status", from: PaymentStatusDraft, to: PaymentStatusDraft},
+		{name: "Unknown status", from: PaymentStatus("UNKNOWN"), to: PaymentStatusDraft},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if want, have := tc.allowed, tc.from.CanTransitionTo(tc.to); want != have {
+				t.Fatalf("invalid transition result: want %v, have %v", want, have)
+			}
+		})
+	}
+}
+
+func TestPayment_HasSameContent(t *testing.T) {
+	base := Payment{
+		BaseObject: BaseObject{ID: MustIDFrom("276c8bbf-79ca-4ac2-b319-0f1c51463540")},
+		Amount: Monetary{
+			Value:    MustDecimalFrom("1000.0"),
+			Currency: "EUR",
+		},
+		Scheme: "SWIFT",
+		Status: PaymentStatusDraft,
+	}
+
+	testCases := []struct {
+		name   string
+		in     func(Payment) Payment
+		result bool
+	}{
+		{
+			name:   "Identical",
+			in:     func(p Payment) Payment { return p },
+			result: true,
+		},
+		{
+			name: "Different status",
+			in: func(p Payment) Payment {
+				p.Status = PaymentStatusSubmitted
+				return p
+			},
+			result: true,
+		},
+		{
+			name: "Same amount with different scale",
+			in: func(p Payment) Payment {
+				p.Amount.Value = MustDecimalFrom("1000.00")
+				return p
+			},
+			result: true,
+		},
+		{
+			name: "Different amount",
+			in: func(p Payment) Payment {
+				p.Amount.Value = MustDecimalFrom("1000.01")
+				return p
+			},
+		},
+		{
+			name: "Different scheme",
+			in: func(p Payment) Payment {
+				p.Scheme = "SEPA"
+				return p
+			},
+		},
+		{
+			name: "Different timestamps",
+			in: func(p Payment) Payment {
+				p.CreatedAt = time.Date(2019, 6, 12, 12, 0, 0, 0, time.UTC)
+				p.UpdatedAt = time.Date(2019, 6, 13, 12, 0, 0, 0, time.UTC)
+				return p
+			},
+			result: true,
+		},
+		{
+			name: "Different execution attempts",
+			in: func(p Payment) Payment {
+				p.Execution = PaymentExecution{Attempts: 2, LastError: "currency \"EUR\" is not supported"}
+				return p
+			},
+			result: true,
+		},
+		{
+			name: "Different scheme answer",
+			in: func(p Payment) Payment {
+				p.SchemeReference = "SIM-1"
+				p.RejectionReason = "Limit exceeded"
+				return p
+			},
+			result: true,
+		},
+		{
+			name: "Different value date",
+			in: func(p Payment) Payment {
+				d := MustDateFrom("2019-06-17")
+				p.ValueDate = &d
+				return p
+			},
+			result: true,
+		},
+		{
+			name: "Different requested execution date",
+			in: func(p Payment) Payment {
+				d := MustDateFrom("2019-06-14")
+				p.RequestedExecutionDate = &d
+				return p
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if want, have := tc.result, base.HasSameContent(tc.in(base)); want != have {
+				t.Fatalf("invalid comparison result: want %v, have %v", want, have)
 			}
 		})
 	}
 }
 
 func assertInvalidArgumentError(t *testing.T, err error) {
-	switch err := err.(type) {
-	case errors.Error:
-		if want, have := errors.ErrCodeGenericInvalidArgument, err.Code; want != have {
-			t.Fatalf("invalid error code: want %s, have %s", want, have)
-		}
-	default:
-		t.Fatalf("invalid error type: want %T, have %T", errors.Error{}, err)
+	if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+		t.Fatalf("invalid error: want %s, have %v", errors.ErrCodeGenericInvalidArgument, err)
 	}
 }
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	latinTextPattern         = regexp.MustCompile(`^[A-Za-z0-9/?:().,'+ -]*$`)
+	numericReferencePattern  = regexp.MustCompile(`^[0-9]+$`)
+	creditorReferencePattern = regexp.MustCompile(`^RF[0-9]{2}[A-Z0-9]{1,21}$`)
+)
+
+// ValidateLatinText checks the text to use only the basic Latin character set
+// which is supported by both the SEPA and the SWIFT messages.
+func ValidateLatinText(text string) error {
+	if !latinTextPattern.MatchString(text) {
+		return fmt.Errorf("%q contains characters other than letters, digits, spaces and /-?:().,'+", text)
+	}
+	return nil
+}
+
+// ValidateReference checks the reference to be a Latin text which neither
+// starts nor ends with a slash and does not contain two consecutive slashes.
+func ValidateReference(ref string) error {
+	err := ValidateLatinText(ref)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(ref, "/") || strings.HasSuffix(ref, "/") || strings.Contains(ref, "//") {
+		return fmt.Errorf("%q must not start or end with a slash or contain two consecutive slashes", ref)
+	}
+	return nil
+}
+
+func ValidateNumericReference(ref string) error {
+	if !numericReferencePattern.MatchString(ref) {
+		return fmt.Errorf("%q must contain digits only", ref)
+	}
+	return nil
+}
+
+// ValidateCreditorReference checks the ISO 11649 structured creditor
+// reference in its electronic format, e.g. RF18539007547034.
+func ValidateCreditorReference(ref string) error {
+	if !creditorReferencePattern.MatchString(ref) {
+		return fmt.Errorf("%q is not a creditor reference in electronic format", ref)
+	}
+	if !hasValidMod97Checksum(ref) {
+		return fmt.Errorf("creditor reference %q has invalid check digits", ref)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "testing"
+
+func TestValidateReference(t *testing.T) {
+	testCases := []struct {
+		name  string
+		in    string
+		valid bool
+	}{
+		{name: "Plain reference", in: "INV-2019/0042 (June)", valid: true},
+		{name: "Punctuation", in: "A.B,C'D+E?F:G", valid: true},
+		{name: "Empty", in: "", valid: true},
+		{name: "Leading slash", in: "/INV-42"},
+		{name: "Trailing slash", in: "INV-42/"},
+		{name: "Double slash", in: "INV//42"},
+		{name: "Accented letter", in: "Faktúra 42"},
+		{name: "Underscore", in: "INV_42"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateReference(tc.in)
+			if want, have := tc.valid, err == nil; want != have {
+				t.Fatalf("unexpected validity of %q: want %t, have %t (%v)", tc.in, want, have, err)
+			}
+		})
+	}
+}
+
+func TestValidateNumericReference(t *testing.T) {
+	testCases := []struct {
+		name  string
+		in    string
+		valid bool
+	}{
+		{name: "Digits", in: "1002001", valid: true},
+		{name: "Letters", in: "10O2001"},
+		{name: "Spaces", in: "100 2001"},
+		{name: "Empty", in: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNumericReference(tc.in)
+			if want, have := tc.valid, err == nil; want != have {
+				t.Fatalf("unexpected validity of %q: want %t, have %t (%v)", tc.in, want, have, err)
+			}
+		})
+	}
+}
+
+func TestValidateCreditorReference(t *testing.T) {
+	testCases := []struct {
+		name  string
+		in    string
+		valid bool
+	}{
+		{name: "Numeric reference", in: "RF18539007547034", valid: true},
+		{name: "Alphanumeric reference", in: "RF712348231", valid: true},
+		{name: "Invalid check digits", in: "RF19539007547034"},
+		{name: "Too long", in: "RF18539007547034539007547034"},
+		{name: "Spaces", in: "RF18 5390 0754 7034"},
+		{name: "Missing prefix", in: "18539007547034"},
+		{name: "Empty", in: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCreditorReference(tc.in)
+			if want, have := tc.valid, err == nil; want != have {
+				t.Fatalf("unexpected validity of %q: want %t, have %t (%v)", tc.in, want, have, err)
+			}
+		})
+	}
+}
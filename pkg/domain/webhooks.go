@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+)
+
+// MinWebhookSecretLength is the shortest secret the webhooks can be signed
+// with, the generated secrets are longer.
+const MinWebhookSecretLength = 16
+
+func (t PaymentEventType) IsValid() bool {
+	switch t {
+	case PaymentEventCreated, PaymentEventUpdated, PaymentEventStatusChanged, PaymentEventDeleted, PaymentEventRestored:
+		return true
+	}
+	return false
+}
+
+// WebhookSubscription registers an endpoint the payment events are pushed
+// to. The subscription without the event types receives all the events.
+// The secret the webhooks are signed with is write-only, it is returned
+// only when generated along with the created subscription.
+type WebhookSubscription struct {
+	BaseObject
+
+	TargetURL  string             `json:"target_url"`
+	EventTypes []PaymentEventType `json:"event_types"`
+	Secret     string             `json:"secret,omitempty"`
+	Active     bool               `json:"active"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+func (s WebhookSubscription) GetName() string {
+	return "webhook-subscriptions"
+}
+
+func (s WebhookSubscription) Validate() error {
+	var violations errors.Multi
+	if s.ID.IsNil() {
+		violations = append(violations, WebhookViolation("/data/id", "subscription id must not be nil"))
+	}
+	u, err := url.Parse(s.TargetURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		violations = append(violations, WebhookViolation("/data/attributes/target_url", "target url must be an absolute http or https url"))
+	}
+	for i, t := range s.EventTypes {
+		if !t.IsValid() {
+			violations = append(violations, WebhookViolation(
+				fmt.Sprintf("/data/attributes/event_types/%d", i),
+				fmt.Sprintf("unknown event type %q", t),
+			))
+		}
+	}
+	if s.Secret != "" && len(s.Secret) < MinWebhookSecretLength {
+		violations = append(violations, WebhookViolation(
+			"/data/attributes/secret",
+			fmt.Sprintf("secret must be at least %d characters long", MinWebhookSecretLength),
+		))
+	}
+	return violations.Err()
+}
+
+// Matches reports whether the subscription receives the events of the type.
+func (s WebhookSubscription) Matches(t PaymentEventType) bool {
+	if !s.Active {
+		return false
+	}
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, et := range s.EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookViolation returns an error of the webhook document value given by
+// the JSON pointer.
+func WebhookViolation(pointer, detail string) errors.Error {
+	return errors.Generic(
+		errors.ErrCodeGenericInvalidArgument,
+		"invalid webhook subscription",
+		detail,
+		map[string]interface{}{errors.ExtraPointer: pointer},
+	)
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending    WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusDelivered  WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryStatusDeadLetter WebhookDeliveryStatus = "DEAD_LETTER"
+)
+
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusDelivered, WebhookDeliveryStatusDeadLetter:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery pushes a single payment event to a subscription. The
+// delivery which fails is retried until it runs out of the attempts, then it
+// is left as a dead letter until delivered again manually.
+type WebhookDelivery struct {
+	BaseObject
+
+	SubscriptionID ID                    `json:"subscription_id"`
+	EventID        ID                    `json:"event_id"`
+	EventType      PaymentEventType      `json:"event_type"`
+	PaymentID      ID                    `json:"payment_id"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	ResponseStatus int                   `json:"response_status,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+func (d WebhookDelivery) GetName() string {
+	return "webhook-deliveries"
+}
+
+// NewWebhookDelivery returns the pending delivery of the event to the
+// subscription, the payload is the JSON encoded event.
+func NewWebhookDelivery(subscription *WebhookSubscription, event *PaymentEvent, now time.Time) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.Generic(errors.ErrCodeGenericInternal, "unable to encode payment event", err.Error())
+	}
+	delivery := &WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		PaymentID:      event.PaymentID,
+		Payload:        payload,
+		Status:         WebhookDeliveryStatusPending,
+		CreatedAt:      now,
+	}
+	delivery.ID = NewID()
+	return delivery, nil
+}
+
+type WebhookDeliverySearchRequest struct {
+	resource.SearchFilter
+	*resource.SearchPagination
+}
+
+func (r WebhookDeliverySearchRequest) SubscriptionIDs() []ID {
+	ids, _ := r.SearchFilter["subscription_id"].([]ID)
+	return ids
+}
+
+func (r WebhookDeliverySearchRequest) PaymentIDs() []ID {
+	ids, _ := r.SearchFilter["payment_id"].([]ID)
+	return ids
+}
+
+func (r WebhookDeliverySearchRequest) EventTypes() []PaymentEventType {
+	types, _ := r.SearchFilter["event_type"].([]PaymentEventType)
+	return types
+}
+
+func (r WebhookDeliverySearchRequest) Statuses() []WebhookDeliveryStatus {
+	statuses, _ := r.SearchFilter["status"].([]WebhookDeliveryStatus)
+	return statuses
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestWebhookSubscription_Validate(t *testing.T) {
+	id := MustIDFrom("276c8bbf-79ca-4ac2-b319-0f1c51463540")
+
+	testCases := []struct {
+		name    string
+		in      WebhookSubscription
+		errFunc func(t *testing.T, err error)
+	}{
+		{
+			name: "Valid",
+			in: WebhookSubscription{
+				BaseObject: BaseObject{ID: id},
+				TargetURL:  "https://partner.example.com/hooks",
+				EventTypes: []PaymentEventType{PaymentEventCreated, PaymentEventStatusChanged},
+				Secret:     "0123456789abcdef",
+			},
+			errFunc: func(t *testing.T, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:    "No ID",
+			in:      WebhookSubscription{TargetURL: "https://partner.example.com/hooks"},
+			errFunc: assertInvalidArgumentError,
+		},
+		{
+			name:    "Relative URL",
+			in:      WebhookSubscription{BaseObject: BaseObject{ID: id}, TargetURL: "/hooks"},
+			errFunc: assertInvalidArgumentError,
+		},
+		{
+			name:    "Unsupported URL scheme",
+			in:      WebhookSubscription{BaseObject: BaseObject{ID: id}, TargetURL: "ftp://partner.example.com/hooks"},
+			errFunc: assertInvalidArgumentError,
+		},
+		{
+			name: "Unknown event type",
+			in: WebhookSubscription{
+				BaseObject: BaseObject{ID: id},
+				TargetURL:  "https://partner.example.com/hooks",
+				EventTypes: []PaymentEventType{"payment.paid"},
+			},
+			errFunc: assertInvalidArgumentError,
+		},
+		{
+			name: "Short secret",
+			in: WebhookSubscription{
+				BaseObject: BaseObject{ID: id},
+				TargetURL:  "https://partner.example.com/hooks",
+				Secret:     "secret",
+			},
+			errFunc: assertInvalidArgumentError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.errFunc(t, tc.in.Validate())
+		})
+	}
+}
+
+func TestWebhookSubscription_Matches(t *testing.T) {
+	testCases := []struct {
+		name         string
+		subscription WebhookSubscription
+		eventType    PaymentEventType
+		matches      bool
+	}{
+		{
+			name:         "All events",
+			subscription: WebhookSubscription{Active: true},
+			eventType:    PaymentEventDeleted,
+			matches:      true,
+		},
+		{
+			name:         "Subscribed event",
+			subscription: WebhookSubscription{Active: true, EventTypes: []PaymentEventType{PaymentEventCreated, PaymentEventDeleted}},
+			eventType:    PaymentEventDeleted,
+			matches:      true,
+		},
+		{
+			name:         "Other event",
+			subscription: WebhookSubscription{Active: true, EventTypes: []PaymentEventType{PaymentEventCreated}},
+			eventType:    PaymentEventDeleted,
+		},
+		{
+			name:         "Inactive subscription",
+			subscription: WebhookSubscription{},
+			eventType:    PaymentEventDeleted,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if want, have := tc.matches, tc.subscription.Matches(tc.eventType); want != have {
+				t.Errorf("unexpected match: want %v, have %v", want, have)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Package events delivers the payment events to the parties reacting to the
+// payment changes.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+)
+
+// Sink receives the published payment events. An error means the event has
+// not been received and it should be published again later, so the sinks
+// may receive the same event more than once.
+type Sink interface {
+	Publish(context.Context, *domain.PaymentEvent) error
+}
+
+// Log writes the events to the log.
+type Log struct {
+	Logger *log.Logger
+}
+
+func NewLog(logger *log.Logger) *Log {
+	return &Log{Logger: logger}
+}
+
+func (s *Log) Publish(_ context.Context, event *domain.PaymentEvent) error {
+	s.Logger.Printf("event %s: %s of payment %s by %s", event.ID, event.Type, event.PaymentID, event.Actor)
+	return nil
+}
+
+// HTTP posts the JSON encoded events to the URL, any response other than
+// 2xx is considered a failure.
+type HTTP struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewHTTP(url string) *HTTP {
+	return &HTTP{URL: url, Client: http.DefaultClient}
+}
+
+func (s *HTTP) Publish(ctx context.Context, event *domain.PaymentEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to encode event: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to publish event: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unable to publish event: sink responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+)
+
+func TestHTTP_Publish(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statusCode int
+		err        bool
+	}{
+		{
+			name:       "Received",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "Unavailable",
+			statusCode: http.StatusServiceUnavailable,
+			err:        true,
+		},
+	}
+
+	event := &domain.PaymentEvent{
+		ID:        domain.NewID(),
+		Type:      domain.PaymentEventCreated,
+		PaymentID: domain.NewID(),
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var received domain.PaymentEvent
+				err := json.NewDecoder(r.Body).Decode(&received)
+				if err != nil {
+					t.Errorf("unable to decode event: %v", err)
+				}
+				if r.Method != "POST" || received.ID != event.ID || received.Type != event.Type {
+					t.Errorf("unexpected request: %s %+v", r.Method, received)
+				}
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			err := NewHTTP(server.URL).Publish(context.Background(), event)
+			if tc.err && err == nil {
+				t.Fatalf("expected error, have <nil>")
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
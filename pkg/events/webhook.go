@@ -0,0 +1,96 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The headers of the webhook requests. The signature is the HMAC-SHA256 of
+// the timestamp, a dot and the body keyed with the subscription secret, so
+// the receivers can verify both the origin and the age of the request.
+const (
+	WebhookIDHeader        = "X-Webhook-Id"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign returns the signature of the body sent at the timestamp, given in
+// seconds since the Unix epoch.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of the webhook request body and that the
+// request is not older than the tolerance, so it can not be replayed later.
+func Verify(secret string, header http.Header, body []byte, now time.Time, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid webhook timestamp: %q", header.Get(WebhookTimestampHeader))
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("webhook timestamp out of tolerance: %v", age)
+	}
+	if !hmac.Equal([]byte(header.Get(WebhookSignatureHeader)), []byte(Sign(secret, timestamp, body))) {
+		return fmt.Errorf("invalid webhook signature")
+	}
+	return nil
+}
+
+// WebhookRequest is a single push of a payment event to a subscription.
+type WebhookRequest struct {
+	ID        string
+	EventType string
+	URL       string
+	Secret    string
+	Body      []byte
+}
+
+// Webhook posts the signed webhook requests, any response other than 2xx is
+// considered a failure.
+type Webhook struct {
+	Client *http.Client
+}
+
+func NewWebhook() *Webhook {
+	return &Webhook{Client: http.DefaultClient}
+}
+
+// Send posts the request signed at the given time and returns the status
+// of the response, if any.
+func (w *Webhook) Send(ctx context.Context, r WebhookRequest, now time.Time) (int, error) {
+	req, err := http.NewRequest("POST", r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, fmt.Errorf("unable to create request: %v", err)
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, r.ID)
+	req.Header.Set(WebhookEventHeader, r.EventType)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, Sign(r.Secret, timestamp, r.Body))
+
+	resp, err := w.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("unable to send webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unable to send webhook: receiver responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
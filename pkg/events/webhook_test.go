@@ -0,0 +1,122 @@
+package events
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testSecret = "0123456789abcdef"
+
+func TestWebhook_Send(t *testing.T) {
+	now := time.Date(2019, 6, 12, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		err        bool
+	}{
+		{
+			name:       "Delivered",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Rejected",
+			statusCode: http.StatusBadRequest,
+			err:        true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("unable to read body: %v", err)
+				}
+				err = Verify(testSecret, r.Header, body, now, time.Minute)
+				if err != nil {
+					t.Errorf("unable to verify webhook: %v", err)
+				}
+				if want, have := "d-1", r.Header.Get(WebhookIDHeader); want != have {
+					t.Errorf("unexpected webhook id: want %q, have %q", want, have)
+				}
+				if want, have := "payment.created", r.Header.Get(WebhookEventHeader); want != have {
+					t.Errorf("unexpected webhook event: want %q, have %q", want, have)
+				}
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			status, err := NewWebhook().Send(context.Background(), WebhookRequest{
+				ID:        "d-1",
+				EventType: "payment.created",
+				URL:       server.URL,
+				Secret:    testSecret,
+				Body:      []byte(`{"type":"payment.created"}`),
+			}, now)
+			if tc.err && err == nil {
+				t.Fatalf("expected error, have <nil>")
+			}
+			if !tc.err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, have := tc.statusCode, status; want != have {
+				t.Fatalf("unexpected response status: want %d, have %d", want, have)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2019, 6, 12, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"payment.created"}`)
+	signed := func(secret string, at time.Time, body []byte) http.Header {
+		return http.Header{
+			WebhookTimestampHeader: []string{strconv.FormatInt(at.Unix(), 10)},
+			WebhookSignatureHeader: []string{Sign(secret, at.Unix(), body)},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		header http.Header
+		valid  bool
+	}{
+		{
+			name:   "Valid",
+			header: signed(testSecret, now.Add(-time.Minute), body),
+			valid:  true,
+		},
+		{
+			name:   "Other secret",
+			header: signed("fedcba9876543210", now, body),
+		},
+		{
+			name:   "Other body",
+			header: signed(testSecret, now, []byte(`{"type":"payment.deleted"}`)),
+		},
+		{
+			name:   "Replayed",
+			header: signed(testSecret, now.Add(-time.Hour), body),
+		},
+		{
+			name:   "Unsigned",
+			header: http.Header{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(testSecret, tc.header, body, now, 5*time.Minute)
+			if tc.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("expected error, have <nil>")
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package gateway connects the payments to the payment schemes.
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+)
+
+// Gateway sends the payments to a single payment scheme. An error means the
+// scheme has not answered and the payment should be sent again later, the
+// gateways therefore answer a payment sent again the same as the first time.
+type Gateway interface {
+	Send(context.Context, *domain.Payment) (*Ack, error)
+}
+
+// Ack is the answer of the scheme, either the acceptance along with the
+// reference the scheme assigned to the payment or the rejection reason.
+type Ack struct {
+	Accepted  bool   `json:"accepted"`
+	Reference string `json:"reference,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// SendPath is the path the payments are posted to by the HTTP gateway.
+const SendPath = "/payments"
+
+// IdempotencyKeyHeader carries the payment id, the remote gateway answers the
+// requests with the same key only once.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// HTTP sends the payments to a remote gateway, e.g. the HTTP stand-in of
+// the simulator, as JSON encoded payment snapshots.
+type HTTP struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewHTTP(url string) *HTTP {
+	return &HTTP{URL: strings.TrimSuffix(url, "/"), Client: http.DefaultClient}
+}
+
+func (g *HTTP) Send(ctx context.Context, payment *domain.Payment) (*Ack, error) {
+	body, err := json.Marshal(domain.NewPaymentSnapshot(payment))
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode payment: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", g.URL+SendPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, payment.ID.String())
+
+	resp, err := g.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("unable to send payment: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to send payment: gateway responded with %s", resp.Status)
+	}
+	ack := new(Ack)
+	err = json.NewDecoder(resp.Body).Decode(ack)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode acknowledgement: %v", err)
+	}
+	return ack, nil
+}
@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+)
+
+func TestHTTP_Send(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+		ack     Ack
+		err     bool
+	}{
+		{
+			name: "Accepted",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"accepted":true,"reference":"REF-1"}`))
+			},
+			ack: Ack{Accepted: true, Reference: "REF-1"},
+		},
+		{
+			name: "Unavailable",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			err: true,
+		},
+		{
+			name: "Invalid acknowledgement",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`accepted`))
+			},
+			err: true,
+		},
+	}
+
+	payment := &domain.Payment{BaseObject: domain.BaseObject{ID: domain.NewID()}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "POST" || r.URL.Path != SendPath {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				if want, have := payment.ID.String(), r.Header.Get(IdempotencyKeyHeader); want != have {
+					t.Errorf("unexpected idempotency key: want %q, have %q", want, have)
+				}
+				tc.handler(w, r)
+			}))
+			defer server.Close()
+
+			ack, err := NewHTTP(server.URL+"/").Send(context.Background(), payment)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, have <nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, have := tc.ack, *ack; want != have {
+				t.Fatalf("unexpected acknowledgement: want %+v, have %+v", want, have)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Package simulator provides a gateway standing in for the payment schemes,
+// so the whole payment lifecycle can be exercised offline.
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/gateway"
+)
+
+type Action string
+
+const (
+	ActionAccept Action = "accept"
+	ActionReject Action = "reject"
+	ActionDelay  Action = "delay"
+)
+
+// Rule decides about the payments it matches, the unset conditions match any
+// payment. A delay postpones the answer and lets the following rules decide.
+type Rule struct {
+	Action                Action
+	Scheme                string
+	Currency              string
+	MinAmount             *domain.Decimal
+	CreditorAccountNumber string
+	Delay                 time.Duration
+	Reason                string
+}
+
+func (r Rule) matches(payment *domain.Payment) bool {
+	switch {
+	case r.Scheme != "" && r.Scheme != payment.Scheme:
+		return false
+	case r.Currency != "" && r.Currency != payment.Amount.Currency:
+		return false
+	case r.MinAmount != nil && payment.Amount.Value.Cmp(*r.MinAmount) < 0:
+		return false
+	case r.CreditorAccountNumber != "" && r.CreditorAccountNumber != payment.Creditor.AccountNumber:
+		return false
+	}
+	return true
+}
+
+// Simulator answers the payments according to the first deciding rule they
+// match, the payments matching no such rule are accepted. A payment sent again
+// is given the answer it was given the first time.
+type Simulator struct {
+	rules []Rule
+
+	mu      sync.Mutex
+	answers map[string]*gateway.Ack
+}
+
+func New(rules ...Rule) *Simulator {
+	return &Simulator{rules: rules, answers: make(map[string]*gateway.Ack)}
+}
+
+func (s *Simulator) Send(ctx context.Context, payment *domain.Payment) (*gateway.Ack, error) {
+	return s.send(ctx, payment.ID.String(), payment)
+}
+
+func (s *Simulator) send(ctx context.Context, key string, payment *domain.Payment) (*gateway.Ack, error) {
+	s.mu.Lock()
+	ack, ok := s.answers[key]
+	s.mu.Unlock()
+	if ok {
+		return ack, nil
+	}
+
+	ack, err := s.decide(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if answered, ok := s.answers[key]; ok {
+		return answered, nil
+	}
+	s.answers[key] = ack
+	return ack, nil
+}
+
+func (s *Simulator) decide(ctx context.Context, payment *domain.Payment) (*gateway.Ack, error) {
+	for _, r := range s.rules {
+		if !r.matches(payment) {
+			continue
+		}
+		switch r.Action {
+		case ActionDelay:
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(r.Delay):
+			}
+		case ActionReject:
+			return &gateway.Ack{Reason: r.Reason}, nil
+		case ActionAccept:
+			return s.accept(payment), nil
+		}
+	}
+	return s.accept(payment), nil
+}
+
+func (s *Simulator) accept(payment *domain.Payment) *gateway.Ack {
+	return &gateway.Ack{Accepted: true, Reference: "SIM-" + payment.ID.String()}
+}
+
+// Handler serves the simulator as a remote gateway, see gateway.HTTP.
+func (s *Simulator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(gateway.SendPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		var snapshot domain.PaymentSnapshot
+		err := json.NewDecoder(r.Body).Decode(&snapshot)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		payment := snapshot.ToPayment()
+		key := r.Header.Get(gateway.IdempotencyKeyHeader)
+		if key == "" {
+			key = payment.ID.String()
+		}
+		ack, err := s.send(r.Context(), key, payment)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ack)
+	})
+	return mux
+}
+
+// LoadRules reads the rules from a JSON array, e.g.
+// [{"action": "reject", "currency": "GBP", "min_amount": "1000", "reason": "Limit exceeded"}],
+// the delays are given as durations, e.g. "2s".
+func LoadRules(r io.Reader) ([]Rule, error) {
+	var encoded []struct {
+		Action                Action          `json:"action"`
+		Scheme                string          `json:"scheme"`
+		Currency              string          `json:"currency"`
+		MinAmount             *domain.Decimal `json:"min_amount"`
+		CreditorAccountNumber string          `json:"creditor_account_number"`
+		Delay                 string          `json:"delay"`
+		Reason                string          `json:"reason"`
+	}
+	err := json.NewDecoder(r).Decode(&encoded)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode rules: %v", err)
+	}
+
+	rules := make([]Rule, len(encoded))
+	for i, e := range encoded {
+		rules[i] = Rule{
+			Action:                e.Action,
+			Scheme:                e.Scheme,
+			Currency:              e.Currency,
+			MinAmount:             e.MinAmount,
+			CreditorAccountNumber: e.CreditorAccountNumber,
+			Reason:                e.Reason,
+		}
+		switch e.Action {
+		case ActionAccept, ActionReject:
+		case ActionDelay:
+			rules[i].Delay, err = time.ParseDuration(e.Delay)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: invalid delay %q", i, e.Delay)
+			}
+		default:
+			return nil, fmt.Errorf("rule %d: unknown action %q", i, e.Action)
+		}
+	}
+	return rules, nil
+}
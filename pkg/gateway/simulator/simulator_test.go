@@ -0,0 +1,186 @@
+package simulator
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/gateway"
+)
+
+func testPayment(scheme, value, currency string) *domain.Payment {
+	return &domain.Payment{
+		BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+		Scheme:     scheme,
+		Amount:     domain.Monetary{Value: domain.MustDecimalFrom(value), Currency: currency},
+		Creditor:   domain.PaymentParty{AccountNumber: "SK3112000000198742637541"},
+	}
+}
+
+func testRules(t *testing.T) []Rule {
+	t.Helper()
+
+	rules, err := LoadRules(strings.NewReader(`[
+		{"action": "delay", "scheme": "SWIFT", "delay": "50ms"},
+		{"action": "reject", "currency": "GBP", "min_amount": "1000", "reason": "Limit exceeded"},
+		{"action": "accept", "creditor_account_number": "SK3112000000198742637541"},
+		{"action": "reject", "scheme": "SEPA", "reason": "Unknown creditor"}
+	]`))
+	if err != nil {
+		t.Fatalf("unable to load rules: %v", err)
+	}
+	return rules
+}
+
+func TestSimulator_Send(t *testing.T) {
+	accepted := &gateway.Ack{Accepted: true, Reference: "SIM-33b5c07b-c6bd-4a59-b02b-554256eaba5d"}
+
+	testCases := []struct {
+		name    string
+		payment *domain.Payment
+		timeout time.Duration
+		ack     *gateway.Ack
+		err     bool
+	}{
+		{
+			name:    "Accepted by rule",
+			payment: testPayment("SEPA", "10.00", "EUR"),
+			ack:     accepted,
+		},
+		{
+			name: "Rejected by rule",
+			payment: func() *domain.Payment {
+				p := testPayment("SEPA", "10.00", "EUR")
+				p.Creditor.AccountNumber = "DE89370400440532013000"
+				return p
+			}(),
+			ack: &gateway.Ack{Reason: "Unknown creditor"},
+		},
+		{
+			name:    "Rejected after delay",
+			payment: testPayment("SWIFT", "1000.00", "GBP"),
+			ack:     &gateway.Ack{Reason: "Limit exceeded"},
+		},
+		{
+			name:    "Accepted after delay",
+			payment: testPayment("SWIFT", "999.99", "GBP"),
+			ack:     accepted,
+		},
+		{
+			name:    "Delay exceeding timeout",
+			payment: testPayment("SWIFT", "10.00", "GBP"),
+			timeout: time.Millisecond,
+			err:     true,
+		},
+		{
+			name:    "Accepted without matching rule",
+			payment: testPayment("FPS", "10.00", "GBP"),
+			ack:     accepted,
+		},
+	}
+
+	rules := testRules(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(rules...)
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			ack, err := s.Send(ctx, tc.payment)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, have <nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(tc.ack, ack) {
+				t.Fatalf("unexpected acknowledgement: %v", cmp.Diff(tc.ack, ack))
+			}
+		})
+	}
+}
+
+func TestSimulator_Handler(t *testing.T) {
+	server := httptest.NewServer(New(testRules(t)...).Handler())
+	defer server.Close()
+
+	gw := gateway.NewHTTP(server.URL)
+
+	ack, err := gw.Send(context.Background(), testPayment("SWIFT", "1000.00", "GBP"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (&gateway.Ack{Reason: "Limit exceeded"}); !cmp.Equal(want, ack) {
+		t.Fatalf("unexpected acknowledgement: %v", cmp.Diff(want, ack))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	delayed := testPayment("SWIFT", "10.00", "GBP")
+	delayed.ID = domain.NewID()
+	_, err = gw.Send(ctx, delayed)
+	if err == nil {
+		t.Fatalf("expected error, have <nil>")
+	}
+}
+
+func TestSimulator_SendAgain(t *testing.T) {
+	server := httptest.NewServer(New(testRules(t)...).Handler())
+	defer server.Close()
+
+	gw := gateway.NewHTTP(server.URL)
+
+	payment := testPayment("SEPA", "10.00", "EUR")
+	first, err := gw.Send(context.Background(), payment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The payment sent again is given the first answer, even though it
+	// would be decided differently by now.
+	payment.Creditor.AccountNumber = "DE89370400440532013000"
+	again, err := gw.Send(context.Background(), payment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(first, again) {
+		t.Fatalf("unexpected acknowledgement: %v", cmp.Diff(first, again))
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	testCases := []struct {
+		name  string
+		in    string
+		valid bool
+	}{
+		{name: "Valid rules", in: `[{"action": "accept"}, {"action": "delay", "delay": "2s"}]`, valid: true},
+		{name: "Unknown action", in: `[{"action": "bounce"}]`},
+		{name: "Invalid delay", in: `[{"action": "delay", "delay": "soon"}]`},
+		{name: "Invalid amount", in: `[{"action": "reject", "min_amount": "lots"}]`},
+		{name: "Not an array", in: `{"action": "accept"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadRules(strings.NewReader(tc.in))
+			if tc.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("expected error, have <nil>")
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ActorHeader carries the name of the authenticated user and RolesHeader the
+// comma separated roles granted to the user. The server itself does not
+// authenticate, it relies on the gateway in front of it to set the headers,
+// and to strip them from the client requests.
+const (
+	ActorHeader = "X-Actor"
+	RolesHeader = "X-Actor-Roles"
+)
+
+const RoleAdmin = "admin"
+
+const anonymous = "anonymous"
+
+type Principal struct {
+	Name  string
+	Roles []string
+}
+
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+func FromContext(ctx context.Context) Principal {
+	p, ok := ctx.Value(contextKey{}).(Principal)
+	if !ok || p.Name == "" {
+		return Principal{Name: anonymous}
+	}
+	return p
+}
+
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := Principal{Name: r.Header.Get(ActorHeader)}
+		for _, role := range strings.Split(r.Header.Get(RolesHeader), ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				p.Roles = append(p.Roles, role)
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+	})
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMiddleware(t *testing.T) {
+	testCases := []struct {
+		name   string
+		header http.Header
+		out    Principal
+	}{
+		{
+			name:   "Authenticated actor",
+			header: http.Header{"X-Actor": []string{"jane.doe"}},
+			out:    Principal{Name: "jane.doe"},
+		},
+		{
+			name:   "Authenticated actor with roles",
+			header: http.Header{"X-Actor": []string{"jane.doe"}, "X-Actor-Roles": []string{"admin, auditor,"}},
+			out:    Principal{Name: "jane.doe", Roles: []string{"admin", "auditor"}},
+		},
+		{
+			name:   "Anonymous actor",
+			header: http.Header{"X-Actor-Roles": []string{"admin"}},
+			out:    Principal{Name: "anonymous"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out Principal
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				out = FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header = tc.header
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if want, have := tc.out, out; !cmp.Equal(want, have) {
+				t.Fatalf("unexpected principal: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
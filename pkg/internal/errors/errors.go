@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type errorCategory string
 
@@ -15,10 +18,14 @@ func (c errorCodeGeneric) code() string   { return string(c) }
 func (c errorCodeGeneric) String() string { return c.code() }
 
 const (
-	ErrCodeGenericAlreadyExists   = errorCodeGeneric("ALREADY_EXISTS")
-	ErrCodeGenericInvalidArgument = errorCodeGeneric("INVALID_ARGUMENT")
-	ErrCodeGenericInternal        = errorCodeGeneric("INTERNAL")
-	ErrCodeGenericNotFound        = errorCodeGeneric("NOT_FOUND")
+	ErrCodeGenericAlreadyExists      = errorCodeGeneric("ALREADY_EXISTS")
+	ErrCodeGenericInvalidArgument    = errorCodeGeneric("INVALID_ARGUMENT")
+	ErrCodeGenericInvalidState       = errorCodeGeneric("INVALID_STATE")
+	ErrCodeGenericInternal           = errorCodeGeneric("INTERNAL")
+	ErrCodeGenericNotFound           = errorCodeGeneric("NOT_FOUND")
+	ErrCodeGenericPermissionDenied   = errorCodeGeneric("PERMISSION_DENIED")
+	ErrCodeGenericPreconditionFailed = errorCodeGeneric("PRECONDITION_FAILED")
+	ErrCodeGenericUnprocessable      = errorCodeGeneric("UNPROCESSABLE")
 )
 
 type errorCodeDataAccess string
@@ -27,12 +34,19 @@ func (c errorCodeDataAccess) code() string   { return string(c) }
 func (c errorCodeDataAccess) String() string { return c.code() }
 
 const (
-	ErrCodeDataAccessInsertFailed = errorCodeDataAccess("INSERT_FAILED")
-	ErrCodeDataAccessSelectFailed = errorCodeDataAccess("SELECT_FAILED")
-	ErrCodeDataAccessDeleteFailed = errorCodeDataAccess("DELETE_FAILED")
-	ErrCodeDataAccessUpdateFailed = errorCodeDataAccess("UPDATE_FAILED")
+	ErrCodeDataAccessInsertFailed   = errorCodeDataAccess("INSERT_FAILED")
+	ErrCodeDataAccessSelectFailed   = errorCodeDataAccess("SELECT_FAILED")
+	ErrCodeDataAccessDeleteFailed   = errorCodeDataAccess("DELETE_FAILED")
+	ErrCodeDataAccessUpdateFailed   = errorCodeDataAccess("UPDATE_FAILED")
+	ErrCodeDataAccessCommitFailed   = errorCodeDataAccess("COMMIT_FAILED")
+	ErrCodeDataAccessRollbackFailed = errorCodeDataAccess("ROLLBACK_FAILED")
+	ErrCodeDataAccessConflict       = errorCodeDataAccess("CONFLICT")
 )
 
+// ExtraPointer is the key of the JSON pointer to the request document value
+// which caused the error.
+const ExtraPointer = "pointer"
+
 type Error struct {
 	Category errorCategory          `json:"category"`
 	Code     Code                   `json:"code"`
@@ -70,6 +84,46 @@ func DataAccess(code errorCodeDataAccess, msg string, detail string, extra ...ma
 	}
 }
 
+// Multi is a list of errors reported at once, e.g. all the violations found
+// while validating a request document.
+type Multi []Error
+
+func (m Multi) Error() string {
+	messages := make([]string, len(m))
+	for i, e := range m {
+		messages[i] = e.Error()
+		if e.Detail != "" {
+			messages[i] += ": " + e.Detail
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Err returns nil if there are no errors, otherwise the errors themselves.
+func (m Multi) Err() error {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Is reports whether err is an Error with the given code, or a non-empty
+// Multi made of such errors only.
+func Is(err error, code Code) bool {
+	switch err := err.(type) {
+	case Error:
+		return err.Code == code
+	case Multi:
+		for _, e := range err {
+			if e.Code != code {
+				return false
+			}
+		}
+		return len(err) > 0
+	}
+	return false
+}
+
 func mergeMaps(extras ...map[string]interface{}) map[string]interface{} {
 	var merged map[string]interface{}
 	if len(extras) != 0 {
@@ -0,0 +1,16 @@
+package mock
+
+import (
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+type CalendarStore struct {
+	GetFn      func(store.Tx, string) (*domain.Calendar, error)
+	GetInvoked bool
+}
+
+func (s *CalendarStore) Get(tx store.Tx, scheme string) (*domain.Calendar, error) {
+	s.GetInvoked = true
+	return s.GetFn(tx, scheme)
+}
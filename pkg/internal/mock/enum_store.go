@@ -8,9 +8,41 @@ import (
 type EnumStore struct {
 	ExistsFn      func(store.Tx, domain.EnumName, string) (bool, error)
 	ExistsInvoked bool
+
+	FindFn      func(store.Tx, domain.EnumName, domain.EnumSearchRequest) ([]domain.Enum, error)
+	FindInvoked bool
+
+	GetCurrencyFn      func(store.Tx, string) (*domain.Currency, error)
+	GetCurrencyInvoked bool
+
+	InsertFn      func(store.Tx, domain.EnumName, domain.Enum) error
+	InsertInvoked bool
+
+	UpdateFn      func(store.Tx, domain.EnumName, domain.Enum) error
+	UpdateInvoked bool
 }
 
 func (s *EnumStore) Exists(tx store.Tx, name domain.EnumName, code string) (bool, error) {
 	s.ExistsInvoked = true
 	return s.ExistsFn(tx, name, code)
 }
+
+func (s *EnumStore) Find(tx store.Tx, name domain.EnumName, req domain.EnumSearchRequest) ([]domain.Enum, error) {
+	s.FindInvoked = true
+	return s.FindFn(tx, name, req)
+}
+
+func (s *EnumStore) GetCurrency(tx store.Tx, code string) (*domain.Currency, error) {
+	s.GetCurrencyInvoked = true
+	return s.GetCurrencyFn(tx, code)
+}
+
+func (s *EnumStore) Insert(tx store.Tx, name domain.EnumName, enum domain.Enum) error {
+	s.InsertInvoked = true
+	return s.InsertFn(tx, name, enum)
+}
+
+func (s *EnumStore) Update(tx store.Tx, name domain.EnumName, enum domain.Enum) error {
+	s.UpdateInvoked = true
+	return s.UpdateFn(tx, name, enum)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+type IdempotencyStore struct {
+	GetFn      func(store.Tx, string, string) (*domain.IdempotencyKey, error)
+	GetInvoked bool
+
+	InsertFn      func(store.Tx, *domain.IdempotencyKey) error
+	InsertInvoked bool
+
+	DeleteFn      func(store.Tx, string, string) error
+	DeleteInvoked bool
+}
+
+func (s *IdempotencyStore) Get(tx store.Tx, actor, key string) (*domain.IdempotencyKey, error) {
+	s.GetInvoked = true
+	return s.GetFn(tx, actor, key)
+}
+
+func (s *IdempotencyStore) Insert(tx store.Tx, k *domain.IdempotencyKey) error {
+	s.InsertInvoked = true
+	return s.InsertFn(tx, k)
+}
+
+func (s *IdempotencyStore) Delete(tx store.Tx, actor, key string) error {
+	s.DeleteInvoked = true
+	return s.DeleteFn(tx, actor, key)
+}
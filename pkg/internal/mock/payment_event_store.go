@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+type PaymentEventStore struct {
+	InsertFn      func(store.Tx, *domain.PaymentEvent) error
+	InsertInvoked bool
+
+	ClaimPendingFn      func(store.Tx, time.Time, int) ([]*domain.PaymentEvent, error)
+	ClaimPendingInvoked bool
+
+	AssignPositionsFn      func(store.Tx, int) (int, error)
+	AssignPositionsInvoked bool
+
+	FindAfterFn      func(store.Tx, int64, int) ([]*domain.PaymentEvent, error)
+	FindAfterInvoked bool
+
+	LastPositionFn      func(store.Tx) (int64, error)
+	LastPositionInvoked bool
+
+	UpdatePublicationFn      func(store.Tx, *domain.PaymentEvent) error
+	UpdatePublicationInvoked bool
+}
+
+func (s *PaymentEventStore) Insert(tx store.Tx, e *domain.PaymentEvent) error {
+	s.InsertInvoked = true
+	return s.InsertFn(tx, e)
+}
+
+func (s *PaymentEventStore) ClaimPending(tx store.Tx, now time.Time, limit int) ([]*domain.PaymentEvent, error) {
+	s.ClaimPendingInvoked = true
+	return s.ClaimPendingFn(tx, now, limit)
+}
+
+func (s *PaymentEventStore) AssignPositions(tx store.Tx, limit int) (int, error) {
+	s.AssignPositionsInvoked = true
+	return s.AssignPositionsFn(tx, limit)
+}
+
+func (s *PaymentEventStore) FindAfter(tx store.Tx, position int64, limit int) ([]*domain.PaymentEvent, error) {
+	s.FindAfterInvoked = true
+	return s.FindAfterFn(tx, position, limit)
+}
+
+func (s *PaymentEventStore) LastPosition(tx store.Tx) (int64, error) {
+	s.LastPositionInvoked = true
+	return s.LastPositionFn(tx)
+}
+
+func (s *PaymentEventStore) UpdatePublication(tx store.Tx, e *domain.PaymentEvent) error {
+	s.UpdatePublicationInvoked = true
+	return s.UpdatePublicationFn(tx, e)
+}
@@ -0,0 +1,24 @@
+package mock
+
+import (
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+type PaymentHistoryStore struct {
+	FindFn      func(store.Tx, domain.ID) ([]*domain.PaymentHistory, error)
+	FindInvoked bool
+
+	InsertFn      func(store.Tx, *domain.PaymentHistory) error
+	InsertInvoked bool
+}
+
+func (s *PaymentHistoryStore) Find(tx store.Tx, id domain.ID) ([]*domain.PaymentHistory, error) {
+	s.FindInvoked = true
+	return s.FindFn(tx, id)
+}
+
+func (s *PaymentHistoryStore) Insert(tx store.Tx, h *domain.PaymentHistory) error {
+	s.InsertInvoked = true
+	return s.InsertFn(tx, h)
+}
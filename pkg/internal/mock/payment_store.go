@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"time"
+
 	"github.com/michaljemala/payments-sample/pkg/domain"
 	"github.com/michaljemala/payments-sample/pkg/internal/store"
 )
@@ -18,11 +20,23 @@ type PaymentStore struct {
 	InsertFn      func(store.Tx, *domain.Payment) error
 	InsertInvoked bool
 
-	DeleteFn      func(store.Tx, domain.ID) error
+	DeleteFn      func(store.Tx, domain.ID, uint, time.Time) error
 	DeleteInvoked bool
 
+	RestoreFn      func(store.Tx, *domain.Payment) error
+	RestoreInvoked bool
+
 	UpdateFn      func(store.Tx, *domain.Payment) error
 	UpdateInvoked bool
+
+	ClaimDueFn      func(store.Tx, time.Time) (*domain.Payment, error)
+	ClaimDueInvoked bool
+
+	ClaimSubmittedFn      func(store.Tx, time.Time) (*domain.Payment, error)
+	ClaimSubmittedInvoked bool
+
+	UpdateExecutionFn      func(store.Tx, *domain.Payment) error
+	UpdateExecutionInvoked bool
 }
 
 func (s *PaymentStore) Count(tx store.Tx, r domain.PaymentSearchRequest) (uint, error) {
@@ -45,12 +59,32 @@ func (s *PaymentStore) Insert(tx store.Tx, p *domain.Payment) error {
 	return s.InsertFn(tx, p)
 }
 
-func (s *PaymentStore) Delete(tx store.Tx, id domain.ID) error {
+func (s *PaymentStore) Delete(tx store.Tx, id domain.ID, version uint, deletedAt time.Time) error {
 	s.DeleteInvoked = true
-	return s.DeleteFn(tx, id)
+	return s.DeleteFn(tx, id, version, deletedAt)
+}
+
+func (s *PaymentStore) Restore(tx store.Tx, p *domain.Payment) error {
+	s.RestoreInvoked = true
+	return s.RestoreFn(tx, p)
 }
 
 func (s *PaymentStore) Update(tx store.Tx, p *domain.Payment) error {
 	s.UpdateInvoked = true
 	return s.UpdateFn(tx, p)
 }
+
+func (s *PaymentStore) ClaimDue(tx store.Tx, now time.Time) (*domain.Payment, error) {
+	s.ClaimDueInvoked = true
+	return s.ClaimDueFn(tx, now)
+}
+
+func (s *PaymentStore) ClaimSubmitted(tx store.Tx, now time.Time) (*domain.Payment, error) {
+	s.ClaimSubmittedInvoked = true
+	return s.ClaimSubmittedFn(tx, now)
+}
+
+func (s *PaymentStore) UpdateExecution(tx store.Tx, p *domain.Payment) error {
+	s.UpdateExecutionInvoked = true
+	return s.UpdateExecutionFn(tx, p)
+}
@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+type WebhookSubscriptionStore struct {
+	FindFn      func(store.Tx) ([]*domain.WebhookSubscription, error)
+	FindInvoked bool
+
+	GetFn      func(store.Tx, domain.ID) (*domain.WebhookSubscription, error)
+	GetInvoked bool
+
+	InsertFn      func(store.Tx, *domain.WebhookSubscription) error
+	InsertInvoked bool
+
+	UpdateFn      func(store.Tx, *domain.WebhookSubscription) error
+	UpdateInvoked bool
+
+	DeleteFn      func(store.Tx, domain.ID, time.Time) error
+	DeleteInvoked bool
+}
+
+func (s *WebhookSubscriptionStore) Find(tx store.Tx) ([]*domain.WebhookSubscription, error) {
+	s.FindInvoked = true
+	return s.FindFn(tx)
+}
+
+func (s *WebhookSubscriptionStore) Get(tx store.Tx, id domain.ID) (*domain.WebhookSubscription, error) {
+	s.GetInvoked = true
+	return s.GetFn(tx, id)
+}
+
+func (s *WebhookSubscriptionStore) Insert(tx store.Tx, sub *domain.WebhookSubscription) error {
+	s.InsertInvoked = true
+	return s.InsertFn(tx, sub)
+}
+
+func (s *WebhookSubscriptionStore) Update(tx store.Tx, sub *domain.WebhookSubscription) error {
+	s.UpdateInvoked = true
+	return s.UpdateFn(tx, sub)
+}
+
+func (s *WebhookSubscriptionStore) Delete(tx store.Tx, id domain.ID, deletedAt time.Time) error {
+	s.DeleteInvoked = true
+	return s.DeleteFn(tx, id, deletedAt)
+}
@@ -0,0 +1,34 @@
+package resource
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+)
+
+// Cursor points to an item of a sorted collection using the values of the
+// item's sort fields, the id tie-breaker included, so paging through the
+// collection is not affected by inserts and deletes of other items.
+type Cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+func (c Cursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func ParseCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid cursor", err.Error())
+	}
+	var c Cursor
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return nil, errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid cursor", err.Error())
+	}
+	return &c, nil
+}
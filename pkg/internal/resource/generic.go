@@ -1,32 +1,40 @@
 package resource
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 )
 
 var (
-	filterParamPattern     = regexp.MustCompile(`^filter\[([\w._]+)\]$`)
-	paginationParamPattern = regexp.MustCompile(`^page\[([number|size]+)\]$`)
+	filterParamPattern     = regexp.MustCompile(`^filter\[([\w._]+)\](?:\[(\w+)\])?$`)
+	paginationParamPattern = regexp.MustCompile(`^page\[(\w+)\]$`)
 )
 
+const sortParam = "sort"
+
 type Generic struct {
-	ParamFunc func(key string, values []string) (interface{}, error)
+	ParamFunc  func(key string, op FilterOperator, values []string) (interface{}, error)
+	SortFields []string
 }
 
 func (r *Generic) ExtractSearchFilter(searchFilterParams map[string][]string) (SearchFilter, error) {
 	filter := make(SearchFilter)
 
 	for key, values := range searchFilterParams {
+		if key == sortParam {
+			continue
+		}
 		tokens := paginationParamPattern.FindStringSubmatch(key)
 		if len(tokens) == 2 {
 			continue
 		}
 
 		tokens = filterParamPattern.FindStringSubmatch(key)
-		if len(tokens) != 2 {
+		if len(tokens) != 3 {
 			return nil, errors.Generic(
 				errors.ErrCodeGenericInvalidArgument,
 				"invalid query parameter",
@@ -35,21 +43,142 @@ func (r *Generic) ExtractSearchFilter(searchFilterParams map[string][]string) (S
 		}
 		key = tokens[1]
 
-		extracted, err := r.ParamFunc(key, values)
+		op := FilterOperatorEq
+		if tokens[2] != "" {
+			op = FilterOperator(tokens[2])
+		}
+		if !op.IsValid() {
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				"unsupported filter operator",
+				fmt.Sprintf("%s: %s", key, op),
+			)
+		}
+		if op != FilterOperatorEq {
+			// Only equality matches a list of values, the others compare
+			// against a single one which might contain a comma itself.
+			values = []string{strings.Join(values, ",")}
+		}
+
+		extracted, err := r.ParamFunc(key, op, values)
 		if err != nil {
 			return nil, errors.Generic(
 				errors.ErrCodeGenericInvalidArgument,
 				"invalid filter parameter",
-				key,
+				FilterKey(key, op),
 			)
 		}
 
+		key = FilterKey(key, op)
 		filter[key] = extracted
 	}
 
 	return filter, nil
 }
 
+func (r *Generic) ExtractSort(queryParams map[string][]string) (SearchSort, error) {
+	values, ok := queryParams[sortParam]
+	if !ok {
+		return nil, nil
+	}
+
+	var (
+		sort SearchSort
+		seen = make(map[string]bool)
+	)
+	for _, value := range values {
+		field := SortField{Field: strings.TrimPrefix(value, "-")}
+		field.Descending = field.Field != value
+
+		if !r.isSortable(field.Field) || seen[field.Field] {
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				"unsupported sort parameter",
+				value,
+			)
+		}
+		seen[field.Field] = true
+
+		sort = append(sort, field)
+	}
+	return sort, nil
+}
+
+func (r *Generic) isSortable(field string) bool {
+	for _, f := range r.SortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractCursor returns the cursor pagination if any of the cursors or just
+// the page size is requested, otherwise nil.
+func (r *Generic) ExtractCursor(paginationParams map[string]string, sort SearchSort) (*SearchCursor, error) {
+	for key := range paginationParams {
+		switch key {
+		case "after", "before", "size", "number":
+		default:
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				"unsupported paging parameter",
+				key,
+			)
+		}
+	}
+
+	_, after := paginationParams["after"]
+	_, before := paginationParams["before"]
+	_, size := paginationParams["size"]
+	if !after && !before && !size {
+		return nil, nil
+	}
+	if after && before {
+		return nil, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"conflicting paging parameters",
+			"after, before",
+		)
+	}
+
+	cursor := &SearchCursor{Size: defaultPageSize}
+	for key, value := range paginationParams {
+		switch key {
+		case "after", "before":
+			c, err := ParseCursor(value)
+			if err != nil {
+				return nil, err
+			}
+			if c.Sort != sort.String() {
+				return nil, errors.Generic(
+					errors.ErrCodeGenericInvalidArgument,
+					"invalid paging parameter",
+					fmt.Sprintf("%s: cursor does not match the sort", key),
+				)
+			}
+			if key == "after" {
+				cursor.After = c
+			} else {
+				cursor.Before = c
+			}
+		case "size":
+			size, err := parsePageSize(key, value)
+			if err != nil {
+				return nil, err
+			}
+			cursor.Size = uint(size)
+		default:
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				"unsupported paging parameter",
+				key,
+			)
+		}
+	}
+	return cursor, nil
+}
+
 func (r *Generic) ExtractPagination(paginationParams map[string]string) (*SearchPagination, error) {
 	var (
 		page, size uint64
@@ -67,13 +196,9 @@ func (r *Generic) ExtractPagination(paginationParams map[string]string) (*Search
 				)
 			}
 		case "size":
-			size, err = strconv.ParseUint(value, 0, 0)
-			if err != nil || size == 0 {
-				return nil, errors.Generic(
-					errors.ErrCodeGenericInvalidArgument,
-					"invalid paging parameter",
-					key,
-				)
+			size, err = parsePageSize(key, value)
+			if err != nil {
+				return nil, err
 			}
 		default:
 			return nil, errors.Generic(
@@ -85,3 +210,24 @@ func (r *Generic) ExtractPagination(paginationParams map[string]string) (*Search
 	}
 	return NewSearchPagination(uint(page), uint(size)), nil
 }
+
+// parsePageSize bounds the page size of both the pagers, so a single request
+// can not scan the whole store.
+func parsePageSize(key, value string) (uint64, error) {
+	size, err := strconv.ParseUint(value, 0, 0)
+	if err != nil || size == 0 {
+		return 0, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid paging parameter",
+			key,
+		)
+	}
+	if size > maxPageSize {
+		return 0, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid paging parameter",
+			fmt.Sprintf("%s: must not be greater than %d", key, maxPageSize),
+		)
+	}
+	return size, nil
+}
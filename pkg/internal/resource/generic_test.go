@@ -12,14 +12,14 @@ import (
 func TestGeneric_ExtractSearchFilter(t *testing.T) {
 	testCases := []struct {
 		name         string
-		paramFunc    func(string, []string) (interface{}, error)
+		paramFunc    func(string, FilterOperator, []string) (interface{}, error)
 		filterParams map[string][]string
 		errFunc      func(*testing.T, error)
 		searchFilter SearchFilter
 	}{
 		{
 			name: "Supported filter parameter",
-			paramFunc: func(key string, values []string) (interface{}, error) {
+			paramFunc: func(key string, op FilterOperator, values []string) (interface{}, error) {
 				if key == "foo" {
 					return values, nil
 				}
@@ -32,9 +32,57 @@ func TestGeneric_ExtractSearchFilter(t *testing.T) {
 				"foo": []string{"bar"},
 			},
 		},
+		{
+			name: "Filter parameter with operator",
+			paramFunc: func(key string, op FilterOperator, values []string) (interface{}, error) {
+				if key == "foo" && op == FilterOperatorPrefix {
+					return values[0], nil
+				}
+				return nil, errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid search filter", "")
+			},
+			filterParams: map[string][]string{
+				"filter[foo][prefix]": {"bar", "baz"},
+			},
+			searchFilter: SearchFilter{
+				"foo[prefix]": "bar,baz",
+			},
+		},
+		{
+			name: "Explicit equality operator",
+			paramFunc: func(key string, op FilterOperator, values []string) (interface{}, error) {
+				return values, nil
+			},
+			filterParams: map[string][]string{
+				"filter[foo][eq]": {"bar", "baz"},
+			},
+			searchFilter: SearchFilter{
+				"foo": []string{"bar", "baz"},
+			},
+		},
+		{
+			name: "Unsupported filter operator",
+			paramFunc: func(key string, op FilterOperator, values []string) (interface{}, error) {
+				return values, nil
+			},
+			filterParams: map[string][]string{
+				"filter[foo][UNKNOWN]": {"bar"},
+			},
+			errFunc: func(t *testing.T, err error) {
+				switch err := err.(type) {
+				case errors.Error:
+					if want, have := errors.ErrCodeGenericInvalidArgument, err.Code; want != have {
+						t.Fatalf("unexpected error code: want %s, have %s", want, have)
+					}
+				case nil:
+					t.Fatal("expected error, have <nil")
+				default:
+					t.Fatalf("unexpected error: (%T)%v", err, err)
+				}
+			},
+		},
 		{
 			name: "Unsupported filter parameter",
-			paramFunc: func(key string, values []string) (interface{}, error) {
+			paramFunc: func(key string, op FilterOperator, values []string) (interface{}, error) {
 				return nil, fmt.Errorf("some error")
 			},
 			filterParams: map[string][]string{
@@ -55,7 +103,7 @@ func TestGeneric_ExtractSearchFilter(t *testing.T) {
 		},
 		{
 			name: "Invalid filter parameter",
-			paramFunc: func(key string, values []string) (interface{}, error) {
+			paramFunc: func(key string, op FilterOperator, values []string) (interface{}, error) {
 				return nil, fmt.Errorf("some error")
 			},
 			filterParams: map[string][]string{
@@ -93,6 +141,164 @@ func TestGeneric_ExtractSearchFilter(t *testing.T) {
 	}
 }
 
+func TestGeneric_ExtractSort(t *testing.T) {
+	testCases := []struct {
+		name        string
+		queryParams map[string][]string
+		errFunc     func(*testing.T, error)
+		sort        SearchSort
+	}{
+		{
+			name:        "No sort parameter",
+			queryParams: map[string][]string{"filter[foo]": {"bar"}},
+		},
+		{
+			name:        "Supported sort fields",
+			queryParams: map[string][]string{"sort": {"-foo", "bar"}},
+			sort: SearchSort{
+				{Field: "foo", Descending: true},
+				{Field: "bar"},
+			},
+		},
+		{
+			name:        "Unsupported sort field",
+			queryParams: map[string][]string{"sort": {"foo", "UNKNOWN"}},
+			errFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:        "Duplicate sort field",
+			queryParams: map[string][]string{"sort": {"foo", "-foo"}},
+			errFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:        "Empty sort field",
+			queryParams: map[string][]string{"sort": {""}},
+			errFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &Generic{SortFields: []string{"foo", "bar"}}
+			sort, err := g.ExtractSort(tc.queryParams)
+			if err != nil {
+				if tc.errFunc == nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				tc.errFunc(t, err)
+			}
+			if want, have := tc.sort, sort; !cmp.Equal(want, have) {
+				t.Fatalf("invalid sort: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+func TestGeneric_ExtractCursor(t *testing.T) {
+	sort := SearchSort{{Field: "foo", Descending: true}}
+	cursor := Cursor{Sort: "-foo", Values: []string{"bar", "1"}}
+
+	testCases := []struct {
+		name             string
+		paginationParams map[string]string
+		errFunc          func(*testing.T, error)
+		cursor           *SearchCursor
+	}{
+		{
+			name:             "No cursor parameters",
+			paginationParams: map[string]string{"number": "2"},
+		},
+		{
+			name:             "First page",
+			paginationParams: map[string]string{"size": "10"},
+			cursor:           &SearchCursor{Size: 10},
+		},
+		{
+			name:             "Page after cursor",
+			paginationParams: map[string]string{"after": cursor.String()},
+			cursor:           &SearchCursor{After: &cursor, Size: defaultPageSize},
+		},
+		{
+			name:             "Page before cursor",
+			paginationParams: map[string]string{"before": cursor.String(), "size": "5"},
+			cursor:           &SearchCursor{Before: &cursor, Size: 5},
+		},
+		{
+			name:             "Page size above maximum",
+			paginationParams: map[string]string{"size": "501"},
+			errFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:             "Both cursors",
+			paginationParams: map[string]string{"after": cursor.String(), "before": cursor.String()},
+			errFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:             "Malformed cursor",
+			paginationParams: map[string]string{"after": "I_AM_NOT_VALID"},
+			errFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:             "Cursor of different sort",
+			paginationParams: map[string]string{"after": Cursor{Sort: "foo", Values: []string{"bar", "1"}}.String()},
+			errFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:             "Cursor with page number",
+			paginationParams: map[string]string{"after": cursor.String(), "number": "2"},
+			errFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &Generic{}
+			cursor, err := g.ExtractCursor(tc.paginationParams, sort)
+			if err != nil {
+				if tc.errFunc == nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				tc.errFunc(t, err)
+			}
+			if want, have := tc.cursor, cursor; !cmp.Equal(want, have) {
+				t.Fatalf("invalid cursor: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
 func TestGeneric_ExtractPagination(t *testing.T) {
 	testCases := []struct {
 		name             string
@@ -129,6 +335,17 @@ func TestGeneric_ExtractPagination(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Page size above maximum",
+			paginationParams: map[string]string{
+				"size": "501",
+			},
+			errFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
 		{
 			name: "Invalid pagination parameters",
 			paginationParams: map[string]string{
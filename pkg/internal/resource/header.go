@@ -0,0 +1,123 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/manyminds/api2go"
+
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+)
+
+const responseHeaderKey = "resource.responseHeader"
+
+// HeaderMiddleware makes the response headers available to the resources,
+// api2go does not expose the response writer to them otherwise.
+func HeaderMiddleware(c api2go.APIContexter, w http.ResponseWriter, r *http.Request) {
+	c.Set(responseHeaderKey, w.Header())
+}
+
+func SetHeader(req api2go.Request, key, value string) {
+	if req.Context == nil {
+		return
+	}
+	v, ok := req.Context.Get(responseHeaderKey)
+	if !ok {
+		return
+	}
+	header, ok := v.(http.Header)
+	if !ok {
+		return
+	}
+	header.Set(key, value)
+}
+
+func ETag(version uint) string {
+	return fmt.Sprintf("%q", strconv.FormatUint(uint64(version), 10))
+}
+
+// ContentETag returns a strong ETag derived from the JSON encoding of v, for
+// the resources which are not versioned.
+func ContentETag(v interface{}) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%q", hex.EncodeToString(sum[:16]))
+}
+
+// ParseIfMatch extracts the version expected by the If-Match header.
+// Zero is returned when the header is missing or matches any version.
+func ParseIfMatch(header http.Header) (uint, error) {
+	value := strings.TrimSpace(header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	invalid := errors.Generic(
+		errors.ErrCodeGenericInvalidArgument,
+		"invalid If-Match header",
+		value,
+	)
+
+	if strings.HasPrefix(value, "W/") || len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, invalid
+	}
+	version, err := strconv.ParseUint(value[1:len(value)-1], 10, 0)
+	if err != nil || version == 0 {
+		return 0, invalid
+	}
+	return uint(version), nil
+}
+
+// NotModifiedMiddleware answers the conditional GET requests with 304 Not
+// Modified whenever the ETag of the response matches the If-None-Match header.
+func NotModifiedMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch := r.Header.Get("If-None-Match")
+		if ifNoneMatch == "" || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponseWriter{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		if buf.status == http.StatusOK && etagMatches(ifNoneMatch, w.Header().Get("ETag")) {
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(buf.status)
+		_, _ = w.Write(buf.body.Bytes())
+	})
+}
+
+// etagMatches compares the ETags weakly as required for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header         { return w.header }
+func (w *bufferedResponseWriter) WriteHeader(status int)      { w.status = status }
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
@@ -0,0 +1,121 @@
+package resource
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/manyminds/api2go"
+
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      string
+		out     uint
+		errFunc func(*testing.T, error)
+	}{
+		{name: "Missing header", in: "", out: 0},
+		{name: "Any version", in: "*", out: 0},
+		{name: "Strong validator", in: `"42"`, out: 42},
+		{name: "Weak validator", in: `W/"42"`, errFunc: assertInvalidArgument},
+		{name: "Unquoted validator", in: "42", errFunc: assertInvalidArgument},
+		{name: "Multiple validators", in: `"1", "2"`, errFunc: assertInvalidArgument},
+		{name: "Zero version", in: `"0"`, errFunc: assertInvalidArgument},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := make(http.Header)
+			if tc.in != "" {
+				header.Set("If-Match", tc.in)
+			}
+
+			version, err := ParseIfMatch(header)
+			if err != nil {
+				if tc.errFunc == nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				tc.errFunc(t, err)
+				return
+			}
+			if want, have := tc.out, version; want != have {
+				t.Fatalf("invalid version: want %d, have %d", want, have)
+			}
+		})
+	}
+}
+
+func TestSetHeader(t *testing.T) {
+	header := make(http.Header)
+	ctx := &api2go.APIContext{}
+	ctx.Set(responseHeaderKey, header)
+
+	SetHeader(api2go.Request{Context: ctx}, "ETag", ETag(3))
+
+	if want, have := `"3"`, header.Get("ETag"); want != have {
+		t.Fatalf("invalid header: want %s, have %s", want, have)
+	}
+}
+
+func assertInvalidArgument(t *testing.T, err error) {
+	switch err := err.(type) {
+	case errors.Error:
+		if want, have := errors.ErrCodeGenericInvalidArgument, err.Code; want != have {
+			t.Fatalf("unexpected error code: want %s, have %s", want, have)
+		}
+	default:
+		t.Fatalf("unexpected error: (%T)%v", err, err)
+	}
+}
+
+func TestNotModifiedMiddleware(t *testing.T) {
+	etag := ContentETag([]string{"EUR", "GBP"})
+	handler := NotModifiedMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+
+	testCases := []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		status      int
+		body        string
+	}{
+		{name: "Unconditional request", method: "GET", status: http.StatusOK, body: `{"data":[]}`},
+		{name: "Matching ETag", method: "GET", ifNoneMatch: etag, status: http.StatusNotModified},
+		{name: "Matching weak ETag", method: "GET", ifNoneMatch: `"x", W/` + etag, status: http.StatusNotModified},
+		{name: "Any ETag", method: "GET", ifNoneMatch: "*", status: http.StatusNotModified},
+		{name: "Stale ETag", method: "GET", ifNoneMatch: `"x"`, status: http.StatusOK, body: `{"data":[]}`},
+		{name: "Not a GET request", method: "POST", ifNoneMatch: etag, status: http.StatusOK, body: `{"data":[]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/currencies", nil)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
+
+			if want, have := tc.status, resp.StatusCode; want != have {
+				t.Fatalf("invalid status: want %d, have %d", want, have)
+			}
+			if want, have := etag, resp.Header.Get("ETag"); want != have {
+				t.Fatalf("invalid etag: want %s, have %s", want, have)
+			}
+			body, _ := ioutil.ReadAll(resp.Body)
+			if want, have := tc.body, string(body); want != have {
+				t.Fatalf("invalid body: want %q, have %q", want, have)
+			}
+		})
+	}
+}
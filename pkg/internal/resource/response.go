@@ -1,10 +1,12 @@
 package resource
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
 	"github.com/manyminds/api2go"
+	"github.com/manyminds/api2go/jsonapi"
 
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 )
@@ -17,6 +19,43 @@ func WrapError(err error) api2go.HTTPError {
 }
 
 func translateError(err error) ([]api2go.Error, int) {
+	if multi, ok := err.(errors.Multi); ok && len(multi) > 0 {
+		return translateMultiError(multi)
+	}
+
+	translated, status := translateErrorCode(err)
+	if err, ok := err.(errors.Error); ok {
+		if pointer, ok := err.Extra[errors.ExtraPointer].(string); ok {
+			for i := range translated {
+				translated[i].Source = &api2go.ErrorSource{Pointer: pointer}
+			}
+		}
+	}
+	return translated, status
+}
+
+// translateMultiError renders every error as a separate JSON:API error. The
+// response status is shared by all the errors, if they differ the most
+// general one of their class is used instead.
+func translateMultiError(multi errors.Multi) ([]api2go.Error, int) {
+	var all []api2go.Error
+	var status int
+	for i, err := range multi {
+		translated, s := translateError(err)
+		all = append(all, translated...)
+		switch {
+		case i == 0:
+			status = s
+		case s != status && (s >= http.StatusInternalServerError || status >= http.StatusInternalServerError):
+			status = http.StatusInternalServerError
+		case s != status:
+			status = http.StatusBadRequest
+		}
+	}
+	return all, status
+}
+
+func translateErrorCode(err error) ([]api2go.Error, int) {
 	switch err := err.(type) {
 	case errors.Error:
 		switch err.Category {
@@ -36,6 +75,13 @@ func translateError(err error) ([]api2go.Error, int) {
 					Title:  err.Message,
 					Detail: err.Detail,
 				}}, http.StatusBadRequest
+			case errors.ErrCodeGenericInvalidState:
+				return []api2go.Error{{
+					Status: fmt.Sprintf("%d", http.StatusConflict),
+					Code:   err.Code.String(),
+					Title:  err.Message,
+					Detail: err.Detail,
+				}}, http.StatusConflict
 			case errors.ErrCodeGenericNotFound:
 				return []api2go.Error{{
 					Status: fmt.Sprintf("%d", http.StatusNotFound),
@@ -43,6 +89,27 @@ func translateError(err error) ([]api2go.Error, int) {
 					Title:  err.Message,
 					Detail: err.Detail,
 				}}, http.StatusNotFound
+			case errors.ErrCodeGenericPermissionDenied:
+				return []api2go.Error{{
+					Status: fmt.Sprintf("%d", http.StatusForbidden),
+					Code:   err.Code.String(),
+					Title:  err.Message,
+					Detail: err.Detail,
+				}}, http.StatusForbidden
+			case errors.ErrCodeGenericPreconditionFailed:
+				return []api2go.Error{{
+					Status: fmt.Sprintf("%d", http.StatusPreconditionFailed),
+					Code:   err.Code.String(),
+					Title:  err.Message,
+					Detail: err.Detail,
+				}}, http.StatusPreconditionFailed
+			case errors.ErrCodeGenericUnprocessable:
+				return []api2go.Error{{
+					Status: fmt.Sprintf("%d", http.StatusUnprocessableEntity),
+					Code:   err.Code.String(),
+					Title:  err.Message,
+					Detail: err.Detail,
+				}}, http.StatusUnprocessableEntity
 			default:
 				return []api2go.Error{{
 					Status: fmt.Sprintf("%d", http.StatusInternalServerError),
@@ -51,6 +118,16 @@ func translateError(err error) ([]api2go.Error, int) {
 					Detail: err.Detail,
 				}}, http.StatusInternalServerError
 			}
+		case errors.ErrCategoryDataAccess:
+			switch err.Code {
+			case errors.ErrCodeDataAccessConflict:
+				return []api2go.Error{{
+					Status: fmt.Sprintf("%d", http.StatusConflict),
+					Code:   err.Code.String(),
+					Title:  err.Message,
+					Detail: err.Detail,
+				}}, http.StatusConflict
+			}
 		}
 	}
 
@@ -60,6 +137,27 @@ func translateError(err error) ([]api2go.Error, int) {
 	}}, http.StatusInternalServerError
 }
 
+func WriteError(w http.ResponseWriter, contentType string, err error) {
+	translated, status := translateError(err)
+	data, _ := json.Marshal(api2go.HTTPError{Errors: translated})
+	writeResult(w, contentType, data, status)
+}
+
+func WriteObject(w http.ResponseWriter, contentType string, v interface{}, status int) {
+	data, err := jsonapi.Marshal(v)
+	if err != nil {
+		WriteError(w, contentType, err)
+		return
+	}
+	writeResult(w, contentType, data, status)
+}
+
+func writeResult(w http.ResponseWriter, contentType string, data []byte, status int) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
 func WrapObject(v interface{}, status int) api2go.Responder {
 	return &api2go.Response{Res: v, Code: status}
 }
@@ -67,3 +165,37 @@ func WrapObject(v interface{}, status int) api2go.Responder {
 func WrapArray(v interface{}, status int) api2go.Responder {
 	return &api2go.Response{Res: v, Code: status}
 }
+
+// CursorResponse is a collection response with the links to the adjacent
+// pages, cursor values are kept opaque to the clients.
+type CursorResponse struct {
+	api2go.Response
+	Next, Prev string
+}
+
+func WrapCursorArray(v interface{}, next, prev string, status int) api2go.Responder {
+	return &CursorResponse{
+		Response: api2go.Response{Res: v, Code: status},
+		Next:     next,
+		Prev:     prev,
+	}
+}
+
+func (r CursorResponse) Links(req *http.Request, baseURL string) jsonapi.Links {
+	links := make(jsonapi.Links)
+	if r.Next != "" {
+		links["next"] = cursorLink(req, baseURL, "page[after]", r.Next)
+	}
+	if r.Prev != "" {
+		links["prev"] = cursorLink(req, baseURL, "page[before]", r.Prev)
+	}
+	return links
+}
+
+func cursorLink(req *http.Request, baseURL, key, cursor string) jsonapi.Link {
+	params := req.URL.Query()
+	params.Del("page[after]")
+	params.Del("page[before]")
+	params.Set(key, cursor)
+	return jsonapi.Link{Href: baseURL + "?" + params.Encode()}
+}
@@ -3,8 +3,13 @@ package resource
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/manyminds/api2go"
+	"github.com/manyminds/api2go/jsonapi"
+
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 )
 
@@ -29,11 +34,65 @@ func TestTranslateError(t *testing.T) {
 			in:     errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid argument", ""),
 			status: http.StatusBadRequest,
 		},
+		{
+			name:   "InvalidState",
+			in:     errors.Generic(errors.ErrCodeGenericInvalidState, "invalid state", ""),
+			status: http.StatusConflict,
+		},
 		{
 			name:   "NotFound",
 			in:     errors.Generic(errors.ErrCodeGenericNotFound, "not found", ""),
 			status: http.StatusNotFound,
 		},
+		{
+			name:   "PermissionDenied",
+			in:     errors.Generic(errors.ErrCodeGenericPermissionDenied, "permission denied", ""),
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "PreconditionFailed",
+			in:     errors.Generic(errors.ErrCodeGenericPreconditionFailed, "precondition failed", ""),
+			status: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "Unprocessable",
+			in:     errors.Generic(errors.ErrCodeGenericUnprocessable, "unprocessable", ""),
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "DataAccessConflict",
+			in:     errors.DataAccess(errors.ErrCodeDataAccessConflict, "conflict", ""),
+			status: http.StatusConflict,
+		},
+		{
+			name: "Multiple errors",
+			in: errors.Multi{
+				errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid argument", ""),
+				errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid argument", ""),
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "Multiple errors of different status",
+			in: errors.Multi{
+				errors.Generic(errors.ErrCodeGenericNotFound, "not found", ""),
+				errors.Generic(errors.ErrCodeGenericInvalidState, "invalid state", ""),
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "Multiple errors with server failure",
+			in: errors.Multi{
+				errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid argument", ""),
+				errors.DataAccess(errors.ErrCodeDataAccessInsertFailed, "insert failed", ""),
+			},
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "DataAccessFailure",
+			in:     errors.DataAccess(errors.ErrCodeDataAccessInsertFailed, "insert failed", ""),
+			status: http.StatusInternalServerError,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -46,3 +105,86 @@ func TestTranslateError(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateError_Source(t *testing.T) {
+	testCases := []struct {
+		name   string
+		in     error
+		source []*api2go.ErrorSource
+	}{
+		{
+			name: "Error with pointer",
+			in: errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid argument", "",
+				map[string]interface{}{errors.ExtraPointer: "/data/attributes/debtor/account_number"}),
+			source: []*api2go.ErrorSource{{Pointer: "/data/attributes/debtor/account_number"}},
+		},
+		{
+			name:   "Error without pointer",
+			in:     errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid argument", ""),
+			source: []*api2go.ErrorSource{nil},
+		},
+		{
+			name: "Multiple errors with pointers",
+			in: errors.Multi{
+				errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid argument", "",
+					map[string]interface{}{errors.ExtraPointer: "/data/attributes/scheme"}),
+				errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid argument", "",
+					map[string]interface{}{errors.ExtraPointer: "/data/attributes/amount/currency"}),
+			},
+			source: []*api2go.ErrorSource{
+				{Pointer: "/data/attributes/scheme"},
+				{Pointer: "/data/attributes/amount/currency"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			translated, _ := translateError(tc.in)
+
+			var source []*api2go.ErrorSource
+			for _, e := range translated {
+				source = append(source, e.Source)
+			}
+			if want, have := tc.source, source; !cmp.Equal(want, have) {
+				t.Fatalf("unexpected error source: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+func TestCursorResponse_Links(t *testing.T) {
+	testCases := []struct {
+		name       string
+		url        string
+		next, prev string
+		links      jsonapi.Links
+	}{
+		{
+			name:  "Last page",
+			url:   "/payments?page[size]=2",
+			links: jsonapi.Links{},
+		},
+		{
+			name: "Middle page",
+			url:  "/payments?page[after]=A&page[size]=2&sort=-id",
+			next: "B",
+			prev: "C",
+			links: jsonapi.Links{
+				"next": {Href: "/payments?page%5Bafter%5D=B&page%5Bsize%5D=2&sort=-id"},
+				"prev": {Href: "/payments?page%5Bbefore%5D=C&page%5Bsize%5D=2&sort=-id"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.url, nil)
+			resp := WrapCursorArray(nil, tc.next, tc.prev, http.StatusOK).(api2go.LinksResponder)
+
+			if want, have := tc.links, resp.Links(req, "/payments"); !cmp.Equal(want, have) {
+				t.Fatalf("unexpected links: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
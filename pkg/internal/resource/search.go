@@ -1,12 +1,117 @@
 package resource
 
+import "strings"
+
 type SearchFilter map[string]interface{}
 
+// FilterOperator restricts how a filter parameter value is matched, it is
+// given as a suffix of the parameter, e.g. filter[amount.value][gte]=100.
+type FilterOperator string
+
+const (
+	FilterOperatorEq     FilterOperator = "eq"
+	FilterOperatorGt     FilterOperator = "gt"
+	FilterOperatorGte    FilterOperator = "gte"
+	FilterOperatorLt     FilterOperator = "lt"
+	FilterOperatorLte    FilterOperator = "lte"
+	FilterOperatorPrefix FilterOperator = "prefix"
+)
+
+func (op FilterOperator) IsValid() bool {
+	switch op {
+	case FilterOperatorEq,
+		FilterOperatorGt,
+		FilterOperatorGte,
+		FilterOperatorLt,
+		FilterOperatorLte,
+		FilterOperatorPrefix:
+		return true
+	}
+	return false
+}
+
+func (op FilterOperator) IsRange() bool {
+	switch op {
+	case FilterOperatorGt,
+		FilterOperatorGte,
+		FilterOperatorLt,
+		FilterOperatorLte:
+		return true
+	}
+	return false
+}
+
+// FilterKey returns the search filter key under which the value of the field
+// restricted by the operator is kept. Equality filters use the field itself.
+func FilterKey(field string, op FilterOperator) string {
+	if op == FilterOperatorEq {
+		return field
+	}
+	return field + "[" + string(op) + "]"
+}
+
+// SortField orders the search results by a resource field.
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// SearchSort lists the fields in the order of their precedence.
+type SearchSort []SortField
+
+// String returns the sort in the format of the sort query parameter.
+func (s SearchSort) String() string {
+	fields := make([]string, len(s))
+	for i, f := range s {
+		fields[i] = f.Field
+		if f.Descending {
+			fields[i] = "-" + f.Field
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// WithTieBreaker returns the sort completed by the ascending tie-breaker
+// field unless it is sorted by already.
+func (s SearchSort) WithTieBreaker(field string) SearchSort {
+	for _, f := range s {
+		if f.Field == field {
+			return s
+		}
+	}
+	sort := make(SearchSort, len(s), len(s)+1)
+	copy(sort, s)
+	return append(sort, SortField{Field: field})
+}
+
+// SearchCursor selects a page of at most Size items following the After
+// cursor or preceding the Before cursor. Without any of the cursors the first
+// page is selected.
+type SearchCursor struct {
+	After, Before *Cursor
+	Size          uint
+}
+
+// Limit returns the number of items to be loaded, an extra item is loaded
+// to tell whether there is another page in the direction of paging.
+func (c *SearchCursor) Limit() uint {
+	return c.Size + 1
+}
+
+// Position returns the cursor the page is bounded by, if any.
+func (c *SearchCursor) Position() *Cursor {
+	if c.Before != nil {
+		return c.Before
+	}
+	return c.After
+}
+
 type SearchPagination struct{ page, size uint }
 
 const (
 	defaultPageNumber = 1
 	defaultPageSize   = 100
+	maxPageSize       = 500
 )
 
 func NewSearchPagination(page, size uint) *SearchPagination {
@@ -28,3 +28,23 @@ func TestNewSearchPagination(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchSort_WithTieBreaker(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   SearchSort
+		out  string
+	}{
+		{name: "No sort", in: nil, out: "id"},
+		{name: "Other fields", in: SearchSort{{Field: "foo", Descending: true}}, out: "-foo,id"},
+		{name: "Sorted by tie-breaker", in: SearchSort{{Field: "id", Descending: true}, {Field: "foo"}}, out: "-id,foo"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if want, have := tc.out, tc.in.WithTieBreaker("id").String(); want != have {
+				t.Errorf("unexpected sort: want %q, have %q", want, have)
+			}
+		})
+	}
+}
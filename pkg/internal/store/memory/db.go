@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"sync"
+)
+
+// DB is a transactional in-memory database. Records are kept in named tables
+// and are accessed by their keys. Committed states are never modified, each
+// commit produces a new state instead, so every transaction can work with
+// a consistent snapshot taken when the transaction began.
+type DB struct {
+	mu    sync.Mutex
+	state *state
+}
+
+func NewDB() *DB {
+	return &DB{state: &state{tables: make(map[string]table)}}
+}
+
+func (db *DB) Close() error {
+	return nil
+}
+
+func (db *DB) snapshot() *state {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.state
+}
+
+type state struct {
+	tables map[string]table
+}
+
+type table map[string]*entry
+
+// entry wraps a stored value, its identity is used to detect concurrent
+// modifications of the same key.
+type entry struct {
+	value interface{}
+}
+
+func (s *state) get(tableName, key string) *entry {
+	return s.tables[tableName][key]
+}
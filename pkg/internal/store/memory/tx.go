@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+type TxManager struct {
+	db *DB
+}
+
+func NewTxManager(db *DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+func (m *TxManager) Begin(ctx context.Context) (store.Tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &Tx{
+		db:       m.db,
+		snapshot: m.db.snapshot(),
+		writes:   make(map[string]map[string]*entry),
+	}, nil
+}
+
+// Tx provides snapshot isolation: reads see the state committed before
+// the transaction began together with its own writes. Commit fails if any
+// of the written keys has been committed by another transaction meanwhile.
+type Tx struct {
+	db       *DB
+	snapshot *state
+	writes   map[string]map[string]*entry
+	done     bool
+}
+
+func (tx *Tx) Get(tableName, key string) (interface{}, bool) {
+	if written, ok := tx.writes[tableName][key]; ok {
+		if written == nil {
+			return nil, false
+		}
+		return written.value, true
+	}
+	e := tx.snapshot.get(tableName, key)
+	if e == nil {
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (tx *Tx) Put(tableName, key string, value interface{}) {
+	tx.write(tableName, key, &entry{value: value})
+}
+
+func (tx *Tx) Delete(tableName, key string) {
+	tx.write(tableName, key, nil)
+}
+
+// Scan calls fn for every record of the table in the order of the keys
+// until fn returns false.
+func (tx *Tx) Scan(tableName string, fn func(key string, value interface{}) bool) {
+	var keys []string
+	for key := range tx.snapshot.tables[tableName] {
+		if _, ok := tx.writes[tableName][key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	for key, written := range tx.writes[tableName] {
+		if written != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, _ := tx.Get(tableName, key)
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return errors.DataAccess(errors.ErrCodeDataAccessCommitFailed, "unable to commit transaction", "transaction already finished")
+	}
+	tx.done = true
+
+	if len(tx.writes) == 0 {
+		return nil
+	}
+
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+
+	current := tx.db.state
+	for tableName, writes := range tx.writes {
+		for key := range writes {
+			if current.get(tableName, key) != tx.snapshot.get(tableName, key) {
+				return errors.DataAccess(
+					errors.ErrCodeDataAccessConflict,
+					"unable to commit transaction",
+					fmt.Sprintf("concurrent modification of %s %q", tableName, key),
+				)
+			}
+		}
+	}
+
+	next := &state{tables: make(map[string]table, len(current.tables))}
+	for tableName, t := range current.tables {
+		next.tables[tableName] = t
+	}
+	for tableName, writes := range tx.writes {
+		t := make(table, len(current.tables[tableName])+len(writes))
+		for key, e := range current.tables[tableName] {
+			t[key] = e
+		}
+		for key, e := range writes {
+			if e == nil {
+				delete(t, key)
+				continue
+			}
+			t[key] = e
+		}
+		next.tables[tableName] = t
+	}
+	tx.db.state = next
+
+	return nil
+}
+
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return errors.DataAccess(errors.ErrCodeDataAccessRollbackFailed, "unable to rollback transaction", "transaction already finished")
+	}
+	tx.done = true
+	tx.writes = nil
+	return nil
+}
+
+func (tx *Tx) write(tableName, key string, e *entry) {
+	writes, ok := tx.writes[tableName]
+	if !ok {
+		writes = make(map[string]*entry)
+		tx.writes[tableName] = writes
+	}
+	writes[key] = e
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+func TestTx_Isolation(t *testing.T) {
+	m := NewTxManager(NewDB())
+
+	writer := mustBegin(t, m)
+	writer.(*Tx).Put("t", "a", 1)
+
+	reader := mustBegin(t, m)
+	if _, ok := reader.(*Tx).Get("t", "a"); ok {
+		t.Fatalf("uncommitted write must not be visible")
+	}
+
+	mustCommit(t, writer)
+
+	if _, ok := reader.(*Tx).Get("t", "a"); ok {
+		t.Fatalf("write committed after begin must not be visible")
+	}
+	mustCommit(t, reader)
+
+	reader = mustBegin(t, m)
+	if v, ok := reader.(*Tx).Get("t", "a"); !ok || v != 1 {
+		t.Fatalf("committed write must be visible: have %v", v)
+	}
+}
+
+func TestTx_Rollback(t *testing.T) {
+	m := NewTxManager(NewDB())
+
+	tx := mustBegin(t, m)
+	tx.(*Tx).Put("t", "a", 1)
+	err := tx.Rollback()
+	if err != nil {
+		t.Fatalf("unable to rollback: %v", err)
+	}
+
+	tx = mustBegin(t, m)
+	if _, ok := tx.(*Tx).Get("t", "a"); ok {
+		t.Fatalf("rolled back write must not be visible")
+	}
+
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatalf("unable to rollback: %v", err)
+	}
+	if want, have := errors.ErrCodeDataAccessRollbackFailed, tx.Rollback(); !errors.Is(have, want) {
+		t.Fatalf("unexpected error: want %s, have %v", want, have)
+	}
+}
+
+func TestTx_Conflict(t *testing.T) {
+	testCases := []struct {
+		name   string
+		first  func(*Tx)
+		second func(*Tx)
+		want   errors.Code
+	}{
+		{
+			name:   "Same key",
+			first:  func(tx *Tx) { tx.Put("t", "a", 1) },
+			second: func(tx *Tx) { tx.Put("t", "a", 2) },
+			want:   errors.ErrCodeDataAccessConflict,
+		},
+		{
+			name:   "Deleted key",
+			first:  func(tx *Tx) { tx.Delete("t", "a") },
+			second: func(tx *Tx) { tx.Put("t", "a", 2) },
+			want:   errors.ErrCodeDataAccessConflict,
+		},
+		{
+			name:   "Different keys",
+			first:  func(tx *Tx) { tx.Put("t", "a", 1) },
+			second: func(tx *Tx) { tx.Put("t", "b", 2) },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewTxManager(NewDB())
+
+			seed := mustBegin(t, m)
+			seed.(*Tx).Put("t", "a", 0)
+			mustCommit(t, seed)
+
+			first, second := mustBegin(t, m), mustBegin(t, m)
+			tc.first(first.(*Tx))
+			tc.second(second.(*Tx))
+
+			mustCommit(t, first)
+
+			err := second.Commit()
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("unexpected error: want %s, have %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestTx_Scan(t *testing.T) {
+	m := NewTxManager(NewDB())
+
+	tx := mustBegin(t, m)
+	tx.(*Tx).Put("t", "b", 2)
+	tx.(*Tx).Put("t", "c", 3)
+	mustCommit(t, tx)
+
+	tx = mustBegin(t, m)
+	tx.(*Tx).Put("t", "a", 1)
+	tx.(*Tx).Delete("t", "c")
+
+	var have []string
+	tx.(*Tx).Scan("t", func(key string, _ interface{}) bool {
+		have = append(have, key)
+		return true
+	})
+
+	if want := []string{"a", "b"}; !reflect.DeepEqual(want, have) {
+		t.Fatalf("unexpected keys: want %v, have %v", want, have)
+	}
+}
+
+func mustBegin(t *testing.T, m *TxManager) store.Tx {
+	t.Helper()
+	tx, err := m.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("unable to begin transaction: %v", err)
+	}
+	return tx
+}
+
+func mustCommit(t *testing.T, tx store.Tx) {
+	t.Helper()
+	err := tx.Commit()
+	if err != nil {
+		t.Fatalf("unable to commit transaction: %v", err)
+	}
+}
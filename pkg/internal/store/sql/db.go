@@ -17,13 +17,23 @@ type Config struct {
 }
 
 type DB struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	dialect Dialect
 }
 
 func Connect(cfg Config) (*DB, error) {
-	if cfg.Driver != "postgres" { // We support only postgres right now.
+	dialect := Dialect(cfg.Driver)
+	if !dialect.IsValid() {
 		return nil, fmt.Errorf("unsuported sql driver: %s", cfg.Driver)
 	}
+	// SQLite allows a single writer only, so all the requests and workers
+	// share a single connection. A transaction holds the connection until it
+	// ends, so a transaction must neither wait for anything but the database,
+	// e.g. the workers call the gateways, sinks and webhook receivers between
+	// their transactions, nor begin another transaction.
+	if dialect == DialectSQLite3 && cfg.MaxOpenConnections == 0 {
+		cfg.MaxOpenConnections = 1
+	}
 
 	db, err := sql.Open(cfg.Driver, cfg.DSN)
 	if err != nil {
@@ -42,7 +52,7 @@ func Connect(cfg Config) (*DB, error) {
 		return nil, err
 	}
 
-	return &DB{db: sqlx.NewDb(db, cfg.Driver)}, nil
+	return &DB{db: sqlx.NewDb(db, cfg.Driver), dialect: dialect}, nil
 }
 
 func (db *DB) Close() error {
@@ -0,0 +1,111 @@
+package sql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Dialect captures the differences of the supported SQL databases which
+// stores have to take into account when building queries.
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectSQLite3  Dialect = "sqlite3"
+)
+
+func (d Dialect) IsValid() bool {
+	switch d {
+	case DialectPostgres, DialectSQLite3:
+		return true
+	}
+	return false
+}
+
+// AnyOf returns a condition matching the column against any value of
+// the given slice together with the condition arguments.
+func (d Dialect) AnyOf(column string, list interface{}) (string, []interface{}) {
+	if d == DialectPostgres {
+		return fmt.Sprintf("%s = ANY (?)", column), []interface{}{pq.Array(list)}
+	}
+
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("sql: AnyOf expects a slice, got %T", list))
+	}
+	if v.Len() == 0 {
+		return "1 = 0", nil
+	}
+	args := make([]interface{}, v.Len())
+	for i := range args {
+		args[i] = v.Index(i).Interface()
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	return fmt.Sprintf("%s IN (%s)", column, placeholders), args
+}
+
+// DecimalKey returns the expression of the decimal column which compares the
+// same way as the decimals, to be compared with the arguments given by
+// DecimalKeyArg. SQLite stores the decimals as text in order to keep their
+// scale, so they are compared by the text keys made of the number of the
+// integer digits, the digits and the fraction without the trailing zeros,
+// e.g. "0212.5" for "12.50". Unlike a conversion to a floating point number,
+// the keys are exact and the expression can be indexed. The column is never
+// expected to hold a negative decimal.
+func (d Dialect) DecimalKey(column string) string {
+	if d != DialectSQLite3 {
+		return column
+	}
+	point := fmt.Sprintf("instr(%s || '.', '.')", column)
+	return fmt.Sprintf(
+		"printf('%%02d', %[2]s - 1) || substr(%[1]s, 1, %[2]s - 1) || rtrim(rtrim(substr(%[1]s, %[2]s), '0'), '.')",
+		column, point,
+	)
+}
+
+// DecimalKeyArg returns the argument to compare the DecimalKey of a column
+// with. The keys of the negative decimals only sort before all the others.
+func (d Dialect) DecimalKeyArg(value string) interface{} {
+	if d != DialectSQLite3 {
+		return value
+	}
+	if strings.HasPrefix(value, "-") {
+		return value
+	}
+	point := strings.IndexByte(value+".", '.')
+	return fmt.Sprintf("%02d", point) + value[:point] + strings.TrimRight(strings.TrimRight(value[point:], "0"), ".")
+}
+
+// SkipLocked returns the locking clause of a query selecting rows to work on,
+// the rows locked by other transactions are skipped instead of waited for.
+// SQLite serializes the writing transactions, so there is nothing to skip.
+func (d Dialect) SkipLocked() string {
+	if d == DialectPostgres {
+		return "FOR UPDATE SKIP LOCKED"
+	}
+	return ""
+}
+
+// ForUpdate returns the locking clause of a query selecting rows which have
+// to be worked on by one transaction at a time, e.g. in a given order.
+func (d Dialect) ForUpdate() string {
+	if d == DialectPostgres {
+		return "FOR UPDATE"
+	}
+	return ""
+}
+
+// HasPrefixFold returns a case-insensitive prefix match condition of the
+// column which is able to use the column's case-insensitive index.
+func (d Dialect) HasPrefixFold(column, prefix string) (string, []interface{}) {
+	pattern := likeEscaper.Replace(prefix) + "%"
+	if d == DialectPostgres {
+		return fmt.Sprintf("lower(%s) LIKE ?", column), []interface{}{strings.ToLower(pattern)}
+	}
+	return fmt.Sprintf(`%s LIKE ? ESCAPE '\'`, column), []interface{}{pattern}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,216 @@
+package sql
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestDialect_AnyOf(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		in      interface{}
+		cond    string
+		args    []interface{}
+	}{
+		{
+			name:    "Postgres",
+			dialect: DialectPostgres,
+			in:      []string{"a", "b"},
+			cond:    "col = ANY (?)",
+			args:    []interface{}{pq.Array([]string{"a", "b"})},
+		},
+		{
+			name:    "SQLite",
+			dialect: DialectSQLite3,
+			in:      []string{"a", "b"},
+			cond:    "col IN (?, ?)",
+			args:    []interface{}{"a", "b"},
+		},
+		{
+			name:    "SQLite single value",
+			dialect: DialectSQLite3,
+			in:      []string{"a"},
+			cond:    "col IN (?)",
+			args:    []interface{}{"a"},
+		},
+		{
+			name:    "SQLite no values",
+			dialect: DialectSQLite3,
+			in:      []string{},
+			cond:    "1 = 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cond, args := tc.dialect.AnyOf("col", tc.in)
+			if want, have := tc.cond, cond; want != have {
+				t.Fatalf("unexpected condition: want %q, have %q", want, have)
+			}
+			if want, have := tc.args, args; !cmp.Equal(want, have) {
+				t.Fatalf("unexpected arguments: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+func TestDialect_HasPrefixFold(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		in      string
+		cond    string
+		args    []interface{}
+	}{
+		{
+			name:    "Postgres",
+			dialect: DialectPostgres,
+			in:      "Joh",
+			cond:    "lower(col) LIKE ?",
+			args:    []interface{}{"joh%"},
+		},
+		{
+			name:    "Postgres wildcards",
+			dialect: DialectPostgres,
+			in:      `10%_\`,
+			cond:    "lower(col) LIKE ?",
+			args:    []interface{}{`10\%\_\\%`},
+		},
+		{
+			name:    "SQLite",
+			dialect: DialectSQLite3,
+			in:      "Joh_",
+			cond:    `col LIKE ? ESCAPE '\'`,
+			args:    []interface{}{`Joh\_%`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cond, args := tc.dialect.HasPrefixFold("col", tc.in)
+			if want, have := tc.cond, cond; want != have {
+				t.Fatalf("unexpected condition: want %q, have %q", want, have)
+			}
+			if want, have := tc.args, args; !cmp.Equal(want, have) {
+				t.Fatalf("unexpected arguments: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+func TestDialect_DecimalKey(t *testing.T) {
+	// Sorted, the last two can not be told apart as floating point numbers.
+	values := []string{
+		"0",
+		"0.05",
+		"0.5",
+		"9.999",
+		"10",
+		"10.01",
+		"100.00",
+		"12345678901234567.01",
+		"12345678901234567.02",
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+	defer db.Close()
+
+	key := DialectSQLite3.DecimalKey("v")
+	for _, stmt := range []string{
+		"CREATE TABLE t (v TEXT NOT NULL)",
+		"CREATE INDEX idx_t_key ON t (" + key + ")",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("unable to create table: %v", err)
+		}
+	}
+	for _, v := range values {
+		if _, err := db.Exec("INSERT INTO t (v) VALUES (?)", v); err != nil {
+			t.Fatalf("unable to insert value: %v", err)
+		}
+	}
+
+	t.Run("Keys", func(t *testing.T) {
+		rows, err := db.Query("SELECT v, " + key + " FROM t")
+		if err != nil {
+			t.Fatalf("unable to select keys: %v", err)
+		}
+		defer rows.Close()
+		var keys []string
+		for rows.Next() {
+			var v, k string
+			if err := rows.Scan(&v, &k); err != nil {
+				t.Fatalf("unable to scan key: %v", err)
+			}
+			if want, have := DialectSQLite3.DecimalKeyArg(v), k; want != have {
+				t.Fatalf("unexpected key of %s: want %q, have %q", v, want, have)
+			}
+			keys = append(keys, k)
+		}
+		if !sort.StringsAreSorted(keys) {
+			t.Fatalf("keys do not sort as decimals: %v", keys)
+		}
+	})
+
+	testCases := []struct {
+		name  string
+		op    string
+		value string
+		found []string
+	}{
+		{name: "Greater than", op: ">", value: "12345678901234567.01", found: []string{"12345678901234567.02"}},
+		{name: "Less than", op: "<", value: "0.050", found: []string{"0"}},
+		{name: "Equal with other scale", op: "=", value: "100", found: []string{"100.00"}},
+		{name: "Greater than negative", op: ">", value: "-1", found: values},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := db.Query("SELECT v FROM t WHERE "+key+" "+tc.op+" ? ORDER BY "+key, DialectSQLite3.DecimalKeyArg(tc.value))
+			if err != nil {
+				t.Fatalf("unable to select values: %v", err)
+			}
+			defer rows.Close()
+			var found []string
+			for rows.Next() {
+				var v string
+				if err := rows.Scan(&v); err != nil {
+					t.Fatalf("unable to scan value: %v", err)
+				}
+				found = append(found, v)
+			}
+			if want, have := tc.found, found; !cmp.Equal(want, have) {
+				t.Fatalf("unexpected values: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+
+	t.Run("Index", func(t *testing.T) {
+		rows, err := db.Query("EXPLAIN QUERY PLAN SELECT v FROM t WHERE "+key+" > ?", DialectSQLite3.DecimalKeyArg("10"))
+		if err != nil {
+			t.Fatalf("unable to explain query: %v", err)
+		}
+		defer rows.Close()
+		var plan []string
+		for rows.Next() {
+			var id, parent, unused int
+			var detail string
+			if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+				t.Fatalf("unable to scan query plan: %v", err)
+			}
+			plan = append(plan, detail)
+		}
+		if !strings.Contains(strings.Join(plan, "\n"), "idx_t_key") {
+			t.Fatalf("index not used: %v", plan)
+		}
+	})
+}
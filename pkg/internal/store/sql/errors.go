@@ -4,6 +4,7 @@ import (
 	"database/sql"
 
 	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
 	"github.com/michaljemala/pqerror"
 
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
@@ -28,6 +29,11 @@ func WrapInsertError(err error, msg string) error {
 		case pqerror.UniqueViolation:
 			return errors.Generic(errors.ErrCodeGenericAlreadyExists, msg, err.Error())
 		}
+	case sqlite3.Error:
+		switch err.ExtendedCode {
+		case sqlite3.ErrConstraintPrimaryKey, sqlite3.ErrConstraintUnique:
+			return errors.Generic(errors.ErrCodeGenericAlreadyExists, msg, err.Error())
+		}
 	}
 	return errors.DataAccess(errors.ErrCodeDataAccessInsertFailed, msg, err.Error())
 }
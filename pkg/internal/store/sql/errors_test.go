@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
 	"github.com/michaljemala/pqerror"
 
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
@@ -93,6 +94,34 @@ func TestWrapInsertError(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "SQLite conflict",
+			in:   sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique},
+			errFunc: func(t *testing.T, err error) {
+				switch err := err.(type) {
+				case errors.Error:
+					if want, have := errors.ErrCodeGenericAlreadyExists, err.Code; want != have {
+						t.Fatalf("unexpected error code: want %s, have %s", want, have)
+					}
+				default:
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name: "SQLite primary key conflict",
+			in:   sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintPrimaryKey},
+			errFunc: func(t *testing.T, err error) {
+				switch err := err.(type) {
+				case errors.Error:
+					if want, have := errors.ErrCodeGenericAlreadyExists, err.Code; want != have {
+						t.Fatalf("unexpected error code: want %s, have %s", want, have)
+					}
+				default:
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
 		{
 			name: "Unknown error",
 			in:   fmt.Errorf("I_AM_UNKNOWN"),
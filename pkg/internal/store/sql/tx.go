@@ -22,11 +22,16 @@ func (m *TxManager) Begin(ctx context.Context) (store.Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Tx{tx: txx}, nil
+	return &Tx{tx: txx, dialect: m.db.dialect}, nil
 }
 
 type Tx struct {
-	tx *sqlx.Tx
+	tx      *sqlx.Tx
+	dialect Dialect
+}
+
+func (tx *Tx) Dialect() Dialect {
+	return tx.dialect
 }
 
 func (tx *Tx) Commit() error {
@@ -0,0 +1,281 @@
+// Package pain001 reads the ISO 20022 customer credit transfer initiations,
+// the pain.001.001.03 and pain.001.001.09 messages, as payments.
+package pain001
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+)
+
+// The namespaces of the supported message versions.
+const (
+	NamespaceV03 = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+	NamespaceV09 = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+)
+
+// notProvided stands for a missing end-to-end identification.
+const notProvided = "NOTPROVIDED"
+
+// paymentNamespace derives the ids of the payments from the file, so the same
+// file imported again yields the same payments.
+var paymentNamespace = uuid.Must(uuid.FromString("6d1f9b4e-6a0e-4d43-9d8a-8f1a2b7c3e51"))
+
+// Parse reads the document as a payment file. The payments are SEPA payments
+// if their service level is SEPA, SWIFT payments otherwise. A transaction
+// which can not be read as a payment is returned with the error, the whole
+// document fails only if it is malformed, of an unsupported version or its
+// number of transactions or control sum do not add up. The control sum adds
+// up the amounts as written, so a transaction rejected on its own still counts,
+// it is not checked if any of the amounts can not be read.
+func Parse(r io.Reader) (*domain.PaymentFile, error) {
+	var doc document
+	err := xml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, invalidFile(fmt.Sprintf("malformed document: %v", err))
+	}
+	switch doc.XMLName.Space {
+	case NamespaceV03, NamespaceV09:
+	default:
+		return nil, invalidFile(fmt.Sprintf("unsupported message %q, only pain.001.001.03 and pain.001.001.09 are supported", doc.XMLName.Space))
+	}
+
+	header := doc.Initiation.GroupHeader
+	if header.MessageID == "" {
+		return nil, invalidFile("message id must not be empty")
+	}
+
+	file := &domain.PaymentFile{MessageID: header.MessageID}
+	sum, summed := decimal.Zero, true
+	for _, info := range doc.Initiation.PaymentInformation {
+		for i, tx := range info.Transactions {
+			transaction := domain.PaymentFileTransaction{
+				PaymentInformationID: info.ID,
+				EndToEndID:           tx.EndToEndID,
+			}
+			if amount, err := decimal.NewFromString(strings.TrimSpace(tx.Amount.Value)); err == nil {
+				sum = sum.Add(amount)
+			} else {
+				summed = false
+			}
+			transaction.Payment, transaction.Err = newPayment(info, tx)
+			if transaction.Payment != nil {
+				id := fmt.Sprintf("%s/%s/%s/%d", doc.Initiation.GroupHeader.InitiatingParty, header.MessageID, info.ID, i)
+				transaction.Payment.ID = domain.ID(uuid.NewV5(paymentNamespace, id))
+			}
+			file.Transactions = append(file.Transactions, transaction)
+		}
+	}
+
+	n, err := strconv.Atoi(header.NumberOfTransactions)
+	if err != nil || n != len(file.Transactions) {
+		return nil, invalidFile(fmt.Sprintf("number of transactions %q does not match the %d transactions", header.NumberOfTransactions, len(file.Transactions)))
+	}
+	if header.ControlSum != "" && summed {
+		controlSum, err := decimal.NewFromString(header.ControlSum)
+		if err != nil || !controlSum.Equal(sum) {
+			return nil, invalidFile(fmt.Sprintf("control sum %q does not match the sum %s of the amounts", header.ControlSum, sum))
+		}
+	}
+
+	return file, nil
+}
+
+func newPayment(info paymentInformation, tx creditTransfer) (*domain.Payment, error) {
+	if info.Method != "TRF" {
+		return nil, invalidTransaction(fmt.Sprintf("unsupported payment method %q", info.Method))
+	}
+
+	value, err := domain.DecimalFrom(tx.Amount.Value)
+	if err != nil {
+		return nil, invalidTransaction(fmt.Sprintf("invalid amount %q", tx.Amount.Value))
+	}
+
+	payment := &domain.Payment{
+		Scheme: domain.PaymentSchemeSWIFT,
+		Amount: domain.Monetary{Value: value, Currency: tx.Amount.Currency},
+		Debtor: domain.PaymentParty{
+			Name:            info.Debtor.Name,
+			Address:         info.Debtor.Address.toAddress(),
+			AccountName:     info.DebtorAccount.Name,
+			AccountNumber:   info.DebtorAccount.number(),
+			AccountProvider: info.DebtorAgent.toAccountProvider(),
+		},
+		Creditor: domain.PaymentParty{
+			Name:            tx.Creditor.Name,
+			Address:         tx.Creditor.Address.toAddress(),
+			AccountName:     tx.CreditorAccount.Name,
+			AccountNumber:   tx.CreditorAccount.number(),
+			AccountProvider: tx.CreditorAgent.toAccountProvider(),
+		},
+		PaymentPurpose: tx.Purpose,
+		RemittanceInformation: domain.RemittanceInformation{
+			Unstructured: strings.Join(tx.Remittance.Unstructured, " "),
+			Structured:   tx.Remittance.CreditorReference,
+		},
+	}
+	if tx.EndToEndID != notProvided {
+		payment.EndToEndReference = tx.EndToEndID
+	}
+	// The service level of the transaction overrides the one of the payment
+	// information block.
+	serviceLevel := info.TypeInformation.ServiceLevel
+	if tx.TypeInformation.ServiceLevel != "" {
+		serviceLevel = tx.TypeInformation.ServiceLevel
+	}
+	if serviceLevel == "SEPA" {
+		payment.Scheme = domain.PaymentSchemeSEPA
+	}
+	if s := info.RequestedExecutionDate.value(); s != "" {
+		date, err := domain.DateFrom(s)
+		if err != nil {
+			return nil, invalidTransaction(fmt.Sprintf("invalid requested execution date %q", s))
+		}
+		payment.RequestedExecutionDate = &date
+	}
+
+	return payment, nil
+}
+
+func invalidFile(detail string) error {
+	return errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid payment file", detail)
+}
+
+func invalidTransaction(detail string) error {
+	return errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid payment file transaction", detail)
+}
+
+// The elements of the supported message versions, the versions differ only in
+// the way the dates and the BICs are given.
+type (
+	document struct {
+		XMLName    xml.Name `xml:"Document"`
+		Initiation struct {
+			GroupHeader struct {
+				MessageID            string `xml:"MsgId"`
+				NumberOfTransactions string `xml:"NbOfTxs"`
+				ControlSum           string `xml:"CtrlSum"`
+				InitiatingParty      string `xml:"InitgPty>Nm"`
+			} `xml:"GrpHdr"`
+			PaymentInformation []paymentInformation `xml:"PmtInf"`
+		} `xml:"CstmrCdtTrfInitn"`
+	}
+	paymentInformation struct {
+		ID                     string           `xml:"PmtInfId"`
+		Method                 string           `xml:"PmtMtd"`
+		TypeInformation        typeInformation  `xml:"PmtTpInf"`
+		RequestedExecutionDate executionDate    `xml:"ReqdExctnDt"`
+		Debtor                 party            `xml:"Dbtr"`
+		DebtorAccount          account          `xml:"DbtrAcct"`
+		DebtorAgent            agent            `xml:"DbtrAgt"`
+		Transactions           []creditTransfer `xml:"CdtTrfTxInf"`
+	}
+	typeInformation struct {
+		ServiceLevel string `xml:"SvcLvl>Cd"`
+	}
+	creditTransfer struct {
+		EndToEndID      string          `xml:"PmtId>EndToEndId"`
+		TypeInformation typeInformation `xml:"PmtTpInf"`
+		Amount          struct {
+			Currency string `xml:"Ccy,attr"`
+			Value    string `xml:",chardata"`
+		} `xml:"Amt>InstdAmt"`
+		CreditorAgent   agent   `xml:"CdtrAgt"`
+		Creditor        party   `xml:"Cdtr"`
+		CreditorAccount account `xml:"CdtrAcct"`
+		Purpose         string  `xml:"Purp>Cd"`
+		Remittance      struct {
+			Unstructured      []string `xml:"Ustrd"`
+			CreditorReference string   `xml:"Strd>CdtrRefInf>Ref"`
+		} `xml:"RmtInf"`
+	}
+	party struct {
+		Name    string        `xml:"Nm"`
+		Address postalAddress `xml:"PstlAdr"`
+	}
+	postalAddress struct {
+		StreetName         string   `xml:"StrtNm"`
+		BuildingNumber     string   `xml:"BldgNb"`
+		PostCode           string   `xml:"PstCd"`
+		TownName           string   `xml:"TwnNm"`
+		CountrySubDivision string   `xml:"CtrySubDvsn"`
+		Country            string   `xml:"Ctry"`
+		Lines              []string `xml:"AdrLine"`
+	}
+	account struct {
+		IBAN  string `xml:"Id>IBAN"`
+		Other string `xml:"Id>Othr>Id"`
+		Name  string `xml:"Nm"`
+	}
+	agent struct {
+		BIC   string `xml:"FinInstnId>BIC"`
+		BICFI string `xml:"FinInstnId>BICFI"`
+		Name  string `xml:"FinInstnId>Nm"`
+	}
+	// executionDate is the date itself in pain.001.001.03, a choice of
+	// a date or a date and time in pain.001.001.09.
+	executionDate struct {
+		Value    string `xml:",chardata"`
+		Date     string `xml:"Dt"`
+		DateTime string `xml:"DtTm"`
+	}
+)
+
+func (a postalAddress) toAddress() domain.Address {
+	address := domain.Address{
+		Line1:       strings.TrimSpace(a.StreetName + " " + a.BuildingNumber),
+		City:        a.TownName,
+		PostalCode:  a.PostCode,
+		CountryCode: a.Country,
+	}
+	lines := a.Lines
+	if address.Line1 == "" && len(lines) > 0 {
+		address.Line1, lines = lines[0], lines[1:]
+	}
+	if len(lines) > 0 {
+		line2 := strings.Join(lines, ", ")
+		address.Line2 = &line2
+	}
+	if a.CountrySubDivision != "" {
+		region := a.CountrySubDivision
+		address.Region = &region
+	}
+	return address
+}
+
+func (a account) number() string {
+	if a.IBAN != "" {
+		return a.IBAN
+	}
+	return a.Other
+}
+
+func (a agent) toAccountProvider() domain.AccountProvider {
+	provider := domain.AccountProvider{Code: a.BIC}
+	if a.BICFI != "" {
+		provider.Code = a.BICFI
+	}
+	if a.Name != "" {
+		name := a.Name
+		provider.Name = &name
+	}
+	return provider
+}
+
+func (d executionDate) value() string {
+	switch {
+	case d.Date != "":
+		return d.Date
+	case len(d.DateTime) >= len("2006-01-02"):
+		return d.DateTime[:len("2006-01-02")]
+	}
+	return strings.TrimSpace(d.Value)
+}
@@ -0,0 +1,243 @@
+package pain001
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+)
+
+const testDocumentV03 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">
+  <CstmrCdtTrfInitn>
+    <GrpHdr>
+      <MsgId>MSG-1</MsgId>
+      <CreDtTm>2019-06-12T10:00:00</CreDtTm>
+      <NbOfTxs>%s</NbOfTxs>
+      <CtrlSum>%s</CtrlSum>
+      <InitgPty><Nm>ACME</Nm></InitgPty>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>PMT-1</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <PmtTpInf><SvcLvl><Cd>SEPA</Cd></SvcLvl></PmtTpInf>
+      <ReqdExctnDt>2019-06-14</ReqdExctnDt>
+      <Dbtr>
+        <Nm>ACME</Nm>
+        <PstlAdr><StrtNm>Hauptstrasse</StrtNm><BldgNb>1</BldgNb><PstCd>10115</PstCd><TwnNm>Berlin</TwnNm><Ctry>DE</Ctry></PstlAdr>
+      </Dbtr>
+      <DbtrAcct><Id><IBAN>DE89370400440532013000</IBAN></Id></DbtrAcct>
+      <DbtrAgt><FinInstnId><BIC>COBADEFFXXX</BIC></FinInstnId></DbtrAgt>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>E2E-1</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="EUR">%s</InstdAmt></Amt>
+        <Cdtr>
+          <Nm>Jane Roe</Nm>
+          <PstlAdr><Ctry>SK</Ctry><AdrLine>Hlavna 1</AdrLine><AdrLine>Bratislava</AdrLine></PstlAdr>
+        </Cdtr>
+        <CdtrAcct><Id><IBAN>SK3112000000198742637541</IBAN></Id></CdtrAcct>
+        <RmtInf><Ustrd>Invoice 1</Ustrd><Ustrd>and 2</Ustrd></RmtInf>
+      </CdtTrfTxInf>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>NOTPROVIDED</EndToEndId></PmtId>
+        <PmtTpInf><SvcLvl><Cd>URGP</Cd></SvcLvl></PmtTpInf>
+        <Amt><InstdAmt Ccy="GBP">250</InstdAmt></Amt>
+        <CdtrAgt><FinInstnId><BIC>BARCGB22</BIC></FinInstnId></CdtrAgt>
+        <Cdtr><Nm>John Smith</Nm><PstlAdr><Ctry>GB</Ctry></PstlAdr></Cdtr>
+        <CdtrAcct><Id><Othr><Id>12345678</Id></Othr></Id></CdtrAcct>
+        <Purp><Cd>SUPP</Cd></Purp>
+        <RmtInf><Strd><CdtrRefInf><Ref>RF18539007547034</Ref></CdtrRefInf></Strd></RmtInf>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`
+
+const testDocumentV09 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09">
+  <CstmrCdtTrfInitn>
+    <GrpHdr>
+      <MsgId>MSG-2</MsgId>
+      <NbOfTxs>1</NbOfTxs>
+      <InitgPty><Nm>ACME</Nm></InitgPty>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>PMT-1</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <PmtTpInf><SvcLvl><Cd>SEPA</Cd></SvcLvl></PmtTpInf>
+      <ReqdExctnDt><DtTm>2019-06-14T08:00:00</DtTm></ReqdExctnDt>
+      <Dbtr><Nm>ACME</Nm><PstlAdr><Ctry>DE</Ctry></PstlAdr></Dbtr>
+      <DbtrAcct><Id><IBAN>DE89370400440532013000</IBAN></Id></DbtrAcct>
+      <DbtrAgt><FinInstnId><BICFI>COBADEFFXXX</BICFI></FinInstnId></DbtrAgt>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>E2E-1</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="EUR">10.5</InstdAmt></Amt>
+        <Cdtr><Nm>Jane Roe</Nm><PstlAdr><Ctry>SK</Ctry></PstlAdr></Cdtr>
+        <CdtrAcct><Id><IBAN>SK3112000000198742637541</IBAN></Id></CdtrAcct>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		document string
+		errFunc  func(*testing.T, error)
+		fileFunc func(*testing.T, *domain.PaymentFile)
+	}{
+		{
+			name:     "pain.001.001.03",
+			document: fmt.Sprintf(testDocumentV03, "2", "350.00", "100"),
+			fileFunc: func(t *testing.T, file *domain.PaymentFile) {
+				if want, have := "MSG-1", file.MessageID; want != have {
+					t.Errorf("unexpected message id: want %q, have %q", want, have)
+				}
+				if want, have := 2, len(file.Transactions); want != have {
+					t.Fatalf("unexpected number of transactions: want %d, have %d", want, have)
+				}
+
+				sepa := file.Transactions[0]
+				if sepa.Err != nil {
+					t.Fatalf("unexpected transaction error: %v", sepa.Err)
+				}
+				if want, have := "PMT-1", sepa.PaymentInformationID; want != have {
+					t.Errorf("unexpected payment information id: want %q, have %q", want, have)
+				}
+				payment := sepa.Payment
+				for _, c := range []struct{ field, want, have string }{
+					{"scheme", domain.PaymentSchemeSEPA, payment.Scheme},
+					{"amount", "100 EUR", payment.Amount.Value.String() + " " + payment.Amount.Currency},
+					{"end-to-end reference", "E2E-1", payment.EndToEndReference},
+					{"requested execution date", "2019-06-14", payment.RequestedExecutionDate.String()},
+					{"debtor name", "ACME", payment.Debtor.Name},
+					{"debtor account number", "DE89370400440532013000", payment.Debtor.AccountNumber},
+					{"debtor account provider", "COBADEFFXXX", payment.Debtor.AccountProvider.Code},
+					{"debtor address line 1", "Hauptstrasse 1", payment.Debtor.Address.Line1},
+					{"debtor city", "Berlin", payment.Debtor.Address.City},
+					{"debtor postal code", "10115", payment.Debtor.Address.PostalCode},
+					{"creditor address line 1", "Hlavna 1", payment.Creditor.Address.Line1},
+					{"creditor address line 2", "Bratislava", *payment.Creditor.Address.Line2},
+					{"creditor country", "SK", payment.Creditor.Address.CountryCode},
+					{"unstructured remittance", "Invoice 1 and 2", payment.RemittanceInformation.Unstructured},
+				} {
+					if c.want != c.have {
+						t.Errorf("unexpected %s: want %q, have %q", c.field, c.want, c.have)
+					}
+				}
+
+				swift := file.Transactions[1].Payment
+				for _, c := range []struct{ field, want, have string }{
+					{"scheme", domain.PaymentSchemeSWIFT, swift.Scheme},
+					{"end-to-end reference", "", swift.EndToEndReference},
+					{"creditor account number", "12345678", swift.Creditor.AccountNumber},
+					{"creditor account provider", "BARCGB22", swift.Creditor.AccountProvider.Code},
+					{"purpose", "SUPP", swift.PaymentPurpose},
+					{"structured remittance", "RF18539007547034", swift.RemittanceInformation.Structured},
+				} {
+					if c.want != c.have {
+						t.Errorf("unexpected %s: want %q, have %q", c.field, c.want, c.have)
+					}
+				}
+
+				if payment.ID.IsNil() || payment.ID == swift.ID {
+					t.Errorf("unexpected payment ids: %s, %s", payment.ID, swift.ID)
+				}
+			},
+		},
+		{
+			name:     "pain.001.001.09",
+			document: testDocumentV09,
+			fileFunc: func(t *testing.T, file *domain.PaymentFile) {
+				payment := file.Transactions[0].Payment
+				if want, have := "2019-06-14", payment.RequestedExecutionDate.String(); want != have {
+					t.Errorf("unexpected requested execution date: want %q, have %q", want, have)
+				}
+				if want, have := "COBADEFFXXX", payment.Debtor.AccountProvider.Code; want != have {
+					t.Errorf("unexpected debtor account provider: want %q, have %q", want, have)
+				}
+			},
+		},
+		{
+			name:     "Same file",
+			document: fmt.Sprintf(testDocumentV03, "2", "350", "100"),
+			fileFunc: func(t *testing.T, file *domain.PaymentFile) {
+				same, err := Parse(strings.NewReader(fmt.Sprintf(testDocumentV03, "2", "350", "100")))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if want, have := file.Transactions[0].Payment.ID, same.Transactions[0].Payment.ID; want != have {
+					t.Errorf("unexpected payment id: want %s, have %s", want, have)
+				}
+			},
+		},
+		{
+			name:     "Invalid amount",
+			document: fmt.Sprintf(testDocumentV03, "2", "", "ten"),
+			fileFunc: func(t *testing.T, file *domain.PaymentFile) {
+				if file.Transactions[0].Payment != nil {
+					t.Errorf("unexpected payment: %v", file.Transactions[0].Payment)
+				}
+				if !errors.Is(file.Transactions[0].Err, errors.ErrCodeGenericInvalidArgument) {
+					t.Errorf("unexpected transaction error: %v", file.Transactions[0].Err)
+				}
+				if file.Transactions[1].Err != nil {
+					t.Errorf("unexpected transaction error: %v", file.Transactions[1].Err)
+				}
+			},
+		},
+		{
+			name:     "Invalid amount with control sum",
+			document: fmt.Sprintf(testDocumentV03, "2", "350", "ten"),
+			fileFunc: func(t *testing.T, file *domain.PaymentFile) {
+				if !errors.Is(file.Transactions[0].Err, errors.ErrCodeGenericInvalidArgument) {
+					t.Errorf("unexpected transaction error: %v", file.Transactions[0].Err)
+				}
+				if file.Transactions[1].Err != nil {
+					t.Errorf("unexpected transaction error: %v", file.Transactions[1].Err)
+				}
+			},
+		},
+		{
+			name:     "Unsupported version",
+			document: strings.Replace(testDocumentV09, "pain.001.001.09", "pain.001.001.02", 1),
+			errFunc:  expectInvalidFile,
+		},
+		{
+			name:     "Number of transactions mismatch",
+			document: fmt.Sprintf(testDocumentV03, "3", "350", "100"),
+			errFunc:  expectInvalidFile,
+		},
+		{
+			name:     "Control sum mismatch",
+			document: fmt.Sprintf(testDocumentV03, "2", "300", "100"),
+			errFunc:  expectInvalidFile,
+		},
+		{
+			name:     "Malformed document",
+			document: testDocumentV09[:200],
+			errFunc:  expectInvalidFile,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := Parse(strings.NewReader(tc.document))
+			if tc.errFunc != nil {
+				tc.errFunc(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.fileFunc(t, file)
+		})
+	}
+}
+
+func expectInvalidFile(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+		t.Fatalf("expected invalid argument error, have %v", err)
+	}
+}
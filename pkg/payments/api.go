@@ -1,66 +1,305 @@
 package payments
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/manyminds/api2go"
 
 	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/events"
+	"github.com/michaljemala/payments-sample/pkg/gateway"
+	"github.com/michaljemala/payments-sample/pkg/internal/auth"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+	"github.com/michaljemala/payments-sample/pkg/internal/service"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+	"github.com/michaljemala/payments-sample/pkg/internal/store/memory"
 	"github.com/michaljemala/payments-sample/pkg/internal/store/sql"
 )
 
 type Config struct {
-	Prefix string
-	Driver string
-	DSN    string
-	Logger *log.Logger
+	Prefix            string
+	Driver            string
+	DSN               string
+	IdempotencyKeyTTL time.Duration
+	EnumCacheTTL      time.Duration
+	WorkerInterval    time.Duration
+	WorkerMaxAttempts int
+	WorkerBackoff     time.Duration
+	Gateways          map[string]gateway.Gateway
+	GatewayTimeout    time.Duration
+	EventSinks        []events.Sink
+	EventTimeout      time.Duration
+	ChangesInterval   time.Duration
+	PaymentFileMode   string
+	Clock             func() time.Time
+	Logger            *log.Logger
 }
 
 type API struct {
-	config  Config
-	db      *sql.DB
-	handler http.Handler
+	config     Config
+	db         io.Closer
+	handler    http.Handler
+	worker     *Worker
+	dispatcher *Worker
+	relay      *Worker
+	webhooks   *Worker
+	changes    *changeFeed
 }
 
-const jsonApiContentType = "application/vnd.api+json"
+const (
+	jsonApiContentType       = "application/vnd.api+json"
+	memoryDriver             = "memory"
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+	defaultEnumCacheTTL      = time.Minute
+	defaultWorkerInterval    = 10 * time.Second
+	defaultWorkerMaxAttempts = 5
+	defaultWorkerBackoff     = time.Minute
+	maxWorkerBackoff         = time.Hour
+	maxRelayBackoff          = 10 * time.Minute
+	defaultGatewayTimeout    = 30 * time.Second
+	defaultEventTimeout      = 10 * time.Second
+	defaultChangesInterval   = time.Second
+	defaultPaymentFileMode   = domain.PaymentImportAllOrNothing
+)
 
 func NewAPI(c Config) (*API, error) {
-	db, err := sql.Connect(sql.Config{
-		Driver: c.Driver,
-		DSN:    c.DSN,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect to store: %v", err)
+	if c.PaymentFileMode == "" {
+		c.PaymentFileMode = string(defaultPaymentFileMode)
+	}
+	if !domain.PaymentImportMode(c.PaymentFileMode).IsValid() {
+		return nil, fmt.Errorf("invalid payment file mode %q", c.PaymentFileMode)
+	}
+
+	var (
+		db                io.Closer
+		txManager         store.TxManager
+		paymentStore      paymentStore
+		enumStore         enumStore
+		idempotencyStore  idempotencyStore
+		historyStore      paymentHistoryStore
+		eventStore        paymentEventStore
+		subscriptionStore webhookSubscriptionStore
+		deliveryStore     webhookDeliveryStore
+		calendarStore     calendarStore
+	)
+
+	switch c.Driver {
+	case memoryDriver:
+		memDB := memory.NewDB()
+		txManager = memory.NewTxManager(memDB)
+
+		memEnumStore := newMemoryEnumStore()
+		err := seedMemoryStore(txManager, memEnumStore)
+		if err != nil {
+			return nil, fmt.Errorf("unable to seed store: %v", err)
+		}
+
+		db = memDB
+		paymentStore = newMemoryPaymentStore()
+		enumStore = memEnumStore
+		idempotencyStore = newMemoryIdempotencyStore()
+		historyStore = newMemoryPaymentHistoryStore()
+		eventStore = newMemoryPaymentEventStore()
+		subscriptionStore = newMemoryWebhookSubscriptionStore()
+		deliveryStore = newMemoryWebhookDeliveryStore()
+		calendarStore = newMemoryCalendarStore()
+	default:
+		sqlDB, err := sql.Connect(sql.Config{
+			Driver: c.Driver,
+			DSN:    c.DSN,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to store: %v", err)
+		}
+
+		db = sqlDB
+		txManager = sql.NewTxManager(sqlDB)
+		paymentStore = newPaymentStore()
+		enumStore = newEnumStore()
+		idempotencyStore = newIdempotencyStore()
+		historyStore = newPaymentHistoryStore()
+		eventStore = newPaymentEventStore()
+		subscriptionStore = newWebhookSubscriptionStore()
+		deliveryStore = newWebhookDeliveryStore()
+		calendarStore = newCalendarStore()
+	}
+
+	idempotencyKeyTTL := c.IdempotencyKeyTTL
+	if idempotencyKeyTTL <= 0 {
+		idempotencyKeyTTL = defaultIdempotencyKeyTTL
+	}
+
+	enumCacheTTL := c.EnumCacheTTL
+	if enumCacheTTL <= 0 {
+		enumCacheTTL = defaultEnumCacheTTL
+	}
+	cachedEnumStore := newCachedEnumStore(txManager, enumStore, enumCacheTTL)
+
+	clock := c.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	policy := retryPolicy{
+		maxAttempts: c.WorkerMaxAttempts,
+		backoff:     c.WorkerBackoff,
+		maxBackoff:  maxWorkerBackoff,
+	}
+	if policy.maxAttempts <= 0 {
+		policy.maxAttempts = defaultWorkerMaxAttempts
+	}
+	if policy.backoff <= 0 {
+		policy.backoff = defaultWorkerBackoff
+	}
+	workerInterval := c.WorkerInterval
+	if workerInterval <= 0 {
+		workerInterval = defaultWorkerInterval
 	}
 
-	txManager := sql.NewTxManager(db)
-	paymentStore := newPaymentStore()
-	enumStore := newEnumStore()
-	service := newPaymentService(txManager, paymentStore, enumStore, c.Logger)
+	gatewayTimeout := c.GatewayTimeout
+	if gatewayTimeout <= 0 {
+		gatewayTimeout = defaultGatewayTimeout
+	}
 
-	api := newAPI(c, service)
+	service := newPaymentService(txManager, paymentStore, cachedEnumStore, idempotencyStore, historyStore, eventStore, calendarStore, c.Gateways, gatewayTimeout, idempotencyKeyTTL, clock, c.Logger)
+	enumService := newEnumService(txManager, cachedEnumStore, cachedEnumStore)
+	calendarService := newCalendarService(txManager, calendarStore, clock)
+	eventTimeout := c.EventTimeout
+	if eventTimeout <= 0 {
+		eventTimeout = defaultEventTimeout
+	}
+	webhookService := newWebhookService(txManager, subscriptionStore, deliveryStore, events.NewWebhook(), eventTimeout, clock)
+
+	api := newAPI(c, service, enumService, calendarService, webhookService)
 	api.db = db
+	api.worker = newWorker("scheduler", service.ExecuteDue, policy, workerInterval, c.Logger)
+	api.dispatcher = newWorker("dispatcher", service.DispatchSubmitted, policy, workerInterval, c.Logger)
+	// The webhooks are scheduled by the relay the same way as the events are
+	// published to the other sinks.
+	sinks := append([]events.Sink{webhookService}, c.EventSinks...)
+	relay := newEventRelay(txManager, eventStore, sinks, eventTimeout, clock, c.Logger)
+	// The relay retries the events for as long as the sinks are down, with
+	// a backoff capped lower than the one of the payments, so the events
+	// follow soon once the sinks recover.
+	relayPolicy := retryPolicy{backoff: policy.backoff, maxBackoff: maxRelayBackoff}
+	api.relay = newWorker("relay", relay.Relay, relayPolicy, workerInterval, c.Logger)
+	api.webhooks = newWorker("webhooks", webhookService.DeliverPending, policy, workerInterval, c.Logger)
+	changesInterval := c.ChangesInterval
+	if changesInterval <= 0 {
+		changesInterval = defaultChangesInterval
+	}
+	api.changes = newChangeFeed(txManager, eventStore, changesInterval, c.Logger)
 
 	return api, nil
 }
 
-func newAPI(c Config, service paymentService) *API {
+func seedMemoryStore(txManager store.TxManager, enumStore *memoryEnumStore) error {
+	svc := &service.Generic{TxManager: txManager}
+	return svc.WithTransaction(context.Background(), enumStore.Seed)
+}
+
+func newAPI(c Config, service paymentService, enumService enumService, calendarService calendarService, webhookService webhookService) *API {
 	api := api2go.NewAPI(c.Prefix)
 	api.ContentType = jsonApiContentType
-	api.AddResource(&domain.Payment{}, newResource(service))
-	return &API{config: c, handler: api.Handler()}
+	api.UseMiddleware(resource.HeaderMiddleware)
+
+	paymentResource := newResource(service)
+	api.AddResource(&domain.Payment{}, paymentResource)
+
+	paymentsURL := resourceURL(c.Prefix, "payments")
+	for action, status := range paymentActions {
+		api.Router().Handle("POST", paymentsURL+"/:id/"+action, paymentResource.transitionHandler(status))
+	}
+	api.Router().Handle("POST", paymentsURL+"/:id/restore", paymentResource.restoreHandler())
+	api.Router().Handle("GET", paymentsURL+"/:id/history", paymentResource.historyHandler())
+	api.Router().Handle("POST", resourceURL(c.Prefix, "payment-files"), paymentResource.importHandler(domain.PaymentImportMode(c.PaymentFileMode)))
+
+	api.AddResource(&domain.Scheme{}, newEnumResource(enumNameScheme, enumService))
+	api.AddResource(&domain.Country{}, newEnumResource(enumNameCountry, enumService))
+	api.AddResource(&domain.Currency{}, newEnumResource(enumNameCurrency, enumService))
+
+	calendarResource := newCalendarResource(calendarService)
+	api.Router().Handle("GET", resourceURL(c.Prefix, "calendars")+"/:scheme/business-days", calendarResource.businessDaysHandler())
+
+	api.AddResource(&domain.WebhookSubscription{}, newWebhookSubscriptionResource(webhookService))
+	deliveryResource := newWebhookDeliveryResource(webhookService)
+	api.AddResource(&domain.WebhookDelivery{}, deliveryResource)
+	api.Router().Handle("POST", resourceURL(c.Prefix, "webhook-deliveries")+"/:id/redeliver", deliveryResource.redeliverHandler())
+
+	a := &API{config: c}
+	a.handler = auth.Middleware(a.route(resource.NotModifiedMiddleware(api.Handler())))
+	return a
+}
+
+// route streams the payment changes itself, the router of the resources
+// would take the path for the id of a payment. The stream is served behind
+// the same middleware as the resources.
+func (api *API) route(resources http.Handler) http.Handler {
+	changesURL := resourceURL(api.config.Prefix, "payments") + "/changes"
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if api.changes != nil && r.Method == "GET" && r.URL.Path == changesURL {
+			api.changes.ServeHTTP(w, r)
+			return
+		}
+		resources.ServeHTTP(w, r)
+	})
+}
+
+func resourceURL(prefix, name string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return "/" + name
+	}
+	return "/" + prefix + "/" + name
 }
 
 func (api *API) Prefix() string {
 	return api.config.Prefix
 }
 
+// Worker returns the worker executing the scheduled payments, it is not
+// running until started by the caller.
+func (api *API) Worker() *Worker {
+	return api.worker
+}
+
+// Dispatcher returns the worker sending the submitted payments to the
+// gateways of their schemes, it is not running until started by the caller.
+func (api *API) Dispatcher() *Worker {
+	return api.dispatcher
+}
+
+// Relay returns the worker publishing the payment events to the sinks, it is
+// not running until started by the caller.
+func (api *API) Relay() *Worker {
+	return api.relay
+}
+
+// Webhooks returns the worker pushing the payment events to the webhook
+// subscriptions, it is not running until started by the caller.
+func (api *API) Webhooks() *Worker {
+	return api.webhooks
+}
+
 func (api *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	api.handler.ServeHTTP(w, r)
 }
 
+// Shutdown ends the streams of the payment changes, so they do not hold up
+// the graceful shutdown of the server. The requests are served until the API
+// is closed.
+func (api *API) Shutdown() {
+	if api.changes != nil {
+		api.changes.Close()
+	}
+}
+
 func (api *API) Close() error {
 	if api.db != nil {
 		err := api.db.Close()
@@ -0,0 +1,2357 @@
+package payments
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/manyminds/api2go/jsonapi"
+
+	"github.com/michaljemala/payments-sample/internal/migrate/sqlite3"
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/events"
+	"github.com/michaljemala/payments-sample/pkg/gateway"
+	"github.com/michaljemala/payments-sample/pkg/gateway/simulator"
+)
+
+func TestAPI_MemoryDriver(t *testing.T) {
+	testAPIScenario(t, Config{Driver: "memory"})
+	testAPICursorPaging(t, Config{Driver: "memory"})
+	testAPIReferenceData(t, Config{Driver: "memory"})
+	testAPIEnumAdmin(t, Config{Driver: "memory"})
+	testAPITimestamps(t, Config{Driver: "memory"})
+	testAPIReferences(t, Config{Driver: "memory"})
+	testAPIWorker(t, Config{Driver: "memory"})
+	testAPICalendars(t, Config{Driver: "memory"})
+	testAPIDispatcher(t, Config{Driver: "memory"})
+	testAPIEvents(t, Config{Driver: "memory"})
+	testAPIWebhooks(t, Config{Driver: "memory"})
+	testAPIChanges(t, Config{Driver: "memory"})
+	testAPIPaymentFiles(t, Config{Driver: "memory"})
+	testAPIPaymentFiles(t, Config{Driver: "memory", PaymentFileMode: "partial"})
+}
+
+func TestAPI_SQLiteDriver(t *testing.T) {
+	c, cleanup := testSQLiteConfig(t)
+	defer cleanup()
+	testAPIScenario(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPICursorPaging(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPIReferenceData(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPIEnumAdmin(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPITimestamps(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPIReferences(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPIWorker(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPICalendars(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPIDispatcher(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPIEvents(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPIWebhooks(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPIChanges(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	testAPIPaymentFiles(t, c)
+
+	c, cleanup = testSQLiteConfig(t)
+	defer cleanup()
+	c.PaymentFileMode = "partial"
+	testAPIPaymentFiles(t, c)
+}
+
+func testSQLiteConfig(t *testing.T) (Config, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "payments")
+	if err != nil {
+		t.Fatalf("unable to create database directory: %v", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	dsn := "file:" + filepath.Join(dir, "payments.db") + "?_foreign_keys=1"
+
+	err = sqlite3.NewMigration(sqlite3.Config{
+		DSN:       dsn,
+		SourceURL: "file://../../scripts/migrations/sqlite3",
+		Logger:    log.New(ioutil.Discard, "", 0),
+	}).Do()
+	if err != nil {
+		cleanup()
+		t.Fatalf("unable to migrate database: %v", err)
+	}
+
+	return Config{Driver: "sqlite3", DSN: dsn}, cleanup
+}
+
+func testAPI(t *testing.T, c Config) (*API, func()) {
+	t.Helper()
+
+	api, err := NewAPI(c)
+	if err != nil {
+		t.Fatalf("unable to create payment API: %v", err)
+	}
+	return api, func() {
+		err := api.Close()
+		if err != nil {
+			t.Fatalf("unable to close payment API: %v", err)
+		}
+	}
+}
+
+func testAPIScenario(t *testing.T, c Config) {
+	t.Helper()
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	payment := domain.Payment{
+		BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+		Scheme:     "SEPA",
+		Amount: domain.Monetary{
+			Value:    domain.MustDecimalFrom("100.00"),
+			Currency: "EUR",
+		},
+		Debtor: domain.PaymentParty{
+			Name:          "John Doe",
+			AccountNumber: "DE89370400440532013000",
+			Address:       domain.Address{CountryCode: "DE"},
+		},
+		Creditor: domain.PaymentParty{
+			Name:          "Jane Roe",
+			AccountNumber: "SK3112000000198742637541",
+			Address:       domain.Address{CountryCode: "SK"},
+		},
+	}
+	body, err := jsonapi.Marshal(payment)
+	if err != nil {
+		t.Fatalf("unable to marshal json api payload: %v", err)
+	}
+
+	payment.ID = domain.MustIDFrom("5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b")
+	payment.Scheme = "SWIFT"
+	payment.Amount = domain.Monetary{Value: domain.MustDecimalFrom("2500"), Currency: "GBP"}
+	payment.Debtor.Name = "Johanna Smith"
+	payment.Debtor.AccountProvider.Code = "COBADEFF"
+	payment.Creditor.AccountProvider.Code = "SUBASKBX"
+	otherBody, err := jsonapi.Marshal(payment)
+	if err != nil {
+		t.Fatalf("unable to marshal json api payload: %v", err)
+	}
+
+	payment.ID = domain.MustIDFrom("8c5b3f0e-2d4a-4f6b-9e1c-7a2d3b4c5e6f")
+	payment.Amount = domain.Monetary{Value: domain.MustDecimalFrom("2500.5"), Currency: "HUF"}
+	fractionalBody, err := jsonapi.Marshal(payment)
+	if err != nil {
+		t.Fatalf("unable to marshal json api payload: %v", err)
+	}
+
+	url := "/payments/33b5c07b-c6bd-4a59-b02b-554256eaba5d"
+
+	steps := []struct {
+		name       string
+		method     string
+		url        string
+		body       []byte
+		header     http.Header
+		statusCode int
+		etag       string
+		found      []string
+		history    []string
+		amount     string
+	}{
+		{
+			name:       "Create payment",
+			method:     "POST",
+			url:        "/payments",
+			body:       body,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Create duplicate payment",
+			method:     "POST",
+			url:        "/payments",
+			body:       body,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Create payment with idempotency key",
+			method:     "POST",
+			url:        "/payments",
+			body:       otherBody,
+			header:     http.Header{"Idempotency-Key": []string{"key-1"}},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Replay payment with idempotency key",
+			method:     "POST",
+			url:        "/payments",
+			body:       otherBody,
+			header:     http.Header{"Idempotency-Key": []string{"key-1"}},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Create payment with fractional amount",
+			method:     "POST",
+			url:        "/payments",
+			body:       fractionalBody,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Find payment with canonical amount",
+			method:     "GET",
+			url:        "/payments/5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b",
+			statusCode: http.StatusOK,
+			amount:     "2500.00",
+		},
+		{
+			name:       "Find payments",
+			method:     "GET",
+			url:        "/payments?filter[debtor.account_number]=DE89370400440532013000,1111111111",
+			statusCode: http.StatusOK,
+			found:      []string{"33b5c07b-c6bd-4a59-b02b-554256eaba5d", "5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b"},
+		},
+		{
+			name:       "Find payments sorted by amount",
+			method:     "GET",
+			url:        "/payments?sort=-amount.value",
+			statusCode: http.StatusOK,
+			found:      []string{"5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b", "33b5c07b-c6bd-4a59-b02b-554256eaba5d"},
+		},
+		{
+			name:       "Find payments sorted by creditor name",
+			method:     "GET",
+			url:        "/payments?sort=creditor.name,-id",
+			statusCode: http.StatusOK,
+			found:      []string{"5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b", "33b5c07b-c6bd-4a59-b02b-554256eaba5d"},
+		},
+		{
+			name:       "Find payments sorted by unknown field",
+			method:     "GET",
+			url:        "/payments?sort=creditor.address",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Find payments by amount",
+			method:     "GET",
+			url:        "/payments?filter[amount.value][gt]=100&filter[amount.value][lte]=2500",
+			statusCode: http.StatusOK,
+			found:      []string{"5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b"},
+		},
+		{
+			name:       "Find payments by currency and scheme",
+			method:     "GET",
+			url:        "/payments?filter[amount.currency]=EUR&filter[scheme]=SEPA,SWIFT",
+			statusCode: http.StatusOK,
+			found:      []string{"33b5c07b-c6bd-4a59-b02b-554256eaba5d"},
+		},
+		{
+			name:       "Find payments by debtor name",
+			method:     "GET",
+			url:        "/payments?filter[debtor.name][prefix]=joh",
+			statusCode: http.StatusOK,
+			found:      []string{"33b5c07b-c6bd-4a59-b02b-554256eaba5d", "5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b"},
+		},
+		{
+			name:       "Find payments by creditor name",
+			method:     "GET",
+			url:        "/payments?filter[creditor.name][prefix]=JANE%25",
+			statusCode: http.StatusOK,
+			found:      []string{},
+		},
+		{
+			name:       "Find payment",
+			method:     "GET",
+			url:        url,
+			statusCode: http.StatusOK,
+			etag:       `"1"`,
+		},
+		{
+			name:       "Request payment approval",
+			method:     "POST",
+			url:        url + "/request-approval",
+			header:     http.Header{"X-Actor": []string{"jane.doe"}},
+			statusCode: http.StatusOK,
+			etag:       `"2"`,
+		},
+		{
+			name:       "Delete stale payment",
+			method:     "DELETE",
+			url:        url,
+			header:     http.Header{"If-Match": []string{`"1"`}},
+			statusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "Delete payment",
+			method:     "DELETE",
+			url:        url,
+			header:     http.Header{"If-Match": []string{`"2"`}, "X-Actor": []string{"john.doe"}},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "Find deleted payment",
+			method:     "GET",
+			url:        url,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Delete deleted payment",
+			method:     "DELETE",
+			url:        url,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Find payments without deleted",
+			method:     "GET",
+			url:        "/payments?filter[debtor.account_number]=DE89370400440532013000",
+			statusCode: http.StatusOK,
+			found:      []string{"5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b"},
+		},
+		{
+			name:       "Find deleted payments",
+			method:     "GET",
+			url:        "/payments?filter[deleted]=true",
+			header:     http.Header{"X-Actor": []string{"jane.doe"}},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Find deleted payments as admin",
+			method:     "GET",
+			url:        "/payments?filter[deleted]=true",
+			header:     http.Header{"X-Actor": []string{"root"}, "X-Actor-Roles": []string{"admin"}},
+			statusCode: http.StatusOK,
+			found:      []string{"33b5c07b-c6bd-4a59-b02b-554256eaba5d"},
+		},
+		{
+			name:       "Find deleted payment history",
+			method:     "GET",
+			url:        url + "/history",
+			statusCode: http.StatusOK,
+			history:    []string{"CREATE by anonymous", "UPDATE by jane.doe", "DELETE by john.doe"},
+		},
+		{
+			name:       "Restore payment",
+			method:     "POST",
+			url:        url + "/restore",
+			header:     http.Header{"X-Actor": []string{"jane.doe"}},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Restore payment as admin",
+			method:     "POST",
+			url:        url + "/restore",
+			header:     http.Header{"X-Actor": []string{"root"}, "X-Actor-Roles": []string{"admin"}},
+			statusCode: http.StatusOK,
+			etag:       `"4"`,
+		},
+		{
+			name:       "Restore restored payment",
+			method:     "POST",
+			url:        url + "/restore",
+			header:     http.Header{"X-Actor": []string{"root"}, "X-Actor-Roles": []string{"admin"}},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Find restored payment",
+			method:     "GET",
+			url:        url,
+			statusCode: http.StatusOK,
+			etag:       `"4"`,
+		},
+		{
+			name:       "Find restored payment history",
+			method:     "GET",
+			url:        url + "/history",
+			statusCode: http.StatusOK,
+			history:    []string{"CREATE by anonymous", "UPDATE by jane.doe", "DELETE by john.doe", "RESTORE by root"},
+		},
+		{
+			name:       "Find unknown payment history",
+			method:     "GET",
+			url:        "/payments/e3a3d2b1-8a1c-4b52-9a7e-1f6f7d1c2b3a/history",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, step := range steps {
+		var reqBody io.Reader
+		if step.body != nil {
+			reqBody = bytes.NewBuffer(step.body)
+		}
+		req, err := http.NewRequest(step.method, step.url, reqBody)
+		if err != nil {
+			t.Fatalf("%s: unable to create request: %v", step.name, err)
+		}
+		for key, values := range step.header {
+			req.Header[key] = values
+		}
+
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		resp := rec.Result()
+
+		if want, have := step.statusCode, resp.StatusCode; want != have {
+			t.Fatalf("%s: invalid response status: want %v, have %v", step.name, want, have)
+		}
+		if want, have := step.etag, resp.Header.Get("ETag"); want != "" && want != have {
+			t.Fatalf("%s: invalid etag: want %s, have %s", step.name, want, have)
+		}
+		if step.found != nil {
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("%s: unable to read response body: %v", step.name, err)
+			}
+			var found []domain.Payment
+			err = jsonapi.Unmarshal(data, &found)
+			if err != nil {
+				t.Fatalf("%s: unable to unmarshal json api payload: %v", step.name, err)
+			}
+			have := []string{}
+			for _, payment := range found {
+				have = append(have, payment.ID.String())
+			}
+			if want := step.found; !cmp.Equal(want, have) {
+				t.Fatalf("%s: unexpected payments: %v", step.name, cmp.Diff(want, have))
+			}
+		}
+		if step.amount != "" {
+			var doc struct {
+				Data struct {
+					Attributes struct {
+						Amount struct {
+							Value interface{} `json:"value"`
+						} `json:"amount"`
+					} `json:"attributes"`
+				} `json:"data"`
+			}
+			err := json.NewDecoder(resp.Body).Decode(&doc)
+			if err != nil {
+				t.Fatalf("%s: unable to decode response body: %v", step.name, err)
+			}
+			if want, have := step.amount, doc.Data.Attributes.Amount.Value; want != have {
+				t.Fatalf("%s: invalid amount: want %q, have %#v", step.name, want, have)
+			}
+		}
+		if step.history != nil {
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("%s: unable to read response body: %v", step.name, err)
+			}
+			var history []domain.PaymentHistory
+			err = jsonapi.Unmarshal(data, &history)
+			if err != nil {
+				t.Fatalf("%s: unable to unmarshal json api payload: %v", step.name, err)
+			}
+			have := []string{}
+			for _, entry := range history {
+				have = append(have, fmt.Sprintf("%s by %s", entry.Operation, entry.Actor))
+			}
+			if want := step.history; !cmp.Equal(want, have) {
+				t.Fatalf("%s: unexpected history: %v", step.name, cmp.Diff(want, have))
+			}
+		}
+	}
+}
+
+func testAPICursorPaging(t *testing.T, c Config) {
+	t.Helper()
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	amounts := map[string]string{
+		"10000000-0000-4000-8000-000000000000": "50.00",
+		"20000000-0000-4000-8000-000000000000": "10.00",
+		"30000000-0000-4000-8000-000000000000": "30.00",
+		"40000000-0000-4000-8000-000000000000": "30.00",
+		"50000000-0000-4000-8000-000000000000": "20.00",
+	}
+	for id, amount := range amounts {
+		body, err := jsonapi.Marshal(domain.Payment{
+			BaseObject: domain.BaseObject{ID: domain.MustIDFrom(id)},
+			Scheme:     "SEPA",
+			Amount:     domain.Monetary{Value: domain.MustDecimalFrom(amount), Currency: "EUR"},
+			Debtor:     domain.PaymentParty{AccountNumber: "DE89370400440532013000", Address: domain.Address{CountryCode: "DE"}},
+			Creditor:   domain.PaymentParty{AccountNumber: "SK3112000000198742637541", Address: domain.Address{CountryCode: "SK"}},
+		})
+		if err != nil {
+			t.Fatalf("unable to marshal json api payload: %v", err)
+		}
+		req := httptest.NewRequest("POST", "/payments", bytes.NewBuffer(body))
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if want, have := http.StatusCreated, rec.Code; want != have {
+			t.Fatalf("unable to create payment: want %d, have %d", want, have)
+		}
+	}
+
+	pages := []struct {
+		follow     string
+		found      []string
+		next, prev bool
+	}{
+		{
+			found: []string{"10000000-0000-4000-8000-000000000000", "30000000-0000-4000-8000-000000000000"},
+			next:  true,
+		},
+		{
+			follow: "next",
+			found:  []string{"40000000-0000-4000-8000-000000000000", "50000000-0000-4000-8000-000000000000"},
+			next:   true,
+			prev:   true,
+		},
+		{
+			follow: "next",
+			found:  []string{"20000000-0000-4000-8000-000000000000"},
+			prev:   true,
+		},
+		{
+			follow: "prev",
+			found:  []string{"40000000-0000-4000-8000-000000000000", "50000000-0000-4000-8000-000000000000"},
+			next:   true,
+			prev:   true,
+		},
+		{
+			follow: "prev",
+			found:  []string{"10000000-0000-4000-8000-000000000000", "30000000-0000-4000-8000-000000000000"},
+			next:   true,
+		},
+	}
+
+	var links map[string]jsonapi.Link
+	url := "/payments?sort=-amount.value&page[size]=2"
+	for i, page := range pages {
+		if page.follow != "" {
+			url = links[page.follow].Href
+		}
+
+		req := httptest.NewRequest("GET", url, nil)
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if want, have := http.StatusOK, rec.Code; want != have {
+			t.Fatalf("page %d: invalid response status: want %d, have %d", i, want, have)
+		}
+
+		var doc struct {
+			Links map[string]jsonapi.Link `json:"links"`
+		}
+		err := json.Unmarshal(rec.Body.Bytes(), &doc)
+		if err != nil {
+			t.Fatalf("page %d: unable to unmarshal links: %v", i, err)
+		}
+		links = doc.Links
+
+		var found []domain.Payment
+		err = jsonapi.Unmarshal(rec.Body.Bytes(), &found)
+		if err != nil {
+			t.Fatalf("page %d: unable to unmarshal json api payload: %v", i, err)
+		}
+		var have []string
+		for _, payment := range found {
+			have = append(have, payment.ID.String())
+		}
+		if want := page.found; !cmp.Equal(want, have) {
+			t.Fatalf("page %d: unexpected payments: %v", i, cmp.Diff(want, have))
+		}
+
+		if _, ok := links["next"]; ok != page.next {
+			t.Fatalf("page %d: unexpected next link: %v", i, links)
+		}
+		if _, ok := links["prev"]; ok != page.prev {
+			t.Fatalf("page %d: unexpected prev link: %v", i, links)
+		}
+	}
+}
+
+func testAPIReferenceData(t *testing.T, c Config) {
+	t.Helper()
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	get := func(url, ifNoneMatch string) *http.Response {
+		req := httptest.NewRequest("GET", url, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	resp := get("/currencies?filter[code]=HUF,EUR", "")
+	if want, have := http.StatusOK, resp.StatusCode; want != have {
+		t.Fatalf("invalid response status: want %v, have %v", want, have)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body: %v", err)
+	}
+	var currencies []domain.Currency
+	err = jsonapi.Unmarshal(data, &currencies)
+	if err != nil {
+		t.Fatalf("unable to unmarshal json api payload: %v", err)
+	}
+	want := []domain.Currency{
+		{Code: "EUR", Name: "Euro", MinorUnits: 2, Active: true},
+		{Code: "HUF", Name: "Hungarian forint", MinorUnits: 0, Active: true},
+	}
+	if have := currencies; !cmp.Equal(want, have) {
+		t.Fatalf("unexpected currencies: %v", cmp.Diff(want, have))
+	}
+
+	etag := resp.Header.Get("ETag")
+	if want, have := http.StatusNotModified, get("/currencies?filter[code]=HUF,EUR", etag).StatusCode; want != have {
+		t.Fatalf("invalid cached response status: want %v, have %v", want, have)
+	}
+	if want, have := http.StatusOK, get("/currencies", etag).StatusCode; want != have {
+		t.Fatalf("invalid changed response status: want %v, have %v", want, have)
+	}
+
+	data, err = ioutil.ReadAll(get("/countries?filter[name][prefix]=slo", "").Body)
+	if err != nil {
+		t.Fatalf("unable to read response body: %v", err)
+	}
+	var countries []domain.Country
+	err = jsonapi.Unmarshal(data, &countries)
+	if err != nil {
+		t.Fatalf("unable to unmarshal json api payload: %v", err)
+	}
+	if want, have := []domain.Country{{Code: "SI", Name: "Slovenia", Active: true}, {Code: "SK", Name: "Slovakia", Active: true}}, countries; !cmp.Equal(want, have) {
+		t.Fatalf("unexpected countries: %v", cmp.Diff(want, have))
+	}
+
+	if want, have := http.StatusOK, get("/schemes/SEPA", "").StatusCode; want != have {
+		t.Fatalf("invalid scheme response status: want %v, have %v", want, have)
+	}
+	if want, have := http.StatusNotFound, get("/schemes/ACH", "").StatusCode; want != have {
+		t.Fatalf("invalid missing scheme response status: want %v, have %v", want, have)
+	}
+}
+
+func testAPIEnumAdmin(t *testing.T, c Config) {
+	t.Helper()
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	paymentBody := func(id string) []byte {
+		body, err := jsonapi.Marshal(domain.Payment{
+			BaseObject: domain.BaseObject{ID: domain.MustIDFrom(id)},
+			Scheme:     "SWIFT",
+			Amount:     domain.Monetary{Value: domain.MustDecimalFrom("10.125"), Currency: "XTS"},
+			Debtor:     domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}, Address: domain.Address{CountryCode: "GB"}},
+			Creditor: domain.PaymentParty{
+				AccountNumber:   "9876543210",
+				AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"},
+				Address:         domain.Address{CountryCode: "GB"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unable to marshal json api payload: %v", err)
+		}
+		return body
+	}
+	currencyBody := []byte(`{"data":{"type":"currencies","id":"XTS","attributes":{"name":"Testing","minor_units":3}}}`)
+
+	steps := []struct {
+		name       string
+		method     string
+		url        string
+		body       []byte
+		admin      bool
+		statusCode int
+	}{
+		{
+			name:       "Create payment in unknown currency",
+			method:     "POST",
+			url:        "/payments",
+			body:       paymentBody("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Create currency by non-admin",
+			method:     "POST",
+			url:        "/currencies",
+			body:       currencyBody,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Create currency",
+			method:     "POST",
+			url:        "/currencies",
+			body:       currencyBody,
+			admin:      true,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Create duplicate currency",
+			method:     "POST",
+			url:        "/currencies",
+			body:       currencyBody,
+			admin:      true,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Create payment in created currency",
+			method:     "POST",
+			url:        "/payments",
+			body:       paymentBody("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Submit payment in created currency",
+			method:     "POST",
+			url:        "/payments/33b5c07b-c6bd-4a59-b02b-554256eaba5d/submit",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Deactivate currency",
+			method:     "DELETE",
+			url:        "/currencies/XTS",
+			admin:      true,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "Create payment in inactive currency",
+			method:     "POST",
+			url:        "/payments",
+			body:       paymentBody("5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b"),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Find payment in inactive currency",
+			method:     "GET",
+			url:        "/payments/33b5c07b-c6bd-4a59-b02b-554256eaba5d",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Accept payment in inactive currency",
+			method:     "PATCH",
+			url:        "/payments/33b5c07b-c6bd-4a59-b02b-554256eaba5d",
+			body:       []byte(`{"data":{"type":"payments","id":"33b5c07b-c6bd-4a59-b02b-554256eaba5d","attributes":{"status":"ACCEPTED"}}}`),
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Edit accepted payment in inactive currency",
+			method:     "PATCH",
+			url:        "/payments/33b5c07b-c6bd-4a59-b02b-554256eaba5d",
+			body:       []byte(`{"data":{"type":"payments","id":"33b5c07b-c6bd-4a59-b02b-554256eaba5d","attributes":{"amount":{"value":"20.000","currency":"XTS"}}}}`),
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Activate currency",
+			method:     "PATCH",
+			url:        "/currencies/XTS",
+			body:       []byte(`{"data":{"type":"currencies","id":"XTS","attributes":{"active":true}}}`),
+			admin:      true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Create payment in activated currency",
+			method:     "POST",
+			url:        "/payments",
+			body:       paymentBody("5a3f6ab4-3b6e-4bd8-a1c0-4e5d36cf2d1b"),
+			statusCode: http.StatusCreated,
+		},
+	}
+
+	for _, step := range steps {
+		req := httptest.NewRequest(step.method, step.url, bytes.NewReader(step.body))
+		req.Header.Set("X-Actor", "root")
+		if step.admin {
+			req.Header.Set("X-Actor-Roles", "admin")
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+
+		if want, have := step.statusCode, rec.Code; want != have {
+			t.Fatalf("%s: invalid response status: want %v, have %v: %s", step.name, want, have, rec.Body)
+		}
+	}
+}
+
+func testAPITimestamps(t *testing.T, c Config) {
+	t.Helper()
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	today := domain.DateOf(time.Now().UTC())
+	dates := map[string]*domain.Date{
+		"10000000-0000-4000-8000-000000000000": testDate(today.AddDate(0, 0, 2).Format("2006-01-02")),
+		"20000000-0000-4000-8000-000000000000": nil,
+		"30000000-0000-4000-8000-000000000000": testDate(today.AddDate(0, 0, 1).Format("2006-01-02")),
+		"40000000-0000-4000-8000-000000000000": testDate(today.AddDate(0, 0, -1).Format("2006-01-02")),
+	}
+	do := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+
+	created := make(map[string]domain.Payment)
+	for _, id := range []string{
+		"10000000-0000-4000-8000-000000000000",
+		"20000000-0000-4000-8000-000000000000",
+		"30000000-0000-4000-8000-000000000000",
+		"40000000-0000-4000-8000-000000000000",
+	} {
+		body, err := jsonapi.Marshal(domain.Payment{
+			BaseObject:             domain.BaseObject{ID: domain.MustIDFrom(id)},
+			Scheme:                 "SEPA",
+			Amount:                 domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "EUR"},
+			Debtor:                 domain.PaymentParty{AccountNumber: "DE89370400440532013000", Address: domain.Address{CountryCode: "DE"}},
+			Creditor:               domain.PaymentParty{AccountNumber: "SK3112000000198742637541", Address: domain.Address{CountryCode: "SK"}},
+			RequestedExecutionDate: dates[id],
+		})
+		if err != nil {
+			t.Fatalf("unable to marshal json api payload: %v", err)
+		}
+		rec := do("POST", "/payments", body)
+		if dates[id] != nil && dates[id].Before(today) {
+			testErrorPointers(t, rec.Result(), http.StatusBadRequest, "/data/attributes/requested_execution_date")
+			continue
+		}
+		if want, have := http.StatusCreated, rec.Code; want != have {
+			t.Fatalf("unable to create payment: want %d, have %d: %s", want, have, rec.Body)
+		}
+		var payment domain.Payment
+		err = jsonapi.Unmarshal(rec.Body.Bytes(), &payment)
+		if err != nil {
+			t.Fatalf("unable to unmarshal json api payload: %v", err)
+		}
+		if payment.CreatedAt.IsZero() || !payment.CreatedAt.Equal(payment.UpdatedAt) {
+			t.Fatalf("unexpected timestamps: created at %v, updated at %v", payment.CreatedAt, payment.UpdatedAt)
+		}
+		created[id] = payment
+	}
+
+	first := created["10000000-0000-4000-8000-000000000000"]
+	rec := do("PATCH", "/payments/"+first.ID.String(), []byte(`{"data":{"type":"payments","id":"`+first.ID.String()+
+		`","attributes":{"created_at":"2000-01-01T00:00:00Z","updated_at":"2000-01-01T00:00:00Z"}}}`))
+	if want, have := http.StatusOK, rec.Code; want != have {
+		t.Fatalf("unable to update payment: want %d, have %d: %s", want, have, rec.Body)
+	}
+	var updated domain.Payment
+	err := jsonapi.Unmarshal(rec.Body.Bytes(), &updated)
+	if err != nil {
+		t.Fatalf("unable to unmarshal json api payload: %v", err)
+	}
+	if !updated.CreatedAt.Equal(first.CreatedAt) || updated.UpdatedAt.Before(first.UpdatedAt) {
+		t.Fatalf("unexpected timestamps: created at %v, updated at %v", updated.CreatedAt, updated.UpdatedAt)
+	}
+
+	searches := []struct {
+		url   string
+		found []string
+	}{
+		{
+			url: "/payments?sort=requested_execution_date",
+			found: []string{
+				"30000000-0000-4000-8000-000000000000",
+				"10000000-0000-4000-8000-000000000000",
+				"20000000-0000-4000-8000-000000000000",
+			},
+		},
+		{
+			url: "/payments?sort=-created_at",
+			found: []string{
+				"30000000-0000-4000-8000-000000000000",
+				"20000000-0000-4000-8000-000000000000",
+				"10000000-0000-4000-8000-000000000000",
+			},
+		},
+		{
+			url: "/payments?filter[created_at][gt]=" + first.CreatedAt.Format(time.RFC3339Nano) + "&sort=created_at",
+			found: []string{
+				"20000000-0000-4000-8000-000000000000",
+				"30000000-0000-4000-8000-000000000000",
+			},
+		},
+		{
+			url:   "/payments?filter[updated_at][gte]=" + updated.UpdatedAt.Format(time.RFC3339Nano),
+			found: []string{"10000000-0000-4000-8000-000000000000"},
+		},
+		{
+			url:   "/payments?filter[requested_execution_date][gt]=" + today.String(),
+			found: []string{"10000000-0000-4000-8000-000000000000", "30000000-0000-4000-8000-000000000000"},
+		},
+		{
+			url:   "/payments?filter[requested_execution_date][gte]=" + dates["10000000-0000-4000-8000-000000000000"].String(),
+			found: []string{"10000000-0000-4000-8000-000000000000"},
+		},
+	}
+	for _, search := range searches {
+		rec := do("GET", search.url, nil)
+		if want, have := http.StatusOK, rec.Code; want != have {
+			t.Fatalf("%s: invalid response status: want %d, have %d: %s", search.url, want, have, rec.Body)
+		}
+		var found []domain.Payment
+		err := jsonapi.Unmarshal(rec.Body.Bytes(), &found)
+		if err != nil {
+			t.Fatalf("%s: unable to unmarshal json api payload: %v", search.url, err)
+		}
+		var have []string
+		for _, payment := range found {
+			have = append(have, payment.ID.String())
+		}
+		if want := search.found; !cmp.Equal(want, have) {
+			t.Fatalf("%s: unexpected payments: %v", search.url, cmp.Diff(want, have))
+		}
+	}
+}
+
+func testAPIReferences(t *testing.T, c Config) {
+	t.Helper()
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	payments := []domain.Payment{
+		{
+			BaseObject:            domain.BaseObject{ID: domain.MustIDFrom("10000000-0000-4000-8000-000000000000")},
+			Scheme:                "SEPA",
+			Amount:                domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "EUR"},
+			Debtor:                domain.PaymentParty{AccountNumber: "DE89370400440532013000", Address: domain.Address{CountryCode: "DE"}},
+			Creditor:              domain.PaymentParty{AccountNumber: "SK3112000000198742637541", Address: domain.Address{CountryCode: "SK"}},
+			EndToEndReference:     "INV-2019/0042",
+			NumericReference:      "1002001",
+			PaymentPurpose:        "SUPP",
+			RemittanceInformation: domain.RemittanceInformation{Structured: "RF18539007547034"},
+		},
+		{
+			BaseObject:            domain.BaseObject{ID: domain.MustIDFrom("20000000-0000-4000-8000-000000000000")},
+			Scheme:                "SWIFT",
+			Amount:                domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "GBP"},
+			Debtor:                domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}, Address: domain.Address{CountryCode: "GB"}},
+			Creditor:              domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}, Address: domain.Address{CountryCode: "GB"}},
+			EndToEndReference:     "PO-77",
+			NumericReference:      "1002001",
+			PaymentPurpose:        "Office supplies",
+			RemittanceInformation: domain.RemittanceInformation{Unstructured: "Order 77, thank you"},
+		},
+	}
+	for _, payment := range payments {
+		body, err := jsonapi.Marshal(payment)
+		if err != nil {
+			t.Fatalf("unable to marshal json api payload: %v", err)
+		}
+		req := httptest.NewRequest("POST", "/payments", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if want, have := http.StatusCreated, rec.Code; want != have {
+			t.Fatalf("unable to create payment: want %d, have %d: %s", want, have, rec.Body)
+		}
+	}
+
+	searches := []struct {
+		url   string
+		found []string
+	}{
+		{
+			url:   "/payments?filter[reference]=RF18539007547034",
+			found: []string{"10000000-0000-4000-8000-000000000000"},
+		},
+		{
+			url:   "/payments?filter[reference]=1002001",
+			found: []string{"10000000-0000-4000-8000-000000000000", "20000000-0000-4000-8000-000000000000"},
+		},
+		{
+			url:   "/payments?filter[reference]=PO-77,unknown",
+			found: []string{"20000000-0000-4000-8000-000000000000"},
+		},
+		{
+			url:   "/payments?filter[end_to_end_reference]=INV-2019/0042&filter[numeric_reference]=1002001",
+			found: []string{"10000000-0000-4000-8000-000000000000"},
+		},
+		{
+			url:   "/payments?filter[remittance_information.structured]=RF18539007547034",
+			found: []string{"10000000-0000-4000-8000-000000000000"},
+		},
+	}
+	for _, search := range searches {
+		req := httptest.NewRequest("GET", search.url, nil)
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if want, have := http.StatusOK, rec.Code; want != have {
+			t.Fatalf("%s: invalid response status: want %d, have %d: %s", search.url, want, have, rec.Body)
+		}
+		var found []domain.Payment
+		err := jsonapi.Unmarshal(rec.Body.Bytes(), &found)
+		if err != nil {
+			t.Fatalf("%s: unable to unmarshal json api payload: %v", search.url, err)
+		}
+		var have []string
+		for _, payment := range found {
+			have = append(have, payment.ID.String())
+		}
+		if want := search.found; !cmp.Equal(want, have) {
+			t.Fatalf("%s: unexpected payments: %v", search.url, cmp.Diff(want, have))
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/payments/10000000-0000-4000-8000-000000000000", nil)
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	var payment domain.Payment
+	err := jsonapi.Unmarshal(rec.Body.Bytes(), &payment)
+	if err != nil {
+		t.Fatalf("unable to unmarshal json api payload: %v", err)
+	}
+	if want, have := payments[0], payment; want.EndToEndReference != have.EndToEndReference ||
+		want.NumericReference != have.NumericReference || want.PaymentPurpose != have.PaymentPurpose ||
+		want.RemittanceInformation != have.RemittanceInformation {
+		t.Fatalf("unexpected references: %+v", have)
+	}
+}
+
+func testAPIWorker(t *testing.T, c Config) {
+	t.Helper()
+
+	now := testClock()
+	c.Clock = func() time.Time { return now }
+	c.WorkerBackoff = time.Hour
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	do := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewReader(body))
+		req.Header.Set("X-Actor", "root")
+		req.Header.Set("X-Actor-Roles", "admin")
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+	today := domain.DateOf(now)
+	dates := map[string]*domain.Date{
+		"10000000-0000-4000-8000-000000000000": testDate(today.Format("2006-01-02")),
+		"20000000-0000-4000-8000-000000000000": testDate(today.AddDate(0, 0, 1).Format("2006-01-02")),
+		"30000000-0000-4000-8000-000000000000": nil,
+	}
+	for _, id := range []string{
+		"10000000-0000-4000-8000-000000000000",
+		"20000000-0000-4000-8000-000000000000",
+		"30000000-0000-4000-8000-000000000000",
+	} {
+		body, err := jsonapi.Marshal(domain.Payment{
+			BaseObject:             domain.BaseObject{ID: domain.MustIDFrom(id)},
+			Scheme:                 "SEPA",
+			Amount:                 domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "EUR"},
+			Debtor:                 domain.PaymentParty{AccountNumber: "DE89370400440532013000", Address: domain.Address{CountryCode: "DE"}},
+			Creditor:               domain.PaymentParty{AccountNumber: "SK3112000000198742637541", Address: domain.Address{CountryCode: "SK"}},
+			RequestedExecutionDate: dates[id],
+		})
+		if err != nil {
+			t.Fatalf("unable to marshal json api payload: %v", err)
+		}
+		if rec := do("POST", "/payments", body); rec.Code != http.StatusCreated {
+			t.Fatalf("unable to create payment: want %d, have %d: %s", http.StatusCreated, rec.Code, rec.Body)
+		}
+	}
+
+	type state struct {
+		Status   domain.PaymentStatus
+		Attempts int
+	}
+	states := func() map[string]state {
+		states := make(map[string]state)
+		for id := range dates {
+			rec := do("GET", "/payments/"+id, nil)
+			var doc struct {
+				Data struct {
+					Attributes struct {
+						Status domain.PaymentStatus `json:"status"`
+					} `json:"attributes"`
+					Meta struct {
+						Execution domain.PaymentExecution `json:"execution"`
+					} `json:"meta"`
+				} `json:"data"`
+			}
+			err := json.NewDecoder(rec.Body).Decode(&doc)
+			if err != nil {
+				t.Fatalf("unable to decode response body: %v", err)
+			}
+			states[id] = state{Status: doc.Data.Attributes.Status, Attempts: doc.Data.Meta.Execution.Attempts}
+		}
+		return states
+	}
+
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		request   []byte
+		processed int
+		states    map[string]state
+	}{
+		{
+			name:      "Execute payment due today",
+			processed: 1,
+			states: map[string]state{
+				"10000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted},
+				"20000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusDraft},
+				"30000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusDraft},
+			},
+		},
+		{
+			name:      "Nothing is due",
+			processed: 0,
+			states: map[string]state{
+				"10000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted},
+				"20000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusDraft},
+				"30000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusDraft},
+			},
+		},
+		{
+			name:      "Fail invalid payment due tomorrow",
+			advance:   24 * time.Hour,
+			request:   []byte(`{"data":{"type":"currencies","id":"EUR","attributes":{"active":false}}}`),
+			processed: 1,
+			states: map[string]state{
+				"10000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted},
+				"20000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusDraft, Attempts: 1},
+				"30000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusDraft},
+			},
+		},
+		{
+			name:      "Do not retry before backoff",
+			advance:   30 * time.Minute,
+			request:   []byte(`{"data":{"type":"currencies","id":"EUR","attributes":{"active":true}}}`),
+			processed: 0,
+			states: map[string]state{
+				"10000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted},
+				"20000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusDraft, Attempts: 1},
+				"30000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusDraft},
+			},
+		},
+		{
+			name:      "Retry after backoff",
+			advance:   30 * time.Minute,
+			processed: 1,
+			states: map[string]state{
+				"10000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted},
+				"20000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted},
+				"30000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusDraft},
+			},
+		},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if step.request != nil {
+			if rec := do("PATCH", "/currencies/EUR", step.request); rec.Code != http.StatusOK {
+				t.Fatalf("%s: unable to update currency: want %d, have %d: %s", step.name, http.StatusOK, rec.Code, rec.Body)
+			}
+		}
+
+		if want, have := step.processed, api.Worker().poll(context.Background()); want != have {
+			t.Fatalf("%s: unexpected processed payments: want %d, have %d", step.name, want, have)
+		}
+		if want, have := step.states, states(); !cmp.Equal(want, have) {
+			t.Fatalf("%s: unexpected payments: %v", step.name, cmp.Diff(want, have))
+		}
+	}
+
+	rec := do("GET", "/payments/20000000-0000-4000-8000-000000000000/history", nil)
+	var history []domain.PaymentHistory
+	err := jsonapi.Unmarshal(rec.Body.Bytes(), &history)
+	if err != nil {
+		t.Fatalf("unable to unmarshal json api payload: %v", err)
+	}
+	have := []string{}
+	for _, entry := range history {
+		have = append(have, fmt.Sprintf("%s by %s", entry.Operation, entry.Actor))
+	}
+	if want := []string{"CREATE by root", "UPDATE by scheduler"}; !cmp.Equal(want, have) {
+		t.Fatalf("unexpected history: %v", cmp.Diff(want, have))
+	}
+
+	// The requested execution dates which have passed since do not block the
+	// payments from being changed, only a newly requested date is checked.
+	today = domain.DateOf(now)
+	edits := []struct {
+		name       string
+		id         string
+		attributes string
+		advance    time.Duration
+		statusCode int
+	}{
+		{
+			name:       "Accept payment executed yesterday",
+			id:         "10000000-0000-4000-8000-000000000000",
+			attributes: `{"status":"ACCEPTED"}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Request execution of draft today",
+			id:         "30000000-0000-4000-8000-000000000000",
+			attributes: `{"requested_execution_date":"` + today.String() + `"}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Edit draft requested to be executed yesterday",
+			id:         "30000000-0000-4000-8000-000000000000",
+			attributes: `{"amount":{"value":"20.00","currency":"EUR"}}`,
+			advance:    24 * time.Hour,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Request execution of draft yesterday",
+			id:         "30000000-0000-4000-8000-000000000000",
+			attributes: `{"requested_execution_date":"` + today.AddDays(-1).String() + `"}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, edit := range edits {
+		now = now.Add(edit.advance)
+		body := []byte(`{"data":{"type":"payments","id":"` + edit.id + `","attributes":` + edit.attributes + `}}`)
+		if rec := do("PATCH", "/payments/"+edit.id, body); rec.Code != edit.statusCode {
+			t.Fatalf("%s: invalid response status: want %d, have %d: %s", edit.name, edit.statusCode, rec.Code, rec.Body)
+		}
+	}
+}
+
+func testAPICalendars(t *testing.T, c Config) {
+	t.Helper()
+
+	// After the cut-off of both SEPA and SWIFT on the Thursday before Easter.
+	now := time.Date(2019, 4, 18, 15, 0, 0, 0, time.UTC)
+	c.Clock = func() time.Time { return now }
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	do := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+	sepa := func(id string, requested *domain.Date) domain.Payment {
+		return domain.Payment{
+			BaseObject:             domain.BaseObject{ID: domain.MustIDFrom(id)},
+			Scheme:                 "SEPA",
+			Amount:                 domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "EUR"},
+			Debtor:                 domain.PaymentParty{AccountNumber: "DE89370400440532013000", Address: domain.Address{CountryCode: "DE"}},
+			Creditor:               domain.PaymentParty{AccountNumber: "SK3112000000198742637541", Address: domain.Address{CountryCode: "SK"}},
+			RequestedExecutionDate: requested,
+		}
+	}
+	payments := []struct {
+		payment   domain.Payment
+		valueDate string
+	}{
+		{
+			payment:   sepa("10000000-0000-4000-8000-000000000000", nil),
+			valueDate: "2019-04-23",
+		},
+		{
+			payment:   sepa("20000000-0000-4000-8000-000000000000", testDate("2019-04-22")),
+			valueDate: "2019-04-23",
+		},
+		{
+			payment:   sepa("30000000-0000-4000-8000-000000000000", testDate("2019-04-25")),
+			valueDate: "2019-04-25",
+		},
+		{
+			payment: domain.Payment{
+				BaseObject:             domain.BaseObject{ID: domain.MustIDFrom("40000000-0000-4000-8000-000000000000")},
+				Scheme:                 "SWIFT",
+				Amount:                 domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "GBP"},
+				Debtor:                 domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}, Address: domain.Address{CountryCode: "GB"}},
+				Creditor:               domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}, Address: domain.Address{CountryCode: "GB"}},
+				RequestedExecutionDate: testDate("2019-05-06"),
+				ValueDate:              testDate("2019-05-06"),
+			},
+			valueDate: "2019-05-07",
+		},
+	}
+	for _, p := range payments {
+		body, err := jsonapi.Marshal(p.payment)
+		if err != nil {
+			t.Fatalf("unable to marshal json api payload: %v", err)
+		}
+		rec := do("POST", "/payments", body)
+		if want, have := http.StatusCreated, rec.Code; want != have {
+			t.Fatalf("unable to create payment: want %d, have %d: %s", want, have, rec.Body)
+		}
+		rec = do("GET", "/payments/"+p.payment.ID.String(), nil)
+		var payment domain.Payment
+		err = jsonapi.Unmarshal(rec.Body.Bytes(), &payment)
+		if err != nil {
+			t.Fatalf("unable to unmarshal json api payload: %v", err)
+		}
+		if payment.ValueDate == nil || payment.ValueDate.String() != p.valueDate {
+			t.Fatalf("payment %s: invalid value date: want %s, have %v", payment.ID, p.valueDate, payment.ValueDate)
+		}
+	}
+
+	rec := do("PATCH", "/payments/30000000-0000-4000-8000-000000000000",
+		[]byte(`{"data":{"type":"payments","id":"30000000-0000-4000-8000-000000000000","attributes":{"requested_execution_date":"2019-04-27"}}}`))
+	if want, have := http.StatusOK, rec.Code; want != have {
+		t.Fatalf("unable to update payment: want %d, have %d: %s", want, have, rec.Body)
+	}
+	var updated domain.Payment
+	err := jsonapi.Unmarshal(rec.Body.Bytes(), &updated)
+	if err != nil {
+		t.Fatalf("unable to unmarshal json api payload: %v", err)
+	}
+	if updated.ValueDate == nil || updated.ValueDate.String() != "2019-04-29" {
+		t.Fatalf("invalid value date of updated payment: want 2019-04-29, have %v", updated.ValueDate)
+	}
+
+	steps := []struct {
+		name       string
+		url        string
+		statusCode int
+		days       []string
+	}{
+		{
+			name:       "Business days around Easter",
+			url:        "/calendars/SEPA/business-days?filter[date][gte]=2019-04-18&filter[date][lte]=2019-04-24",
+			statusCode: http.StatusOK,
+			days:       []string{"2019-04-18T14:00:00Z", "2019-04-23T14:00:00Z", "2019-04-24T14:00:00Z"},
+		},
+		{
+			name:       "Business days from today",
+			url:        "/calendars/SWIFT/business-days?filter[date][lte]=2019-04-23",
+			statusCode: http.StatusOK,
+			days:       []string{"2019-04-18T13:00:00Z", "2019-04-23T13:00:00Z"},
+		},
+		{
+			name:       "Reversed range",
+			url:        "/calendars/SEPA/business-days?filter[date][gte]=2019-04-24&filter[date][lte]=2019-04-18",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Too long range",
+			url:        "/calendars/SEPA/business-days?filter[date][gte]=2019-01-01&filter[date][lte]=2021-01-01",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid date",
+			url:        "/calendars/SEPA/business-days?filter[date][gte]=tomorrow",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown scheme",
+			url:        "/calendars/FPS/business-days",
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, step := range steps {
+		rec := do("GET", step.url, nil)
+		if want, have := step.statusCode, rec.Code; want != have {
+			t.Fatalf("%s: invalid response status: want %d, have %d: %s", step.name, want, have, rec.Body)
+		}
+		if step.days == nil {
+			continue
+		}
+		var doc struct {
+			Data []struct {
+				Attributes domain.BusinessDay `json:"attributes"`
+			} `json:"data"`
+		}
+		err := json.NewDecoder(rec.Body).Decode(&doc)
+		if err != nil {
+			t.Fatalf("%s: unable to decode response body: %v", step.name, err)
+		}
+		have := []string{}
+		for _, d := range doc.Data {
+			have = append(have, d.Attributes.CutOff.UTC().Format(time.RFC3339))
+		}
+		if want := step.days; !cmp.Equal(want, have) {
+			t.Fatalf("%s: unexpected business days: %v", step.name, cmp.Diff(want, have))
+		}
+	}
+}
+
+type testGateway func(context.Context, *domain.Payment) (*gateway.Ack, error)
+
+func (g testGateway) Send(ctx context.Context, payment *domain.Payment) (*gateway.Ack, error) {
+	return g(ctx, payment)
+}
+
+func testAPIDispatcher(t *testing.T, c Config) {
+	t.Helper()
+
+	now := testClock()
+	c.Clock = func() time.Time { return now }
+	c.WorkerBackoff = time.Hour
+	c.WorkerMaxAttempts = 2
+
+	sim := simulator.New(simulator.Rule{
+		Action:                simulator.ActionReject,
+		CreditorAccountNumber: "DE89370400440532013000",
+		Reason:                "Unknown creditor",
+	})
+	unavailable := true
+	unanswered := domain.MustIDFrom("50000000-0000-4000-8000-000000000000")
+	c.GatewayTimeout = 10 * time.Millisecond
+	c.Gateways = map[string]gateway.Gateway{
+		"SEPA": sim,
+		"SWIFT": testGateway(func(ctx context.Context, payment *domain.Payment) (*gateway.Ack, error) {
+			if unavailable || payment.ID == unanswered {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return sim.Send(ctx, payment)
+		}),
+	}
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	do := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+	sepa := func(id, creditor string) domain.Payment {
+		return domain.Payment{
+			BaseObject: domain.BaseObject{ID: domain.MustIDFrom(id)},
+			Scheme:     "SEPA",
+			Amount:     domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "EUR"},
+			Debtor:     domain.PaymentParty{AccountNumber: "SK3112000000198742637541", Address: domain.Address{CountryCode: "SK"}},
+			Creditor:   domain.PaymentParty{AccountNumber: creditor, Address: domain.Address{CountryCode: "DE"}},
+		}
+	}
+	swift := func(id string) domain.Payment {
+		return domain.Payment{
+			BaseObject: domain.BaseObject{ID: domain.MustIDFrom(id)},
+			Scheme:     "SWIFT",
+			Amount:     domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "GBP"},
+			Debtor:     domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}, Address: domain.Address{CountryCode: "GB"}},
+			Creditor:   domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}, Address: domain.Address{CountryCode: "GB"}},
+		}
+	}
+	payments := []domain.Payment{
+		sepa("10000000-0000-4000-8000-000000000000", "SK3112000000198742637541"),
+		sepa("20000000-0000-4000-8000-000000000000", "DE89370400440532013000"),
+		swift("30000000-0000-4000-8000-000000000000"),
+		swift("50000000-0000-4000-8000-000000000000"),
+		sepa("40000000-0000-4000-8000-000000000000", "SK3112000000198742637541"),
+	}
+	for i, payment := range payments {
+		body, err := jsonapi.Marshal(payment)
+		if err != nil {
+			t.Fatalf("unable to marshal json api payload: %v", err)
+		}
+		if rec := do("POST", "/payments", body); rec.Code != http.StatusCreated {
+			t.Fatalf("unable to create payment: want %d, have %d: %s", http.StatusCreated, rec.Code, rec.Body)
+		}
+		if i == len(payments)-1 {
+			// The last payment is left as a draft.
+			break
+		}
+		if rec := do("POST", "/payments/"+payment.ID.String()+"/submit", nil); rec.Code != http.StatusOK {
+			t.Fatalf("unable to submit payment: want %d, have %d: %s", http.StatusOK, rec.Code, rec.Body)
+		}
+	}
+
+	type state struct {
+		Status          domain.PaymentStatus
+		SchemeReference string
+		RejectionReason string
+		Attempts        int
+	}
+	states := func() map[string]state {
+		states := make(map[string]state)
+		for _, payment := range payments {
+			rec := do("GET", "/payments/"+payment.ID.String(), nil)
+			var doc struct {
+				Data struct {
+					Attributes domain.Payment `json:"attributes"`
+					Meta       struct {
+						Execution domain.PaymentExecution `json:"execution"`
+					} `json:"meta"`
+				} `json:"data"`
+			}
+			err := json.NewDecoder(rec.Body).Decode(&doc)
+			if err != nil {
+				t.Fatalf("unable to decode response body: %v", err)
+			}
+			states[payment.ID.String()] = state{
+				Status:          doc.Data.Attributes.Status,
+				SchemeReference: doc.Data.Attributes.SchemeReference,
+				RejectionReason: doc.Data.Attributes.RejectionReason,
+				Attempts:        doc.Data.Meta.Execution.Attempts,
+			}
+		}
+		return states
+	}
+	accepted := state{Status: domain.PaymentStatusAccepted, SchemeReference: "SIM-10000000-0000-4000-8000-000000000000"}
+	rejected := state{Status: domain.PaymentStatusRejected, RejectionReason: "Unknown creditor"}
+	draft := state{Status: domain.PaymentStatusDraft}
+
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		available bool
+		processed int
+		states    map[string]state
+	}{
+		{
+			name:      "Send submitted payments",
+			processed: 4,
+			states: map[string]state{
+				"10000000-0000-4000-8000-000000000000": accepted,
+				"20000000-0000-4000-8000-000000000000": rejected,
+				"30000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted, Attempts: 1},
+				"40000000-0000-4000-8000-000000000000": draft,
+				"50000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted, Attempts: 1},
+			},
+		},
+		{
+			name:      "Do not resend before backoff",
+			advance:   30 * time.Minute,
+			available: true,
+			processed: 0,
+			states: map[string]state{
+				"10000000-0000-4000-8000-000000000000": accepted,
+				"20000000-0000-4000-8000-000000000000": rejected,
+				"30000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted, Attempts: 1},
+				"40000000-0000-4000-8000-000000000000": draft,
+				"50000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusSubmitted, Attempts: 1},
+			},
+		},
+		{
+			name:      "Resend after backoff",
+			advance:   30 * time.Minute,
+			available: true,
+			processed: 2,
+			states: map[string]state{
+				"10000000-0000-4000-8000-000000000000": accepted,
+				"20000000-0000-4000-8000-000000000000": rejected,
+				"30000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusAccepted, SchemeReference: "SIM-30000000-0000-4000-8000-000000000000"},
+				"40000000-0000-4000-8000-000000000000": draft,
+				"50000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusUnconfirmed, Attempts: 2},
+			},
+		},
+		{
+			name:      "Do not resend unconfirmed payment",
+			advance:   24 * time.Hour,
+			available: true,
+			processed: 0,
+			states: map[string]state{
+				"10000000-0000-4000-8000-000000000000": accepted,
+				"20000000-0000-4000-8000-000000000000": rejected,
+				"30000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusAccepted, SchemeReference: "SIM-30000000-0000-4000-8000-000000000000"},
+				"40000000-0000-4000-8000-000000000000": draft,
+				"50000000-0000-4000-8000-000000000000": {Status: domain.PaymentStatusUnconfirmed, Attempts: 2},
+			},
+		},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		unavailable = !step.available
+
+		if want, have := step.processed, api.Dispatcher().poll(context.Background()); want != have {
+			t.Fatalf("%s: unexpected processed payments: want %d, have %d", step.name, want, have)
+		}
+		if want, have := step.states, states(); !cmp.Equal(want, have) {
+			t.Fatalf("%s: unexpected payments: %v", step.name, cmp.Diff(want, have))
+		}
+	}
+
+	for _, id := range []string{"10000000-0000-4000-8000-000000000000", "50000000-0000-4000-8000-000000000000"} {
+		rec := do("GET", "/payments/"+id+"/history", nil)
+		var history []domain.PaymentHistory
+		err := jsonapi.Unmarshal(rec.Body.Bytes(), &history)
+		if err != nil {
+			t.Fatalf("unable to unmarshal json api payload: %v", err)
+		}
+		have := []string{}
+		for _, entry := range history {
+			have = append(have, fmt.Sprintf("%s by %s", entry.Operation, entry.Actor))
+		}
+		if want := []string{"CREATE by anonymous", "UPDATE by anonymous", "UPDATE by dispatcher"}; !cmp.Equal(want, have) {
+			t.Fatalf("payment %s: unexpected history: %v", id, cmp.Diff(want, have))
+		}
+	}
+
+	// The unconfirmed payment is sent again once submitted manually.
+	if rec := do("POST", "/payments/"+unanswered.String()+"/submit", nil); rec.Code != http.StatusOK {
+		t.Fatalf("unable to submit unconfirmed payment: want %d, have %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	if want, have := 1, api.Dispatcher().poll(context.Background()); want != have {
+		t.Fatalf("unexpected processed payments: want %d, have %d", want, have)
+	}
+	if want, have := (state{Status: domain.PaymentStatusSubmitted, Attempts: 1}), states()[unanswered.String()]; want != have {
+		t.Fatalf("unexpected unconfirmed payment: want %+v, have %+v", want, have)
+	}
+}
+
+type testSink func(context.Context, *domain.PaymentEvent) error
+
+func (s testSink) Publish(ctx context.Context, event *domain.PaymentEvent) error {
+	return s(ctx, event)
+}
+
+func testAPIEvents(t *testing.T, c Config) {
+	t.Helper()
+
+	now := testClock()
+	c.Clock = func() time.Time { return now }
+	c.WorkerBackoff = 10 * time.Minute
+	c.WorkerMaxAttempts = 2
+	var logs bytes.Buffer
+	c.Logger = log.New(&logs, "", 0)
+
+	first, second, poisoned := "10000000-0000-4000-8000-000000000000", "20000000-0000-4000-8000-000000000000", "30000000-0000-4000-8000-000000000000"
+	unavailable, rejecting := true, true
+	published := make(map[domain.ID][]string)
+	c.EventSinks = []events.Sink{
+		testSink(func(_ context.Context, event *domain.PaymentEvent) error {
+			if unavailable {
+				return fmt.Errorf("sink unavailable")
+			}
+			if rejecting && event.PaymentID.String() == poisoned && event.Type == domain.PaymentEventCreated {
+				return fmt.Errorf("event rejected")
+			}
+			return nil
+		}),
+		testSink(func(_ context.Context, event *domain.PaymentEvent) error {
+			published[event.PaymentID] = append(published[event.PaymentID], fmt.Sprintf("%s by %s", event.Type, event.Actor))
+			return nil
+		}),
+	}
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	do := func(method, url string, header http.Header, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewReader(body))
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+	actor := http.Header{"X-Actor": []string{"jane.doe"}}
+
+	for _, id := range []string{first, second, poisoned} {
+		body, err := jsonapi.Marshal(domain.Payment{
+			BaseObject: domain.BaseObject{ID: domain.MustIDFrom(id)},
+			Scheme:     "SEPA",
+			Amount:     domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "EUR"},
+			Debtor:     domain.PaymentParty{AccountNumber: "SK3112000000198742637541", Address: domain.Address{CountryCode: "SK"}},
+			Creditor:   domain.PaymentParty{AccountNumber: "DE89370400440532013000", Address: domain.Address{CountryCode: "DE"}},
+		})
+		if err != nil {
+			t.Fatalf("unable to marshal json api payload: %v", err)
+		}
+		if rec := do("POST", "/payments", actor, body); rec.Code != http.StatusCreated {
+			t.Fatalf("unable to create payment: want %d, have %d: %s", http.StatusCreated, rec.Code, rec.Body)
+		}
+	}
+	requests := []struct {
+		method, url string
+		header      http.Header
+		body        string
+		statusCode  int
+	}{
+		{
+			method:     "PATCH",
+			url:        "/payments/" + first,
+			header:     actor,
+			body:       `{"data":{"type":"payments","id":"` + first + `","attributes":{"payment_purpose":"RENT"}}}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			method:     "POST",
+			url:        "/payments/" + first + "/submit",
+			header:     http.Header{"X-Actor": []string{"john.doe"}},
+			statusCode: http.StatusOK,
+		},
+		{
+			method:     "DELETE",
+			url:        "/payments/" + second,
+			header:     http.Header{"If-Match": []string{`"1"`}, "X-Actor": []string{"john.doe"}},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			method:     "PATCH",
+			url:        "/payments/" + poisoned,
+			header:     actor,
+			body:       `{"data":{"type":"payments","id":"` + poisoned + `","attributes":{"payment_purpose":"RENT"}}}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			// A failed change does not announce anything.
+			method:     "DELETE",
+			url:        "/payments/" + first,
+			statusCode: http.StatusConflict,
+		},
+	}
+	for _, r := range requests {
+		if rec := do(r.method, r.url, r.header, []byte(r.body)); rec.Code != r.statusCode {
+			t.Fatalf("%s %s: want %d, have %d: %s", r.method, r.url, r.statusCode, rec.Code, rec.Body)
+		}
+	}
+
+	all := map[domain.ID][]string{
+		domain.MustIDFrom(first): {
+			"payment.created by jane.doe",
+			"payment.updated by jane.doe",
+			"payment.status_changed by john.doe",
+		},
+		domain.MustIDFrom(second): {
+			"payment.created by jane.doe",
+			"payment.deleted by john.doe",
+		},
+	}
+	accepted := map[domain.ID][]string{domain.MustIDFrom(poisoned): {
+		"payment.created by jane.doe",
+		"payment.updated by jane.doe",
+	}}
+	for id, events := range all {
+		accepted[id] = events
+	}
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		available bool
+		accepting bool
+		processed int
+		published map[domain.ID][]string
+	}{
+		{
+			name:      "Keep events when sink is unavailable",
+			processed: 1,
+			published: map[domain.ID][]string{},
+		},
+		{
+			name:      "Do not republish before backoff",
+			advance:   5 * time.Minute,
+			available: true,
+			processed: 0,
+			published: map[domain.ID][]string{},
+		},
+		{
+			name:      "Republish in order after backoff",
+			advance:   5 * time.Minute,
+			available: true,
+			processed: 3,
+			published: all,
+		},
+		{
+			name:      "Keep retrying rejected event with capped backoff",
+			advance:   24 * time.Hour,
+			available: true,
+			processed: 1,
+			published: all,
+		},
+		{
+			name:      "Publish rejected event in order once accepted",
+			advance:   10 * time.Minute,
+			available: true,
+			accepting: true,
+			processed: 2,
+			published: accepted,
+		},
+		{
+			name:      "Publish once",
+			available: true,
+			accepting: true,
+			processed: 0,
+			published: accepted,
+		},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		unavailable, rejecting = !step.available, !step.accepting
+
+		if want, have := step.processed, api.Relay().poll(context.Background()); want != have {
+			t.Fatalf("%s: unexpected processed batches: want %d, have %d", step.name, want, have)
+		}
+		if want, have := step.published, published; !cmp.Equal(want, have) {
+			t.Fatalf("%s: unexpected published events: %v", step.name, cmp.Diff(want, have))
+		}
+	}
+
+	if want := "of payment " + poisoned + ", attempt 3, retrying in 10m0s: event rejected"; !strings.Contains(logs.String(), want) {
+		t.Fatalf("failed attempt not logged: %s", logs.String())
+	}
+}
+
+func testAPIWebhooks(t *testing.T, c Config) {
+	t.Helper()
+
+	now := testClock()
+	c.Clock = func() time.Time { return now }
+	c.WorkerBackoff = time.Hour
+	c.WorkerMaxAttempts = 2
+
+	const secret = "0123456789abcdef"
+	unavailable := true
+	received := []string{}
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("unable to read webhook: %v", err)
+		}
+		err = events.Verify(secret, r.Header, body, now, time.Minute)
+		if err != nil {
+			t.Errorf("unable to verify webhook: %v", err)
+		}
+		if unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, r.Header.Get(events.WebhookEventHeader))
+	}))
+	defer receiver.Close()
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	do := func(method, url string, header http.Header, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewReader([]byte(body)))
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+	admin := http.Header{"X-Actor": []string{"root"}, "X-Actor-Roles": []string{"admin"}}
+	deliveries := func(query string) []string {
+		t.Helper()
+		rec := do("GET", "/webhook-deliveries"+query, admin, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unable to list webhook deliveries: want %d, have %d: %s", http.StatusOK, rec.Code, rec.Body)
+		}
+		var list []domain.WebhookDelivery
+		err := jsonapi.Unmarshal(rec.Body.Bytes(), &list)
+		if err != nil {
+			t.Fatalf("unable to unmarshal json api payload: %v", err)
+		}
+		have := []string{}
+		for _, d := range list {
+			have = append(have, fmt.Sprintf("%s %s after %d", d.EventType, d.Status, d.Attempts))
+		}
+		return have
+	}
+
+	all, deleted := "a0000000-0000-4000-8000-000000000000", "b0000000-0000-4000-8000-000000000000"
+	payment := "10000000-0000-4000-8000-000000000000"
+	paymentBody := `{"data":{"type":"payments","id":"` + payment + `","attributes":{"scheme":"SEPA",` +
+		`"amount":{"value":"10.00","currency":"EUR"},` +
+		`"debtor":{"account_number":"SK3112000000198742637541","address":{"country_code":"SK"}},` +
+		`"creditor":{"account_number":"DE89370400440532013000","address":{"country_code":"DE"}}}}}`
+
+	requests := []struct {
+		name        string
+		method, url string
+		header      http.Header
+		body        string
+		statusCode  int
+	}{
+		{
+			name:       "Subscribe as non-admin",
+			method:     "POST",
+			url:        "/webhook-subscriptions",
+			body:       `{"data":{"type":"webhook-subscriptions","id":"` + all + `","attributes":{"target_url":"` + receiver.URL + `"}}}`,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Subscribe with short secret",
+			method:     "POST",
+			url:        "/webhook-subscriptions",
+			header:     admin,
+			body:       `{"data":{"type":"webhook-subscriptions","id":"` + all + `","attributes":{"target_url":"` + receiver.URL + `","secret":"short"}}}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Subscribe to all events",
+			method:     "POST",
+			url:        "/webhook-subscriptions",
+			header:     admin,
+			body:       `{"data":{"type":"webhook-subscriptions","id":"` + all + `","attributes":{"target_url":"` + receiver.URL + `","secret":"` + secret + `"}}}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Subscribe to deleted payments",
+			method:     "POST",
+			url:        "/webhook-subscriptions",
+			header:     admin,
+			body:       `{"data":{"type":"webhook-subscriptions","id":"` + deleted + `","attributes":{"target_url":"` + receiver.URL + `","event_types":["payment.deleted"],"secret":"` + secret + `"}}}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Create payment",
+			method:     "POST",
+			url:        "/payments",
+			body:       paymentBody,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "Update payment",
+			method:     "PATCH",
+			url:        "/payments/" + payment,
+			body:       `{"data":{"type":"payments","id":"` + payment + `","attributes":{"amount":{"value":"20.00","currency":"EUR"}}}}`,
+			statusCode: http.StatusOK,
+		},
+	}
+	for _, r := range requests {
+		if rec := do(r.method, r.url, r.header, r.body); rec.Code != r.statusCode {
+			t.Fatalf("%s: want %d, have %d: %s", r.name, r.statusCode, rec.Code, rec.Body)
+		}
+	}
+
+	rec := do("GET", "/webhook-subscriptions/"+all, admin, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unable to get webhook subscription: want %d, have %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	if bytes.Contains(rec.Body.Bytes(), []byte(secret)) {
+		t.Fatalf("unexpected secret in webhook subscription: %s", rec.Body)
+	}
+	if want, have := 2, api.Relay().poll(context.Background()); want != have {
+		t.Fatalf("unexpected published events: want %d, have %d", want, have)
+	}
+
+	steps := []struct {
+		name       string
+		advance    time.Duration
+		available  bool
+		processed  int
+		received   []string
+		deliveries []string
+	}{
+		{
+			name:       "Retry in order when receiver is unavailable",
+			processed:  1,
+			received:   []string{},
+			deliveries: []string{"payment.created PENDING after 1", "payment.updated PENDING after 0"},
+		},
+		{
+			name:       "Do not retry before backoff",
+			advance:    30 * time.Minute,
+			processed:  0,
+			received:   []string{},
+			deliveries: []string{"payment.created PENDING after 1", "payment.updated PENDING after 0"},
+		},
+		{
+			name:       "Dead letter when out of attempts",
+			advance:    30 * time.Minute,
+			processed:  2,
+			received:   []string{},
+			deliveries: []string{"payment.created DEAD_LETTER after 2", "payment.updated PENDING after 1"},
+		},
+		{
+			name:       "Keep dead letter",
+			advance:    24 * time.Hour,
+			available:  true,
+			processed:  1,
+			received:   []string{"payment.updated"},
+			deliveries: []string{"payment.created DEAD_LETTER after 2", "payment.updated DELIVERED after 2"},
+		},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		unavailable = !step.available
+
+		if want, have := step.processed, api.Webhooks().poll(context.Background()); want != have {
+			t.Fatalf("%s: unexpected processed deliveries: want %d, have %d", step.name, want, have)
+		}
+		if want, have := step.received, received; !cmp.Equal(want, have) {
+			t.Fatalf("%s: unexpected received webhooks: %v", step.name, cmp.Diff(want, have))
+		}
+		if want, have := step.deliveries, deliveries(""); !cmp.Equal(want, have) {
+			t.Fatalf("%s: unexpected deliveries: %v", step.name, cmp.Diff(want, have))
+		}
+	}
+
+	var dead []domain.WebhookDelivery
+	err := jsonapi.Unmarshal(do("GET", "/webhook-deliveries?filter[status]=DEAD_LETTER", admin, "").Body.Bytes(), &dead)
+	if err != nil || len(dead) != 1 {
+		t.Fatalf("unable to find dead letter: %v", err)
+	}
+	if rec := do("POST", "/webhook-deliveries/"+dead[0].ID.String()+"/redeliver", nil, ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("unexpected redelivery as non-admin: want %d, have %d: %s", http.StatusForbidden, rec.Code, rec.Body)
+	}
+	if rec := do("POST", "/webhook-deliveries/"+dead[0].ID.String()+"/redeliver", admin, ""); rec.Code != http.StatusOK {
+		t.Fatalf("unable to redeliver: want %d, have %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	if rec := do("DELETE", "/payments/"+payment, http.Header{"If-Match": []string{`"2"`}}, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("unable to delete payment: want %d, have %d: %s", http.StatusNoContent, rec.Code, rec.Body)
+	}
+	if want, have := 1, api.Relay().poll(context.Background()); want != have {
+		t.Fatalf("unexpected published events: want %d, have %d", want, have)
+	}
+
+	if want, have := 3, api.Webhooks().poll(context.Background()); want != have {
+		t.Fatalf("unexpected processed deliveries: want %d, have %d", want, have)
+	}
+	if want, have := []string{"payment.updated", "payment.created", "payment.deleted", "payment.deleted"}, received; !cmp.Equal(want, have) {
+		t.Fatalf("unexpected received webhooks: %v", cmp.Diff(want, have))
+	}
+	want := []string{"payment.deleted DELIVERED after 1"}
+	if have := deliveries("?filter[subscription_id]=" + deleted); !cmp.Equal(want, have) {
+		t.Fatalf("unexpected deliveries of subscription: %v", cmp.Diff(want, have))
+	}
+	want = []string{
+		"payment.created DELIVERED after 1",
+		"payment.updated DELIVERED after 2",
+		"payment.deleted DELIVERED after 1",
+		"payment.deleted DELIVERED after 1",
+	}
+	if have := deliveries(""); !cmp.Equal(want, have) {
+		t.Fatalf("unexpected deliveries: %v", cmp.Diff(want, have))
+	}
+
+	other := "20000000-0000-4000-8000-000000000000"
+	if rec := do("POST", "/payments", nil, strings.Replace(paymentBody, payment, other, 1)); rec.Code != http.StatusCreated {
+		t.Fatalf("unable to create payment: want %d, have %d: %s", http.StatusCreated, rec.Code, rec.Body)
+	}
+	if want, have := 1, api.Relay().poll(context.Background()); want != have {
+		t.Fatalf("unexpected published events: want %d, have %d", want, have)
+	}
+	if rec := do("DELETE", "/webhook-subscriptions/"+all, admin, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("unable to unsubscribe: want %d, have %d: %s", http.StatusNoContent, rec.Code, rec.Body)
+	}
+	if rec := do("GET", "/webhook-subscriptions/"+all, admin, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("unexpected deleted subscription: want %d, have %d: %s", http.StatusNotFound, rec.Code, rec.Body)
+	}
+	want = []string{
+		"payment.created DELIVERED after 1",
+		"payment.updated DELIVERED after 2",
+		"payment.deleted DELIVERED after 1",
+		"payment.created DEAD_LETTER after 0",
+	}
+	if have := deliveries("?filter[subscription_id]=" + all); !cmp.Equal(want, have) {
+		t.Fatalf("unexpected deliveries after unsubscribe: %v", cmp.Diff(want, have))
+	}
+	if want, have := 0, api.Webhooks().poll(context.Background()); want != have {
+		t.Fatalf("unexpected processed deliveries after unsubscribe: want %d, have %d", want, have)
+	}
+	if rec := do("POST", "/webhook-deliveries/"+dead[0].ID.String()+"/redeliver", admin, ""); rec.Code != http.StatusConflict {
+		t.Fatalf("unexpected redelivery after unsubscribe: want %d, have %d: %s", http.StatusConflict, rec.Code, rec.Body)
+	}
+}
+
+func testAPIChanges(t *testing.T, c Config) {
+	t.Helper()
+
+	c.ChangesInterval = 10 * time.Millisecond
+
+	api, close := testAPI(t, c)
+	defer close()
+	server := httptest.NewServer(api)
+	defer server.Close()
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	do := func(method, url string, header http.Header, body []byte) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+url, bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("unable to create request: %v", err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unable to send request: %v", err)
+		}
+		return resp
+	}
+	create := func(id, debtor, creditor string) {
+		t.Helper()
+		body, err := jsonapi.Marshal(domain.Payment{
+			BaseObject: domain.BaseObject{ID: domain.MustIDFrom(id)},
+			Scheme:     "SEPA",
+			Amount:     domain.Monetary{Value: domain.MustDecimalFrom("10.00"), Currency: "EUR"},
+			Debtor:     domain.PaymentParty{AccountNumber: debtor, Address: domain.Address{CountryCode: debtor[:2]}},
+			Creditor:   domain.PaymentParty{AccountNumber: creditor, Address: domain.Address{CountryCode: creditor[:2]}},
+		})
+		if err != nil {
+			t.Fatalf("unable to marshal json api payload: %v", err)
+		}
+		resp := do("POST", "/payments", nil, body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("unable to create payment: want %d, have %d", http.StatusCreated, resp.StatusCode)
+		}
+	}
+	type change struct{ id, event, paymentID string }
+	next := func(r *bufio.Reader) (change, error) {
+		var c change
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return c, err
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "" && c.event != "":
+				return c, nil
+			case strings.HasPrefix(line, "id: "):
+				c.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				c.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				var event domain.PaymentEvent
+				err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event)
+				if err != nil {
+					return c, err
+				}
+				c.paymentID = event.PaymentID.String()
+			}
+		}
+	}
+
+	const sk, de = "SK3112000000198742637541", "DE89370400440532013000"
+	first, second, third := "10000000-0000-4000-8000-000000000000", "20000000-0000-4000-8000-000000000000", "30000000-0000-4000-8000-000000000000"
+	filter := "/payments/changes?filter[creditor.account_number]=" + de
+
+	for _, tc := range []struct {
+		name   string
+		url    string
+		header http.Header
+	}{
+		{name: "Deleted filter", url: "/payments/changes?filter[deleted]=true"},
+		{name: "Unsupported filter", url: "/payments/changes?filter[unknown]=1"},
+		{name: "Invalid Last-Event-ID", url: filter, header: http.Header{"Last-Event-Id": []string{"abc"}}},
+	} {
+		resp := do("GET", tc.url, tc.header, nil)
+		resp.Body.Close()
+		if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+			t.Fatalf("%s: unexpected status code: want %d, have %d", tc.name, want, have)
+		}
+	}
+
+	// The changes made before the stream is opened are not streamed.
+	create(first, sk, de)
+
+	stream := do("GET", filter, nil, nil)
+	defer stream.Body.Close()
+	if want, have := "text/event-stream", stream.Header.Get("Content-Type"); want != have {
+		t.Fatalf("unexpected content type: want %q, have %q", want, have)
+	}
+
+	create(second, sk, de)
+	create(third, de, sk)
+	body := `{"data":{"type":"payments","id":"` + first + `","attributes":{"payment_purpose":"RENT"}}}`
+	resp := do("PATCH", "/payments/"+first, nil, []byte(body))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unable to update payment: want %d, have %d", http.StatusOK, resp.StatusCode)
+	}
+	resp = do("DELETE", "/payments/"+third, nil, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("unable to delete payment: want %d, have %d", http.StatusNoContent, resp.StatusCode)
+	}
+
+	reader := bufio.NewReader(stream.Body)
+	var have []change
+	for i := 0; i < 2; i++ {
+		c, err := next(reader)
+		if err != nil {
+			t.Fatalf("unable to read change: %v", err)
+		}
+		have = append(have, c)
+	}
+	want := []string{"payment.created " + second, "payment.updated " + first}
+	for i, c := range have {
+		if c.event+" "+c.paymentID != want[i] {
+			t.Fatalf("unexpected change %d: want %q, have %q", i, want[i], c.event+" "+c.paymentID)
+		}
+	}
+
+	// Resuming skips the received changes as well as the filtered ones.
+	resumed := do("GET", filter, http.Header{"Last-Event-Id": []string{have[0].id}}, nil)
+	defer resumed.Body.Close()
+	reader = bufio.NewReader(resumed.Body)
+	c2, err := next(reader)
+	if err != nil {
+		t.Fatalf("unable to read resumed change: %v", err)
+	}
+	if c2 != have[1] {
+		t.Fatalf("unexpected resumed change: want %v, have %v", have[1], c2)
+	}
+
+	api.Shutdown()
+	if c, err := next(reader); err != io.EOF {
+		t.Fatalf("unexpected change after shutdown: %v, %v", c, err)
+	}
+}
+
+const testPaymentFile = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">
+  <CstmrCdtTrfInitn>
+    <GrpHdr><MsgId>%s</MsgId><NbOfTxs>2</NbOfTxs><CtrlSum>30.00</CtrlSum><InitgPty><Nm>ACME</Nm></InitgPty></GrpHdr>
+    <PmtInf>
+      <PmtInfId>PMT-1</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <PmtTpInf><SvcLvl><Cd>SEPA</Cd></SvcLvl></PmtTpInf>
+      <Dbtr><Nm>ACME</Nm><PstlAdr><Ctry>DE</Ctry></PstlAdr></Dbtr>
+      <DbtrAcct><Id><IBAN>DE89370400440532013000</IBAN></Id></DbtrAcct>
+      <DbtrAgt><FinInstnId><BIC>COBADEFFXXX</BIC></FinInstnId></DbtrAgt>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>E2E-1</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="EUR">10.00</InstdAmt></Amt>
+        <Cdtr><Nm>Jane Roe</Nm><PstlAdr><Ctry>SK</Ctry></PstlAdr></Cdtr>
+        <CdtrAcct><Id><IBAN>SK3112000000198742637541</IBAN></Id></CdtrAcct>
+      </CdtTrfTxInf>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>E2E-2</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="EUR">20.00</InstdAmt></Amt>
+        <Cdtr><Nm>John Smith</Nm><PstlAdr><Ctry>%s</Ctry></PstlAdr></Cdtr>
+        <CdtrAcct><Id><IBAN>SK3112000000198742637541</IBAN></Id></CdtrAcct>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`
+
+func testAPIPaymentFiles(t *testing.T, c Config) {
+	t.Helper()
+
+	api, close := testAPI(t, c)
+	defer close()
+
+	type outcome struct {
+		statusCode int
+		statuses   []domain.PaymentFileStatus
+		payments   int
+	}
+	var (
+		allOrNothing = domain.PaymentImportAllOrNothing
+		partial      = domain.PaymentImportPartial
+		imported     = domain.PaymentFileStatusImported
+		rejected     = domain.PaymentFileStatusRejected
+		notImported  = domain.PaymentFileStatusNotImported
+	)
+	steps := []struct {
+		name     string
+		body     string
+		outcomes map[domain.PaymentImportMode]outcome
+	}{
+		{
+			name: "Import invalid file",
+			body: strings.Replace(fmt.Sprintf(testPaymentFile, "MSG-1", "SK"), "<NbOfTxs>2", "<NbOfTxs>3", 1),
+			outcomes: map[domain.PaymentImportMode]outcome{
+				allOrNothing: {statusCode: http.StatusBadRequest},
+				partial:      {statusCode: http.StatusBadRequest},
+			},
+		},
+		{
+			name: "Import file with rejected payment",
+			body: fmt.Sprintf(testPaymentFile, "MSG-1", "US"),
+			outcomes: map[domain.PaymentImportMode]outcome{
+				allOrNothing: {statusCode: http.StatusUnprocessableEntity, statuses: []domain.PaymentFileStatus{notImported, rejected}},
+				partial:      {statusCode: http.StatusCreated, statuses: []domain.PaymentFileStatus{imported, rejected}, payments: 1},
+			},
+		},
+		{
+			name: "Import fixed file",
+			body: fmt.Sprintf(testPaymentFile, "MSG-1", "SK"),
+			outcomes: map[domain.PaymentImportMode]outcome{
+				allOrNothing: {statusCode: http.StatusCreated, statuses: []domain.PaymentFileStatus{imported, imported}, payments: 2},
+				partial:      {statusCode: http.StatusCreated, statuses: []domain.PaymentFileStatus{rejected, imported}, payments: 2},
+			},
+		},
+		{
+			name: "Import file again",
+			body: fmt.Sprintf(testPaymentFile, "MSG-1", "SK"),
+			outcomes: map[domain.PaymentImportMode]outcome{
+				allOrNothing: {statusCode: http.StatusUnprocessableEntity, statuses: []domain.PaymentFileStatus{rejected, rejected}, payments: 2},
+				partial:      {statusCode: http.StatusUnprocessableEntity, statuses: []domain.PaymentFileStatus{rejected, rejected}, payments: 2},
+			},
+		},
+	}
+	mode := domain.PaymentImportMode(c.PaymentFileMode)
+	if mode == "" {
+		mode = defaultPaymentFileMode
+	}
+	for _, step := range steps {
+		want := step.outcomes[mode]
+
+		req := httptest.NewRequest("POST", "/payment-files", strings.NewReader(step.body))
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		if rec.Code != want.statusCode {
+			t.Fatalf("%s (%s): unexpected status code: want %d, have %d: %s", step.name, mode, want.statusCode, rec.Code, rec.Body)
+		}
+
+		if want.statuses != nil {
+			var report domain.PaymentFileReport
+			err := jsonapi.Unmarshal(rec.Body.Bytes(), &report)
+			if err != nil {
+				t.Fatalf("%s (%s): unable to unmarshal json api payload: %v", step.name, mode, err)
+			}
+			have := []domain.PaymentFileStatus{}
+			for _, result := range report.Results {
+				have = append(have, result.Status)
+				if result.Status == rejected && len(result.Errors) == 0 {
+					t.Errorf("%s (%s): payment %s rejected without errors", step.name, mode, result.EndToEndID)
+				}
+			}
+			if diff := cmp.Diff(want.statuses, have); diff != "" {
+				t.Errorf("%s (%s): unexpected statuses: (-want +have)\n%s", step.name, mode, diff)
+			}
+		}
+
+		req = httptest.NewRequest("GET", "/payments", nil)
+		rec = httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		var payments []domain.Payment
+		err := jsonapi.Unmarshal(rec.Body.Bytes(), &payments)
+		if err != nil {
+			t.Fatalf("%s (%s): unable to unmarshal json api payload: %v", step.name, mode, err)
+		}
+		if len(payments) != want.payments {
+			t.Errorf("%s (%s): unexpected number of payments: want %d, have %d", step.name, mode, want.payments, len(payments))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/manyminds/api2go/routing"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+)
+
+type calendarService interface {
+	BusinessDays(context.Context, string, domain.BusinessDaySearchRequest) ([]domain.BusinessDay, error)
+}
+
+// calendarResource exposes the business days of the payment schemes. The
+// responses carry an ETag of their content, so the clients can cache them.
+type calendarResource struct {
+	*resource.Generic
+	service calendarService
+}
+
+func newCalendarResource(service calendarService) calendarResource {
+	return calendarResource{
+		Generic: &resource.Generic{ParamFunc: calendarParamFunc},
+		service: service,
+	}
+}
+
+func (r calendarResource) businessDaysHandler() routing.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, params map[string]string, _ map[string]interface{}) {
+		filter, err := r.ExtractSearchFilter(req.URL.Query())
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		days, err := r.service.BusinessDays(req.Context(), params["scheme"], domain.BusinessDaySearchRequest{
+			SearchFilter: filter,
+		})
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		w.Header().Set("ETag", resource.ContentETag(days))
+		resource.WriteObject(w, jsonApiContentType, days, http.StatusOK)
+	}
+}
+
+func calendarParamFunc(key string, op resource.FilterOperator, values []string) (interface{}, error) {
+	switch {
+	case key == "date" && (op == resource.FilterOperatorGte || op == resource.FilterOperatorLte):
+		value, err := domain.DateFrom(values[0])
+		if err != nil {
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				err.Error(),
+				fmt.Sprintf("field %q: %q has not valid format", key, values[0]),
+			)
+		}
+		return value, nil
+	default:
+		return nil, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"unsupported filter parameter",
+			resource.FilterKey(key, op),
+		)
+	}
+}
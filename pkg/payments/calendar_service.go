@@ -0,0 +1,79 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/service"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+const (
+	defaultBusinessDaysRange = 30
+	maxBusinessDaysRange     = 366
+)
+
+type defaultCalendarService struct {
+	*service.Generic
+
+	calendarStore calendarStore
+	clock         func() time.Time
+}
+
+func newCalendarService(txManager store.TxManager, calendarStore calendarStore, clock func() time.Time) calendarService {
+	return &defaultCalendarService{
+		Generic:       &service.Generic{TxManager: txManager},
+		calendarStore: calendarStore,
+		clock:         clock,
+	}
+}
+
+// BusinessDays lists the business days of the scheme within the requested
+// range. The range starts today in the time zone of the scheme and spans 30
+// days unless requested otherwise.
+func (s *defaultCalendarService) BusinessDays(ctx context.Context, scheme string, searchReq domain.BusinessDaySearchRequest) (days []domain.BusinessDay, err error) {
+	var calendar *domain.Calendar
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		calendar, err = s.calendarStore.Get(tx, scheme)
+		return err
+	})
+	if errors.Is(err, errors.ErrCodeGenericNotFound) {
+		return nil, errors.Generic(
+			errors.ErrCodeGenericNotFound,
+			"calendar not found",
+			fmt.Sprintf("scheme %q has no calendar", scheme),
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dateRange := searchReq.DateRange()
+	from := domain.DateOf(s.clock().In(calendar.Location))
+	if dateRange.Gte != nil {
+		from = *dateRange.Gte
+	}
+	to := from.AddDays(defaultBusinessDaysRange)
+	if dateRange.Lte != nil {
+		to = *dateRange.Lte
+	}
+	switch {
+	case to.Before(from):
+		return nil, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid date range",
+			fmt.Sprintf("range end %s precedes its start %s", to, from),
+		)
+	case from.AddDays(maxBusinessDaysRange).Before(to):
+		return nil, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid date range",
+			fmt.Sprintf("range must not span more than %d days", maxBusinessDaysRange),
+		)
+	}
+
+	return calendar.BusinessDays(from, to), nil
+}
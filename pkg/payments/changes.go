@@ -0,0 +1,210 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+	"github.com/michaljemala/payments-sample/pkg/internal/service"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+const (
+	changeFeedBatchSize = 100
+	changeFeedHeartbeat = 15 * time.Second
+)
+
+// changeFeed streams the payment events as Server-Sent Events. Each stream
+// polls the event log on its own, the id of a streamed event is its position,
+// so a client reconnecting with the Last-Event-ID header resumes right after
+// the last event it has received. The events are positioned in the order they
+// were committed, before each poll, so an event whose transaction commits
+// late is streamed after the events committed before it instead of being
+// skipped.
+type changeFeed struct {
+	*service.Generic
+
+	eventStore paymentEventStore
+	filter     *resource.Generic
+	interval   time.Duration
+	heartbeat  time.Duration
+	batchSize  int
+	logger     *log.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newChangeFeed(txManager store.TxManager, eventStore paymentEventStore, interval time.Duration, logger *log.Logger) *changeFeed {
+	if logger == nil {
+		logger = log.New(ioutil.Discard, "", 0)
+	}
+	return &changeFeed{
+		Generic:    &service.Generic{TxManager: txManager},
+		eventStore: eventStore,
+		filter:     &resource.Generic{ParamFunc: paymentParamFunc},
+		interval:   interval,
+		heartbeat:  changeFeedHeartbeat,
+		batchSize:  changeFeedBatchSize,
+		logger:     logger,
+		done:       make(chan struct{}),
+	}
+}
+
+// Close ends all the open streams, the clients are expected to reconnect
+// to another server.
+func (f *changeFeed) Close() {
+	f.closeOnce.Do(func() { close(f.done) })
+}
+
+func (f *changeFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		resource.WriteError(w, jsonApiContentType, errors.Generic(
+			errors.ErrCodeGenericInternal,
+			"unable to stream payment changes",
+			"streaming not supported",
+		))
+		return
+	}
+
+	filter, err := f.filter.ExtractSearchFilter(r.URL.Query())
+	if err != nil {
+		resource.WriteError(w, jsonApiContentType, err)
+		return
+	}
+	if _, ok := filter["deleted"]; ok {
+		resource.WriteError(w, jsonApiContentType, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"unsupported filter parameter",
+			"the changes include the deletions, they can not be filtered by deleted",
+		))
+		return
+	}
+
+	position, err := f.lastEventID(r)
+	if err != nil {
+		resource.WriteError(w, jsonApiContentType, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	searchReq := domain.PaymentSearchRequest{SearchFilter: filter}
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	idle := time.Now()
+	for {
+		events, err := f.find(r.Context(), position)
+		if err != nil {
+			// The client resumes from the last event it has received.
+			if r.Context().Err() == nil {
+				f.logger.Printf("changes: unable to find payment events: %v", err)
+			}
+			return
+		}
+		for _, event := range events {
+			position = event.Position
+			if event.Payment != nil && !matchesPaymentFilter(searchReq, event.Payment.ToPayment()) {
+				continue
+			}
+			err := writeChange(w, event)
+			if err != nil {
+				return
+			}
+			idle = time.Now()
+		}
+		if time.Since(idle) >= f.heartbeat {
+			_, err := fmt.Fprint(w, ": heartbeat\n\n")
+			if err != nil {
+				return
+			}
+			idle = time.Now()
+		}
+		flusher.Flush()
+
+		if len(events) == f.batchSize {
+			continue
+		}
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		case <-f.done:
+			return
+		}
+	}
+}
+
+// lastEventID returns the position to stream the events after, the streams
+// without the Last-Event-ID header start with the events to come.
+func (f *changeFeed) lastEventID(r *http.Request) (position int64, err error) {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		position, err = strconv.ParseInt(id, 10, 64)
+		if err != nil || position < 0 {
+			return 0, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				"invalid Last-Event-ID header",
+				fmt.Sprintf("%q is not an event id", id),
+			)
+		}
+		return position, nil
+	}
+	err = f.position(r.Context())
+	if err != nil {
+		return 0, err
+	}
+	err = f.WithTransaction(r.Context(), func(tx store.Tx) error {
+		position, err = f.eventStore.LastPosition(tx)
+		return err
+	})
+	return position, err
+}
+
+func (f *changeFeed) find(ctx context.Context, position int64) (events []*domain.PaymentEvent, err error) {
+	err = f.position(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = f.WithTransaction(ctx, func(tx store.Tx) error {
+		events, err = f.eventStore.FindAfter(tx, position, f.batchSize)
+		return err
+	})
+	return events, err
+}
+
+// position assigns the positions to the events committed since the last
+// poll, in a transaction of its own so they are visible to the find. The
+// streams position the events in turns, the memory store rejects all but one
+// of the streams positioning at once, the events are positioned by it then.
+func (f *changeFeed) position(ctx context.Context) error {
+	err := f.WithTransaction(ctx, func(tx store.Tx) error {
+		_, err := f.eventStore.AssignPositions(tx, f.batchSize)
+		return err
+	})
+	if errors.Is(err, errors.ErrCodeDataAccessConflict) {
+		return nil
+	}
+	return err
+}
+
+func writeChange(w http.ResponseWriter, event *domain.PaymentEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Position, event.Type, data)
+	return err
+}
@@ -0,0 +1,64 @@
+package payments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/store/memory"
+)
+
+func TestChangeFeed_OutOfOrderCommits(t *testing.T) {
+	ctx := context.Background()
+	txManager := memory.NewTxManager(memory.NewDB())
+	eventStore := newMemoryPaymentEventStore()
+	feed := newChangeFeed(txManager, eventStore, 0, nil)
+
+	insert := func(paymentID string) (*domain.PaymentEvent, func() error) {
+		t.Helper()
+		tx, err := txManager.Begin(ctx)
+		if err != nil {
+			t.Fatalf("unable to begin transaction: %v", err)
+		}
+		event := &domain.PaymentEvent{ID: domain.NewID(), Type: domain.PaymentEventCreated, PaymentID: domain.MustIDFrom(paymentID)}
+		err = eventStore.Insert(tx, event)
+		if err != nil {
+			t.Fatalf("unable to insert payment event: %v", err)
+		}
+		return event, tx.Commit
+	}
+	find := func(name string, position int64, want ...*domain.PaymentEvent) int64 {
+		t.Helper()
+		events, err := feed.find(ctx, position)
+		if err != nil {
+			t.Fatalf("%s: unable to find payment events: %v", name, err)
+		}
+		if len(events) != len(want) {
+			t.Fatalf("%s: unexpected events: want %d, have %d", name, len(want), len(events))
+		}
+		for i, event := range events {
+			if event.ID != want[i].ID {
+				t.Fatalf("%s: unexpected event %d: want %s, have %s", name, i, want[i].ID, event.ID)
+			}
+			if event.Position <= position {
+				t.Fatalf("%s: unexpected position of event %d: %d is not after %d", name, i, event.Position, position)
+			}
+			position = event.Position
+		}
+		return position
+	}
+
+	// The first event is recorded first, but its transaction commits last.
+	first, commitFirst := insert("10000000-0000-4000-8000-000000000000")
+	second, commitSecond := insert("20000000-0000-4000-8000-000000000000")
+	if err := commitSecond(); err != nil {
+		t.Fatalf("unable to commit second transaction: %v", err)
+	}
+
+	position := find("Second event committed", 0, second)
+	if err := commitFirst(); err != nil {
+		t.Fatalf("unable to commit first transaction: %v", err)
+	}
+	position = find("First event committed late", position, first)
+	find("No more events", position)
+}
@@ -0,0 +1,151 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/manyminds/api2go"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+)
+
+type enumService interface {
+	Search(context.Context, domain.EnumName, domain.EnumSearchRequest) ([]domain.Enum, error)
+	Create(context.Context, domain.EnumName, domain.Enum) error
+	Update(context.Context, domain.EnumName, domain.Enum) error
+	Deactivate(context.Context, domain.EnumName, string) error
+}
+
+// enumResource exposes an enumeration as a resource, which can be changed by
+// admins only. The responses carry an ETag of their content, so the clients
+// can cache them.
+type enumResource struct {
+	*resource.Generic
+	name    domain.EnumName
+	service enumService
+}
+
+func newEnumResource(name domain.EnumName, service enumService) enumResource {
+	return enumResource{
+		Generic: &resource.Generic{ParamFunc: enumParamFunc},
+		name:    name,
+		service: service,
+	}
+}
+
+func (r enumResource) FindOne(code string, req api2go.Request) (api2go.Responder, error) {
+	enums, err := r.service.Search(req.PlainRequest.Context(), r.name, domain.EnumSearchRequest{
+		SearchFilter: resource.SearchFilter{"code": []string{code}},
+	})
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+	if len(enums) == 0 {
+		return nil, resource.WrapError(errors.Generic(
+			errors.ErrCodeGenericNotFound,
+			"enum not found",
+			fmt.Sprintf("%s %q not found", strings.ToLower(string(r.name)), code),
+		))
+	}
+
+	resource.SetHeader(req, "ETag", resource.ContentETag(enums[0]))
+
+	return resource.WrapObject(enums[0], http.StatusOK), nil
+}
+
+func (r enumResource) FindAll(req api2go.Request) (api2go.Responder, error) {
+	filter, err := r.ExtractSearchFilter(req.QueryParams)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	enums, err := r.service.Search(req.PlainRequest.Context(), r.name, domain.EnumSearchRequest{
+		SearchFilter: filter,
+	})
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+	if enums == nil {
+		enums = []domain.Enum{}
+	}
+
+	resource.SetHeader(req, "ETag", resource.ContentETag(enums))
+
+	return resource.WrapArray(enums, http.StatusOK), nil
+}
+
+func (r enumResource) Create(obj interface{}, req api2go.Request) (api2go.Responder, error) {
+	enum := obj.(domain.Enum)
+
+	err := enum.Validate()
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	err = r.service.Create(req.PlainRequest.Context(), r.name, enum)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	resource.SetHeader(req, "ETag", resource.ContentETag(enum))
+
+	return resource.WrapObject(enum, http.StatusCreated), nil
+}
+
+func (r enumResource) Update(obj interface{}, req api2go.Request) (api2go.Responder, error) {
+	enum := obj.(domain.Enum)
+
+	err := enum.Validate()
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	err = r.service.Update(req.PlainRequest.Context(), r.name, enum)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	resource.SetHeader(req, "ETag", resource.ContentETag(enum))
+
+	return resource.WrapObject(enum, http.StatusOK), nil
+}
+
+// Delete deactivates the entry, as the existing payments keep referring to it.
+func (r enumResource) Delete(code string, req api2go.Request) (api2go.Responder, error) {
+	err := r.service.Deactivate(req.PlainRequest.Context(), r.name, code)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	return resource.WrapObject(nil, http.StatusNoContent), nil
+}
+
+func enumParamFunc(key string, op resource.FilterOperator, values []string) (interface{}, error) {
+	switch {
+	case key == "code" && op == resource.FilterOperatorEq:
+		return values, nil
+	case key == "name" && op == resource.FilterOperatorPrefix:
+		return values[0], nil
+	case key == "active" && op == resource.FilterOperatorEq:
+		active, err := strconv.ParseBool(values[0])
+		if err != nil || len(values) > 1 {
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				"invalid filter parameter",
+				fmt.Sprintf("field %q: %q is not a boolean", key, strings.Join(values, ",")),
+			)
+		}
+		return active, nil
+	default:
+		return nil, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"unsupported filter parameter",
+			resource.FilterKey(key, op),
+		)
+	}
+}
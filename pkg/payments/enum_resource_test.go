@@ -0,0 +1,465 @@
+package payments
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/manyminds/api2go/jsonapi"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/mock"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+func TestEnum_FindAll(t *testing.T) {
+	testCases := []struct {
+		name        string
+		url         string
+		ifNoneMatch string
+		statusCode  int
+		out         []string
+	}{
+		{
+			name:       "All currencies",
+			url:        "/currencies",
+			statusCode: http.StatusOK,
+			out:        []string{"EUR", "HUF"},
+		},
+		{
+			name:       "Currencies by code",
+			url:        "/currencies?filter[code]=EUR,HUF",
+			statusCode: http.StatusOK,
+			out:        []string{"EUR", "HUF"},
+		},
+		{
+			name:       "Currencies by name",
+			url:        "/currencies?filter[name][prefix]=eu",
+			statusCode: http.StatusOK,
+			out:        []string{"EUR"},
+		},
+		{
+			name:       "Active currencies",
+			url:        "/currencies?filter[active]=true",
+			statusCode: http.StatusOK,
+			out:        []string{"HUF"},
+		},
+		{
+			name:       "Invalid active filter",
+			url:        "/currencies?filter[active]=maybe",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "Cached currencies",
+			url:         "/currencies",
+			ifNoneMatch: testEnumETag,
+			statusCode:  http.StatusNotModified,
+		},
+		{
+			name:       "Unsupported filter",
+			url:        "/currencies?filter[minor_units]=2",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, _, close := testEnumHandler(t, testEnumStore())
+			defer close()
+
+			req := httptest.NewRequest("GET", tc.url, nil)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
+
+			if want, have := tc.statusCode, resp.StatusCode; want != have {
+				t.Fatalf("invalid response status: want %v, have %v", want, have)
+			}
+			if tc.out == nil {
+				return
+			}
+			if resp.Header.Get("ETag") == "" {
+				t.Fatalf("missing etag")
+			}
+
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %v", err)
+			}
+			var currencies []domain.Currency
+			err = jsonapi.Unmarshal(data, &currencies)
+			if err != nil {
+				t.Fatalf("unable to unmarshal json api payload: %v", err)
+			}
+			have := []string{}
+			for _, c := range currencies {
+				have = append(have, c.Code)
+			}
+			if want := tc.out; !cmp.Equal(want, have) {
+				t.Fatalf("unexpected currencies: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+func TestEnum_FindOne(t *testing.T) {
+	testCases := []struct {
+		name       string
+		url        string
+		statusCode int
+		out        domain.Currency
+	}{
+		{
+			name:       "Existing currency",
+			url:        "/currencies/HUF",
+			statusCode: http.StatusOK,
+			out:        domain.Currency{Code: "HUF", Name: "Hungarian forint", Active: true},
+		},
+		{
+			name:       "Missing currency",
+			url:        "/currencies/USD",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, _, close := testEnumHandler(t, testEnumStore())
+			defer close()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", tc.url, nil))
+			resp := rec.Result()
+
+			if want, have := tc.statusCode, resp.StatusCode; want != have {
+				t.Fatalf("invalid response status: want %v, have %v", want, have)
+			}
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %v", err)
+			}
+			var out domain.Currency
+			err = jsonapi.Unmarshal(data, &out)
+			if err != nil {
+				t.Fatalf("unable to unmarshal json api payload: %v", err)
+			}
+			if want, have := tc.out, out; !cmp.Equal(want, have) {
+				t.Fatalf("invalid currency: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+func TestEnum_Create(t *testing.T) {
+	testCases := []struct {
+		name       string
+		in         string
+		admin      bool
+		insertErr  error
+		statusCode int
+		pointers   []string
+		out        *domain.Currency
+	}{
+		{
+			name:       "Currency created by admin",
+			in:         `{"data":{"type":"currencies","id":"XTS","attributes":{"name":"Testing","minor_units":3,"max_amount":"100.000"}}}`,
+			admin:      true,
+			statusCode: http.StatusCreated,
+			out:        &domain.Currency{Code: "XTS", Name: "Testing", MinorUnits: 3, MaxAmount: testDecimal("100.000"), Active: true},
+		},
+		{
+			name:       "Currency created by non-admin",
+			in:         `{"data":{"type":"currencies","id":"XTS","attributes":{"name":"Testing"}}}`,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Invalid currency",
+			in:         `{"data":{"type":"currencies","id":"xts","attributes":{"minor_units":5,"max_amount":"-1"}}}`,
+			admin:      true,
+			statusCode: http.StatusBadRequest,
+			pointers: []string{
+				"/data/id",
+				"/data/attributes/name",
+				"/data/attributes/minor_units",
+				"/data/attributes/max_amount",
+			},
+		},
+		{
+			name:       "Existing currency",
+			in:         `{"data":{"type":"currencies","id":"EUR","attributes":{"name":"Euro"}}}`,
+			admin:      true,
+			insertErr:  errors.Generic(errors.ErrCodeGenericAlreadyExists, "unable to insert enum", ""),
+			statusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var inserted *domain.Currency
+			enumStore := testEnumStore()
+			enumStore.InsertFn = func(tx store.Tx, name domain.EnumName, enum domain.Enum) error {
+				if want, have := enumNameCurrency, name; want != have {
+					t.Fatalf("unexpected enum name: want %v, have %v", want, have)
+				}
+				inserted = enum.(*domain.Currency)
+				return tc.insertErr
+			}
+			handler, cache, close := testEnumHandler(t, enumStore)
+			defer close()
+
+			req := httptest.NewRequest("POST", "/currencies", strings.NewReader(tc.in))
+			req.Header.Set("X-Actor", "root")
+			if tc.admin {
+				req.Header.Set("X-Actor-Roles", "admin")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
+
+			if tc.pointers != nil {
+				testErrorPointers(t, resp, tc.statusCode, tc.pointers...)
+				return
+			}
+			if want, have := tc.statusCode, resp.StatusCode; want != have {
+				t.Fatalf("invalid response status: want %v, have %v", want, have)
+			}
+			if tc.out == nil {
+				if len(cache.invalidated) > 0 {
+					t.Fatalf("unexpected cache invalidation: %v", cache.invalidated)
+				}
+				return
+			}
+			opts := []cmp.Option{
+				cmp.Transformer("Decimal", func(in domain.Decimal) string {
+					return in.String()
+				}),
+			}
+
+			if want, have := tc.out, inserted; !cmp.Equal(want, have, opts...) {
+				t.Fatalf("invalid inserted currency: %v", cmp.Diff(want, have))
+			}
+			if want, have := []domain.EnumName{enumNameCurrency}, cache.invalidated; !cmp.Equal(want, have) {
+				t.Fatalf("invalid cache invalidation: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+func TestEnum_Update(t *testing.T) {
+	testCases := []struct {
+		name       string
+		url        string
+		in         string
+		admin      bool
+		statusCode int
+		out        *domain.Currency
+	}{
+		{
+			name:       "Currency updated by admin",
+			url:        "/currencies/HUF",
+			in:         `{"data":{"type":"currencies","id":"HUF","attributes":{"minor_units":2}}}`,
+			admin:      true,
+			statusCode: http.StatusOK,
+			out:        &domain.Currency{Code: "HUF", Name: "Hungarian forint", MinorUnits: 2, Active: true},
+		},
+		{
+			name:       "Currency activated by admin",
+			url:        "/currencies/EUR",
+			in:         `{"data":{"type":"currencies","id":"EUR","attributes":{"active":true}}}`,
+			admin:      true,
+			statusCode: http.StatusOK,
+			out:        &domain.Currency{Code: "EUR", Name: "Euro", MinorUnits: 2, Active: true},
+		},
+		{
+			name:       "Currency updated by non-admin",
+			url:        "/currencies/HUF",
+			in:         `{"data":{"type":"currencies","id":"HUF","attributes":{"minor_units":2}}}`,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Missing currency",
+			url:        "/currencies/USD",
+			in:         `{"data":{"type":"currencies","id":"USD","attributes":{"name":"US dollar"}}}`,
+			admin:      true,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var updated *domain.Currency
+			enumStore := testEnumStore()
+			enumStore.UpdateFn = func(tx store.Tx, name domain.EnumName, enum domain.Enum) error {
+				updated = enum.(*domain.Currency)
+				return nil
+			}
+			handler, cache, close := testEnumHandler(t, enumStore)
+			defer close()
+
+			req := httptest.NewRequest("PATCH", tc.url, strings.NewReader(tc.in))
+			req.Header.Set("X-Actor", "root")
+			if tc.admin {
+				req.Header.Set("X-Actor-Roles", "admin")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
+
+			if want, have := tc.statusCode, resp.StatusCode; want != have {
+				t.Fatalf("invalid response status: want %v, have %v", want, have)
+			}
+			if want, have := tc.out, updated; !cmp.Equal(want, have) {
+				t.Fatalf("invalid updated currency: %v", cmp.Diff(want, have))
+			}
+			if tc.out == nil {
+				return
+			}
+			if want, have := resource.ContentETag(tc.out), resp.Header.Get("ETag"); want != have {
+				t.Fatalf("invalid etag: want %v, have %v", want, have)
+			}
+			if want, have := []domain.EnumName{enumNameCurrency}, cache.invalidated; !cmp.Equal(want, have) {
+				t.Fatalf("invalid cache invalidation: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+func TestEnum_Delete(t *testing.T) {
+	testCases := []struct {
+		name       string
+		url        string
+		admin      bool
+		statusCode int
+		out        *domain.Currency
+	}{
+		{
+			name:       "Currency deactivated by admin",
+			url:        "/currencies/HUF",
+			admin:      true,
+			statusCode: http.StatusNoContent,
+			out:        &domain.Currency{Code: "HUF", Name: "Hungarian forint"},
+		},
+		{
+			name:       "Inactive currency",
+			url:        "/currencies/EUR",
+			admin:      true,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "Currency deactivated by non-admin",
+			url:        "/currencies/HUF",
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Missing currency",
+			url:        "/currencies/USD",
+			admin:      true,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var updated *domain.Currency
+			enumStore := testEnumStore()
+			enumStore.UpdateFn = func(tx store.Tx, name domain.EnumName, enum domain.Enum) error {
+				updated = enum.(*domain.Currency)
+				return nil
+			}
+			handler, _, close := testEnumHandler(t, enumStore)
+			defer close()
+
+			req := httptest.NewRequest("DELETE", tc.url, nil)
+			req.Header.Set("X-Actor", "root")
+			if tc.admin {
+				req.Header.Set("X-Actor-Roles", "admin")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
+
+			if want, have := tc.statusCode, resp.StatusCode; want != have {
+				t.Fatalf("invalid response status: want %v, have %v", want, have)
+			}
+			if want, have := tc.out, updated; !cmp.Equal(want, have) {
+				t.Fatalf("invalid deactivated currency: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+// testCurrencies holds an active currency and an inactive one.
+var testCurrencies = []domain.Currency{
+	{Code: "EUR", Name: "Euro", MinorUnits: 2},
+	{Code: "HUF", Name: "Hungarian forint", Active: true},
+}
+
+var testEnumETag = func() string {
+	var enums []domain.Enum
+	for i := range testCurrencies {
+		enums = append(enums, &testCurrencies[i])
+	}
+	return resource.ContentETag(enums)
+}()
+
+func testEnumStore() *mock.EnumStore {
+	return &mock.EnumStore{
+		FindFn: func(tx store.Tx, name domain.EnumName, req domain.EnumSearchRequest) ([]domain.Enum, error) {
+			var enums []domain.Enum
+			for i := range testCurrencies {
+				c := testCurrencies[i]
+				if codes := req.Codes(); len(codes) > 0 && !containsString(codes, c.Code) {
+					continue
+				}
+				if prefix := req.NamePrefix(); prefix != "" && !hasPrefixFold(c.Name, prefix) {
+					continue
+				}
+				if active, ok := req.Active(); ok && c.Active != active {
+					continue
+				}
+				enums = append(enums, &c)
+			}
+			return enums, nil
+		},
+	}
+}
+
+type testEnumCache struct {
+	invalidated []domain.EnumName
+}
+
+func (c *testEnumCache) Invalidate(ctx context.Context, name domain.EnumName) {
+	c.invalidated = append(c.invalidated, name)
+}
+
+func testEnumHandler(t *testing.T, enumStore enumStore) (*API, *testEnumCache, func()) {
+	t.Helper()
+
+	cache := &testEnumCache{}
+	api := newAPI(Config{}, nil, newEnumService(&mock.TxManager{}, enumStore, cache), nil, nil)
+	return api, cache, func() {
+		err := api.Close()
+		if err != nil {
+			t.Fatalf("unable to tear down enum handler: %v", err)
+		}
+	}
+}
+
+func testDecimal(s string) *domain.Decimal {
+	d := domain.MustDecimalFrom(s)
+	return &d
+}
@@ -0,0 +1,113 @@
+package payments
+
+import (
+	"context"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/auth"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+	"github.com/michaljemala/payments-sample/pkg/internal/service"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+type defaultEnumService struct {
+	*service.Generic
+
+	enumStore enumStore
+	cache     enumCache
+}
+
+type enumCache interface {
+	Invalidate(context.Context, domain.EnumName)
+}
+
+func newEnumService(txManager store.TxManager, enumStore enumStore, cache enumCache) enumService {
+	return &defaultEnumService{
+		Generic:   &service.Generic{TxManager: txManager},
+		enumStore: enumStore,
+		cache:     cache,
+	}
+}
+
+func (s *defaultEnumService) Search(ctx context.Context, name domain.EnumName, searchReq domain.EnumSearchRequest) (enums []domain.Enum, err error) {
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		enums, err = s.enumStore.Find(tx, name, searchReq)
+		return err
+	})
+	return enums, err
+}
+
+// Create adds a new active entry to the enumeration.
+func (s *defaultEnumService) Create(ctx context.Context, name domain.EnumName, enum domain.Enum) error {
+	err := authorizeEnumChange(ctx, "unable to create enum")
+	if err != nil {
+		return err
+	}
+
+	enum.SetActive(true)
+
+	return s.withChange(ctx, name, func(tx store.Tx) error {
+		return s.enumStore.Insert(tx, name, enum)
+	})
+}
+
+func (s *defaultEnumService) Update(ctx context.Context, name domain.EnumName, enum domain.Enum) error {
+	err := authorizeEnumChange(ctx, "unable to update enum")
+	if err != nil {
+		return err
+	}
+
+	return s.withChange(ctx, name, func(tx store.Tx) error {
+		return s.enumStore.Update(tx, name, enum)
+	})
+}
+
+// Deactivate makes the entry unusable for new payments, the existing payments
+// referring to it are not affected. The entry can be activated again by an update.
+func (s *defaultEnumService) Deactivate(ctx context.Context, name domain.EnumName, code string) error {
+	err := authorizeEnumChange(ctx, "unable to deactivate enum")
+	if err != nil {
+		return err
+	}
+
+	return s.withChange(ctx, name, func(tx store.Tx) error {
+		enums, err := s.enumStore.Find(tx, name, domain.EnumSearchRequest{
+			SearchFilter: resource.SearchFilter{"code": []string{code}},
+		})
+		if err != nil {
+			return err
+		}
+		if len(enums) == 0 {
+			return errors.Generic(errors.ErrCodeGenericNotFound, "unable to deactivate enum", "enum not found")
+		}
+		if !enums[0].IsActive() {
+			return nil
+		}
+
+		enums[0].SetActive(false)
+		return s.enumStore.Update(tx, name, enums[0])
+	})
+}
+
+// withChange runs the change in a transaction and invalidates the cached
+// enumeration once the change is committed.
+func (s *defaultEnumService) withChange(ctx context.Context, name domain.EnumName, fn func(store.Tx) error) error {
+	err := s.WithTransaction(ctx, fn)
+	if err != nil {
+		return err
+	}
+	s.cache.Invalidate(ctx, name)
+	return nil
+}
+
+func authorizeEnumChange(ctx context.Context, msg string) error {
+	if !auth.FromContext(ctx).HasRole(auth.RoleAdmin) {
+		return errors.Generic(
+			errors.ErrCodeGenericPermissionDenied,
+			msg,
+			"only admins can change enumerations",
+		)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package payments
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/events"
+	"github.com/michaljemala/payments-sample/pkg/internal/service"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+const relayBatchSize = 100
+
+// eventRelay publishes the payment events recorded in the outbox to the
+// sinks. An event is marked as published once all the sinks received it.
+type eventRelay struct {
+	*service.Generic
+
+	eventStore paymentEventStore
+	sinks      []events.Sink
+	batchSize  int
+	timeout    time.Duration
+	clock      func() time.Time
+	logger     *log.Logger
+}
+
+func newEventRelay(txManager store.TxManager, eventStore paymentEventStore, sinks []events.Sink, timeout time.Duration, clock func() time.Time, logger *log.Logger) *eventRelay {
+	if logger == nil {
+		logger = log.New(ioutil.Discard, "", 0)
+	}
+	return &eventRelay{
+		Generic:    &service.Generic{TxManager: txManager},
+		eventStore: eventStore,
+		sinks:      sinks,
+		batchSize:  relayBatchSize,
+		timeout:    timeout,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+// Relay publishes a batch of the pending events and reports whether there
+// were any. The events of a payment are published in order: once an event of
+// a payment fails, the following events of the payment wait until it is
+// published. A failed event is retried with the backoff of the policy for as
+// long as it takes, the attempts of the policy are not limited, as skipping
+// an event would break the order of the events of the payment. Without any
+// sink the events are kept in the outbox.
+//
+// The batch is claimed in a transaction of its own, which counts the attempts
+// and schedules the next ones past the time the publishing may take, so no
+// other relay picks the events meanwhile and no lock is held while waiting
+// for the sinks. The results are recorded in another transaction.
+func (r *eventRelay) Relay(ctx context.Context, policy retryPolicy) (bool, error) {
+	if len(r.sinks) == 0 {
+		return false, nil
+	}
+
+	var claimed []*domain.PaymentEvent
+	err := r.WithTransaction(ctx, func(tx store.Tx) (err error) {
+		now := r.clock().UTC().Truncate(time.Microsecond)
+		claimed, err = r.eventStore.ClaimPending(tx, now, r.batchSize)
+		if err != nil {
+			return err
+		}
+		lease := time.Duration(len(claimed)) * r.timeout
+		for _, event := range claimed {
+			next := now.Add(lease + policy.delay(event.Publication.Attempts))
+			event.Publication.Attempts++
+			event.Publication.NextAttemptAt = &next
+			err = r.eventStore.UpdatePublication(tx, event)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil || len(claimed) == 0 {
+		return false, err
+	}
+
+	failures := make([]error, len(claimed))
+	for i, event := range claimed {
+		failures[i] = r.publish(ctx, event)
+	}
+
+	err = r.WithTransaction(ctx, func(tx store.Tx) error {
+		now := r.clock().UTC().Truncate(time.Microsecond)
+		for i, event := range claimed {
+			publication := &event.Publication
+			switch failure := failures[i]; {
+			case failure == nil:
+				publication.Status = domain.PaymentEventPublicationPublished
+				publication.PublishedAt = &now
+			default:
+				delay := policy.delay(publication.Attempts - 1)
+				next := now.Add(delay)
+				publication.NextAttemptAt = &next
+				publication.LastError = failure.Error()
+				r.logger.Printf("relay: unable to publish payment event %s of payment %s, attempt %d, retrying in %v: %v",
+					event.ID, event.PaymentID, publication.Attempts, delay, failure)
+			}
+
+			err := r.eventStore.UpdatePublication(tx, event)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return true, err
+}
+
+// publish limits the time each event takes rather than the whole batch, so
+// a slow sink does not hold up the events following it for long.
+func (r *eventRelay) publish(ctx context.Context, event *domain.PaymentEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	for _, sink := range r.sinks {
+		err := sink.Publish(ctx, event)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,22 +4,102 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/manyminds/api2go"
+	"github.com/manyminds/api2go/routing"
 
 	"github.com/michaljemala/payments-sample/pkg/domain"
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+	"github.com/michaljemala/payments-sample/pkg/pain001"
 )
 
 type paymentService interface {
 	Search(context.Context, domain.PaymentSearchRequest) (*domain.PaymentSearchResponse, error)
 	Load(context.Context, domain.ID) (*domain.Payment, error)
-	Create(context.Context, *domain.Payment) error
-	Delete(context.Context, domain.ID) error
+	Create(context.Context, *domain.Payment, string) (*domain.Payment, bool, error)
+	Delete(context.Context, domain.ID, uint) error
+	Restore(context.Context, domain.ID) (*domain.Payment, error)
 	Update(context.Context, *domain.Payment) error
+	Transition(context.Context, domain.ID, domain.PaymentStatus) (*domain.Payment, error)
+	History(context.Context, domain.ID) ([]*domain.PaymentHistory, error)
+	Import(context.Context, *domain.PaymentFile, domain.PaymentImportMode) (*domain.PaymentFileReport, error)
 }
 
+// paymentActions maps the payment transition endpoints to the target statuses.
+var paymentActions = map[string]domain.PaymentStatus{
+	"request-approval": domain.PaymentStatusPendingApproval,
+	"submit":           domain.PaymentStatusSubmitted,
+	"accept":           domain.PaymentStatusAccepted,
+	"settle":           domain.PaymentStatusSettled,
+	"reject":           domain.PaymentStatusRejected,
+	"cancel":           domain.PaymentStatusCancelled,
+	"return":           domain.PaymentStatusReturned,
+}
+
+var paymentSortFields = []string{
+	"id",
+	"amount.value",
+	"amount.currency",
+	"scheme",
+	"status",
+	"creditor.name",
+	"creditor.account_number",
+	"debtor.name",
+	"debtor.account_number",
+	"created_at",
+	"updated_at",
+	"requested_execution_date",
+}
+
+// sortTimeLayout formats the timestamps with a fixed width, so the cursor
+// values sort the same way as the timestamps themselves.
+const sortTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// unscheduledDate stands for a missing requested execution date, so the
+// payments to be executed as soon as possible sort after the scheduled ones.
+const unscheduledDate = "9999-12-31"
+
+// paymentSortValues extracts the values of the sortable fields which make
+// up the pagination cursors.
+var paymentSortValues = map[string]func(*domain.Payment) string{
+	"id":                      func(p *domain.Payment) string { return p.ID.String() },
+	"amount.value":            func(p *domain.Payment) string { return p.Amount.Value.String() },
+	"amount.currency":         func(p *domain.Payment) string { return p.Amount.Currency },
+	"scheme":                  func(p *domain.Payment) string { return p.Scheme },
+	"status":                  func(p *domain.Payment) string { return string(p.Status) },
+	"creditor.name":           func(p *domain.Payment) string { return p.Creditor.Name },
+	"creditor.account_number": func(p *domain.Payment) string { return p.Creditor.AccountNumber },
+	"debtor.name":             func(p *domain.Payment) string { return p.Debtor.Name },
+	"debtor.account_number":   func(p *domain.Payment) string { return p.Debtor.AccountNumber },
+	"created_at":              func(p *domain.Payment) string { return p.CreatedAt.UTC().Format(sortTimeLayout) },
+	"updated_at":              func(p *domain.Payment) string { return p.UpdatedAt.UTC().Format(sortTimeLayout) },
+	"requested_execution_date": func(p *domain.Payment) string {
+		if p.RequestedExecutionDate == nil {
+			return unscheduledDate
+		}
+		return p.RequestedExecutionDate.String()
+	},
+}
+
+const paymentTieBreaker = "id"
+
+func paymentCursor(sort resource.SearchSort, payment *domain.Payment) resource.Cursor {
+	cursor := resource.Cursor{Sort: sort.String()}
+	for _, f := range sort.WithTieBreaker(paymentTieBreaker) {
+		cursor.Values = append(cursor.Values, paymentSortValues[f.Field](payment))
+	}
+	return cursor
+}
+
+const (
+	maxIdempotencyKeyLength = 255
+	maxPaymentFileSize      = 10 << 20
+)
+
 type Resource struct {
 	*resource.Generic
 	service paymentService
@@ -28,7 +108,8 @@ type Resource struct {
 func newResource(service paymentService) Resource {
 	return Resource{
 		Generic: &resource.Generic{
-			ParamFunc: paymentParamFunc,
+			ParamFunc:  paymentParamFunc,
+			SortFields: paymentSortFields,
 		},
 		service: service,
 	}
@@ -45,6 +126,8 @@ func (r Resource) FindOne(oid string, req api2go.Request) (api2go.Responder, err
 		return nil, resource.WrapError(err)
 	}
 
+	resource.SetHeader(req, "ETag", resource.ETag(payment.Version))
+
 	return resource.WrapObject(payment, http.StatusOK), nil
 }
 
@@ -54,14 +137,40 @@ func (r Resource) FindAll(req api2go.Request) (api2go.Responder, error) {
 		return nil, resource.WrapError(err)
 	}
 
+	sort, err := r.ExtractSort(req.QueryParams)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+	cursor, err := r.ExtractCursor(req.Pagination, sort)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
 	searchResp, err := r.service.Search(req.PlainRequest.Context(), domain.PaymentSearchRequest{
 		SearchFilter: filter,
+		SearchSort:   sort,
+		SearchCursor: cursor,
 	})
 	if err != nil {
 		return nil, resource.WrapError(err)
 	}
 
-	return resource.WrapArray(searchResp.Data, http.StatusOK), nil
+	if cursor == nil {
+		return resource.WrapArray(searchResp.Data, http.StatusOK), nil
+	}
+
+	var next, prev string
+	if n := len(searchResp.Data); n > 0 {
+		first, last := searchResp.Data[0], searchResp.Data[n-1]
+		if cursor.Before != nil || searchResp.HasMore {
+			next = paymentCursor(sort, last).String()
+		}
+		if cursor.After != nil || (cursor.Before != nil && searchResp.HasMore) {
+			prev = paymentCursor(sort, first).String()
+		}
+	}
+
+	return resource.WrapCursorArray(searchResp.Data, next, prev, http.StatusOK), nil
 }
 
 func (r Resource) PaginatedFindAll(req api2go.Request) (uint, api2go.Responder, error) {
@@ -69,6 +178,10 @@ func (r Resource) PaginatedFindAll(req api2go.Request) (uint, api2go.Responder,
 	if err != nil {
 		return 0, nil, err
 	}
+	sort, err := r.ExtractSort(req.QueryParams)
+	if err != nil {
+		return 0, nil, resource.WrapError(err)
+	}
 	pagination, err := r.ExtractPagination(req.Pagination)
 	if err != nil {
 		return 0, nil, err
@@ -76,6 +189,7 @@ func (r Resource) PaginatedFindAll(req api2go.Request) (uint, api2go.Responder,
 
 	searchResp, err := r.service.Search(req.PlainRequest.Context(), domain.PaymentSearchRequest{
 		SearchFilter:     filter,
+		SearchSort:       sort,
 		SearchPagination: pagination,
 	})
 	if err != nil {
@@ -88,17 +202,24 @@ func (r Resource) PaginatedFindAll(req api2go.Request) (uint, api2go.Responder,
 func (r Resource) Create(obj interface{}, req api2go.Request) (api2go.Responder, error) {
 	payment := obj.(*domain.Payment)
 
-	err := payment.Validate()
-	if err != nil {
-		return nil, resource.WrapError(err)
+	idempotencyKey := req.Header.Get("Idempotency-Key")
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return nil, resource.WrapError(errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid Idempotency-Key header",
+			fmt.Sprintf("key must not be longer than %d characters", maxIdempotencyKeyLength),
+		))
 	}
 
-	err = r.service.Create(req.PlainRequest.Context(), payment)
+	created, replayed, err := r.service.Create(req.PlainRequest.Context(), payment, idempotencyKey)
 	if err != nil {
 		return nil, resource.WrapError(err)
 	}
+	if replayed {
+		resource.SetHeader(req, "Idempotent-Replayed", "true")
+	}
 
-	return resource.WrapObject(payment, http.StatusCreated), nil
+	return resource.WrapObject(created, http.StatusCreated), nil
 }
 
 func (r Resource) Delete(oid string, req api2go.Request) (api2go.Responder, error) {
@@ -107,7 +228,12 @@ func (r Resource) Delete(oid string, req api2go.Request) (api2go.Responder, erro
 		return nil, resource.WrapError(err)
 	}
 
-	err = r.service.Delete(req.PlainRequest.Context(), id)
+	version, err := resource.ParseIfMatch(req.Header)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	err = r.service.Delete(req.PlainRequest.Context(), id, version)
 	if err != nil {
 		return nil, resource.WrapError(err)
 	}
@@ -118,17 +244,108 @@ func (r Resource) Delete(oid string, req api2go.Request) (api2go.Responder, erro
 func (r Resource) Update(obj interface{}, req api2go.Request) (api2go.Responder, error) {
 	payment := obj.(*domain.Payment)
 
-	err := r.service.Update(req.PlainRequest.Context(), payment)
+	version, err := resource.ParseIfMatch(req.Header)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+	if version > 0 {
+		payment.Version = version
+	}
+
+	err = r.service.Update(req.PlainRequest.Context(), payment)
 	if err != nil {
 		return nil, resource.WrapError(err)
 	}
 
+	resource.SetHeader(req, "ETag", resource.ETag(payment.Version))
+
 	return resource.WrapObject(payment, http.StatusOK), nil
 }
 
-func paymentParamFunc(key string, values []string) (interface{}, error) {
-	switch key {
-	case "id":
+func (r Resource) transitionHandler(status domain.PaymentStatus) routing.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, params map[string]string, _ map[string]interface{}) {
+		id, err := domain.IDFrom(params["id"])
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		payment, err := r.service.Transition(req.Context(), id, status)
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		w.Header().Set("ETag", resource.ETag(payment.Version))
+		resource.WriteObject(w, jsonApiContentType, payment, http.StatusOK)
+	}
+}
+
+func (r Resource) restoreHandler() routing.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, params map[string]string, _ map[string]interface{}) {
+		id, err := domain.IDFrom(params["id"])
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		payment, err := r.service.Restore(req.Context(), id)
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		w.Header().Set("ETag", resource.ETag(payment.Version))
+		resource.WriteObject(w, jsonApiContentType, payment, http.StatusOK)
+	}
+}
+
+func (r Resource) historyHandler() routing.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, params map[string]string, _ map[string]interface{}) {
+		id, err := domain.IDFrom(params["id"])
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		history, err := r.service.History(req.Context(), id)
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		resource.WriteObject(w, jsonApiContentType, history, http.StatusOK)
+	}
+}
+
+// importHandler creates the payments of a pain.001 file. The report is
+// returned as created once any payment is imported, as unprocessable
+// otherwise.
+func (r Resource) importHandler(mode domain.PaymentImportMode) routing.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, _ map[string]string, _ map[string]interface{}) {
+		file, err := pain001.Parse(http.MaxBytesReader(w, req.Body, maxPaymentFileSize))
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		report, err := r.service.Import(req.Context(), file, mode)
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		status := http.StatusCreated
+		if report.Imported == 0 {
+			status = http.StatusUnprocessableEntity
+		}
+		resource.WriteObject(w, jsonApiContentType, report, status)
+	}
+}
+
+func paymentParamFunc(key string, op resource.FilterOperator, values []string) (interface{}, error) {
+	switch {
+	case key == "id" && op == resource.FilterOperatorEq:
 		var ids []domain.ID
 		for i, s := range values {
 			id, err := domain.IDFrom(s)
@@ -142,14 +359,63 @@ func paymentParamFunc(key string, values []string) (interface{}, error) {
 			ids = append(ids, id)
 		}
 		return ids, nil
-	case "creditor.account_number",
-		"debtor.account_number":
+	case key == "creditor.account_number" && op == resource.FilterOperatorEq,
+		key == "debtor.account_number" && op == resource.FilterOperatorEq,
+		key == "amount.currency" && op == resource.FilterOperatorEq,
+		key == "scheme" && op == resource.FilterOperatorEq,
+		key == "end_to_end_reference" && op == resource.FilterOperatorEq,
+		key == "numeric_reference" && op == resource.FilterOperatorEq,
+		key == "remittance_information.structured" && op == resource.FilterOperatorEq,
+		key == "reference" && op == resource.FilterOperatorEq:
 		return values, nil
+	case key == "amount.value" && op.IsRange():
+		value, err := domain.DecimalFrom(values[0])
+		if err != nil {
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				err.Error(),
+				fmt.Sprintf("field %q: %q has not valid format", key, values[0]),
+			)
+		}
+		return value, nil
+	case (key == "created_at" || key == "updated_at") && op.IsRange():
+		value, err := time.Parse(time.RFC3339Nano, values[0])
+		if err != nil {
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				"invalid format of timestamp",
+				fmt.Sprintf("field %q: %q is not an RFC 3339 timestamp", key, values[0]),
+			)
+		}
+		return value, nil
+	case key == "requested_execution_date" && op.IsRange():
+		value, err := domain.DateFrom(values[0])
+		if err != nil {
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				err.Error(),
+				fmt.Sprintf("field %q: %q has not valid format", key, values[0]),
+			)
+		}
+		return value, nil
+	case key == "creditor.name" && op == resource.FilterOperatorPrefix,
+		key == "debtor.name" && op == resource.FilterOperatorPrefix:
+		return values[0], nil
+	case key == "deleted" && op == resource.FilterOperatorEq:
+		deleted, err := strconv.ParseBool(values[0])
+		if err != nil || len(values) > 1 {
+			return nil, errors.Generic(
+				errors.ErrCodeGenericInvalidArgument,
+				"invalid filter parameter",
+				fmt.Sprintf("field %q: %q is not a boolean", key, strings.Join(values, ",")),
+			)
+		}
+		return deleted, nil
 	default:
 		return nil, errors.Generic(
 			errors.ErrCodeGenericInvalidArgument,
 			"unsupported filter parameter",
-			key,
+			resource.FilterKey(key, op),
 		)
 	}
 }
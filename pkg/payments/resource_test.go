@@ -2,22 +2,30 @@ package payments
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/middleware"
 	"github.com/google/go-cmp/cmp"
+	"github.com/manyminds/api2go"
 	"github.com/manyminds/api2go/jsonapi"
 
 	"github.com/michaljemala/payments-sample/pkg/domain"
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 	"github.com/michaljemala/payments-sample/pkg/internal/mock"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
 	"github.com/michaljemala/payments-sample/pkg/internal/service"
 	"github.com/michaljemala/payments-sample/pkg/internal/store"
+	"github.com/michaljemala/payments-sample/pkg/internal/store/memory"
 )
 
 func TestMain(m *testing.M) {
@@ -48,19 +56,64 @@ func TestPayment_Create(t *testing.T) {
 					Value:    domain.MustDecimalFrom("100.00"),
 					Currency: "EUR",
 				},
-				Debtor:   domain.PaymentParty{AccountNumber: "0123456789"},
-				Creditor: domain.PaymentParty{AccountNumber: "9876543210"},
+				Debtor:   domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+				Creditor: domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}},
 			},
 			statusCode: http.StatusCreated,
 			out: domain.Payment{
 				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
 				Scheme:     "SWIFT",
+				Status:     domain.PaymentStatusDraft,
 				Amount: domain.Monetary{
 					Value:    domain.MustDecimalFrom("100.00"),
 					Currency: "EUR",
 				},
-				Debtor:   domain.PaymentParty{AccountNumber: "0123456789"},
-				Creditor: domain.PaymentParty{AccountNumber: "9876543210"},
+				Debtor:    domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+				Creditor:  domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}},
+				CreatedAt: testClock(),
+				UpdatedAt: testClock(),
+			},
+		},
+		{
+			name: "Client supplied timestamps",
+			paymentStore: &mock.PaymentStore{
+				InsertFn: func(store.Tx, *domain.Payment) error { return nil },
+			},
+			in: domain.Payment{
+				BaseObject:             domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Scheme:                 "SWIFT",
+				Amount:                 domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+				Debtor:                 domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+				Creditor:               domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}},
+				RequestedExecutionDate: testDate("2019-06-14"),
+				CreatedAt:              time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:              time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			statusCode: http.StatusCreated,
+			out: domain.Payment{
+				BaseObject:             domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Scheme:                 "SWIFT",
+				Status:                 domain.PaymentStatusDraft,
+				Amount:                 domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+				Debtor:                 domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+				Creditor:               domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}},
+				RequestedExecutionDate: testDate("2019-06-14"),
+				CreatedAt:              testClock(),
+				UpdatedAt:              testClock(),
+			},
+		},
+		{
+			name: "Past requested execution date",
+			in: domain.Payment{
+				BaseObject:             domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Scheme:                 "SWIFT",
+				Amount:                 domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+				Debtor:                 domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+				Creditor:               domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}},
+				RequestedExecutionDate: testDate("2019-06-11"),
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				testErrorPointers(t, resp, http.StatusBadRequest, "/data/attributes/requested_execution_date")
 			},
 		},
 		{
@@ -77,8 +130,8 @@ func TestPayment_Create(t *testing.T) {
 					Value:    domain.MustDecimalFrom("100.00"),
 					Currency: "EUR",
 				},
-				Debtor:   domain.PaymentParty{AccountNumber: "0123456789"},
-				Creditor: domain.PaymentParty{AccountNumber: "9876543210"},
+				Debtor:   domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+				Creditor: domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}},
 			},
 			respFunc: func(t *testing.T, resp *http.Response) {
 				if want, have := http.StatusConflict, resp.StatusCode; want != have {
@@ -88,6 +141,43 @@ func TestPayment_Create(t *testing.T) {
 		},
 		{
 			name: "Invalid payment",
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Amount:     domain.Monetary{Value: domain.MustDecimalFrom("100.00")},
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				testErrorPointers(t, resp, http.StatusBadRequest,
+					"/data/attributes/scheme",
+					"/data/attributes/amount/currency",
+					"/data/attributes/creditor/account_number",
+					"/data/attributes/debtor/account_number",
+				)
+			},
+		},
+		{
+			name: "Invalid SEPA payment",
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Scheme:     "SEPA",
+				Amount:     domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "GBP"},
+				Debtor: domain.PaymentParty{
+					AccountNumber: "DE89370400440532013001",
+					Address:       domain.Address{CountryCode: "DE"},
+				},
+				Creditor: domain.PaymentParty{
+					Address: domain.Address{CountryCode: "SK"},
+				},
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				testErrorPointers(t, resp, http.StatusBadRequest,
+					"/data/attributes/creditor/account_number",
+					"/data/attributes/amount/currency",
+					"/data/attributes/debtor/account_number",
+				)
+			},
+		},
+		{
+			name: "Empty payment",
 			in:   domain.Payment{},
 			respFunc: func(t *testing.T, resp *http.Response) {
 				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
@@ -95,6 +185,108 @@ func TestPayment_Create(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Valid SEPA payment",
+			paymentStore: &mock.PaymentStore{
+				InsertFn: func(store.Tx, *domain.Payment) error { return nil },
+			},
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Scheme:     "SEPA",
+				Amount:     domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+				Debtor: domain.PaymentParty{
+					AccountNumber:   "DE89370400440532013000",
+					AccountProvider: domain.AccountProvider{Code: "DEUTDEFF"},
+					Address:         domain.Address{CountryCode: "DE"},
+				},
+				Creditor: domain.PaymentParty{
+					AccountNumber: "SK3112000000198742637541",
+					Address:       domain.Address{CountryCode: "SK"},
+				},
+			},
+			statusCode: http.StatusCreated,
+			out: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Scheme:     "SEPA",
+				Status:     domain.PaymentStatusDraft,
+				Amount:     domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+				Debtor: domain.PaymentParty{
+					AccountNumber:   "DE89370400440532013000",
+					AccountProvider: domain.AccountProvider{Code: "DEUTDEFF"},
+					Address:         domain.Address{CountryCode: "DE"},
+				},
+				Creditor: domain.PaymentParty{
+					AccountNumber: "SK3112000000198742637541",
+					Address:       domain.Address{CountryCode: "SK"},
+				},
+				CreatedAt: testClock(),
+				UpdatedAt: testClock(),
+			},
+		},
+		{
+			name: "Invalid SEPA IBAN",
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Scheme:     "SEPA",
+				Amount:     domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+				Debtor: domain.PaymentParty{
+					AccountNumber: "DE89370400440532013000",
+					Address:       domain.Address{CountryCode: "DE"},
+				},
+				Creditor: domain.PaymentParty{
+					AccountNumber: "SK3112000000198742637542",
+					Address:       domain.Address{CountryCode: "SK"},
+				},
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				testErrorPointers(t, resp, http.StatusBadRequest, "/data/attributes/creditor/account_number")
+			},
+		},
+		{
+			name: "Invalid SEPA BIC",
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Scheme:     "SEPA",
+				Amount:     domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+				Debtor: domain.PaymentParty{
+					AccountNumber:   "DE89370400440532013000",
+					AccountProvider: domain.AccountProvider{Code: "DEUT"},
+					Address:         domain.Address{CountryCode: "DE"},
+				},
+				Creditor: domain.PaymentParty{
+					AccountNumber: "SK3112000000198742637541",
+					Address:       domain.Address{CountryCode: "SK"},
+				},
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				testErrorPointers(t, resp, http.StatusBadRequest, "/data/attributes/debtor/account_provider/code")
+			},
+		},
+		{
+			name: "Unsupported SEPA IBAN country",
+			enumStore: &mock.EnumStore{
+				ExistsFn: func(tx store.Tx, name domain.EnumName, code string) (bool, error) {
+					return name != enumNameCountry || code != "GB", nil
+				},
+				GetCurrencyFn: testGetCurrency,
+			},
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Scheme:     "SEPA",
+				Amount:     domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+				Debtor: domain.PaymentParty{
+					AccountNumber: "DE89370400440532013000",
+					Address:       domain.Address{CountryCode: "DE"},
+				},
+				Creditor: domain.PaymentParty{
+					AccountNumber: "GB29NWBK60161331926819",
+					Address:       domain.Address{CountryCode: "SK"},
+				},
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				testErrorPointers(t, resp, http.StatusBadRequest, "/data/attributes/creditor/account_number")
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -153,6 +345,226 @@ func TestPayment_Create(t *testing.T) {
 	}
 }
 
+func TestPayment_CreateIdempotent(t *testing.T) {
+	payment := domain.Payment{
+		BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+		Scheme:     "SWIFT",
+		Amount: domain.Monetary{
+			Value:    domain.MustDecimalFrom("100.00"),
+			Currency: "EUR",
+		},
+		Debtor:   domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+		Creditor: domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}},
+	}
+	retried := payment
+	retried.ID = domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")
+	changed := payment
+	changed.Amount.Value = domain.MustDecimalFrom("200.00")
+
+	testCases := []struct {
+		name       string
+		in         []domain.Payment
+		actors     []string
+		concurrent bool
+		statusCode []int
+		inserted   int
+	}{
+		{
+			name:       "Replay with the same body",
+			in:         []domain.Payment{payment, payment},
+			statusCode: []int{http.StatusCreated, http.StatusCreated},
+			inserted:   1,
+		},
+		{
+			name:       "Replay with a fresh ID",
+			in:         []domain.Payment{payment, retried},
+			statusCode: []int{http.StatusCreated, http.StatusCreated},
+			inserted:   1,
+		},
+		{
+			name:       "Replay with a different body",
+			in:         []domain.Payment{payment, changed},
+			statusCode: []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			inserted:   1,
+		},
+		{
+			name:       "Same key of different actors",
+			in:         []domain.Payment{payment, payment},
+			actors:     []string{"jane.doe", "john.doe"},
+			statusCode: []int{http.StatusCreated, http.StatusCreated},
+			inserted:   2,
+		},
+		{
+			name:       "Concurrent request committed first",
+			in:         []domain.Payment{payment},
+			concurrent: true,
+			statusCode: []int{http.StatusCreated},
+			inserted:   1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys := make(map[string]*domain.IdempotencyKey)
+			var inserted int
+			concurrent := tc.concurrent
+
+			svc := testPaymentService(&mock.PaymentStore{
+				InsertFn: func(tx store.Tx, p *domain.Payment) error {
+					inserted++
+					p.Version = 1
+					return nil
+				},
+			}, nil)
+			svc.idempotencyStore = &mock.IdempotencyStore{
+				GetFn: func(tx store.Tx, actor, key string) (*domain.IdempotencyKey, error) {
+					record, ok := keys[actor+"/"+key]
+					if !ok {
+						return nil, errors.Generic(errors.ErrCodeGenericNotFound, "idempotency key not found", "")
+					}
+					return record, nil
+				},
+				InsertFn: func(tx store.Tx, record *domain.IdempotencyKey) error {
+					keys[record.Actor+"/"+record.Key] = record
+					if concurrent {
+						// The other request has recorded the same key in the
+						// meantime, this one is rolled back.
+						concurrent = false
+						return errors.Generic(errors.ErrCodeGenericAlreadyExists, "unable to insert idempotency key", "")
+					}
+					return nil
+				},
+			}
+
+			handler, close := testServiceHandler(t, svc)
+			defer close()
+
+			for i, in := range tc.in {
+				body, err := jsonapi.Marshal(in)
+				if err != nil {
+					t.Fatalf("unable to marshal json api payload: %v", err)
+				}
+
+				req, err := http.NewRequest("POST", "/payments", bytes.NewBuffer(body))
+				if err != nil {
+					t.Fatalf("unable to create request: %v", err)
+				}
+				req.Header.Set("Idempotency-Key", "0d1f5d3c-key")
+				if tc.actors != nil {
+					req.Header.Set("X-Actor", tc.actors[i])
+				}
+
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				resp := rec.Result()
+
+				if want, have := tc.statusCode[i], resp.StatusCode; want != have {
+					t.Fatalf("invalid response status of request %d: want %v, have %v", i, want, have)
+				}
+				if resp.StatusCode != http.StatusCreated {
+					continue
+				}
+
+				data, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Fatalf("unable to read response body: %v", err)
+				}
+
+				var out domain.Payment
+				err = jsonapi.Unmarshal(data, &out)
+				if err != nil {
+					t.Fatalf("unable to unmarshal json api payload: %v", err)
+				}
+				if want, have := payment.ID, out.ID; want != have {
+					t.Fatalf("invalid payment of request %d: want %v, have %v", i, want, have)
+				}
+			}
+
+			if want, have := tc.inserted, inserted; want != have {
+				t.Fatalf("invalid number of inserted payments: want %d, have %d", want, have)
+			}
+		})
+	}
+}
+
+// racingIdempotencyStore holds the requests recording their keys until all of
+// them are about to, so none of them sees the keys of the others.
+type racingIdempotencyStore struct {
+	idempotencyStore
+	racing *sync.WaitGroup
+}
+
+func (s racingIdempotencyStore) Insert(tx store.Tx, record *domain.IdempotencyKey) error {
+	s.racing.Done()
+	s.racing.Wait()
+	return s.idempotencyStore.Insert(tx, record)
+}
+
+func TestPayment_CreateIdempotentConcurrent(t *testing.T) {
+	const requests = 3
+
+	svc := testPaymentService(newMemoryPaymentStore(), nil)
+	svc.Generic = &service.Generic{TxManager: memory.NewTxManager(memory.NewDB())}
+	racing := new(sync.WaitGroup)
+	racing.Add(requests)
+	svc.idempotencyStore = racingIdempotencyStore{idempotencyStore: newMemoryIdempotencyStore(), racing: racing}
+	svc.historyStore = newMemoryPaymentHistoryStore()
+	svc.eventStore = newMemoryPaymentEventStore()
+
+	type result struct {
+		payment  *domain.Payment
+		replayed bool
+		err      error
+	}
+	results := make(chan result, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			payment := &domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.NewID()},
+				Scheme:     "SWIFT",
+				Amount:     domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+				Debtor:     domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+				Creditor:   domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}},
+			}
+			created, replayed, err := svc.Create(context.Background(), payment, "0d1f5d3c-key")
+			results <- result{created, replayed, err}
+		}()
+	}
+
+	var created domain.ID
+	var replayed int
+	for i := 0; i < requests; i++ {
+		r := <-results
+		if r.err != nil {
+			t.Fatalf("unexpected error: %v", r.err)
+		}
+		if r.replayed {
+			replayed++
+			continue
+		}
+		created = r.payment.ID
+	}
+	if want, have := requests-1, replayed; want != have {
+		t.Fatalf("invalid number of replayed requests: want %d, have %d", want, have)
+	}
+
+	var count uint
+	err := svc.WithTransaction(context.Background(), func(tx store.Tx) (err error) {
+		count, err = svc.paymentStore.Count(tx, domain.PaymentSearchRequest{})
+		if err != nil {
+			return err
+		}
+		_, err = svc.paymentStore.Get(tx, created)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unable to load created payment: %v", err)
+	}
+	if want, have := uint(1), count; want != have {
+		t.Fatalf("invalid number of inserted payments: want %d, have %d", want, have)
+	}
+}
+
 func TestPayment_FindOne(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -161,6 +573,7 @@ func TestPayment_FindOne(t *testing.T) {
 		in           domain.ID
 		reqFunc      func(*testing.T, *http.Request)
 		statusCode   int
+		etag         string
 		out          domain.Payment
 		respFunc     func(*testing.T, *http.Response)
 	}{
@@ -175,13 +588,15 @@ func TestPayment_FindOne(t *testing.T) {
 							Value:    domain.MustDecimalFrom("100.00"),
 							Currency: "EUR",
 						},
-						Debtor:   domain.PaymentParty{AccountNumber: "0123456789"},
+						Debtor:   domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
 						Creditor: domain.PaymentParty{AccountNumber: "9876543210"},
+						Version:  7,
 					}, nil
 				},
 			},
 			in:         domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
 			statusCode: http.StatusOK,
+			etag:       `"7"`,
 			out: domain.Payment{
 				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
 				Scheme:     "SWIFT",
@@ -189,7 +604,7 @@ func TestPayment_FindOne(t *testing.T) {
 					Value:    domain.MustDecimalFrom("100.00"),
 					Currency: "EUR",
 				},
-				Debtor:   domain.PaymentParty{AccountNumber: "0123456789"},
+				Debtor:   domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
 				Creditor: domain.PaymentParty{AccountNumber: "9876543210"},
 			},
 		},
@@ -241,6 +656,9 @@ func TestPayment_FindOne(t *testing.T) {
 			if want, have := tc.statusCode, resp.StatusCode; want != have {
 				t.Fatalf("invalid response status: want %v, have %v", want, have)
 			}
+			if want, have := tc.etag, resp.Header.Get("ETag"); want != have {
+				t.Fatalf("invalid response ETag: want %v, have %v", want, have)
+			}
 
 			data, err := ioutil.ReadAll(resp.Body)
 			if err != nil {
@@ -326,139 +744,706 @@ func TestPayment_FindAll(t *testing.T) {
 			},
 		},
 		{
-			name: "Invalid search filter",
-			in:   "filter[UNKNOWN]=SOME_VALUE",
-			respFunc: func(t *testing.T, resp *http.Response) {
-				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
-					t.Fatalf("unexpected response status: want %d, have %d", want, have)
-				}
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			handler, close := testPaymentHandler(t, tc.paymentStore, tc.enumStore)
-			defer close()
-
-			url := fmt.Sprintf("/payments?%s", tc.in)
-
-			req, err := http.NewRequest("GET", url, nil)
-			if err != nil {
-				t.Fatalf("unable to create request: %v", err)
-			}
-
-			if tc.reqFunc != nil {
-				tc.reqFunc(t, req)
-			}
-
-			rec := httptest.NewRecorder()
-			handler.ServeHTTP(rec, req)
-			resp := rec.Result()
-
-			if tc.respFunc != nil {
-				tc.respFunc(t, resp)
-				return
-			}
-
-			if want, have := tc.statusCode, resp.StatusCode; want != have {
-				t.Fatalf("invalid response status: want %v, have %v", want, have)
-			}
-
-			data, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				t.Fatalf("unable to read response body: %v", err)
-			}
-
-			var out []domain.Payment
-			err = jsonapi.Unmarshal(data, &out)
-			if err != nil {
-				t.Fatalf("unable to unmarshal json api payload: %v", err)
-			}
-
-			opts := []cmp.Option{
-				cmp.Transformer("Decimal", func(in domain.Decimal) string {
-					return in.String()
-				}),
-			}
-
-			if want, have := tc.out, out; !cmp.Equal(want, have, opts...) {
-				t.Fatalf("invalid payment: %v", cmp.Diff(want, have, opts...))
-			}
-		})
-	}
-}
-
-func TestPayment_PaginatedFindAll(t *testing.T) {
-	testCases := []struct {
-		name         string
-		paymentStore paymentStore
-		enumStore    enumStore
-		in           string
-		reqFunc      func(*testing.T, *http.Request)
-		statusCode   int
-		out          []domain.Payment
-		respFunc     func(*testing.T, *http.Response)
-	}{
-		{
-			name: "No pagination",
+			name: "Operator search filter",
 			paymentStore: &mock.PaymentStore{
-				CountFn: func(store.Tx, domain.PaymentSearchRequest) (uint, error) {
-					return 5, nil
-				},
-				FindFn: func(store.Tx, domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+				FindFn: func(tx store.Tx, r domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+					amountRange := r.AmountRange()
+					if amountRange.Gte == nil || amountRange.Gte.String() != "100" {
+						t.Fatalf("unexpected amount lower bound: %v", amountRange.Gte)
+					}
+					if amountRange.Lt == nil || amountRange.Lt.String() != "1000.5" {
+						t.Fatalf("unexpected amount upper bound: %v", amountRange.Lt)
+					}
+					if amountRange.Gt != nil || amountRange.Lte != nil {
+						t.Fatal("unexpected amount bounds")
+					}
+					if want, have := []string{"EUR", "GBP"}, r.Currencies(); !cmp.Equal(want, have) {
+						t.Fatalf("unexpected currencies: %v", cmp.Diff(want, have))
+					}
+					if want, have := []string{"SEPA"}, r.Schemes(); !cmp.Equal(want, have) {
+						t.Fatalf("unexpected schemes: %v", cmp.Diff(want, have))
+					}
+					if want, have := "Doe, J", r.CreditorNamePrefix(); want != have {
+						t.Fatalf("unexpected creditor name prefix: want %q, have %q", want, have)
+					}
 					return []*domain.Payment{
 						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
-						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")}},
-						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("3ebbc3b4-d1ec-4316-a191-da97bcaec65d")}},
-						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("1caef84f-50a7-446c-bb16-c0b197bba112")}},
-						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("1166b097-9010-42cd-84fe-f843a37d805a")}},
 					}, nil
 				},
 			},
+			in: "filter[amount.value][gte]=100&filter[amount.value][lt]=1000.50" +
+				"&filter[amount.currency]=EUR,GBP&filter[scheme]=SEPA&filter[creditor.name][prefix]=Doe,%20J",
 			statusCode: http.StatusOK,
 			out: []domain.Payment{
 				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
-				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")}},
-				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("3ebbc3b4-d1ec-4316-a191-da97bcaec65d")}},
-				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("1caef84f-50a7-446c-bb16-c0b197bba112")}},
-				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("1166b097-9010-42cd-84fe-f843a37d805a")}},
 			},
 		},
 		{
-			name: "Valid pagination",
+			name: "Sorted search",
 			paymentStore: &mock.PaymentStore{
-				CountFn: func(store.Tx, domain.PaymentSearchRequest) (uint, error) {
-					return 5, nil
-				},
 				FindFn: func(tx store.Tx, r domain.PaymentSearchRequest) ([]*domain.Payment, error) {
-					if r.SearchPagination == nil {
-						t.Fatal("invalid pagination")
+					want := resource.SearchSort{
+						{Field: "amount.value", Descending: true},
+						{Field: "creditor.name"},
 					}
-					if r.SearchPagination.Page() != 1 {
-						t.Fatal("unexpected page number")
+					if have := r.SearchSort; !cmp.Equal(want, have) {
+						t.Fatalf("unexpected sort: %v", cmp.Diff(want, have))
 					}
-					if r.SearchPagination.Size() != 2 {
+					return []*domain.Payment{
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+					}, nil
+				},
+			},
+			in:         "sort=-amount.value,creditor.name",
+			statusCode: http.StatusOK,
+			out: []domain.Payment{
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+			},
+		},
+		{
+			name: "Timestamp search filter",
+			paymentStore: &mock.PaymentStore{
+				FindFn: func(tx store.Tx, r domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+					createdAt := r.CreatedAtRange()
+					if createdAt.Gte == nil || !createdAt.Gte.Equal(time.Date(2019, 6, 1, 8, 30, 0, 0, time.UTC)) {
+						t.Fatalf("unexpected created at lower bound: %v", createdAt.Gte)
+					}
+					if createdAt.Gt != nil || createdAt.Lt != nil || createdAt.Lte != nil {
+						t.Fatal("unexpected created at bounds")
+					}
+					updatedAt := r.UpdatedAtRange()
+					if updatedAt.Lt == nil || !updatedAt.Lt.Equal(time.Date(2019, 6, 12, 10, 0, 0, 0, time.UTC)) {
+						t.Fatalf("unexpected updated at upper bound: %v", updatedAt.Lt)
+					}
+					executionDate := r.RequestedExecutionDateRange()
+					if executionDate.Lte == nil || executionDate.Lte.String() != "2019-06-30" {
+						t.Fatalf("unexpected requested execution date upper bound: %v", executionDate.Lte)
+					}
+					return []*domain.Payment{
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+					}, nil
+				},
+			},
+			in: "filter[created_at][gte]=2019-06-01T10:30:00%2B02:00&filter[updated_at][lt]=2019-06-12T10:00:00Z" +
+				"&filter[requested_execution_date][lte]=2019-06-30",
+			statusCode: http.StatusOK,
+			out: []domain.Payment{
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+			},
+		},
+		{
+			name: "Reference search filter",
+			paymentStore: &mock.PaymentStore{
+				FindFn: func(tx store.Tx, r domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+					if want, have := []string{"RF18539007547034", "1002001"}, r.References(); !cmp.Equal(want, have) {
+						t.Fatalf("unexpected references: %v", cmp.Diff(want, have))
+					}
+					if want, have := []string{"INV-42"}, r.EndToEndReferences(); !cmp.Equal(want, have) {
+						t.Fatalf("unexpected end-to-end references: %v", cmp.Diff(want, have))
+					}
+					return []*domain.Payment{
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+					}, nil
+				},
+			},
+			in:         "filter[reference]=RF18539007547034,1002001&filter[end_to_end_reference]=INV-42",
+			statusCode: http.StatusOK,
+			out: []domain.Payment{
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+			},
+		},
+		{
+			name: "Invalid timestamp search filter",
+			in:   "filter[created_at][gte]=yesterday",
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Invalid date search filter",
+			in:   "filter[requested_execution_date][gt]=2019-06-31",
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Unsupported sort field",
+			in:   "sort=-creditor.address",
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Unsupported search filter operator",
+			in:   "filter[scheme][gte]=SEPA",
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Invalid amount search filter",
+			in:   "filter[amount.value][gte]=ONE_HUNDRED",
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Deleted payments by admin",
+			paymentStore: &mock.PaymentStore{
+				FindFn: func(tx store.Tx, req domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+					if !req.Deleted() {
+						t.Fatalf("expected deleted payments to be searched")
+					}
+					return []*domain.Payment{
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+					}, nil
+				},
+			},
+			in: "filter[deleted]=true",
+			reqFunc: func(t *testing.T, req *http.Request) {
+				req.Header.Set("X-Actor", "root")
+				req.Header.Set("X-Actor-Roles", "admin")
+			},
+			statusCode: http.StatusOK,
+			out: []domain.Payment{
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+			},
+		},
+		{
+			name: "Deleted payments by non-admin",
+			in:   "filter[deleted]=true",
+			reqFunc: func(t *testing.T, req *http.Request) {
+				req.Header.Set("X-Actor", "jane.doe")
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusForbidden, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Invalid deleted search filter",
+			in:   "filter[deleted]=yes",
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Invalid search filter",
+			in:   "filter[UNKNOWN]=SOME_VALUE",
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, close := testPaymentHandler(t, tc.paymentStore, tc.enumStore)
+			defer close()
+
+			url := fmt.Sprintf("/payments?%s", tc.in)
+
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+
+			if tc.reqFunc != nil {
+				tc.reqFunc(t, req)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
+
+			if tc.respFunc != nil {
+				tc.respFunc(t, resp)
+				return
+			}
+
+			if want, have := tc.statusCode, resp.StatusCode; want != have {
+				t.Fatalf("invalid response status: want %v, have %v", want, have)
+			}
+
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %v", err)
+			}
+
+			var out []domain.Payment
+			err = jsonapi.Unmarshal(data, &out)
+			if err != nil {
+				t.Fatalf("unable to unmarshal json api payload: %v", err)
+			}
+
+			opts := []cmp.Option{
+				cmp.Transformer("Decimal", func(in domain.Decimal) string {
+					return in.String()
+				}),
+			}
+
+			if want, have := tc.out, out; !cmp.Equal(want, have, opts...) {
+				t.Fatalf("invalid payment: %v", cmp.Diff(want, have, opts...))
+			}
+		})
+	}
+}
+
+func TestPayment_PaginatedFindAll(t *testing.T) {
+	testCases := []struct {
+		name         string
+		paymentStore paymentStore
+		enumStore    enumStore
+		in           string
+		reqFunc      func(*testing.T, *http.Request)
+		statusCode   int
+		out          []domain.Payment
+		respFunc     func(*testing.T, *http.Response)
+	}{
+		{
+			name: "No pagination",
+			paymentStore: &mock.PaymentStore{
+				CountFn: func(store.Tx, domain.PaymentSearchRequest) (uint, error) {
+					return 5, nil
+				},
+				FindFn: func(store.Tx, domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+					return []*domain.Payment{
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")}},
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("3ebbc3b4-d1ec-4316-a191-da97bcaec65d")}},
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("1caef84f-50a7-446c-bb16-c0b197bba112")}},
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("1166b097-9010-42cd-84fe-f843a37d805a")}},
+					}, nil
+				},
+			},
+			statusCode: http.StatusOK,
+			out: []domain.Payment{
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")}},
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("3ebbc3b4-d1ec-4316-a191-da97bcaec65d")}},
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("1caef84f-50a7-446c-bb16-c0b197bba112")}},
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("1166b097-9010-42cd-84fe-f843a37d805a")}},
+			},
+		},
+		{
+			name: "Valid pagination",
+			paymentStore: &mock.PaymentStore{
+				CountFn: func(store.Tx, domain.PaymentSearchRequest) (uint, error) {
+					return 5, nil
+				},
+				FindFn: func(tx store.Tx, r domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+					if r.SearchPagination == nil {
+						t.Fatal("invalid pagination")
+					}
+					if r.SearchPagination.Page() != 1 {
+						t.Fatal("unexpected page number")
+					}
+					if r.SearchPagination.Size() != 2 {
 						t.Fatal("unexpected page size")
 					}
-					return []*domain.Payment{
-						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
-						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")}},
-					}, nil
+					return []*domain.Payment{
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+						{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")}},
+					}, nil
+				},
+			},
+			in:         "page[number]=1&page[size]=2",
+			statusCode: http.StatusOK,
+			out: []domain.Payment{
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
+				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")}},
+			},
+		},
+		{
+			name: "Invalid pagination",
+			in:   "page[UNKNOWN]=SOME_VALUE",
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, close := testPaymentHandler(t, tc.paymentStore, tc.enumStore)
+			defer close()
+
+			url := fmt.Sprintf("/payments?%s", tc.in)
+
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+
+			if tc.reqFunc != nil {
+				tc.reqFunc(t, req)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
+
+			if tc.respFunc != nil {
+				tc.respFunc(t, resp)
+				return
+			}
+
+			if want, have := tc.statusCode, resp.StatusCode; want != have {
+				t.Fatalf("invalid response status: want %v, have %v", want, have)
+			}
+
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %v", err)
+			}
+
+			var out []domain.Payment
+			err = jsonapi.Unmarshal(data, &out)
+			if err != nil {
+				t.Fatalf("unable to unmarshal json api payload: %v", err)
+			}
+
+			opts := []cmp.Option{
+				cmp.Transformer("Decimal", func(in domain.Decimal) string {
+					return in.String()
+				}),
+			}
+
+			if want, have := tc.out, out; !cmp.Equal(want, have, opts...) {
+				t.Fatalf("invalid payment: %v", cmp.Diff(want, have, opts...))
+			}
+		})
+	}
+}
+
+func TestPayment_Update(t *testing.T) {
+	testCases := []struct {
+		name         string
+		paymentStore paymentStore
+		enumStore    enumStore
+		in           domain.Payment
+		reqFunc      func(*testing.T, *http.Request)
+		statusCode   int
+		out          domain.Payment
+		respFunc     func(*testing.T, *http.Response)
+	}{
+		{
+			name: "Valid payment",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return &domain.Payment{
+						BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
+						Amount: domain.Monetary{
+							Currency: "EUR",
+							Value:    domain.MustDecimalFrom("0"),
+						},
+						Status:    domain.PaymentStatusDraft,
+						CreatedAt: time.Date(2019, 6, 1, 8, 0, 0, 0, time.UTC),
+						UpdatedAt: time.Date(2019, 6, 1, 8, 0, 0, 0, time.UTC),
+					}, nil
+				},
+				UpdateFn: func(tx store.Tx, p *domain.Payment) error {
+					if p == nil {
+						t.Fatal("unexpected payment")
+					}
+					p.Amount.Value = domain.MustDecimalFrom("100.00")
+					return nil
+				},
+			},
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
+				Scheme:     "FPS",
+				Amount: domain.Monetary{
+					Value:    domain.MustDecimalFrom("100.00"),
+					Currency: "EUR",
+				},
+				Debtor:    domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+				Creditor:  domain.PaymentParty{AccountNumber: "9876543210"},
+				CreatedAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			statusCode: http.StatusOK,
+			out: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
+				Scheme:     "FPS",
+				Amount: domain.Monetary{
+					Value:    domain.MustDecimalFrom("100.00"),
+					Currency: "EUR",
+				},
+				Debtor:    domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}},
+				Creditor:  domain.PaymentParty{AccountNumber: "9876543210"},
+				Status:    domain.PaymentStatusDraft,
+				CreatedAt: time.Date(2019, 6, 1, 8, 0, 0, 0, time.UTC),
+				UpdatedAt: testClock(),
+			},
+		},
+		{
+			name: "Settled payment",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return &domain.Payment{
+						BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
+						Amount: domain.Monetary{
+							Currency: "EUR",
+							Value:    domain.MustDecimalFrom("0"),
+						},
+						Status: domain.PaymentStatusSettled,
+					}, nil
+				},
+			},
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
+				Amount: domain.Monetary{
+					Value:    domain.MustDecimalFrom("100.00"),
+					Currency: "EUR",
+				},
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusConflict, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Stale version",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return &domain.Payment{
+						BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
+						Amount: domain.Monetary{
+							Currency: "EUR",
+							Value:    domain.MustDecimalFrom("0"),
+						},
+						Status:  domain.PaymentStatusDraft,
+						Version: 3,
+					}, nil
+				},
+			},
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
+				Amount: domain.Monetary{
+					Value:    domain.MustDecimalFrom("100.00"),
+					Currency: "EUR",
+				},
+			},
+			reqFunc: func(t *testing.T, req *http.Request) {
+				req.Header.Set("If-Match", `"2"`)
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusPreconditionFailed, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Invalid status transition",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return &domain.Payment{
+						BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
+						Amount: domain.Monetary{
+							Currency: "EUR",
+							Value:    domain.MustDecimalFrom("100.00"),
+						},
+						Status: domain.PaymentStatusDraft,
+					}, nil
+				},
+			},
+			in: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
+				Amount: domain.Monetary{
+					Value:    domain.MustDecimalFrom("100.00"),
+					Currency: "EUR",
+				},
+				Status: domain.PaymentStatusSettled,
+			},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusConflict, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Missing payment",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return nil, errors.Generic(errors.ErrCodeGenericNotFound, "payment not found", "")
+				},
+			},
+			in: domain.Payment{},
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusNotFound, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, close := testPaymentHandler(t, tc.paymentStore, tc.enumStore)
+			defer close()
+
+			body, err := jsonapi.Marshal(tc.in)
+			if err != nil {
+				t.Fatalf("unable to marshal json api payload: %v", err)
+			}
+
+			url := fmt.Sprintf("/payments/%s", tc.in.ID.String())
+
+			req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+
+			if tc.reqFunc != nil {
+				tc.reqFunc(t, req)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
+
+			if tc.respFunc != nil {
+				tc.respFunc(t, resp)
+				return
+			}
+
+			if want, have := tc.statusCode, resp.StatusCode; want != have {
+				t.Fatalf("invalid response status: want %v, have %v", want, have)
+			}
+
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %v", err)
+			}
+
+			var out domain.Payment
+			err = jsonapi.Unmarshal(data, &out)
+			if err != nil {
+				t.Fatalf("unable to unmarshal json api payload: %v", err)
+			}
+
+			opts := []cmp.Option{
+				cmp.Transformer("Decimal", func(in domain.Decimal) string {
+					return in.String()
+				}),
+			}
+
+			if want, have := tc.out, out; !cmp.Equal(want, have, opts...) {
+				t.Fatalf("invalid payment: %v", cmp.Diff(want, have, opts...))
+			}
+		})
+	}
+}
+
+func TestPayment_Delete(t *testing.T) {
+	testCases := []struct {
+		name         string
+		paymentStore paymentStore
+		enumStore    enumStore
+		in           domain.ID
+		reqFunc      func(*testing.T, *http.Request)
+		statusCode   int
+		respFunc     func(*testing.T, *http.Response)
+	}{
+		{
+			name: "Existing payment",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return &domain.Payment{BaseObject: domain.BaseObject{ID: id}, Status: domain.PaymentStatusDraft, Version: 3}, nil
+				},
+				DeleteFn: func(tx store.Tx, id domain.ID, version uint, deletedAt time.Time) error {
+					return nil
+				},
+			},
+			in:         domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name: "Submitted payment",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return &domain.Payment{BaseObject: domain.BaseObject{ID: id}, Status: domain.PaymentStatusSubmitted, Version: 3}, nil
+				},
+				DeleteFn: func(tx store.Tx, id domain.ID, version uint, deletedAt time.Time) error {
+					t.Fatal("submitted payment must not be deleted")
+					return nil
+				},
+			},
+			in: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusConflict, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Missing payment",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return nil, errors.Generic(errors.ErrCodeGenericNotFound, "unable to get payment", "")
+				},
+			},
+			in: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusNotFound, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
+		},
+		{
+			name: "Matching version",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return &domain.Payment{BaseObject: domain.BaseObject{ID: id}, Status: domain.PaymentStatusDraft, Version: 3}, nil
+				},
+				DeleteFn: func(tx store.Tx, id domain.ID, version uint, deletedAt time.Time) error {
+					if want, have := uint(3), version; want != have {
+						t.Fatalf("unexpected version: want %d, have %d", want, have)
+					}
+					return nil
 				},
 			},
-			in:         "page[number]=1&page[size]=2",
-			statusCode: http.StatusOK,
-			out: []domain.Payment{
-				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")}},
-				{BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")}},
+			in: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			reqFunc: func(t *testing.T, req *http.Request) {
+				req.Header.Set("If-Match", `"3"`)
 			},
+			statusCode: http.StatusNoContent,
 		},
 		{
-			name: "Invalid pagination",
-			in:   "page[UNKNOWN]=SOME_VALUE",
+			name: "Stale version",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return &domain.Payment{BaseObject: domain.BaseObject{ID: id}, Status: domain.PaymentStatusDraft, Version: 3}, nil
+				},
+				DeleteFn: func(tx store.Tx, id domain.ID, version uint, deletedAt time.Time) error {
+					return errors.Generic(errors.ErrCodeGenericPreconditionFailed, "unable to delete payment", "")
+				},
+			},
+			in: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			reqFunc: func(t *testing.T, req *http.Request) {
+				req.Header.Set("If-Match", `"2"`)
+			},
 			respFunc: func(t *testing.T, resp *http.Response) {
-				if want, have := http.StatusBadRequest, resp.StatusCode; want != have {
+				if want, have := http.StatusPreconditionFailed, resp.StatusCode; want != have {
 					t.Fatalf("unexpected response status: want %d, have %d", want, have)
 				}
 			},
@@ -470,9 +1455,9 @@ func TestPayment_PaginatedFindAll(t *testing.T) {
 			handler, close := testPaymentHandler(t, tc.paymentStore, tc.enumStore)
 			defer close()
 
-			url := fmt.Sprintf("/payments?%s", tc.in)
+			url := fmt.Sprintf("/payments/%s", tc.in)
 
-			req, err := http.NewRequest("GET", url, nil)
+			req, err := http.NewRequest("DELETE", url, nil)
 			if err != nil {
 				t.Fatalf("unable to create request: %v", err)
 			}
@@ -498,72 +1483,67 @@ func TestPayment_PaginatedFindAll(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unable to read response body: %v", err)
 			}
-
-			var out []domain.Payment
-			err = jsonapi.Unmarshal(data, &out)
-			if err != nil {
-				t.Fatalf("unable to unmarshal json api payload: %v", err)
-			}
-
-			opts := []cmp.Option{
-				cmp.Transformer("Decimal", func(in domain.Decimal) string {
-					return in.String()
-				}),
-			}
-
-			if want, have := tc.out, out; !cmp.Equal(want, have, opts...) {
-				t.Fatalf("invalid payment: %v", cmp.Diff(want, have, opts...))
+			if len(data) > 0 {
+				t.Fatalf("invalid response body: %v", data)
 			}
 		})
 	}
 }
 
-func TestPayment_Update(t *testing.T) {
+func TestPayment_Transition(t *testing.T) {
 	testCases := []struct {
 		name         string
 		paymentStore paymentStore
 		enumStore    enumStore
-		in           domain.Payment
+		in           domain.ID
+		action       string
 		reqFunc      func(*testing.T, *http.Request)
 		statusCode   int
 		out          domain.Payment
 		respFunc     func(*testing.T, *http.Response)
 	}{
 		{
-			name: "Valid payment",
+			name: "Allowed transition",
 			paymentStore: &mock.PaymentStore{
 				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
 					return &domain.Payment{
-						BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
-						Amount: domain.Monetary{
-							Currency: "EUR",
-							Value:    domain.MustDecimalFrom("0"),
-						},
+						BaseObject: domain.BaseObject{ID: id},
+						Status:     domain.PaymentStatusDraft,
 					}, nil
 				},
 				UpdateFn: func(tx store.Tx, p *domain.Payment) error {
-					if p == nil {
-						t.Fatal("unexpected payment")
+					if want, have := domain.PaymentStatusSubmitted, p.Status; want != have {
+						t.Fatalf("unexpected payment status: want %s, have %s", want, have)
 					}
-					p.Amount.Value = domain.MustDecimalFrom("100.00")
 					return nil
 				},
 			},
-			in: domain.Payment{
-				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
-				Amount: domain.Monetary{
-					Value:    domain.MustDecimalFrom("100.00"),
-					Currency: "EUR",
-				},
-			},
+			in:         domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			action:     "submit",
 			statusCode: http.StatusOK,
 			out: domain.Payment{
-				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("b12fc840-2511-452a-8cdf-407c09eba168")},
-				Amount: domain.Monetary{
-					Value:    domain.MustDecimalFrom("100.00"),
-					Currency: "EUR",
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Status:     domain.PaymentStatusSubmitted,
+				UpdatedAt:  testClock(),
+			},
+		},
+		{
+			name: "Forbidden transition",
+			paymentStore: &mock.PaymentStore{
+				GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+					return &domain.Payment{
+						BaseObject: domain.BaseObject{ID: id},
+						Status:     domain.PaymentStatusSettled,
+					}, nil
 				},
 			},
+			in:     domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			action: "cancel",
+			respFunc: func(t *testing.T, resp *http.Response) {
+				if want, have := http.StatusConflict, resp.StatusCode; want != have {
+					t.Fatalf("unexpected response status: want %d, have %d", want, have)
+				}
+			},
 		},
 		{
 			name: "Missing payment",
@@ -572,7 +1552,8 @@ func TestPayment_Update(t *testing.T) {
 					return nil, errors.Generic(errors.ErrCodeGenericNotFound, "payment not found", "")
 				},
 			},
-			in: domain.Payment{},
+			in:     domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			action: "submit",
 			respFunc: func(t *testing.T, resp *http.Response) {
 				if want, have := http.StatusNotFound, resp.StatusCode; want != have {
 					t.Fatalf("unexpected response status: want %d, have %d", want, have)
@@ -586,14 +1567,9 @@ func TestPayment_Update(t *testing.T) {
 			handler, close := testPaymentHandler(t, tc.paymentStore, tc.enumStore)
 			defer close()
 
-			body, err := jsonapi.Marshal(tc.in)
-			if err != nil {
-				t.Fatalf("unable to marshal json api payload: %v", err)
-			}
-
-			url := fmt.Sprintf("/payments/%s", tc.in.ID.String())
+			url := fmt.Sprintf("/payments/%s/%s", tc.in, tc.action)
 
-			req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(body))
+			req, err := http.NewRequest("POST", url, nil)
 			if err != nil {
 				t.Fatalf("unable to create request: %v", err)
 			}
@@ -639,46 +1615,72 @@ func TestPayment_Update(t *testing.T) {
 	}
 }
 
-func TestPayment_Delete(t *testing.T) {
+func TestPayment_Restore(t *testing.T) {
 	testCases := []struct {
 		name         string
 		paymentStore paymentStore
-		enumStore    enumStore
 		in           domain.ID
 		reqFunc      func(*testing.T, *http.Request)
 		statusCode   int
-		respFunc     func(*testing.T, *http.Response)
+		out          domain.Payment
 	}{
 		{
-			name: "Existing payment",
+			name: "Deleted payment",
 			paymentStore: &mock.PaymentStore{
-				DeleteFn: func(tx store.Tx, id domain.ID) error {
+				FindFn: func(tx store.Tx, req domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+					deletedAt := testClock()
+					return []*domain.Payment{{
+						BaseObject: domain.BaseObject{ID: req.IDs()[0]},
+						Status:     domain.PaymentStatusDraft,
+						Version:    2,
+						DeletedAt:  &deletedAt,
+					}}, nil
+				},
+				RestoreFn: func(tx store.Tx, p *domain.Payment) error {
+					p.Version++
+					p.DeletedAt = nil
 					return nil
 				},
 			},
-			in:         domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
-			statusCode: http.StatusNoContent,
+			in: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			reqFunc: func(t *testing.T, req *http.Request) {
+				req.Header.Set("X-Actor", "root")
+				req.Header.Set("X-Actor-Roles", "admin")
+			},
+			statusCode: http.StatusOK,
+			out: domain.Payment{
+				BaseObject: domain.BaseObject{ID: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")},
+				Status:     domain.PaymentStatusDraft,
+				UpdatedAt:  testClock(),
+			},
 		},
 		{
-			name: "Missing payment (idempotent)",
+			name: "Missing payment",
 			paymentStore: &mock.PaymentStore{
-				DeleteFn: func(tx store.Tx, id domain.ID) error {
-					return nil
+				FindFn: func(tx store.Tx, req domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+					return nil, nil
 				},
 			},
+			in: domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
+			reqFunc: func(t *testing.T, req *http.Request) {
+				req.Header.Set("X-Actor", "root")
+				req.Header.Set("X-Actor-Roles", "admin")
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Non-admin actor",
 			in:         domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d"),
-			statusCode: http.StatusNoContent,
+			statusCode: http.StatusForbidden,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			handler, close := testPaymentHandler(t, tc.paymentStore, tc.enumStore)
+			handler, close := testPaymentHandler(t, tc.paymentStore, nil)
 			defer close()
 
-			url := fmt.Sprintf("/payments/%s", tc.in)
-
-			req, err := http.NewRequest("DELETE", url, nil)
+			req, err := http.NewRequest("POST", fmt.Sprintf("/payments/%s/restore", tc.in), nil)
 			if err != nil {
 				t.Fatalf("unable to create request: %v", err)
 			}
@@ -691,42 +1693,308 @@ func TestPayment_Delete(t *testing.T) {
 			handler.ServeHTTP(rec, req)
 			resp := rec.Result()
 
-			if tc.respFunc != nil {
-				tc.respFunc(t, resp)
+			if want, have := tc.statusCode, resp.StatusCode; want != have {
+				t.Fatalf("invalid response status: want %v, have %v", want, have)
+			}
+			if tc.statusCode != http.StatusOK {
 				return
 			}
+			if want, have := `"3"`, resp.Header.Get("ETag"); want != have {
+				t.Fatalf("invalid etag: want %s, have %s", want, have)
+			}
+
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unable to read response body: %v", err)
+			}
+
+			var out domain.Payment
+			err = jsonapi.Unmarshal(data, &out)
+			if err != nil {
+				t.Fatalf("unable to unmarshal json api payload: %v", err)
+			}
+
+			opts := []cmp.Option{
+				cmp.Transformer("Decimal", func(in domain.Decimal) string {
+					return in.String()
+				}),
+			}
+
+			if want, have := tc.out, out; !cmp.Equal(want, have, opts...) {
+				t.Fatalf("invalid payment: %v", cmp.Diff(want, have, opts...))
+			}
+		})
+	}
+}
+
+func TestPayment_History(t *testing.T) {
+	paymentID := domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")
+	entryID := domain.MustIDFrom("8d1b6f3e-2c7a-4f0e-9b5d-3a6c1e2f4b7d")
+
+	testCases := []struct {
+		name         string
+		historyStore paymentHistoryStore
+		in           string
+		statusCode   int
+		out          []domain.PaymentHistory
+	}{
+		{
+			name: "Existing history",
+			historyStore: &mock.PaymentHistoryStore{
+				FindFn: func(tx store.Tx, id domain.ID) ([]*domain.PaymentHistory, error) {
+					return []*domain.PaymentHistory{{
+						BaseObject: domain.BaseObject{ID: entryID},
+						PaymentID:  id,
+						Operation:  domain.PaymentOperationCreate,
+						Actor:      "jane.doe",
+						RequestID:  "host/abc-000001",
+						Timestamp:  testClock(),
+						After: domain.NewPaymentSnapshot(&domain.Payment{
+							BaseObject: domain.BaseObject{ID: id},
+							Status:     domain.PaymentStatusDraft,
+							Version:    1,
+						}),
+					}}, nil
+				},
+			},
+			in:         paymentID.String(),
+			statusCode: http.StatusOK,
+			out: []domain.PaymentHistory{{
+				BaseObject: domain.BaseObject{ID: entryID},
+				PaymentID:  paymentID,
+				Operation:  domain.PaymentOperationCreate,
+				Actor:      "jane.doe",
+				RequestID:  "host/abc-000001",
+				Timestamp:  testClock(),
+				After: domain.NewPaymentSnapshot(&domain.Payment{
+					BaseObject: domain.BaseObject{ID: paymentID},
+					Status:     domain.PaymentStatusDraft,
+					Version:    1,
+				}),
+			}},
+		},
+		{
+			name: "Missing history",
+			historyStore: &mock.PaymentHistoryStore{
+				FindFn: func(tx store.Tx, id domain.ID) ([]*domain.PaymentHistory, error) {
+					return nil, nil
+				},
+			},
+			in:         paymentID.String(),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid ID",
+			in:         "invalid",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := testPaymentService(nil, nil)
+			svc.historyStore = tc.historyStore
+
+			handler, close := testServiceHandler(t, svc)
+			defer close()
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("/payments/%s/history", tc.in), nil)
+			if err != nil {
+				t.Fatalf("unable to create request: %v", err)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			resp := rec.Result()
 
 			if want, have := tc.statusCode, resp.StatusCode; want != have {
 				t.Fatalf("invalid response status: want %v, have %v", want, have)
 			}
+			if tc.out == nil {
+				return
+			}
 
 			data, err := ioutil.ReadAll(resp.Body)
 			if err != nil {
 				t.Fatalf("unable to read response body: %v", err)
 			}
-			if len(data) > 0 {
-				t.Fatalf("invalid response body: %v", data)
+
+			var out []domain.PaymentHistory
+			err = jsonapi.Unmarshal(data, &out)
+			if err != nil {
+				t.Fatalf("unable to unmarshal json api payload: %v", err)
+			}
+
+			opts := []cmp.Option{
+				cmp.Transformer("Decimal", func(in domain.Decimal) string {
+					return in.String()
+				}),
+			}
+
+			if want, have := tc.out, out; !cmp.Equal(want, have, opts...) {
+				t.Fatalf("invalid payment history: %v", cmp.Diff(want, have, opts...))
 			}
 		})
 	}
 }
 
+func TestPayment_HistoryRecording(t *testing.T) {
+	var recorded []*domain.PaymentHistory
+
+	svc := testPaymentService(&mock.PaymentStore{
+		GetFn: func(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+			return &domain.Payment{
+				BaseObject: domain.BaseObject{ID: id},
+				Status:     domain.PaymentStatusDraft,
+				Version:    1,
+			}, nil
+		},
+		UpdateFn: func(tx store.Tx, p *domain.Payment) error {
+			p.Version++
+			return nil
+		},
+	}, nil)
+	svc.historyStore = &mock.PaymentHistoryStore{
+		InsertFn: func(tx store.Tx, h *domain.PaymentHistory) error {
+			recorded = append(recorded, h)
+			return nil
+		},
+	}
+
+	api, close := testServiceHandler(t, svc)
+	defer close()
+
+	req, err := http.NewRequest("POST", "/payments/33b5c07b-c6bd-4a59-b02b-554256eaba5d/submit", nil)
+	if err != nil {
+		t.Fatalf("unable to create request: %v", err)
+	}
+	req.Header.Set("X-Actor", "jane.doe")
+	req.Header.Set("X-Request-Id", "req-1")
+
+	rec := httptest.NewRecorder()
+	middleware.RequestID(api).ServeHTTP(rec, req)
+
+	if want, have := http.StatusOK, rec.Code; want != have {
+		t.Fatalf("invalid response status: want %v, have %v", want, have)
+	}
+	if want, have := 1, len(recorded); want != have {
+		t.Fatalf("unexpected number of history entries: want %d, have %d", want, have)
+	}
+
+	paymentID := domain.MustIDFrom("33b5c07b-c6bd-4a59-b02b-554256eaba5d")
+	want := &domain.PaymentHistory{
+		BaseObject: recorded[0].BaseObject,
+		PaymentID:  paymentID,
+		Operation:  domain.PaymentOperationUpdate,
+		Actor:      "jane.doe",
+		RequestID:  "req-1",
+		Timestamp:  testClock(),
+		Before: domain.NewPaymentSnapshot(&domain.Payment{
+			BaseObject: domain.BaseObject{ID: paymentID},
+			Status:     domain.PaymentStatusDraft,
+			Version:    1,
+		}),
+		After: domain.NewPaymentSnapshot(&domain.Payment{
+			BaseObject: domain.BaseObject{ID: paymentID},
+			Status:     domain.PaymentStatusSubmitted,
+			Version:    2,
+			UpdatedAt:  testClock(),
+		}),
+	}
+	opts := []cmp.Option{
+		cmp.Transformer("Decimal", func(in domain.Decimal) string {
+			return in.String()
+		}),
+	}
+	if have := recorded[0]; !cmp.Equal(want, have, opts...) {
+		t.Fatalf("invalid payment history: %v", cmp.Diff(want, have, opts...))
+	}
+}
+
 func testPaymentHandler(t *testing.T, paymentStore paymentStore, enumStore enumStore) (*API, func()) {
 	t.Helper()
+	return testServiceHandler(t, testPaymentService(paymentStore, enumStore))
+}
 
+func testPaymentService(paymentStore paymentStore, enumStore enumStore) *defaultPaymentService {
 	if enumStore == nil {
 		enumStore = &mock.EnumStore{
 			ExistsFn: func(store.Tx, domain.EnumName, string) (bool, error) {
 				return true, nil
 			},
+			GetCurrencyFn: testGetCurrency,
 		}
 	}
 
-	api := newAPI(Config{}, &defaultPaymentService{
+	return &defaultPaymentService{
 		Generic:      &service.Generic{TxManager: &mock.TxManager{}},
 		paymentStore: paymentStore,
 		enumStore:    enumStore,
-	})
+		idempotencyStore: &mock.IdempotencyStore{
+			GetFn: func(store.Tx, string, string) (*domain.IdempotencyKey, error) {
+				return nil, errors.Generic(errors.ErrCodeGenericNotFound, "idempotency key not found", "")
+			},
+			InsertFn: func(store.Tx, *domain.IdempotencyKey) error { return nil },
+			DeleteFn: func(store.Tx, string, string) error { return nil },
+		},
+		historyStore: &mock.PaymentHistoryStore{
+			InsertFn: func(store.Tx, *domain.PaymentHistory) error { return nil },
+		},
+		eventStore: &mock.PaymentEventStore{
+			InsertFn: func(store.Tx, *domain.PaymentEvent) error { return nil },
+		},
+		calendarStore: &mock.CalendarStore{
+			GetFn: func(store.Tx, string) (*domain.Calendar, error) {
+				return nil, errors.Generic(errors.ErrCodeGenericNotFound, "calendar not found", "")
+			},
+		},
+		rules:             newPaymentRules(enumStore, testClock),
+		idempotencyKeyTTL: time.Hour,
+		clock:             testClock,
+	}
+}
+
+func testErrorPointers(t *testing.T, resp *http.Response, status int, pointers ...string) {
+	t.Helper()
+
+	if want, have := status, resp.StatusCode; want != have {
+		t.Fatalf("unexpected response status: want %d, have %d", want, have)
+	}
+
+	var httpErr api2go.HTTPError
+	err := json.NewDecoder(resp.Body).Decode(&httpErr)
+	if err != nil {
+		t.Fatalf("unable to decode error response: %v", err)
+	}
+	var have []string
+	for _, e := range httpErr.Errors {
+		if e.Source == nil {
+			t.Fatalf("missing error source: %+v", e)
+		}
+		have = append(have, e.Source.Pointer)
+	}
+	if want := pointers; !cmp.Equal(want, have) {
+		t.Fatalf("unexpected error pointers: %v", cmp.Diff(want, have))
+	}
+}
+
+func testGetCurrency(_ store.Tx, code string) (*domain.Currency, error) {
+	return &domain.Currency{Code: code, MinorUnits: 2}, nil
+}
+
+func testClock() time.Time {
+	return time.Date(2019, 6, 12, 12, 0, 0, 0, time.UTC)
+}
+
+func testDate(s string) *domain.Date {
+	d := domain.MustDateFrom(s)
+	return &d
+}
+
+func testServiceHandler(t *testing.T, service paymentService) (*API, func()) {
+	t.Helper()
+
+	api := newAPI(Config{}, service, nil, nil, nil)
 	return api, func() {
 		err := api.Close()
 		if err != nil {
@@ -2,11 +2,22 @@ package payments
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
 
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 
 	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/gateway"
+	"github.com/michaljemala/payments-sample/pkg/internal/auth"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
 	"github.com/michaljemala/payments-sample/pkg/internal/service"
 	"github.com/michaljemala/payments-sample/pkg/internal/store"
 )
@@ -14,8 +25,18 @@ import (
 type defaultPaymentService struct {
 	*service.Generic
 
-	paymentStore paymentStore
-	enumStore    enumStore
+	paymentStore     paymentStore
+	enumStore        enumStore
+	idempotencyStore idempotencyStore
+	historyStore     paymentHistoryStore
+	eventStore       paymentEventStore
+	calendarStore    calendarStore
+	gateways         map[string]gateway.Gateway
+	gatewayTimeout   time.Duration
+	rules            *paymentRules
+
+	idempotencyKeyTTL time.Duration
+	clock             func() time.Time
 
 	logger *log.Logger
 }
@@ -26,24 +47,98 @@ type (
 		Find(store.Tx, domain.PaymentSearchRequest) ([]*domain.Payment, error)
 		Get(store.Tx, domain.ID) (*domain.Payment, error)
 		Insert(store.Tx, *domain.Payment) error
-		Delete(store.Tx, domain.ID) error
+		Delete(store.Tx, domain.ID, uint, time.Time) error
+		Restore(store.Tx, *domain.Payment) error
 		Update(store.Tx, *domain.Payment) error
+		ClaimDue(store.Tx, time.Time) (*domain.Payment, error)
+		ClaimSubmitted(store.Tx, time.Time) (*domain.Payment, error)
+		UpdateExecution(store.Tx, *domain.Payment) error
 	}
 	enumStore interface {
 		Exists(tx store.Tx, name domain.EnumName, code string) (bool, error)
+		Find(tx store.Tx, name domain.EnumName, req domain.EnumSearchRequest) ([]domain.Enum, error)
+		GetCurrency(tx store.Tx, code string) (*domain.Currency, error)
+		Insert(tx store.Tx, name domain.EnumName, enum domain.Enum) error
+		Update(tx store.Tx, name domain.EnumName, enum domain.Enum) error
+	}
+	idempotencyStore interface {
+		Get(tx store.Tx, actor, key string) (*domain.IdempotencyKey, error)
+		Insert(store.Tx, *domain.IdempotencyKey) error
+		Delete(tx store.Tx, actor, key string) error
+	}
+	paymentHistoryStore interface {
+		Find(store.Tx, domain.ID) ([]*domain.PaymentHistory, error)
+		Insert(store.Tx, *domain.PaymentHistory) error
+	}
+	paymentEventStore interface {
+		Insert(store.Tx, *domain.PaymentEvent) error
+		ClaimPending(store.Tx, time.Time, int) ([]*domain.PaymentEvent, error)
+		AssignPositions(store.Tx, int) (int, error)
+		FindAfter(store.Tx, int64, int) ([]*domain.PaymentEvent, error)
+		LastPosition(store.Tx) (int64, error)
+		UpdatePublication(store.Tx, *domain.PaymentEvent) error
+	}
+	calendarStore interface {
+		Get(store.Tx, string) (*domain.Calendar, error)
+	}
+	webhookSubscriptionStore interface {
+		Find(store.Tx) ([]*domain.WebhookSubscription, error)
+		Get(store.Tx, domain.ID) (*domain.WebhookSubscription, error)
+		Insert(store.Tx, *domain.WebhookSubscription) error
+		Update(store.Tx, *domain.WebhookSubscription) error
+		Delete(store.Tx, domain.ID, time.Time) error
+	}
+	webhookDeliveryStore interface {
+		Count(store.Tx, domain.WebhookDeliverySearchRequest) (uint, error)
+		Find(store.Tx, domain.WebhookDeliverySearchRequest) ([]*domain.WebhookDelivery, error)
+		Get(store.Tx, domain.ID) (*domain.WebhookDelivery, error)
+		Exists(tx store.Tx, subscriptionID, eventID domain.ID) (bool, error)
+		Insert(store.Tx, *domain.WebhookDelivery) error
+		Update(store.Tx, *domain.WebhookDelivery) error
+		ClaimPending(store.Tx, time.Time) (*domain.WebhookDelivery, error)
 	}
 )
 
-func newPaymentService(txManager store.TxManager, paymentStore paymentStore, enumStore enumStore, logger *log.Logger) paymentService {
+func newPaymentService(
+	txManager store.TxManager,
+	paymentStore paymentStore,
+	enumStore enumStore,
+	idempotencyStore idempotencyStore,
+	historyStore paymentHistoryStore,
+	eventStore paymentEventStore,
+	calendarStore calendarStore,
+	gateways map[string]gateway.Gateway,
+	gatewayTimeout time.Duration,
+	idempotencyKeyTTL time.Duration,
+	clock func() time.Time,
+	logger *log.Logger,
+) *defaultPaymentService {
 	return &defaultPaymentService{
-		Generic:      &service.Generic{TxManager: txManager},
-		paymentStore: paymentStore,
-		enumStore:    enumStore,
-		logger:       logger,
+		Generic:           &service.Generic{TxManager: txManager},
+		paymentStore:      paymentStore,
+		enumStore:         enumStore,
+		idempotencyStore:  idempotencyStore,
+		historyStore:      historyStore,
+		eventStore:        eventStore,
+		calendarStore:     calendarStore,
+		gateways:          gateways,
+		gatewayTimeout:    gatewayTimeout,
+		rules:             newPaymentRules(enumStore, clock),
+		idempotencyKeyTTL: idempotencyKeyTTL,
+		clock:             clock,
+		logger:            logger,
 	}
 }
 
 func (s *defaultPaymentService) Search(ctx context.Context, searchReq domain.PaymentSearchRequest) (*domain.PaymentSearchResponse, error) {
+	if searchReq.Deleted() && !auth.FromContext(ctx).HasRole(auth.RoleAdmin) {
+		return nil, errors.Generic(
+			errors.ErrCodeGenericPermissionDenied,
+			"unable to search payments",
+			"only admins can search deleted payments",
+		)
+	}
+
 	searchResp := new(domain.PaymentSearchResponse)
 	err := s.WithTransaction(ctx, func(tx store.Tx) error {
 		var err error
@@ -51,6 +146,16 @@ func (s *defaultPaymentService) Search(ctx context.Context, searchReq domain.Pay
 		if err != nil {
 			return err
 		}
+		if c := searchReq.SearchCursor; c != nil && uint(len(searchResp.Data)) > c.Size {
+			// The extra payment loaded beyond the page is the farthest
+			// one from the cursor.
+			searchResp.HasMore = true
+			if c.Before != nil {
+				searchResp.Data = searchResp.Data[1:]
+			} else {
+				searchResp.Data = searchResp.Data[:c.Size]
+			}
+		}
 		if searchReq.SearchPagination != nil {
 			searchResp.Size, err = s.paymentStore.Count(tx, searchReq)
 			if err != nil {
@@ -73,72 +178,801 @@ func (s *defaultPaymentService) Load(ctx context.Context, id domain.ID) (payment
 	return payment, err
 }
 
-func (s *defaultPaymentService) Create(ctx context.Context, payment *domain.Payment) error {
-	return s.WithTransaction(ctx, func(tx store.Tx) error {
-		err := s.validatePayment(tx, payment)
+func (s *defaultPaymentService) Create(ctx context.Context, payment *domain.Payment, idempotencyKey string) (created *domain.Payment, replayed bool, err error) {
+	// The timestamps, the value date and the answer of the scheme are managed
+	// by the service, the client supplied ones are ignored so they do not
+	// affect the fingerprint either.
+	if payment != nil {
+		payment.CreatedAt, payment.UpdatedAt = time.Time{}, time.Time{}
+		payment.ValueDate = nil
+		payment.SchemeReference, payment.RejectionReason = "", ""
+	}
+
+	if idempotencyKey == "" {
+		err = s.WithTransaction(ctx, func(tx store.Tx) error {
+			return s.create(ctx, tx, payment)
+		})
+		return payment, false, err
+	}
+
+	// The keys are chosen by the clients, so they are kept per actor.
+	actor := auth.FromContext(ctx).Name
+	fingerprint, err := paymentFingerprint(payment)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		created, replayed, err = s.replayIdempotent(tx, actor, idempotencyKey, fingerprint)
+		if err != nil || replayed {
+			return err
+		}
+
+		err = s.create(ctx, tx, payment)
 		if err != nil {
 			return err
 		}
 
-		return s.paymentStore.Insert(tx, payment)
+		body, err := encodeRecordedPayment(payment)
+		if err != nil {
+			return err
+		}
+		now := s.clock()
+		created = payment
+		return s.idempotencyStore.Insert(tx, &domain.IdempotencyKey{
+			Actor:          actor,
+			Key:            idempotencyKey,
+			Fingerprint:    fingerprint,
+			ResourceID:     payment.ID,
+			ResponseStatus: http.StatusCreated,
+			ResponseBody:   body,
+			CreatedAt:      now,
+			ExpiresAt:      now.Add(s.idempotencyKeyTTL),
+		})
 	})
+	if errors.Is(err, errors.ErrCodeGenericAlreadyExists) || errors.Is(err, errors.ErrCodeDataAccessConflict) {
+		// A concurrent request with the same key has been committed first,
+		// its response is replayed unless the request differs. The stores
+		// without unique constraints, e.g. the in-memory one, detect it only
+		// as a conflict on commit.
+		conflict := err
+		err = s.WithTransaction(ctx, func(tx store.Tx) error {
+			created, replayed, err = s.replayIdempotent(tx, actor, idempotencyKey, fingerprint)
+			return err
+		})
+		if err == nil && !replayed {
+			return nil, false, conflict
+		}
+	}
+	return created, replayed, err
 }
 
-func (s *defaultPaymentService) Delete(ctx context.Context, id domain.ID) error {
-	return s.WithTransaction(ctx, func(tx store.Tx) error {
-		return s.paymentStore.Delete(tx, id)
+// replayIdempotent returns the payment created by the request with the key
+// unless there was none or it has expired.
+func (s *defaultPaymentService) replayIdempotent(tx store.Tx, actor, key, fingerprint string) (*domain.Payment, bool, error) {
+	record, err := s.idempotencyStore.Get(tx, actor, key)
+	switch {
+	case errors.Is(err, errors.ErrCodeGenericNotFound):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	case record.IsExpired(s.clock()):
+		return nil, false, s.idempotencyStore.Delete(tx, actor, key)
+	case record.Fingerprint != fingerprint:
+		return nil, false, errors.Generic(
+			errors.ErrCodeGenericUnprocessable,
+			"idempotency key reused",
+			"idempotency key has already been used for a different request",
+		)
+	}
+	payment, err := decodeRecordedPayment(record)
+	if err != nil {
+		return nil, false, err
+	}
+	return payment, true, nil
+}
+
+func (s *defaultPaymentService) create(ctx context.Context, tx store.Tx, payment *domain.Payment) error {
+	err := s.validatePayment(tx, payment, nil)
+	if err != nil {
+		return err
+	}
+
+	switch payment.Status {
+	case "":
+		payment.Status = domain.PaymentStatusDraft
+	case domain.PaymentStatusDraft:
+	default:
+		return errors.Generic(
+			errors.ErrCodeGenericInvalidState,
+			"invalid payment status",
+			fmt.Sprintf("payment must be created in %s status", domain.PaymentStatusDraft),
+		)
+	}
+
+	now := s.now()
+	payment.CreatedAt, payment.UpdatedAt = now, now
+	payment.ValueDate, err = s.valueDate(tx, payment, now)
+	if err != nil {
+		return err
+	}
+
+	err = s.paymentStore.Insert(tx, payment)
+	if err != nil {
+		return err
+	}
+
+	return s.recordHistory(ctx, tx, domain.PaymentOperationCreate, nil, payment)
+}
+
+// errPaymentFileRejected rolls back the payment file imported all or nothing.
+var errPaymentFileRejected = fmt.Errorf("payment file rejected")
+
+// Import creates the payments of the file. The file imported all or nothing
+// is imported only if all its payments are valid, otherwise each of the valid
+// payments is imported regardless of the rejected ones.
+func (s *defaultPaymentService) Import(ctx context.Context, file *domain.PaymentFile, mode domain.PaymentImportMode) (*domain.PaymentFileReport, error) {
+	report := &domain.PaymentFileReport{
+		BaseObject: domain.BaseObject{ID: domain.NewID()},
+		MessageID:  file.MessageID,
+		Mode:       mode,
+		Results:    make([]domain.PaymentFileResult, len(file.Transactions)),
+	}
+	for i, transaction := range file.Transactions {
+		report.Results[i] = domain.PaymentFileResult{
+			PaymentInformationID: transaction.PaymentInformationID,
+			EndToEndID:           transaction.EndToEndID,
+		}
+		if transaction.Payment != nil {
+			report.Results[i].PaymentID = transaction.Payment.ID
+		}
+	}
+
+	var err error
+	switch mode {
+	case domain.PaymentImportAllOrNothing:
+		err = s.importAll(ctx, file, report)
+	case domain.PaymentImportPartial:
+		err = s.importEach(ctx, file, report)
+	default:
+		err = errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"invalid payment import mode",
+			fmt.Sprintf("%q is not an import mode", mode),
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range report.Results {
+		switch result.Status {
+		case domain.PaymentFileStatusImported:
+			report.Imported++
+		case domain.PaymentFileStatusRejected:
+			report.Rejected++
+		}
+	}
+	return report, nil
+}
+
+func (s *defaultPaymentService) importAll(ctx context.Context, file *domain.PaymentFile, report *domain.PaymentFileReport) error {
+	rejected := false
+	err := s.WithTransaction(ctx, func(tx store.Tx) error {
+		// All the payments are validated up front so the report tells all
+		// the rejected ones, not just the first one.
+		for i, transaction := range file.Transactions {
+			err := transaction.Err
+			if err == nil {
+				err = s.validateImport(tx, transaction.Payment)
+			}
+			if err != nil {
+				rejected = true
+				err = rejectImport(&report.Results[i], err)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if rejected {
+			return errPaymentFileRejected
+		}
+
+		for i, transaction := range file.Transactions {
+			err := s.create(ctx, tx, transaction.Payment)
+			if err != nil {
+				rejected = true
+				err = rejectImport(&report.Results[i], err)
+				if err != nil {
+					return err
+				}
+				return errPaymentFileRejected
+			}
+			report.Results[i].Status = domain.PaymentFileStatusImported
+		}
+		return nil
 	})
+	if err != nil && err != errPaymentFileRejected {
+		return err
+	}
+
+	if rejected {
+		for i := range report.Results {
+			if report.Results[i].Status != domain.PaymentFileStatusRejected {
+				report.Results[i].Status = domain.PaymentFileStatusNotImported
+			}
+		}
+	}
+	return nil
 }
 
-func (s *defaultPaymentService) Update(ctx context.Context, payment *domain.Payment) error {
+func (s *defaultPaymentService) importEach(ctx context.Context, file *domain.PaymentFile, report *domain.PaymentFileReport) error {
+	for i, transaction := range file.Transactions {
+		err := transaction.Err
+		if err == nil {
+			err = s.WithTransaction(ctx, func(tx store.Tx) error {
+				return s.create(ctx, tx, transaction.Payment)
+			})
+		}
+		if err != nil {
+			err = rejectImport(&report.Results[i], err)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		report.Results[i].Status = domain.PaymentFileStatusImported
+	}
+	return nil
+}
+
+// validateImport validates the payment of a file the way it would be created,
+// the payments imported before are rejected as existing ones.
+func (s *defaultPaymentService) validateImport(tx store.Tx, payment *domain.Payment) error {
+	err := s.validatePayment(tx, payment, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.paymentStore.Get(tx, payment.ID)
+	switch {
+	case err == nil:
+		return errors.Generic(errors.ErrCodeGenericAlreadyExists, "unable to insert payment", "payment already exists")
+	case errors.Is(err, errors.ErrCodeGenericNotFound):
+		return nil
+	}
+	return err
+}
+
+// rejectImport reports the errors of the rejected payment. The errors which do
+// not concern the payment itself fail the whole import.
+func rejectImport(result *domain.PaymentFileResult, err error) error {
+	var errs errors.Multi
+	switch err := err.(type) {
+	case errors.Error:
+		errs = errors.Multi{err}
+	case errors.Multi:
+		errs = err
+	default:
+		return err
+	}
+	for _, e := range errs {
+		if e.Category != errors.ErrCategoryGeneric || e.Code == errors.ErrCodeGenericInternal {
+			return err
+		}
+	}
+
+	result.Status = domain.PaymentFileStatusRejected
+	for _, e := range errs {
+		pointer, _ := e.Extra[errors.ExtraPointer].(string)
+		result.Errors = append(result.Errors, domain.PaymentFileError{
+			Title:   e.Message,
+			Detail:  e.Detail,
+			Pointer: pointer,
+		})
+	}
+	return nil
+}
+
+func (s *defaultPaymentService) Delete(ctx context.Context, id domain.ID, version uint) error {
 	return s.WithTransaction(ctx, func(tx store.Tx) error {
-		err := s.validatePayment(tx, payment)
+		current, err := s.paymentStore.Get(tx, id)
+		if err != nil {
+			return err
+		}
+		if !current.Status.IsDeletable() {
+			return errors.Generic(
+				errors.ErrCodeGenericInvalidState,
+				"payment not deletable",
+				fmt.Sprintf("payment in %s status can not be deleted", current.Status),
+			)
+		}
+
+		deletedAt := s.now()
+		err = s.paymentStore.Delete(tx, id, version, deletedAt)
 		if err != nil {
 			return err
 		}
 
-		return s.paymentStore.Update(tx, payment)
+		deleted := *current
+		deleted.Version++
+		deleted.DeletedAt = &deletedAt
+		deleted.UpdatedAt = deletedAt
+
+		return s.recordHistory(ctx, tx, domain.PaymentOperationDelete, current, &deleted)
 	})
 }
 
-func (s *defaultPaymentService) validatePayment(tx store.Tx, payment *domain.Payment) error {
+func (s *defaultPaymentService) Restore(ctx context.Context, id domain.ID) (payment *domain.Payment, err error) {
+	if !auth.FromContext(ctx).HasRole(auth.RoleAdmin) {
+		return nil, errors.Generic(
+			errors.ErrCodeGenericPermissionDenied,
+			"unable to restore payment",
+			"only admins can restore deleted payments",
+		)
+	}
+
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		deleted, err := s.paymentStore.Find(tx, domain.PaymentSearchRequest{
+			SearchFilter: resource.SearchFilter{"id": []domain.ID{id}, "deleted": true},
+		})
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return errors.Generic(errors.ErrCodeGenericNotFound, "unable to restore payment", "deleted payment not found")
+		}
+
+		restored := *deleted[0]
+		restored.UpdatedAt = s.now()
+		err = s.paymentStore.Restore(tx, &restored)
+		if err != nil {
+			return err
+		}
+		payment = &restored
+
+		return s.recordHistory(ctx, tx, domain.PaymentOperationRestore, deleted[0], payment)
+	})
+	return payment, err
+}
+
+func (s *defaultPaymentService) Update(ctx context.Context, payment *domain.Payment) error {
 	if payment == nil {
 		return errors.Generic(errors.ErrCodeGenericInvalidArgument, "payment must not be nil", "")
 	}
+	return s.WithTransaction(ctx, func(tx store.Tx) error {
+		current, err := s.paymentStore.Get(tx, payment.ID)
+		if err != nil {
+			return err
+		}
+		if payment.Version != current.Version {
+			return errors.Generic(
+				errors.ErrCodeGenericPreconditionFailed,
+				"unable to update payment",
+				fmt.Sprintf("payment has been modified, current version is %d", current.Version),
+			)
+		}
+		err = validateChange(current, payment)
+		if err != nil {
+			return err
+		}
+		// Only the edited content is validated, the reference data it was
+		// once valid against may have been deactivated since.
+		if current.Status.IsEditable() && !current.HasSameContent(*payment) {
+			err = s.validatePayment(tx, payment, current)
+			if err != nil {
+				return err
+			}
+		} else {
+			payment.Amount.Value = current.Amount.Value
+		}
+		payment.CreatedAt = current.CreatedAt
+		payment.UpdatedAt = s.now()
+		payment.SchemeReference = current.SchemeReference
+		payment.RejectionReason = current.RejectionReason
+		// The edit may fix what made the scheduled execution fail.
+		payment.Execution = domain.PaymentExecution{}
+		payment.ValueDate = current.ValueDate
+		if current.Status.IsEditable() {
+			payment.ValueDate, err = s.valueDate(tx, payment, payment.UpdatedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.paymentStore.Update(tx, payment)
+		if err != nil {
+			return err
+		}
+
+		return s.recordHistory(ctx, tx, domain.PaymentOperationUpdate, current, payment)
+	})
+}
+
+func (s *defaultPaymentService) Transition(ctx context.Context, id domain.ID, status domain.PaymentStatus) (payment *domain.Payment, err error) {
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		payment, err = s.paymentStore.Get(tx, id)
+		if err != nil {
+			return err
+		}
+
+		err = validateTransition(payment.Status, status)
+		if err != nil {
+			return err
+		}
+		before := domain.NewPaymentSnapshot(payment)
+		payment.Status = status
+		payment.UpdatedAt = s.now()
+		payment.Execution = domain.PaymentExecution{}
+
+		err = s.paymentStore.Update(tx, payment)
+		if err != nil {
+			return err
+		}
+
+		return s.recordHistory(ctx, tx, domain.PaymentOperationUpdate, before.ToPayment(), payment)
+	})
+	return payment, err
+}
+
+// ExecuteDue submits a single payment whose requested execution date has
+// come. It reports whether there was a due payment. A payment which is no
+// longer valid is not submitted, the failed attempt is recorded instead and
+// the payment is retried later according to the policy, until it runs out of
+// the attempts and gets rejected.
+func (s *defaultPaymentService) ExecuteDue(ctx context.Context, policy retryPolicy) (executed bool, err error) {
+	ctx = auth.NewContext(ctx, auth.Principal{Name: schedulerActor})
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		now := s.now()
+		payment, err := s.paymentStore.ClaimDue(tx, now)
+		if err != nil {
+			if errors.Is(err, errors.ErrCodeGenericNotFound) {
+				return nil
+			}
+			return err
+		}
+		executed = true
+
+		// The reference data may have changed since the payment was
+		// scheduled, the date itself is already known to be due.
+		err = s.rules.ValidateScheduled(tx, payment)
+		if err != nil {
+			if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+				return err
+			}
+			payment.Execution.Attempts++
+			return s.recordFailedAttempt(ctx, tx, payment, policy, now, err, s.rejectExhausted)
+		}
+
+		before := domain.NewPaymentSnapshot(payment)
+		payment.Status = domain.PaymentStatusSubmitted
+		payment.UpdatedAt = now
+		payment.Execution = domain.PaymentExecution{}
+
+		err = s.paymentStore.Update(tx, payment)
+		if err != nil {
+			return err
+		}
+
+		return s.recordHistory(ctx, tx, domain.PaymentOperationUpdate, before.ToPayment(), payment)
+	})
+	return executed, err
+}
+
+// DispatchSubmitted sends a single submitted payment to the gateway of its
+// scheme and records the answer. It reports whether there was a payment to be
+// sent. The attempt is counted and the next one scheduled past the gateway
+// timeout in a transaction of its own before the payment is sent, so no other
+// dispatcher picks the payment meanwhile and no lock is held while waiting
+// for the scheme. The answer is recorded in another transaction. When the
+// scheme does not answer in time the payment is sent again later according to
+// the policy, until it runs out of the attempts and is held for a manual
+// review, as the scheme may have received it anyway.
+func (s *defaultPaymentService) DispatchSubmitted(ctx context.Context, policy retryPolicy) (dispatched bool, err error) {
+	ctx = auth.NewContext(ctx, auth.Principal{Name: dispatcherActor})
+
+	var payment *domain.Payment
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		now := s.now()
+		payment, err = s.paymentStore.ClaimSubmitted(tx, now)
+		if err != nil {
+			if errors.Is(err, errors.ErrCodeGenericNotFound) {
+				return nil
+			}
+			return err
+		}
+		dispatched = true
+
+		// The answer of the last attempt has not been recorded, e.g. the
+		// dispatcher has been stopped while waiting for it.
+		if payment.Execution.Attempts >= policy.maxAttempts {
+			err = s.holdUnconfirmed(ctx, tx, payment, now)
+			payment = nil
+			return err
+		}
+
+		next := now.Add(s.gatewayTimeout + policy.delay(payment.Execution.Attempts))
+		payment.Execution.Attempts++
+		payment.Execution.NextAttemptAt = &next
+		return s.paymentStore.UpdateExecution(tx, payment)
+	})
+	if err != nil || payment == nil {
+		return dispatched, err
+	}
+
+	var ack *gateway.Ack
+	gw, ok := s.gateways[payment.Scheme]
+	if ok {
+		sendCtx, cancel := context.WithTimeout(ctx, s.gatewayTimeout)
+		ack, err = gw.Send(sendCtx, payment)
+		cancel()
+	} else {
+		err = fmt.Errorf("no gateway for scheme %s", payment.Scheme)
+	}
+
+	return true, s.recordDispatch(ctx, payment, ack, err, policy)
+}
+
+// recordDispatch records the answer of the scheme to the payment sent, or the
+// failure to get one. Nothing is recorded if the payment has been changed
+// meanwhile.
+func (s *defaultPaymentService) recordDispatch(ctx context.Context, sent *domain.Payment, ack *gateway.Ack, cause error, policy retryPolicy) error {
+	return s.WithTransaction(ctx, func(tx store.Tx) error {
+		now := s.now()
+		payment, err := s.paymentStore.Get(tx, sent.ID)
+		if err != nil {
+			return err
+		}
+		if payment.Status != domain.PaymentStatusSubmitted || payment.Version != sent.Version {
+			s.logger.Printf("payment %s changed while being sent, the answer of the scheme is not recorded", payment.ID)
+			return nil
+		}
+		if cause != nil {
+			return s.recordFailedAttempt(ctx, tx, payment, policy, now, cause, s.holdUnconfirmed)
+		}
 
-	v := &paymentValidator{s.enumStore}
+		before := domain.NewPaymentSnapshot(payment)
+		if ack.Accepted {
+			payment.Status = domain.PaymentStatusAccepted
+			payment.SchemeReference = ack.Reference
+		} else {
+			payment.Status = domain.PaymentStatusRejected
+			payment.RejectionReason = ack.Reason
+		}
+		payment.UpdatedAt = now
+		payment.Execution = domain.PaymentExecution{}
 
-	err := v.enumExists(tx, enumNameCurrency, payment.Amount.Currency, "payment.amount.currency")
+		err = s.paymentStore.Update(tx, payment)
+		if err != nil {
+			return err
+		}
+
+		return s.recordHistory(ctx, tx, domain.PaymentOperationUpdate, before.ToPayment(), payment)
+	})
+}
+
+// recordFailedAttempt schedules the next attempt to process the payment, the
+// payment itself is left unchanged. The failed attempt is already counted,
+// if it was the last one the payment is given to exhausted instead.
+func (s *defaultPaymentService) recordFailedAttempt(ctx context.Context, tx store.Tx, payment *domain.Payment, policy retryPolicy, now time.Time, cause error, exhausted func(context.Context, store.Tx, *domain.Payment, time.Time) error) error {
+	payment.Execution.LastError = cause.Error()
+	if payment.Execution.Attempts >= policy.maxAttempts {
+		return exhausted(ctx, tx, payment, now)
+	}
+	next := now.Add(policy.delay(payment.Execution.Attempts - 1))
+	payment.Execution.NextAttemptAt = &next
+	return s.paymentStore.UpdateExecution(tx, payment)
+}
+
+// rejectExhausted rejects the payment which has run out of the attempts to
+// process it, giving the last error as the reason. It is a final decision of
+// the worker, made regardless of the transitions available to the clients.
+func (s *defaultPaymentService) rejectExhausted(ctx context.Context, tx store.Tx, payment *domain.Payment, now time.Time) error {
+	lastError := payment.Execution.LastError
+	if lastError == "" {
+		lastError = "no answer recorded"
+	}
+
+	before := domain.NewPaymentSnapshot(payment)
+	payment.Status = domain.PaymentStatusRejected
+	payment.RejectionReason = fmt.Sprintf("giving up after %d attempts: %s", payment.Execution.Attempts, lastError)
+	payment.UpdatedAt = now
+	payment.Execution = domain.PaymentExecution{}
+
+	err := s.paymentStore.Update(tx, payment)
 	if err != nil {
 		return err
 	}
-	err = v.enumExists(tx, enumNameCountry, payment.Creditor.Address.CountryCode, "payment.creditor.address.country_code")
+
+	return s.recordHistory(ctx, tx, domain.PaymentOperationUpdate, before.ToPayment(), payment)
+}
+
+// holdUnconfirmed moves the payment the scheme has never answered to
+// UNCONFIRMED, where it waits until it is confirmed with the scheme and
+// accepted, rejected or submitted again manually. The attempts and the last
+// error are kept for the review.
+func (s *defaultPaymentService) holdUnconfirmed(ctx context.Context, tx store.Tx, payment *domain.Payment, now time.Time) error {
+	before := domain.NewPaymentSnapshot(payment)
+	payment.Status = domain.PaymentStatusUnconfirmed
+	payment.UpdatedAt = now
+	payment.Execution.NextAttemptAt = nil
+
+	err := s.paymentStore.Update(tx, payment)
 	if err != nil {
 		return err
 	}
-	err = v.enumExists(tx, enumNameCountry, payment.Debtor.Address.CountryCode, "payment.debtor.address.country_code")
+
+	return s.recordHistory(ctx, tx, domain.PaymentOperationUpdate, before.ToPayment(), payment)
+}
+
+func (s *defaultPaymentService) History(ctx context.Context, id domain.ID) (history []*domain.PaymentHistory, err error) {
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		history, err = s.historyStore.Find(tx, id)
+		if err != nil {
+			return err
+		}
+		if len(history) == 0 {
+			return errors.Generic(errors.ErrCodeGenericNotFound, "unable to get payment history", "payment not found")
+		}
+		return nil
+	})
+	return history, err
+}
+
+// recordHistory stores the change of the payment along with the event
+// announcing it within the transaction which made the change, so neither the
+// history nor the published events and webhooks can diverge from the payment.
+// The webhooks are scheduled once the relay publishes the event.
+func (s *defaultPaymentService) recordHistory(ctx context.Context, tx store.Tx, op domain.PaymentOperation, before, after *domain.Payment) error {
+	entry := &domain.PaymentHistory{
+		Operation: op,
+		Actor:     auth.FromContext(ctx).Name,
+		RequestID: middleware.GetReqID(ctx),
+		Timestamp: s.clock().UTC(),
+	}
+	entry.ID = domain.NewID()
+	if before != nil {
+		entry.PaymentID = before.ID
+		entry.Before = domain.NewPaymentSnapshot(before)
+	}
+	if after != nil {
+		entry.PaymentID = after.ID
+		entry.After = domain.NewPaymentSnapshot(after)
+	}
+	err := s.historyStore.Insert(tx, entry)
 	if err != nil {
 		return err
 	}
-	err = v.enumExists(tx, enumNameScheme, payment.Scheme, "payment.scheme")
+	return s.eventStore.Insert(tx, domain.NewPaymentEvent(entry))
+}
+
+// valueDate returns the business day the payment received at the given time
+// is executed on. The payments of the schemes without a calendar have none.
+func (s *defaultPaymentService) valueDate(tx store.Tx, payment *domain.Payment, received time.Time) (*domain.Date, error) {
+	calendar, err := s.calendarStore.Get(tx, payment.Scheme)
+	if errors.Is(err, errors.ErrCodeGenericNotFound) {
+		return nil, nil
+	}
 	if err != nil {
-		return err
+		return nil, err
+	}
+	valueDate := calendar.ValueDate(payment.RequestedExecutionDate, received)
+	return &valueDate, nil
+}
+
+// now returns the current time in the precision kept by all the stores.
+func (s *defaultPaymentService) now() time.Time {
+	return s.clock().UTC().Truncate(time.Microsecond)
+}
+
+func encodeRecordedPayment(payment *domain.Payment) ([]byte, error) {
+	data, err := json.Marshal(domain.NewPaymentSnapshot(payment))
+	if err != nil {
+		return nil, errors.Generic(errors.ErrCodeGenericInternal, "unable to record payment", err.Error())
+	}
+	return data, nil
+}
+
+func decodeRecordedPayment(record *domain.IdempotencyKey) (*domain.Payment, error) {
+	var recorded domain.PaymentSnapshot
+	err := json.Unmarshal(record.ResponseBody, &recorded)
+	if err != nil {
+		return nil, errors.Generic(errors.ErrCodeGenericInternal, "unable to replay payment", err.Error())
 	}
+	return recorded.ToPayment(), nil
+}
 
+// paymentFingerprint identifies the payment creation request. The client
+// supplied ID is left out so a retry using a fresh ID is still recognized.
+func paymentFingerprint(payment *domain.Payment) (string, error) {
+	data, err := json.Marshal(payment)
+	if err != nil {
+		return "", errors.Generic(errors.ErrCodeGenericInternal, "unable to fingerprint payment", err.Error())
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func validateChange(current, updated *domain.Payment) error {
+	if updated.Status == "" {
+		updated.Status = current.Status
+	}
+	if !current.Status.IsEditable() && !current.HasSameContent(*updated) {
+		return errors.Generic(
+			errors.ErrCodeGenericInvalidState,
+			"payment not editable",
+			fmt.Sprintf("payment in %s status can not be edited", current.Status),
+		)
+	}
+	if current.Status != updated.Status {
+		return validateTransition(current.Status, updated.Status)
+	}
 	return nil
 }
 
-type paymentValidator struct {
-	enumStore enumStore
+func validateTransition(from, to domain.PaymentStatus) error {
+	if !from.CanTransitionTo(to) {
+		return errors.Generic(
+			errors.ErrCodeGenericInvalidState,
+			"invalid payment status transition",
+			fmt.Sprintf("payment can not transition from %s to %s", from, to),
+		)
+	}
+	return nil
 }
 
-func (v *paymentValidator) enumExists(tx store.Tx, name domain.EnumName, code, field string) error {
-	ok, err := v.enumStore.Exists(tx, name, code)
+// validatePayment validates the payment being created or, if the current one
+// is given, edited. The requested execution date of the edited payment is
+// checked only if it has changed, as it may have passed since. The violations
+// of the structure of the payment and of the rules are reported at once, a
+// value failing the former is not reported by the rules again.
+func (s *defaultPaymentService) validatePayment(tx store.Tx, payment, current *domain.Payment) error {
+	if payment == nil {
+		return errors.Generic(errors.ErrCodeGenericInvalidArgument, "payment must not be nil", "")
+	}
+	var violations errors.Multi
+	if err := payment.Validate(); err != nil {
+		violations = append(violations, err.(errors.Multi)...)
+	}
+
+	validate := s.rules.Validate
+	if current != nil && sameDate(current.RequestedExecutionDate, payment.RequestedExecutionDate) {
+		validate = s.rules.ValidateScheduled
+	}
+	err := validate(tx, payment)
 	if err != nil {
+		found, ok := err.(errors.Multi)
+		if !ok {
+			return err
+		}
+		reported := make(map[interface{}]bool, len(violations))
+		for _, v := range violations {
+			reported[v.Extra[errors.ExtraPointer]] = true
+		}
+		for _, v := range found {
+			if !reported[v.Extra[errors.ExtraPointer]] {
+				violations = append(violations, v)
+			}
+		}
+	}
+	if err := violations.Err(); err != nil {
 		return err
 	}
-	if !ok {
-		return errors.Generic(errors.ErrCodeGenericInvalidArgument, "enum not found", field)
+
+	// Amounts are kept in the canonical scale of their currency.
+	currency, err := s.enumStore.GetCurrency(tx, payment.Amount.Currency)
+	if err != nil {
+		return err
 	}
+	payment.Amount.Value = payment.Amount.Value.Round(currency.MinorUnits)
+
 	return nil
 }
+
+// sameDate reports whether both dates are the same day or neither is set.
+func sameDate(a, b *domain.Date) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b.Time)
+}
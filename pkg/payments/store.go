@@ -1,18 +1,23 @@
 package payments
 
 import (
+	gosql "database/sql"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/michaljemala/payments-sample/pkg/internal/errors"
 
-	"github.com/lib/pq"
-
 	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
 	"github.com/michaljemala/payments-sample/pkg/internal/store"
 	"github.com/michaljemala/payments-sample/pkg/internal/store/sql"
 )
 
+const initialVersion = 1
+
 func newPaymentStore() paymentStore {
 	return &defaultPaymentStore{}
 }
@@ -24,7 +29,7 @@ func (s *defaultPaymentStore) Count(tx store.Tx, req domain.PaymentSearchRequest
 
 	query := `SELECT count(*) FROM payment`
 
-	conds, args := s.extractWhereClause(req)
+	conds, args := s.extractWhereClause(sqlTx.Dialect(), req)
 	if len(conds) > 0 {
 		where := strings.Join(conds, " AND ")
 		query = fmt.Sprintf("%s WHERE %s", query, where)
@@ -48,6 +53,23 @@ func (s *defaultPaymentStore) Find(tx store.Tx, req domain.PaymentSearchRequest)
 		amount_value,
 		amount_currency,
 		scheme_type,
+		status,
+		version,
+		deleted_at,
+		created_at,
+		updated_at,
+		requested_execution_date,
+		value_date,
+		scheme_reference,
+		rejection_reason,
+		end_to_end_reference,
+		numeric_reference,
+		payment_purpose,
+		remittance_unstructured,
+		remittance_structured,
+		execution_attempts,
+		execution_next_attempt_at,
+		execution_last_error,
 		creditor_name,
 		creditor_account_name,
 		creditor_account_number,
@@ -74,15 +96,35 @@ func (s *defaultPaymentStore) Find(tx store.Tx, req domain.PaymentSearchRequest)
 		payment
 	`
 
-	conds, args := s.extractWhereClause(req)
+	// The tie-breaker makes the order, and hence the paging, deterministic.
+	sort := req.SearchSort.WithTieBreaker(paymentTieBreaker)
+	backward := req.SearchCursor != nil && req.SearchCursor.Before != nil
+
+	conds, args := s.extractWhereClause(sqlTx.Dialect(), req)
+	if c := req.SearchCursor; c != nil && c.Position() != nil {
+		cond, condArgs, err := s.extractKeysetClause(sqlTx.Dialect(), sort, c.Position().Values, backward)
+		if err != nil {
+			return nil, err
+		}
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
 	if len(conds) > 0 {
 		where := strings.Join(conds, " AND ")
 		query = fmt.Sprintf("%s WHERE %s", query, where)
 	}
 
+	orderBy, err := s.extractOrderByClause(sqlTx.Dialect(), sort, backward)
+	if err != nil {
+		return nil, err
+	}
+	query = fmt.Sprintf("%s ORDER BY %s", query, orderBy)
+
 	if pag := req.SearchPagination; pag != nil {
 		query = fmt.Sprintf("%s LIMIT %d OFFSET %d", query, pag.Limit(), pag.Offset())
 	}
+	if c := req.SearchCursor; c != nil {
+		query = fmt.Sprintf("%s LIMIT %d", query, c.Limit())
+	}
 
 	rows, err := sqlTx.Query(query, args...)
 	if err != nil {
@@ -98,6 +140,23 @@ func (s *defaultPaymentStore) Find(tx store.Tx, req domain.PaymentSearchRequest)
 			&payment.Amount.Value,
 			&payment.Amount.Currency,
 			&payment.Scheme,
+			&payment.Status,
+			&payment.Version,
+			&payment.DeletedAt,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+			&payment.RequestedExecutionDate,
+			&payment.ValueDate,
+			&payment.SchemeReference,
+			&payment.RejectionReason,
+			&payment.EndToEndReference,
+			&payment.NumericReference,
+			&payment.PaymentPurpose,
+			&payment.RemittanceInformation.Unstructured,
+			&payment.RemittanceInformation.Structured,
+			&payment.Execution.Attempts,
+			&payment.Execution.NextAttemptAt,
+			&payment.Execution.LastError,
 			&payment.Creditor.Name,
 			&payment.Creditor.AccountName,
 			&payment.Creditor.AccountNumber,
@@ -131,6 +190,10 @@ func (s *defaultPaymentStore) Find(tx store.Tx, req domain.PaymentSearchRequest)
 		return nil, sql.WrapSelectError(err, "unable to select payments")
 	}
 
+	if backward {
+		reversePayments(payments)
+	}
+
 	return payments, nil
 }
 
@@ -143,6 +206,23 @@ func (s *defaultPaymentStore) Get(tx store.Tx, id domain.ID) (*domain.Payment, e
 		amount_value,
 		amount_currency,
 		scheme_type,
+		status,
+		version,
+		deleted_at,
+		created_at,
+		updated_at,
+		requested_execution_date,
+		value_date,
+		scheme_reference,
+		rejection_reason,
+		end_to_end_reference,
+		numeric_reference,
+		payment_purpose,
+		remittance_unstructured,
+		remittance_structured,
+		execution_attempts,
+		execution_next_attempt_at,
+		execution_last_error,
 		creditor_name,
 		creditor_account_name,
 		creditor_account_number,
@@ -168,7 +248,7 @@ func (s *defaultPaymentStore) Get(tx store.Tx, id domain.ID) (*domain.Payment, e
 	FROM
 		payment
 	WHERE
-		id = ?`
+		id = ? AND deleted_at IS NULL`
 
 	var payment domain.Payment
 	err := sqlTx.QueryRow(query, id).Scan(
@@ -176,6 +256,23 @@ func (s *defaultPaymentStore) Get(tx store.Tx, id domain.ID) (*domain.Payment, e
 		&payment.Amount.Value,
 		&payment.Amount.Currency,
 		&payment.Scheme,
+		&payment.Status,
+		&payment.Version,
+		&payment.DeletedAt,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+		&payment.RequestedExecutionDate,
+		&payment.ValueDate,
+		&payment.SchemeReference,
+		&payment.RejectionReason,
+		&payment.EndToEndReference,
+		&payment.NumericReference,
+		&payment.PaymentPurpose,
+		&payment.RemittanceInformation.Unstructured,
+		&payment.RemittanceInformation.Structured,
+		&payment.Execution.Attempts,
+		&payment.Execution.NextAttemptAt,
+		&payment.Execution.LastError,
 		&payment.Creditor.Name,
 		&payment.Creditor.AccountName,
 		&payment.Creditor.AccountNumber,
@@ -215,6 +312,22 @@ func (s *defaultPaymentStore) Insert(tx store.Tx, payment *domain.Payment) error
 		amount_value,
 		amount_currency,
 		scheme_type,
+		status,
+		version,
+		created_at,
+		updated_at,
+		requested_execution_date,
+		value_date,
+		scheme_reference,
+		rejection_reason,
+		end_to_end_reference,
+		numeric_reference,
+		payment_purpose,
+		remittance_unstructured,
+		remittance_structured,
+		execution_attempts,
+		execution_next_attempt_at,
+		execution_last_error,
 		creditor_name,
 		creditor_account_name,
 		creditor_account_number,
@@ -237,13 +350,29 @@ func (s *defaultPaymentStore) Insert(tx store.Tx, payment *domain.Payment) error
 		debtor_address_region,
 		debtor_address_postal_code,
 		debtor_address_country_code
-	) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+	) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
 
 	_, err := sqlTx.Exec(query,
 		payment.ID,
 		payment.Amount.Value,
 		payment.Amount.Currency,
 		payment.Scheme,
+		payment.Status,
+		initialVersion,
+		payment.CreatedAt,
+		payment.UpdatedAt,
+		payment.RequestedExecutionDate,
+		payment.ValueDate,
+		payment.SchemeReference,
+		payment.RejectionReason,
+		payment.EndToEndReference,
+		payment.NumericReference,
+		payment.PaymentPurpose,
+		payment.RemittanceInformation.Unstructured,
+		payment.RemittanceInformation.Structured,
+		payment.Execution.Attempts,
+		payment.Execution.NextAttemptAt,
+		payment.Execution.LastError,
 		payment.Creditor.Name,
 		payment.Creditor.AccountName,
 		payment.Creditor.AccountNumber,
@@ -268,17 +397,73 @@ func (s *defaultPaymentStore) Insert(tx store.Tx, payment *domain.Payment) error
 		payment.Debtor.Address.CountryCode,
 	)
 
-	return sql.WrapInsertError(err, "unable to insert payment")
+	if err != nil {
+		return sql.WrapInsertError(err, "unable to insert payment")
+	}
+
+	payment.Version = initialVersion
+
+	return nil
+}
+
+func (s *defaultPaymentStore) Delete(tx store.Tx, id domain.ID, version uint, deletedAt time.Time) error {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `UPDATE payment SET deleted_at = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL`
+	args := []interface{}{deletedAt, deletedAt, id}
+	if version > 0 {
+		query = fmt.Sprintf("%s AND version = ?", query)
+		args = append(args, version)
+	}
+
+	result, err := sqlTx.Exec(query, args...)
+	if err != nil {
+		return sql.WrapDeleteError(err, "unable to delete payment")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return sql.WrapDeleteError(err, "unable to delete payment")
+	}
+	if affected == 0 {
+		if version > 0 {
+			return s.versionMismatch(sqlTx, id, "unable to delete payment")
+		}
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to delete payment", "payment not found")
+	}
+
+	return nil
 }
 
-func (s *defaultPaymentStore) Delete(tx store.Tx, id domain.ID) error {
+func (s *defaultPaymentStore) Restore(tx store.Tx, payment *domain.Payment) error {
 	sqlTx := tx.(*sql.Tx)
 
-	query := `DELETE FROM payment WHERE id = ?`
+	query := `
+	UPDATE payment
+	SET
+		deleted_at = NULL,
+		updated_at = ?,
+		version = version + 1
+	WHERE
+		id = ? AND version = ? AND deleted_at IS NOT NULL`
+
+	result, err := sqlTx.Exec(query, payment.UpdatedAt, payment.ID, payment.Version)
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to restore payment")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to restore payment")
+	}
+	if affected == 0 {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to restore payment", "deleted payment not found")
+	}
 
-	_, err := sqlTx.Exec(query, id)
+	payment.Version++
+	payment.DeletedAt = nil
 
-	return sql.WrapDeleteError(err, "unable to delete payment")
+	return nil
 }
 
 func (s *defaultPaymentStore) Update(tx store.Tx, payment *domain.Payment) error {
@@ -290,6 +475,20 @@ func (s *defaultPaymentStore) Update(tx store.Tx, payment *domain.Payment) error
 		amount_value = ?,
 		amount_currency = ?,
 		scheme_type = ?,
+		status = ?,
+		updated_at = ?,
+		requested_execution_date = ?,
+		value_date = ?,
+		scheme_reference = ?,
+		rejection_reason = ?,
+		end_to_end_reference = ?,
+		numeric_reference = ?,
+		payment_purpose = ?,
+		remittance_unstructured = ?,
+		remittance_structured = ?,
+		execution_attempts = ?,
+		execution_next_attempt_at = ?,
+		execution_last_error = ?,
 		creditor_name = ?,
 		creditor_account_name = ?,
 		creditor_account_number = ?,
@@ -311,14 +510,29 @@ func (s *defaultPaymentStore) Update(tx store.Tx, payment *domain.Payment) error
 		debtor_address_city = ?,
 		debtor_address_region = ?,
 		debtor_address_postal_code = ?,
-		debtor_address_country_code = ?
+		debtor_address_country_code = ?,
+		version = version + 1
 	WHERE
-		id = ?`
+		id = ? AND version = ? AND deleted_at IS NULL`
 
-	_, err := sqlTx.Exec(query,
+	result, err := sqlTx.Exec(query,
 		payment.Amount.Value,
 		payment.Amount.Currency,
 		payment.Scheme,
+		payment.Status,
+		payment.UpdatedAt,
+		payment.RequestedExecutionDate,
+		payment.ValueDate,
+		payment.SchemeReference,
+		payment.RejectionReason,
+		payment.EndToEndReference,
+		payment.NumericReference,
+		payment.PaymentPurpose,
+		payment.RemittanceInformation.Unstructured,
+		payment.RemittanceInformation.Structured,
+		payment.Execution.Attempts,
+		payment.Execution.NextAttemptAt,
+		payment.Execution.LastError,
 		payment.Creditor.Name,
 		payment.Creditor.AccountName,
 		payment.Creditor.AccountNumber,
@@ -342,62 +556,1529 @@ func (s *defaultPaymentStore) Update(tx store.Tx, payment *domain.Payment) error
 		payment.Debtor.Address.PostalCode,
 		payment.Debtor.Address.CountryCode,
 		payment.ID,
+		payment.Version,
+	)
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update payment")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update payment")
+	}
+	if affected == 0 {
+		return s.versionMismatch(sqlTx, payment.ID, "unable to update payment")
+	}
+
+	payment.Version++
+
+	return nil
+}
+
+// ClaimDue locks the draft payment which is due to be executed at the given
+// time and whose next execution attempt is due too. The payments locked by
+// other workers are skipped.
+func (s *defaultPaymentStore) ClaimDue(tx store.Tx, now time.Time) (*domain.Payment, error) {
+	return s.claim(tx, now, domain.PaymentStatusDraft,
+		"requested_execution_date <= ?", "requested_execution_date", domain.DateOf(now.UTC()))
+}
+
+// ClaimSubmitted locks the submitted payment which is waiting the longest to
+// be sent to its scheme, the same way as ClaimDue.
+func (s *defaultPaymentStore) ClaimSubmitted(tx store.Tx, now time.Time) (*domain.Payment, error) {
+	return s.claim(tx, now, domain.PaymentStatusSubmitted, "", "updated_at")
+}
+
+func (s *defaultPaymentStore) claim(tx store.Tx, now time.Time, status domain.PaymentStatus, cond, order string, args ...interface{}) (*domain.Payment, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	if cond != "" {
+		cond += " AND"
+	}
+	query := fmt.Sprintf(`
+	SELECT
+		id
+	FROM
+		payment
+	WHERE
+		status = ? AND deleted_at IS NULL AND
+		%s
+		(execution_next_attempt_at IS NULL OR execution_next_attempt_at <= ?)
+	ORDER BY %s, id
+	LIMIT 1
+	%s`, cond, order, sqlTx.Dialect().SkipLocked())
+
+	args = append([]interface{}{status}, args...)
+	args = append(args, now.UTC())
+
+	var id domain.ID
+	err := sqlTx.QueryRow(query, args...).Scan(&id)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to claim payment")
+	}
+
+	return s.Get(tx, id)
+}
+
+// UpdateExecution records the execution attempts of the payment, it is not
+// a change of the payment so its version is kept.
+func (s *defaultPaymentStore) UpdateExecution(tx store.Tx, payment *domain.Payment) error {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	UPDATE payment
+	SET
+		execution_attempts = ?,
+		execution_next_attempt_at = ?,
+		execution_last_error = ?
+	WHERE
+		id = ? AND deleted_at IS NULL`
+
+	result, err := sqlTx.Exec(query,
+		payment.Execution.Attempts,
+		payment.Execution.NextAttemptAt,
+		payment.Execution.LastError,
+		payment.ID,
 	)
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update payment execution")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update payment execution")
+	}
+	if affected == 0 {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update payment execution", "payment not found")
+	}
+
+	return nil
+}
 
-	return sql.WrapUpdateError(err, "unable to update payment")
+// versionMismatch explains why no row was affected by a versioned statement,
+// the payment is either missing or it has been modified concurrently.
+func (s *defaultPaymentStore) versionMismatch(sqlTx *sql.Tx, id domain.ID, msg string) error {
+	var version uint
+	err := sqlTx.QueryRow(`SELECT version FROM payment WHERE id = ? AND deleted_at IS NULL`, id).Scan(&version)
+	if err != nil {
+		return sql.WrapSelectError(err, msg)
+	}
+	return errors.Generic(
+		errors.ErrCodeGenericPreconditionFailed,
+		msg,
+		fmt.Sprintf("payment has been modified, current version is %d", version),
+	)
 }
 
-func (s *defaultPaymentStore) extractWhereClause(req domain.PaymentSearchRequest) (conds []string, args []interface{}) {
+func (s *defaultPaymentStore) extractWhereClause(dialect sql.Dialect, req domain.PaymentSearchRequest) (conds []string, args []interface{}) {
+	if req.Deleted() {
+		conds = append(conds, "deleted_at IS NOT NULL")
+	} else {
+		conds = append(conds, "deleted_at IS NULL")
+	}
 	if list := req.IDs(); len(list) > 0 {
-		conds = append(conds, "id = ANY (?)")
-		args = append(args, pq.Array(list))
+		cond, condArgs := dialect.AnyOf("id", list)
+		conds, args = append(conds, cond), append(args, condArgs...)
 	}
 	if list := req.CreditorAccountNumbers(); len(list) > 0 {
-		conds = append(conds, "creditor_account_number = ANY (?)")
-		args = append(args, pq.Array(list))
+		cond, condArgs := dialect.AnyOf("creditor_account_number", list)
+		conds, args = append(conds, cond), append(args, condArgs...)
 	}
 	if list := req.DebtorAccountNumbers(); len(list) > 0 {
-		conds = append(conds, "debtor_account_number = ANY (?)")
-		args = append(args, pq.Array(list))
+		cond, condArgs := dialect.AnyOf("debtor_account_number", list)
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if list := req.Currencies(); len(list) > 0 {
+		cond, condArgs := dialect.AnyOf("amount_currency", list)
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if list := req.Schemes(); len(list) > 0 {
+		cond, condArgs := dialect.AnyOf("scheme_type", list)
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if list := req.EndToEndReferences(); len(list) > 0 {
+		cond, condArgs := dialect.AnyOf("end_to_end_reference", list)
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if list := req.NumericReferences(); len(list) > 0 {
+		cond, condArgs := dialect.AnyOf("numeric_reference", list)
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if list := req.CreditorReferences(); len(list) > 0 {
+		cond, condArgs := dialect.AnyOf("remittance_structured", list)
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if list := req.References(); len(list) > 0 {
+		var alts []string
+		for _, column := range []string{"end_to_end_reference", "numeric_reference", "remittance_structured"} {
+			cond, condArgs := dialect.AnyOf(column, list)
+			alts, args = append(alts, cond), append(args, condArgs...)
+		}
+		conds = append(conds, "("+strings.Join(alts, " OR ")+")")
+	}
+	if amountRange := req.AmountRange(); !amountRange.IsEmpty() {
+		bounds := []struct {
+			op    string
+			value *domain.Decimal
+		}{
+			{">", amountRange.Gt},
+			{">=", amountRange.Gte},
+			{"<", amountRange.Lt},
+			{"<=", amountRange.Lte},
+		}
+		for _, b := range bounds {
+			if b.value == nil {
+				continue
+			}
+			conds = append(conds, fmt.Sprintf("%s %s ?", dialect.DecimalKey("amount_value"), b.op))
+			args = append(args, dialect.DecimalKeyArg(b.value.String()))
+		}
+	}
+	timeRanges := []struct {
+		column string
+		value  domain.TimeRange
+	}{
+		{"created_at", req.CreatedAtRange()},
+		{"updated_at", req.UpdatedAtRange()},
+	}
+	for _, r := range timeRanges {
+		bounds := []struct {
+			op    string
+			value *time.Time
+		}{
+			{">", r.value.Gt},
+			{">=", r.value.Gte},
+			{"<", r.value.Lt},
+			{"<=", r.value.Lte},
+		}
+		for _, b := range bounds {
+			if b.value == nil {
+				continue
+			}
+			conds = append(conds, fmt.Sprintf("%s %s ?", r.column, b.op))
+			args = append(args, b.value.UTC())
+		}
+	}
+	if dateRange := req.RequestedExecutionDateRange(); !dateRange.IsEmpty() {
+		bounds := []struct {
+			op    string
+			value *domain.Date
+		}{
+			{">", dateRange.Gt},
+			{">=", dateRange.Gte},
+			{"<", dateRange.Lt},
+			{"<=", dateRange.Lte},
+		}
+		for _, b := range bounds {
+			if b.value == nil {
+				continue
+			}
+			conds = append(conds, fmt.Sprintf("requested_execution_date %s ?", b.op))
+			args = append(args, *b.value)
+		}
+	}
+	if prefix := req.CreditorNamePrefix(); prefix != "" {
+		cond, condArgs := dialect.HasPrefixFold("creditor_name", prefix)
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if prefix := req.DebtorNamePrefix(); prefix != "" {
+		cond, condArgs := dialect.HasPrefixFold("debtor_name", prefix)
+		conds, args = append(conds, cond), append(args, condArgs...)
 	}
 	return conds, args
 }
 
-const (
-	enumNameScheme   = domain.EnumName("SCHEME")
-	enumNameCountry  = domain.EnumName("COUNTRY")
-	enumNameCurrency = domain.EnumName("CURRENCY")
-)
+var paymentSortColumns = map[string]string{
+	"id":                      "id",
+	"amount.value":            "amount_value",
+	"amount.currency":         "amount_currency",
+	"scheme":                  "scheme_type",
+	"status":                  "status",
+	"creditor.name":           "creditor_name",
+	"creditor.account_number": "creditor_account_number",
+	"debtor.name":             "debtor_name",
+	"debtor.account_number":   "debtor_account_number",
+	"created_at":              "created_at",
+	"updated_at":              "updated_at",
+	// The payments without the date sort the same way in every dialect.
+	"requested_execution_date": "COALESCE(requested_execution_date, '" + unscheduledDate + "')",
+}
 
-func newEnumStore() *defaultEnumStore {
-	return &defaultEnumStore{
-		enumMapping: map[domain.EnumName]string{
-			enumNameScheme:   "enum_scheme",
-			enumNameCountry:  "enum_country",
-			enumNameCurrency: "enum_currency",
-		},
+func (s *defaultPaymentStore) extractOrderByClause(dialect sql.Dialect, sort resource.SearchSort, backward bool) (string, error) {
+	var terms []string
+	for _, f := range sort {
+		column, err := s.sortColumn(dialect, f.Field)
+		if err != nil {
+			return "", err
+		}
+		if f.Descending != backward {
+			column += " DESC"
+		}
+		terms = append(terms, column)
 	}
+	return strings.Join(terms, ", "), nil
 }
 
-type defaultEnumStore struct {
-	enumMapping map[domain.EnumName]string
+// extractKeysetClause selects the payments following the cursor values in the
+// sort order, or preceding them when paging backward.
+func (s *defaultPaymentStore) extractKeysetClause(dialect sql.Dialect, sort resource.SearchSort, values []string, backward bool) (string, []interface{}, error) {
+	if len(values) != len(sort) {
+		return "", nil, errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid cursor", "cursor does not match the sort")
+	}
+
+	var (
+		alts []string
+		args []interface{}
+	)
+	for i, f := range sort {
+		var terms []string
+		for j := 0; j <= i; j++ {
+			column, err := s.sortColumn(dialect, sort[j].Field)
+			if err != nil {
+				return "", nil, err
+			}
+			var value interface{} = values[j]
+			switch sort[j].Field {
+			case "amount.value":
+				value = dialect.DecimalKeyArg(values[j])
+			case "created_at", "updated_at":
+				t, err := time.Parse(sortTimeLayout, values[j])
+				if err != nil {
+					return "", nil, errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid cursor", "cursor does not match the sort")
+				}
+				value = t
+			}
+			op := "="
+			if j == i {
+				op = ">"
+				if f.Descending != backward {
+					op = "<"
+				}
+			}
+			terms = append(terms, fmt.Sprintf("%s %s ?", column, op))
+			args = append(args, value)
+		}
+		alts = append(alts, "("+strings.Join(terms, " AND ")+")")
+	}
+	return "(" + strings.Join(alts, " OR ") + ")", args, nil
 }
 
-func (s *defaultEnumStore) Exists(tx store.Tx, name domain.EnumName, code string) (bool, error) {
+func (s *defaultPaymentStore) sortColumn(dialect sql.Dialect, field string) (string, error) {
+	column, ok := paymentSortColumns[field]
+	if !ok {
+		return "", errors.Generic(errors.ErrCodeGenericInvalidArgument, "unsupported sort field", field)
+	}
+	if column == "amount_value" {
+		column = dialect.DecimalKey(column)
+	}
+	return column, nil
+}
+
+func reversePayments(payments []*domain.Payment) {
+	for i, j := 0, len(payments)-1; i < j; i, j = i+1, j-1 {
+		payments[i], payments[j] = payments[j], payments[i]
+	}
+}
+
+func newIdempotencyStore() idempotencyStore {
+	return &defaultIdempotencyStore{}
+}
+
+type defaultIdempotencyStore struct{}
+
+func (s *defaultIdempotencyStore) Get(tx store.Tx, actor, key string) (*domain.IdempotencyKey, error) {
 	sqlTx := tx.(*sql.Tx)
 
-	tableName, ok := s.enumMapping[name]
-	if !ok {
-		return false, errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to select enumeration")
+	query := `
+	SELECT
+		actor,
+		key,
+		fingerprint,
+		resource_id,
+		response_status,
+		response_body,
+		created_at,
+		expires_at
+	FROM
+		idempotency_key
+	WHERE
+		actor = ? AND key = ?`
+
+	var record domain.IdempotencyKey
+	err := sqlTx.QueryRow(query, actor, key).Scan(
+		&record.Actor,
+		&record.Key,
+		&record.Fingerprint,
+		&record.ResourceID,
+		&record.ResponseStatus,
+		&record.ResponseBody,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to get idempotency key")
 	}
 
-	query := fmt.Sprintf(`SELECT count(*) FROM %s WHERE code = ?`, tableName)
+	return &record, nil
+}
+
+func (s *defaultIdempotencyStore) Insert(tx store.Tx, record *domain.IdempotencyKey) error {
+	sqlTx := tx.(*sql.Tx)
 
-	var count uint
-	err := sqlTx.QueryRow(query, code).Scan(&count)
+	query := `
+	INSERT INTO idempotency_key (
+		actor,
+		key,
+		fingerprint,
+		resource_id,
+		response_status,
+		response_body,
+		created_at,
+		expires_at
+	) VALUES (?,?,?,?,?,?,?,?)`
+
+	_, err := sqlTx.Exec(query,
+		record.Actor,
+		record.Key,
+		record.Fingerprint,
+		record.ResourceID,
+		record.ResponseStatus,
+		record.ResponseBody,
+		record.CreatedAt,
+		record.ExpiresAt,
+	)
+
+	return sql.WrapInsertError(err, "unable to insert idempotency key")
+}
+
+func (s *defaultIdempotencyStore) Delete(tx store.Tx, actor, key string) error {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `DELETE FROM idempotency_key WHERE actor = ? AND key = ?`
+
+	_, err := sqlTx.Exec(query, actor, key)
+
+	return sql.WrapDeleteError(err, "unable to delete idempotency key")
+}
+
+func newPaymentHistoryStore() paymentHistoryStore {
+	return &defaultPaymentHistoryStore{}
+}
+
+type defaultPaymentHistoryStore struct{}
+
+func (s *defaultPaymentHistoryStore) Find(tx store.Tx, paymentID domain.ID) ([]*domain.PaymentHistory, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	SELECT
+		id,
+		payment_id,
+		operation,
+		actor,
+		request_id,
+		created_at,
+		before_snapshot,
+		after_snapshot
+	FROM
+		payment_history
+	WHERE
+		payment_id = ?
+	ORDER BY
+		sequence`
+
+	rows, err := sqlTx.Query(query, paymentID)
 	if err != nil {
-		return false, sql.WrapSelectError(err, "unable to query enum")
+		return nil, sql.WrapSelectError(err, "unable to get payment history")
 	}
+	defer rows.Close()
 
-	return count == 1, nil
+	var history []*domain.PaymentHistory
+	for rows.Next() {
+		var (
+			entry         domain.PaymentHistory
+			before, after gosql.NullString
+		)
+		err := rows.Scan(
+			&entry.ID,
+			&entry.PaymentID,
+			&entry.Operation,
+			&entry.Actor,
+			&entry.RequestID,
+			&entry.Timestamp,
+			&before,
+			&after,
+		)
+		if err != nil {
+			return nil, sql.WrapSelectError(err, "unable to scan payment history")
+		}
+		entry.Before, err = decodePaymentSnapshot(before)
+		if err != nil {
+			return nil, err
+		}
+		entry.After, err = decodePaymentSnapshot(after)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, &entry)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to get payment history")
+	}
+
+	return history, nil
+}
+
+func (s *defaultPaymentHistoryStore) Insert(tx store.Tx, entry *domain.PaymentHistory) error {
+	sqlTx := tx.(*sql.Tx)
+
+	before, err := encodePaymentSnapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := encodePaymentSnapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO payment_history (
+		id,
+		payment_id,
+		operation,
+		actor,
+		request_id,
+		created_at,
+		before_snapshot,
+		after_snapshot
+	) VALUES (?,?,?,?,?,?,?,?)`
+
+	_, err = sqlTx.Exec(query,
+		entry.ID,
+		entry.PaymentID,
+		entry.Operation,
+		entry.Actor,
+		entry.RequestID,
+		entry.Timestamp,
+		before,
+		after,
+	)
+
+	return sql.WrapInsertError(err, "unable to insert payment history")
+}
+
+// encodePaymentSnapshot returns the snapshot as a JSON string, as the JSONB
+// columns do not accept binary parameters.
+func encodePaymentSnapshot(snapshot *domain.PaymentSnapshot) (gosql.NullString, error) {
+	if snapshot == nil {
+		return gosql.NullString{}, nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return gosql.NullString{}, errors.Generic(errors.ErrCodeGenericInternal, "unable to encode payment snapshot", err.Error())
+	}
+	return gosql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodePaymentSnapshot(data gosql.NullString) (*domain.PaymentSnapshot, error) {
+	if !data.Valid {
+		return nil, nil
+	}
+	snapshot := new(domain.PaymentSnapshot)
+	err := json.Unmarshal([]byte(data.String), snapshot)
+	if err != nil {
+		return nil, errors.Generic(errors.ErrCodeGenericInternal, "unable to decode payment snapshot", err.Error())
+	}
+	return snapshot, nil
+}
+
+func newPaymentEventStore() paymentEventStore {
+	return &defaultPaymentEventStore{}
+}
+
+type defaultPaymentEventStore struct{}
+
+func (s *defaultPaymentEventStore) Insert(tx store.Tx, event *domain.PaymentEvent) error {
+	sqlTx := tx.(*sql.Tx)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Generic(errors.ErrCodeGenericInternal, "unable to encode payment event", err.Error())
+	}
+
+	query := `
+	INSERT INTO payment_event (
+		id,
+		type,
+		payment_id,
+		occurred_at,
+		payload
+	) VALUES (?,?,?,?,?)`
+
+	_, err = sqlTx.Exec(query,
+		event.ID,
+		event.Type,
+		event.PaymentID,
+		event.OccurredAt,
+		string(payload),
+	)
+
+	return sql.WrapInsertError(err, "unable to insert payment event")
+}
+
+// ClaimPending locks the oldest pending events which are due to be published
+// at the given time, in the order they were recorded. Only the first pending
+// event of each payment is claimed, so the events of a payment are published
+// in order, and the events locked by other relays are skipped.
+func (s *defaultPaymentEventStore) ClaimPending(tx store.Tx, now time.Time, limit int) ([]*domain.PaymentEvent, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := fmt.Sprintf(`
+	SELECT
+		e.payload,
+		e.publication_status,
+		e.publication_attempts,
+		e.publication_next_attempt_at,
+		e.publication_last_error
+	FROM
+		payment_event e
+	WHERE
+		e.publication_status = ? AND
+		(e.publication_next_attempt_at IS NULL OR e.publication_next_attempt_at <= ?) AND
+		NOT EXISTS (
+			SELECT 1 FROM payment_event p
+			WHERE p.payment_id = e.payment_id AND p.sequence < e.sequence AND p.publication_status = ?
+		)
+	ORDER BY
+		e.sequence
+	LIMIT ?
+	%s`, sqlTx.Dialect().SkipLocked())
+
+	pending := domain.PaymentEventPublicationPending
+	rows, err := sqlTx.Query(query, pending, now.UTC(), pending, limit)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to claim payment events")
+	}
+	defer rows.Close()
+
+	var events []*domain.PaymentEvent
+	for rows.Next() {
+		var (
+			payload string
+			event   domain.PaymentEvent
+		)
+		err := rows.Scan(
+			&payload,
+			&event.Publication.Status,
+			&event.Publication.Attempts,
+			&event.Publication.NextAttemptAt,
+			&event.Publication.LastError,
+		)
+		if err != nil {
+			return nil, sql.WrapSelectError(err, "unable to scan payment event")
+		}
+		err = json.Unmarshal([]byte(payload), &event)
+		if err != nil {
+			return nil, errors.Generic(errors.ErrCodeGenericInternal, "unable to decode payment event", err.Error())
+		}
+		events = append(events, &event)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to claim payment events")
+	}
+
+	return events, nil
+}
+
+// AssignPositions numbers the committed events which have no position yet in
+// the order they were recorded, and reports how many there were. The counter
+// of the positions is locked, so the positions are assigned in turns and an
+// event committed late is positioned after the events committed before it.
+func (s *defaultPaymentEventStore) AssignPositions(tx store.Tx, limit int) (int, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	var last int64
+	query := `SELECT position FROM payment_event_feed WHERE id = 1 ` + sqlTx.Dialect().ForUpdate()
+	err := sqlTx.QueryRow(query).Scan(&last)
+	if err != nil {
+		return 0, sql.WrapSelectError(err, "unable to lock payment event feed")
+	}
+
+	query = `
+	SELECT
+		sequence
+	FROM
+		payment_event
+	WHERE
+		feed_position IS NULL
+	ORDER BY
+		sequence
+	LIMIT ?`
+
+	rows, err := sqlTx.Query(query, limit)
+	if err != nil {
+		return 0, sql.WrapSelectError(err, "unable to find unpositioned payment events")
+	}
+	defer rows.Close()
+
+	var sequences []int64
+	for rows.Next() {
+		var sequence int64
+		err := rows.Scan(&sequence)
+		if err != nil {
+			return 0, sql.WrapSelectError(err, "unable to scan payment event")
+		}
+		sequences = append(sequences, sequence)
+	}
+	err = rows.Err()
+	if err != nil {
+		return 0, sql.WrapSelectError(err, "unable to find unpositioned payment events")
+	}
+	if len(sequences) == 0 {
+		return 0, nil
+	}
+
+	for _, sequence := range sequences {
+		last++
+		_, err := sqlTx.Exec(`UPDATE payment_event SET feed_position = ? WHERE sequence = ?`, last, sequence)
+		if err != nil {
+			return 0, sql.WrapUpdateError(err, "unable to position payment event")
+		}
+	}
+	_, err = sqlTx.Exec(`UPDATE payment_event_feed SET position = ? WHERE id = 1`, last)
+	if err != nil {
+		return 0, sql.WrapUpdateError(err, "unable to update payment event feed")
+	}
+
+	return len(sequences), nil
+}
+
+// FindAfter returns the events positioned after the given position in the
+// order they were committed.
+func (s *defaultPaymentEventStore) FindAfter(tx store.Tx, position int64, limit int) ([]*domain.PaymentEvent, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	SELECT
+		sequence,
+		feed_position,
+		payload
+	FROM
+		payment_event
+	WHERE
+		feed_position > ?
+	ORDER BY
+		feed_position
+	LIMIT ?`
+
+	rows, err := sqlTx.Query(query, position, limit)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to find payment events")
+	}
+	defer rows.Close()
+
+	var events []*domain.PaymentEvent
+	for rows.Next() {
+		var (
+			seq     int64
+			pos     int64
+			payload string
+			event   domain.PaymentEvent
+		)
+		err := rows.Scan(&seq, &pos, &payload)
+		if err != nil {
+			return nil, sql.WrapSelectError(err, "unable to scan payment event")
+		}
+		err = json.Unmarshal([]byte(payload), &event)
+		if err != nil {
+			return nil, errors.Generic(errors.ErrCodeGenericInternal, "unable to decode payment event", err.Error())
+		}
+		event.Sequence = seq
+		event.Position = pos
+		events = append(events, &event)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to find payment events")
+	}
+
+	return events, nil
+}
+
+func (s *defaultPaymentEventStore) LastPosition(tx store.Tx) (int64, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	var last int64
+	err := sqlTx.QueryRow(`SELECT position FROM payment_event_feed WHERE id = 1`).Scan(&last)
+	if err != nil {
+		return 0, sql.WrapSelectError(err, "unable to get last payment event position")
+	}
+
+	return last, nil
+}
+
+func (s *defaultPaymentEventStore) UpdatePublication(tx store.Tx, event *domain.PaymentEvent) error {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	UPDATE payment_event
+	SET
+		publication_status = ?,
+		publication_attempts = ?,
+		publication_next_attempt_at = ?,
+		publication_last_error = ?,
+		published_at = ?
+	WHERE
+		id = ?`
+
+	result, err := sqlTx.Exec(query,
+		event.Publication.Status,
+		event.Publication.Attempts,
+		event.Publication.NextAttemptAt,
+		event.Publication.LastError,
+		event.Publication.PublishedAt,
+		event.ID,
+	)
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update payment event publication")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update payment event publication")
+	}
+	if affected == 0 {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update payment event publication", "payment event not found")
+	}
+
+	return nil
+}
+
+const (
+	enumNameScheme   = domain.EnumName("SCHEME")
+	enumNameCountry  = domain.EnumName("COUNTRY")
+	enumNameCurrency = domain.EnumName("CURRENCY")
+)
+
+func newEnumStore() *defaultEnumStore {
+	return &defaultEnumStore{
+		enumMapping: map[domain.EnumName]string{
+			enumNameScheme:   "enum_scheme",
+			enumNameCountry:  "enum_country",
+			enumNameCurrency: "enum_currency",
+		},
+	}
+}
+
+type defaultEnumStore struct {
+	enumMapping map[domain.EnumName]string
+}
+
+func (s *defaultEnumStore) Exists(tx store.Tx, name domain.EnumName, code string) (bool, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	tableName, ok := s.enumMapping[name]
+	if !ok {
+		return false, errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to select enumeration")
+	}
+
+	query := fmt.Sprintf(`SELECT count(*) FROM %s WHERE code = ? AND active`, tableName)
+
+	var count uint
+	err := sqlTx.QueryRow(query, code).Scan(&count)
+	if err != nil {
+		return false, sql.WrapSelectError(err, "unable to query enum")
+	}
+
+	return count == 1, nil
+}
+
+func (s *defaultEnumStore) Find(tx store.Tx, name domain.EnumName, req domain.EnumSearchRequest) ([]domain.Enum, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	tableName, ok := s.enumMapping[name]
+	if !ok {
+		return nil, errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to select enumeration")
+	}
+
+	columns, _ := enumColumns(newEnum(name))
+	query := fmt.Sprintf(`SELECT %s FROM %s`, strings.Join(columns, ", "), tableName)
+
+	var conds []string
+	var args []interface{}
+	if list := req.Codes(); len(list) > 0 {
+		cond, condArgs := sqlTx.Dialect().AnyOf("code", list)
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if prefix := req.NamePrefix(); prefix != "" {
+		cond, condArgs := sqlTx.Dialect().HasPrefixFold("name", prefix)
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if active, ok := req.Active(); ok {
+		conds, args = append(conds, "active = ?"), append(args, active)
+	}
+	if len(conds) > 0 {
+		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conds, " AND "))
+	}
+	query += " ORDER BY code"
+
+	rows, err := sqlTx.Query(query, args...)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to select enum")
+	}
+	defer rows.Close()
+
+	var enums []domain.Enum
+	for rows.Next() {
+		enum := newEnum(name)
+		_, dest := enumColumns(enum)
+		err = rows.Scan(dest...)
+		if err != nil {
+			return nil, sql.WrapSelectError(err, "unable to scan enum")
+		}
+		enums = append(enums, enum)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to select enum")
+	}
+
+	return enums, nil
+}
+
+func (s *defaultEnumStore) GetCurrency(tx store.Tx, code string) (*domain.Currency, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	currency := new(domain.Currency)
+	columns, dest := enumColumns(currency)
+	query := fmt.Sprintf(`SELECT %s FROM enum_currency WHERE code = ?`, strings.Join(columns, ", "))
+
+	err := sqlTx.QueryRow(query, code).Scan(dest...)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to query currency")
+	}
+
+	return currency, nil
+}
+
+func (s *defaultEnumStore) Insert(tx store.Tx, name domain.EnumName, enum domain.Enum) error {
+	sqlTx := tx.(*sql.Tx)
+
+	tableName, ok := s.enumMapping[name]
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to insert enumeration")
+	}
+
+	columns, fields := enumColumns(enum)
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s)`,
+		tableName,
+		strings.Join(columns, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "),
+	)
+
+	_, err := sqlTx.Exec(query, enumValues(fields)...)
+	if err != nil {
+		return sql.WrapInsertError(err, "unable to insert enum")
+	}
+
+	return nil
+}
+
+func (s *defaultEnumStore) Update(tx store.Tx, name domain.EnumName, enum domain.Enum) error {
+	sqlTx := tx.(*sql.Tx)
+
+	tableName, ok := s.enumMapping[name]
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to update enumeration")
+	}
+
+	// The code identifies the entry, hence it is never updated.
+	columns, fields := enumColumns(enum)
+	assignments := make([]string, len(columns)-1)
+	for i, column := range columns[1:] {
+		assignments[i] = column + " = ?"
+	}
+	query := fmt.Sprintf(`UPDATE %s SET %s WHERE code = ?`, tableName, strings.Join(assignments, ", "))
+
+	args := append(enumValues(fields[1:]), enum.GetID())
+	result, err := sqlTx.Exec(query, args...)
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update enum")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update enum")
+	}
+	if affected == 0 {
+		return sql.WrapUpdateError(gosql.ErrNoRows, "unable to update enum")
+	}
+
+	return nil
+}
+
+func newEnum(name domain.EnumName) domain.Enum {
+	switch name {
+	case enumNameScheme:
+		return new(domain.Scheme)
+	case enumNameCountry:
+		return new(domain.Country)
+	case enumNameCurrency:
+		return new(domain.Currency)
+	}
+	return nil
+}
+
+// enumColumns returns the columns of the enumeration table along with the
+// pointers to the matching fields of the entry, the code always comes first.
+func enumColumns(enum domain.Enum) ([]string, []interface{}) {
+	switch e := enum.(type) {
+	case *domain.Scheme:
+		return []string{"code", "name", "active"}, []interface{}{&e.Code, &e.Name, &e.Active}
+	case *domain.Country:
+		return []string{"code", "name", "active"}, []interface{}{&e.Code, &e.Name, &e.Active}
+	case *domain.Currency:
+		return []string{"code", "name", "active", "minor_units", "max_amount"},
+			[]interface{}{&e.Code, &e.Name, &e.Active, &e.MinorUnits, &e.MaxAmount}
+	}
+	return nil, nil
+}
+
+// enumValues dereferences the field pointers returned by enumColumns.
+func enumValues(fields []interface{}) []interface{} {
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		values[i] = reflect.ValueOf(f).Elem().Interface()
+	}
+	return values
+}
+
+func newCalendarStore() calendarStore {
+	return &defaultCalendarStore{}
+}
+
+type defaultCalendarStore struct{}
+
+// Get loads the calendar of the scheme along with all the holidays of the
+// holiday calendar it follows.
+func (s *defaultCalendarStore) Get(tx store.Tx, scheme string) (*domain.Calendar, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	SELECT
+		holiday_calendar,
+		time_zone,
+		cut_off
+	FROM
+		scheme_calendar
+	WHERE
+		scheme = ?`
+
+	var holidayCalendar, timeZone, cutOff string
+	err := sqlTx.QueryRow(query, scheme).Scan(&holidayCalendar, &timeZone, &cutOff)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to get calendar")
+	}
+
+	calendar, err := domain.NewCalendar(scheme, holidayCalendar, timeZone, cutOff)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+	SELECT
+		date,
+		name
+	FROM
+		calendar_holiday
+	WHERE
+		calendar = ?`
+
+	rows, err := sqlTx.Query(query, holidayCalendar)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to get calendar holidays")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			date domain.Date
+			name string
+		)
+		err := rows.Scan(&date, &name)
+		if err != nil {
+			return nil, sql.WrapSelectError(err, "unable to get calendar holidays")
+		}
+		calendar.Holidays[date] = name
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to get calendar holidays")
+	}
+
+	return calendar, nil
+}
+
+func newWebhookSubscriptionStore() webhookSubscriptionStore {
+	return &defaultWebhookSubscriptionStore{}
+}
+
+type defaultWebhookSubscriptionStore struct{}
+
+const webhookSubscriptionColumns = `
+		id,
+		target_url,
+		event_types,
+		secret,
+		active,
+		created_at,
+		updated_at`
+
+func (s *defaultWebhookSubscriptionStore) Find(tx store.Tx) ([]*domain.WebhookSubscription, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `SELECT` + webhookSubscriptionColumns + `
+	FROM
+		webhook_subscription
+	WHERE
+		deleted_at IS NULL
+	ORDER BY
+		created_at, id`
+
+	rows, err := sqlTx.Query(query)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to find webhook subscriptions")
+	}
+	defer rows.Close()
+
+	subscriptions := []*domain.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := s.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to find webhook subscriptions")
+	}
+
+	return subscriptions, nil
+}
+
+func (s *defaultWebhookSubscriptionStore) Get(tx store.Tx, id domain.ID) (*domain.WebhookSubscription, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `SELECT` + webhookSubscriptionColumns + `
+	FROM
+		webhook_subscription
+	WHERE
+		id = ? AND
+		deleted_at IS NULL`
+
+	subscription, err := s.scan(sqlTx.QueryRow(query, id))
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to get webhook subscription")
+	}
+
+	return subscription, nil
+}
+
+func (s *defaultWebhookSubscriptionStore) scan(row interface{ Scan(...interface{}) error }) (*domain.WebhookSubscription, error) {
+	var (
+		subscription domain.WebhookSubscription
+		eventTypes   string
+	)
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.TargetURL,
+		&eventTypes,
+		&subscription.Secret,
+		&subscription.Active,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	subscription.EventTypes = decodeEventTypes(eventTypes)
+	return &subscription, nil
+}
+
+func (s *defaultWebhookSubscriptionStore) Insert(tx store.Tx, subscription *domain.WebhookSubscription) error {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	INSERT INTO webhook_subscription (` + webhookSubscriptionColumns + `
+	) VALUES (?,?,?,?,?,?,?)`
+
+	_, err := sqlTx.Exec(query,
+		subscription.ID,
+		subscription.TargetURL,
+		encodeEventTypes(subscription.EventTypes),
+		subscription.Secret,
+		subscription.Active,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+
+	return sql.WrapInsertError(err, "unable to insert webhook subscription")
+}
+
+func (s *defaultWebhookSubscriptionStore) Update(tx store.Tx, subscription *domain.WebhookSubscription) error {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	UPDATE webhook_subscription
+	SET
+		target_url = ?,
+		event_types = ?,
+		secret = ?,
+		active = ?,
+		updated_at = ?
+	WHERE
+		id = ? AND
+		deleted_at IS NULL`
+
+	result, err := sqlTx.Exec(query,
+		subscription.TargetURL,
+		encodeEventTypes(subscription.EventTypes),
+		subscription.Secret,
+		subscription.Active,
+		subscription.UpdatedAt,
+		subscription.ID,
+	)
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update webhook subscription")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update webhook subscription")
+	}
+	if affected == 0 {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update webhook subscription", "webhook subscription not found")
+	}
+
+	return nil
+}
+
+// Delete marks the subscription as deleted, it is not found anymore but the
+// log of its deliveries is kept.
+func (s *defaultWebhookSubscriptionStore) Delete(tx store.Tx, id domain.ID, deletedAt time.Time) error {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	UPDATE webhook_subscription
+	SET
+		active = ?,
+		deleted_at = ?
+	WHERE
+		id = ? AND
+		deleted_at IS NULL`
+
+	result, err := sqlTx.Exec(query, false, deletedAt.UTC(), id)
+	if err != nil {
+		return sql.WrapDeleteError(err, "unable to delete webhook subscription")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return sql.WrapDeleteError(err, "unable to delete webhook subscription")
+	}
+	if affected == 0 {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to delete webhook subscription", "webhook subscription not found")
+	}
+
+	return nil
+}
+
+// encodeEventTypes returns the event types as a comma separated list, which
+// is stored the same way by all the databases.
+func encodeEventTypes(types []domain.PaymentEventType) string {
+	list := make([]string, len(types))
+	for i, t := range types {
+		list[i] = string(t)
+	}
+	return strings.Join(list, ",")
+}
+
+func decodeEventTypes(s string) []domain.PaymentEventType {
+	types := []domain.PaymentEventType{}
+	for _, t := range strings.Split(s, ",") {
+		if t != "" {
+			types = append(types, domain.PaymentEventType(t))
+		}
+	}
+	return types
+}
+
+func newWebhookDeliveryStore() webhookDeliveryStore {
+	return &defaultWebhookDeliveryStore{}
+}
+
+type defaultWebhookDeliveryStore struct{}
+
+const webhookDeliveryColumns = `
+		id,
+		subscription_id,
+		event_id,
+		event_type,
+		payment_id,
+		payload,
+		status,
+		attempts,
+		next_attempt_at,
+		last_error,
+		response_status,
+		created_at,
+		delivered_at`
+
+func (s *defaultWebhookDeliveryStore) Count(tx store.Tx, req domain.WebhookDeliverySearchRequest) (uint, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `SELECT count(*) FROM webhook_delivery`
+
+	conds, args := s.extractWhereClause(sqlTx.Dialect(), req)
+	if len(conds) > 0 {
+		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conds, " AND "))
+	}
+
+	var count uint
+	err := sqlTx.QueryRow(query, args...).Scan(&count)
+	if err != nil {
+		return 0, sql.WrapSelectError(err, "unable to count webhook deliveries")
+	}
+
+	return count, nil
+}
+
+// Find returns the deliveries in the order they were created.
+func (s *defaultWebhookDeliveryStore) Find(tx store.Tx, req domain.WebhookDeliverySearchRequest) ([]*domain.WebhookDelivery, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `SELECT` + webhookDeliveryColumns + `
+	FROM
+		webhook_delivery`
+
+	conds, args := s.extractWhereClause(sqlTx.Dialect(), req)
+	if len(conds) > 0 {
+		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conds, " AND "))
+	}
+	query += " ORDER BY sequence"
+	if pag := req.SearchPagination; pag != nil {
+		query = fmt.Sprintf("%s LIMIT %d OFFSET %d", query, pag.Limit(), pag.Offset())
+	}
+
+	rows, err := sqlTx.Query(query, args...)
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to find webhook deliveries")
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := s.scan(rows)
+		if err != nil {
+			return nil, sql.WrapSelectError(err, "unable to scan webhook delivery")
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to find webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+func (s *defaultWebhookDeliveryStore) extractWhereClause(dialect sql.Dialect, req domain.WebhookDeliverySearchRequest) (conds []string, args []interface{}) {
+	add := func(cond string, condArgs []interface{}) {
+		conds, args = append(conds, cond), append(args, condArgs...)
+	}
+	if ids := req.SubscriptionIDs(); len(ids) > 0 {
+		add(dialect.AnyOf("subscription_id", ids))
+	}
+	if ids := req.PaymentIDs(); len(ids) > 0 {
+		add(dialect.AnyOf("payment_id", ids))
+	}
+	// The enumerated values are passed as plain strings, which all the
+	// drivers accept as array elements.
+	if types := req.EventTypes(); len(types) > 0 {
+		list := make([]string, len(types))
+		for i, t := range types {
+			list[i] = string(t)
+		}
+		add(dialect.AnyOf("event_type", list))
+	}
+	if statuses := req.Statuses(); len(statuses) > 0 {
+		list := make([]string, len(statuses))
+		for i, s := range statuses {
+			list[i] = string(s)
+		}
+		add(dialect.AnyOf("status", list))
+	}
+	return conds, args
+}
+
+func (s *defaultWebhookDeliveryStore) Get(tx store.Tx, id domain.ID) (*domain.WebhookDelivery, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `SELECT` + webhookDeliveryColumns + `
+	FROM
+		webhook_delivery
+	WHERE
+		id = ?`
+
+	delivery, err := s.scan(sqlTx.QueryRow(query, id))
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to get webhook delivery")
+	}
+
+	return delivery, nil
+}
+
+func (s *defaultWebhookDeliveryStore) scan(row interface{ Scan(...interface{}) error }) (*domain.WebhookDelivery, error) {
+	var (
+		delivery domain.WebhookDelivery
+		payload  string
+	)
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.PaymentID,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastError,
+		&delivery.ResponseStatus,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = json.RawMessage(payload)
+	return &delivery, nil
+}
+
+// Exists reports whether the event has been scheduled to be delivered to the
+// subscription already.
+func (s *defaultWebhookDeliveryStore) Exists(tx store.Tx, subscriptionID, eventID domain.ID) (bool, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `SELECT count(*) FROM webhook_delivery WHERE subscription_id = ? AND event_id = ?`
+
+	var count uint
+	err := sqlTx.QueryRow(query, subscriptionID, eventID).Scan(&count)
+	if err != nil {
+		return false, sql.WrapSelectError(err, "unable to query webhook delivery")
+	}
+
+	return count > 0, nil
+}
+
+func (s *defaultWebhookDeliveryStore) Insert(tx store.Tx, delivery *domain.WebhookDelivery) error {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	INSERT INTO webhook_delivery (` + webhookDeliveryColumns + `
+	) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)`
+
+	_, err := sqlTx.Exec(query,
+		delivery.ID,
+		delivery.SubscriptionID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.PaymentID,
+		string(delivery.Payload),
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastError,
+		delivery.ResponseStatus,
+		delivery.CreatedAt,
+		delivery.DeliveredAt,
+	)
+
+	return sql.WrapInsertError(err, "unable to insert webhook delivery")
+}
+
+func (s *defaultWebhookDeliveryStore) Update(tx store.Tx, delivery *domain.WebhookDelivery) error {
+	sqlTx := tx.(*sql.Tx)
+
+	query := `
+	UPDATE webhook_delivery
+	SET
+		status = ?,
+		attempts = ?,
+		next_attempt_at = ?,
+		last_error = ?,
+		response_status = ?,
+		delivered_at = ?
+	WHERE
+		id = ?`
+
+	result, err := sqlTx.Exec(query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastError,
+		delivery.ResponseStatus,
+		delivery.DeliveredAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update webhook delivery")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return sql.WrapUpdateError(err, "unable to update webhook delivery")
+	}
+	if affected == 0 {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update webhook delivery", "webhook delivery not found")
+	}
+
+	return nil
+}
+
+// ClaimPending locks the oldest pending delivery of an active subscription
+// which is due to be attempted at the given time. Only the first pending
+// delivery of each payment to a subscription is claimed, so a subscription
+// receives the events of a payment in order, and the deliveries locked by
+// other workers are skipped.
+func (s *defaultWebhookDeliveryStore) ClaimPending(tx store.Tx, now time.Time) (*domain.WebhookDelivery, error) {
+	sqlTx := tx.(*sql.Tx)
+
+	query := fmt.Sprintf(`SELECT`+webhookDeliveryColumns+`
+	FROM
+		webhook_delivery d
+	WHERE
+		d.status = ? AND
+		(d.next_attempt_at IS NULL OR d.next_attempt_at <= ?) AND
+		d.subscription_id IN (SELECT id FROM webhook_subscription WHERE active AND deleted_at IS NULL) AND
+		NOT EXISTS (
+			SELECT 1 FROM webhook_delivery p
+			WHERE p.subscription_id = d.subscription_id AND p.payment_id = d.payment_id AND
+				p.sequence < d.sequence AND p.status = ?
+		)
+	ORDER BY d.sequence
+	LIMIT 1
+	%s`, sqlTx.Dialect().SkipLocked())
+
+	pending := domain.WebhookDeliveryStatusPending
+	delivery, err := s.scan(sqlTx.QueryRow(query, pending, now.UTC(), pending))
+	if err != nil {
+		return nil, sql.WrapSelectError(err, "unable to claim webhook delivery")
+	}
+
+	return delivery, nil
 }
@@ -0,0 +1,147 @@
+package payments
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+// cachedEnumStore answers the lookups made by the payment validation from
+// memory. An enumeration is loaded as a whole on its first lookup and kept
+// until it gets invalidated, which the enum service does once its changes are
+// committed. The TTL bounds for how long the changes made by other server
+// instances can go unnoticed.
+//
+// The lookups load through the transaction of the caller, which may have begun
+// before an invalidation and still see the enumeration as it was. Therefore
+// an invalidation bumps the generation and reloads the enumeration through a
+// fresh transaction, and a load is cached only if no invalidation happened
+// while it ran, nor is pending.
+type cachedEnumStore struct {
+	enumStore
+
+	txManager store.TxManager
+	ttl       time.Duration
+	clock     func() time.Time
+
+	mu         sync.Mutex
+	generation uint64
+	entries    map[domain.EnumName]*enumCacheEntry
+	reloading  map[domain.EnumName]int
+}
+
+type enumCacheEntry struct {
+	enums    map[string]domain.Enum
+	loadedAt time.Time
+}
+
+func newCachedEnumStore(txManager store.TxManager, enumStore enumStore, ttl time.Duration) *cachedEnumStore {
+	return &cachedEnumStore{
+		enumStore: enumStore,
+		txManager: txManager,
+		ttl:       ttl,
+		clock:     time.Now,
+		entries:   make(map[domain.EnumName]*enumCacheEntry),
+		reloading: make(map[domain.EnumName]int),
+	}
+}
+
+func (s *cachedEnumStore) Exists(tx store.Tx, name domain.EnumName, code string) (bool, error) {
+	enums, err := s.load(tx, name)
+	if err != nil {
+		return false, err
+	}
+	enum, ok := enums[code]
+	return ok && enum.IsActive(), nil
+}
+
+func (s *cachedEnumStore) GetCurrency(tx store.Tx, code string) (*domain.Currency, error) {
+	enums, err := s.load(tx, enumNameCurrency)
+	if err != nil {
+		return nil, err
+	}
+	enum, ok := enums[code]
+	if !ok {
+		return nil, errors.Generic(errors.ErrCodeGenericNotFound, "currency not found", "")
+	}
+	currency := *enum.(*domain.Currency)
+	return &currency, nil
+}
+
+// Invalidate drops the cached enumeration and reloads it through a fresh
+// transaction. If the reload fails, the enumeration gets loaded on the next
+// lookup instead. It must not be called within a transaction, as SQLite has
+// a single connection only.
+func (s *cachedEnumStore) Invalidate(ctx context.Context, name domain.EnumName) {
+	s.mu.Lock()
+	s.generation++
+	generation := s.generation
+	delete(s.entries, name)
+	s.reloading[name]++
+	s.mu.Unlock()
+
+	enums, loadedAt, err := s.reload(ctx, name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reloading[name]--
+	if s.reloading[name] == 0 {
+		delete(s.reloading, name)
+	}
+	if err == nil && s.generation == generation {
+		s.entries[name] = &enumCacheEntry{enums: enums, loadedAt: loadedAt}
+	}
+}
+
+func (s *cachedEnumStore) reload(ctx context.Context, name domain.EnumName) (map[string]domain.Enum, time.Time, error) {
+	tx, err := s.txManager.Begin(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	now := s.clock()
+	enums, err := s.find(tx, name)
+	return enums, now, err
+}
+
+// load returns the cached enumeration, loading it if missing or expired. The
+// loaded enumeration is cached only if it is of the current generation.
+func (s *cachedEnumStore) load(tx store.Tx, name domain.EnumName) (map[string]domain.Enum, error) {
+	s.mu.Lock()
+	now := s.clock()
+	if entry, ok := s.entries[name]; ok && now.Sub(entry.loadedAt) < s.ttl {
+		s.mu.Unlock()
+		return entry.enums, nil
+	}
+	generation := s.generation
+	s.mu.Unlock()
+
+	enums, err := s.find(tx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generation == generation && s.reloading[name] == 0 {
+		s.entries[name] = &enumCacheEntry{enums: enums, loadedAt: now}
+	}
+	return enums, nil
+}
+
+func (s *cachedEnumStore) find(tx store.Tx, name domain.EnumName) (map[string]domain.Enum, error) {
+	list, err := s.enumStore.Find(tx, name, domain.EnumSearchRequest{})
+	if err != nil {
+		return nil, err
+	}
+	enums := make(map[string]domain.Enum, len(list))
+	for _, enum := range list {
+		enums[enum.GetID()] = enum
+	}
+	return enums, nil
+}
@@ -0,0 +1,129 @@
+package payments
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/mock"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+func TestCachedEnumStore(t *testing.T) {
+	var (
+		loads  int
+		during func()
+	)
+	enumStore := testEnumStore()
+	find := enumStore.FindFn
+	enumStore.FindFn = func(tx store.Tx, name domain.EnumName, req domain.EnumSearchRequest) ([]domain.Enum, error) {
+		if name != enumNameCurrency {
+			return find(tx, name, req)
+		}
+		loads++
+		if during != nil {
+			// The load sees the enumeration as it was before the invalidation.
+			fn := during
+			during = nil
+			fn()
+			return nil, nil
+		}
+		return find(tx, name, req)
+	}
+
+	ctx := context.Background()
+	now := testClock()
+	cache := newCachedEnumStore(&mock.TxManager{}, enumStore, time.Minute)
+	cache.clock = func() time.Time { return now }
+
+	testCases := []struct {
+		name   string
+		before func()
+		code   string
+		exists bool
+		loads  int
+	}{
+		{name: "Active currency loads enumeration", code: "HUF", exists: true, loads: 1},
+		{name: "Inactive currency", code: "EUR", exists: false, loads: 1},
+		{name: "Missing currency", code: "USD", exists: false, loads: 1},
+		{
+			name:   "Invalidated enumeration",
+			before: func() { cache.Invalidate(ctx, enumNameCurrency) },
+			code:   "HUF",
+			exists: true,
+			loads:  2,
+		},
+		{
+			name:   "Other enumeration invalidated",
+			before: func() { cache.Invalidate(ctx, enumNameCountry) },
+			code:   "HUF",
+			exists: true,
+			loads:  2,
+		},
+		{
+			name:   "Expired enumeration",
+			before: func() { now = now.Add(time.Minute) },
+			code:   "HUF",
+			exists: true,
+			loads:  3,
+		},
+		{
+			name: "Invalidated while loading",
+			before: func() {
+				now = now.Add(time.Minute)
+				during = func() { cache.Invalidate(ctx, enumNameCurrency) }
+			},
+			code:   "HUF",
+			exists: false,
+			loads:  5,
+		},
+		{
+			name:   "Reloaded enumeration",
+			code:   "HUF",
+			exists: true,
+			loads:  5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.before != nil {
+				tc.before()
+			}
+
+			exists, err := cache.Exists(&mock.Tx{}, enumNameCurrency, tc.code)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, have := tc.exists, exists; want != have {
+				t.Fatalf("invalid existence: want %v, have %v", want, have)
+			}
+			if want, have := tc.loads, loads; want != have {
+				t.Fatalf("invalid number of loads: want %v, have %v", want, have)
+			}
+		})
+	}
+
+	t.Run("Currency", func(t *testing.T) {
+		currency, err := cache.GetCurrency(&mock.Tx{}, "HUF")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		currency.MinorUnits = 2
+
+		currency, err = cache.GetCurrency(&mock.Tx{}, "HUF")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want, have := int32(0), currency.MinorUnits; want != have {
+			t.Fatalf("cached currency modified: want %v, have %v", want, have)
+		}
+
+		_, err = cache.GetCurrency(&mock.Tx{}, "USD")
+		if !errors.Is(err, errors.ErrCodeGenericNotFound) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,1190 @@
+package payments
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+	"github.com/michaljemala/payments-sample/pkg/internal/store/memory"
+)
+
+const (
+	memoryPaymentTable              = "payment"
+	memoryIdempotencyKeyTable       = "idempotency_key"
+	memoryPaymentHistoryTable       = "payment_history"
+	memoryPaymentEventTable         = "payment_event"
+	memoryPaymentEventFeedTable     = "payment_event_feed"
+	memoryPaymentEventPositionTable = "payment_event_position"
+	memorySubscriptionTable         = "webhook_subscription"
+	memoryDeletedSubscriptionTable  = "deleted_webhook_subscription"
+	memoryDeliveryTable             = "webhook_delivery"
+)
+
+func newMemoryPaymentStore() paymentStore {
+	return &memoryPaymentStore{}
+}
+
+type memoryPaymentStore struct{}
+
+func (s *memoryPaymentStore) Count(tx store.Tx, req domain.PaymentSearchRequest) (uint, error) {
+	memTx := tx.(*memory.Tx)
+
+	payments, err := s.search(memTx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(len(payments)), nil
+}
+
+func (s *memoryPaymentStore) Find(tx store.Tx, req domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+	memTx := tx.(*memory.Tx)
+
+	payments, err := s.search(memTx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if pag := req.SearchPagination; pag != nil {
+		offset, limit := int(pag.Offset()), int(pag.Limit())
+		if offset > len(payments) {
+			offset = len(payments)
+		}
+		if offset+limit > len(payments) {
+			limit = len(payments) - offset
+		}
+		payments = payments[offset : offset+limit]
+	}
+	if c := req.SearchCursor; c != nil {
+		return s.page(payments, req.SearchSort.WithTieBreaker(paymentTieBreaker), c)
+	}
+
+	return payments, nil
+}
+
+// page selects the payments the same way the keyset condition of the SQL
+// store does, i.e. at most the cursor limit following or preceding it.
+func (s *memoryPaymentStore) page(payments []*domain.Payment, sort resource.SearchSort, c *resource.SearchCursor) ([]*domain.Payment, error) {
+	limit := int(c.Limit())
+
+	pos := c.Position()
+	if pos == nil {
+		if len(payments) > limit {
+			payments = payments[:limit]
+		}
+		return payments, nil
+	}
+	if len(pos.Values) != len(sort) {
+		return nil, errors.Generic(errors.ErrCodeGenericInvalidArgument, "invalid cursor", "cursor does not match the sort")
+	}
+
+	compare := func(p *domain.Payment) int {
+		for i, f := range sort {
+			c := compareSortValues(f.Field, paymentSortValues[f.Field](p), pos.Values[i])
+			if f.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	if c.Before != nil {
+		end := 0
+		for end < len(payments) && compare(payments[end]) < 0 {
+			end++
+		}
+		start := end - limit
+		if start < 0 {
+			start = 0
+		}
+		return payments[start:end], nil
+	}
+
+	start := 0
+	for start < len(payments) && compare(payments[start]) <= 0 {
+		start++
+	}
+	end := start + limit
+	if end > len(payments) {
+		end = len(payments)
+	}
+	return payments[start:end], nil
+}
+
+func (s *memoryPaymentStore) Get(tx store.Tx, id domain.ID) (*domain.Payment, error) {
+	memTx := tx.(*memory.Tx)
+
+	payment, ok := s.get(memTx, id)
+	if !ok {
+		return nil, errors.Generic(errors.ErrCodeGenericNotFound, "unable to get payment", "payment not found")
+	}
+
+	return payment, nil
+}
+
+// get loads the payment unless it has been deleted.
+func (s *memoryPaymentStore) get(memTx *memory.Tx, id domain.ID) (*domain.Payment, bool) {
+	v, ok := memTx.Get(memoryPaymentTable, id.String())
+	if !ok {
+		return nil, false
+	}
+	payment := v.(domain.Payment)
+	if payment.IsDeleted() {
+		return nil, false
+	}
+	// The dates are decoded in place by the updates, they must not be
+	// shared with the stored payment.
+	if payment.RequestedExecutionDate != nil {
+		date := *payment.RequestedExecutionDate
+		payment.RequestedExecutionDate = &date
+	}
+	if payment.ValueDate != nil {
+		date := *payment.ValueDate
+		payment.ValueDate = &date
+	}
+	return &payment, true
+}
+
+func (s *memoryPaymentStore) Insert(tx store.Tx, payment *domain.Payment) error {
+	memTx := tx.(*memory.Tx)
+
+	if _, ok := memTx.Get(memoryPaymentTable, payment.ID.String()); ok {
+		return errors.Generic(errors.ErrCodeGenericAlreadyExists, "unable to insert payment", "payment already exists")
+	}
+
+	payment.Version = initialVersion
+	memTx.Put(memoryPaymentTable, payment.ID.String(), *payment)
+
+	return nil
+}
+
+func (s *memoryPaymentStore) Delete(tx store.Tx, id domain.ID, version uint, deletedAt time.Time) error {
+	memTx := tx.(*memory.Tx)
+
+	current, ok := s.get(memTx, id)
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to delete payment", "payment not found")
+	}
+	if version > 0 && current.Version != version {
+		return errors.Generic(
+			errors.ErrCodeGenericPreconditionFailed,
+			"unable to delete payment",
+			fmt.Sprintf("payment has been modified, current version is %d", current.Version),
+		)
+	}
+
+	current.Version++
+	current.DeletedAt = &deletedAt
+	current.UpdatedAt = deletedAt
+	memTx.Put(memoryPaymentTable, id.String(), *current)
+
+	return nil
+}
+
+func (s *memoryPaymentStore) Restore(tx store.Tx, payment *domain.Payment) error {
+	memTx := tx.(*memory.Tx)
+
+	v, ok := memTx.Get(memoryPaymentTable, payment.ID.String())
+	if ok {
+		current := v.(domain.Payment)
+		ok = current.IsDeleted() && current.Version == payment.Version
+	}
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to restore payment", "deleted payment not found")
+	}
+
+	payment.Version++
+	payment.DeletedAt = nil
+	memTx.Put(memoryPaymentTable, payment.ID.String(), *payment)
+
+	return nil
+}
+
+func (s *memoryPaymentStore) Update(tx store.Tx, payment *domain.Payment) error {
+	memTx := tx.(*memory.Tx)
+
+	current, ok := s.get(memTx, payment.ID)
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update payment", "payment not found")
+	}
+	if current.Version != payment.Version {
+		return errors.Generic(
+			errors.ErrCodeGenericPreconditionFailed,
+			"unable to update payment",
+			fmt.Sprintf("payment has been modified, current version is %d", current.Version),
+		)
+	}
+
+	// The creation time is never updated, the same way as in the SQL store.
+	payment.CreatedAt = current.CreatedAt
+	payment.Version++
+	memTx.Put(memoryPaymentTable, payment.ID.String(), *payment)
+
+	return nil
+}
+
+func (s *memoryPaymentStore) ClaimDue(tx store.Tx, now time.Time) (*domain.Payment, error) {
+	today := domain.DateOf(now.UTC())
+	return s.claim(tx.(*memory.Tx), now, domain.PaymentStatusDraft,
+		func(p *domain.Payment) bool {
+			return p.RequestedExecutionDate != nil && !today.Before(*p.RequestedExecutionDate)
+		},
+		func(p, o *domain.Payment) bool {
+			return p.RequestedExecutionDate.Before(*o.RequestedExecutionDate)
+		},
+	)
+}
+
+func (s *memoryPaymentStore) ClaimSubmitted(tx store.Tx, now time.Time) (*domain.Payment, error) {
+	return s.claim(tx.(*memory.Tx), now, domain.PaymentStatusSubmitted,
+		func(*domain.Payment) bool { return true },
+		func(p, o *domain.Payment) bool { return p.UpdatedAt.Before(o.UpdatedAt) },
+	)
+}
+
+// claim picks the first matching payment in the given order, the payments
+// are scanned ordered by id which makes it the tie-breaker. Transactions of
+// the memory store are serialized on commit, so there are no locked payments
+// to be skipped.
+func (s *memoryPaymentStore) claim(
+	memTx *memory.Tx,
+	now time.Time,
+	status domain.PaymentStatus,
+	match func(*domain.Payment) bool,
+	less func(p, o *domain.Payment) bool,
+) (*domain.Payment, error) {
+	var claimed *domain.Payment
+	memTx.Scan(memoryPaymentTable, func(_ string, v interface{}) bool {
+		payment := v.(domain.Payment)
+		if payment.IsDeleted() || payment.Status != status || !match(&payment) {
+			return true
+		}
+		if next := payment.Execution.NextAttemptAt; next != nil && next.After(now) {
+			return true
+		}
+		if claimed == nil || less(&payment, claimed) {
+			claimed = &payment
+		}
+		return true
+	})
+	if claimed == nil {
+		return nil, errors.Generic(errors.ErrCodeGenericNotFound, "unable to claim payment", "no payment is pending")
+	}
+
+	return claimed, nil
+}
+
+func (s *memoryPaymentStore) UpdateExecution(tx store.Tx, payment *domain.Payment) error {
+	memTx := tx.(*memory.Tx)
+
+	current, ok := s.get(memTx, payment.ID)
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update payment execution", "payment not found")
+	}
+
+	current.Execution = payment.Execution
+	memTx.Put(memoryPaymentTable, payment.ID.String(), *current)
+
+	return nil
+}
+
+func (s *memoryPaymentStore) search(memTx *memory.Tx, req domain.PaymentSearchRequest) ([]*domain.Payment, error) {
+	for _, f := range req.SearchSort {
+		if _, ok := paymentSortValues[f.Field]; !ok {
+			return nil, errors.Generic(errors.ErrCodeGenericInvalidArgument, "unsupported sort field", f.Field)
+		}
+	}
+
+	// Scan visits the payments ordered by id, the stable sort keeps it as
+	// the tie-breaker the same way as the SQL store does.
+	var payments []*domain.Payment
+	memTx.Scan(memoryPaymentTable, func(_ string, v interface{}) bool {
+		payment := v.(domain.Payment)
+		if s.matches(req, &payment) {
+			payments = append(payments, &payment)
+		}
+		return true
+	})
+
+	sort.SliceStable(payments, func(i, j int) bool {
+		for _, f := range req.SearchSort {
+			value := paymentSortValues[f.Field]
+			c := compareSortValues(f.Field, value(payments[i]), value(payments[j]))
+			if f.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	return payments, nil
+}
+
+func compareSortValues(field, a, b string) int {
+	if field == "amount.value" {
+		da, errA := domain.DecimalFrom(a)
+		db, errB := domain.DecimalFrom(b)
+		if errA == nil && errB == nil {
+			return da.Cmp(db)
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+func (s *memoryPaymentStore) matches(req domain.PaymentSearchRequest, payment *domain.Payment) bool {
+	return payment.IsDeleted() == req.Deleted() && matchesPaymentFilter(req, payment)
+}
+
+// matchesPaymentFilter reports whether the payment matches the filter of the
+// search request apart from the deletion, which is up to the caller.
+func matchesPaymentFilter(req domain.PaymentSearchRequest, payment *domain.Payment) bool {
+	if list := req.IDs(); len(list) > 0 {
+		found := false
+		for _, id := range list {
+			if id == payment.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if list := req.CreditorAccountNumbers(); len(list) > 0 && !containsString(list, payment.Creditor.AccountNumber) {
+		return false
+	}
+	if list := req.DebtorAccountNumbers(); len(list) > 0 && !containsString(list, payment.Debtor.AccountNumber) {
+		return false
+	}
+	if list := req.Currencies(); len(list) > 0 && !containsString(list, payment.Amount.Currency) {
+		return false
+	}
+	if list := req.Schemes(); len(list) > 0 && !containsString(list, payment.Scheme) {
+		return false
+	}
+	if list := req.EndToEndReferences(); len(list) > 0 && !containsString(list, payment.EndToEndReference) {
+		return false
+	}
+	if list := req.NumericReferences(); len(list) > 0 && !containsString(list, payment.NumericReference) {
+		return false
+	}
+	if list := req.CreditorReferences(); len(list) > 0 && !containsString(list, payment.RemittanceInformation.Structured) {
+		return false
+	}
+	if list := req.References(); len(list) > 0 &&
+		!containsString(list, payment.EndToEndReference) &&
+		!containsString(list, payment.NumericReference) &&
+		!containsString(list, payment.RemittanceInformation.Structured) {
+		return false
+	}
+	if !req.AmountRange().Contains(payment.Amount.Value) {
+		return false
+	}
+	if !req.CreatedAtRange().Contains(payment.CreatedAt) || !req.UpdatedAtRange().Contains(payment.UpdatedAt) {
+		return false
+	}
+	if dateRange := req.RequestedExecutionDateRange(); !dateRange.IsEmpty() {
+		if payment.RequestedExecutionDate == nil || !dateRange.Contains(*payment.RequestedExecutionDate) {
+			return false
+		}
+	}
+	if prefix := req.CreditorNamePrefix(); prefix != "" && !hasPrefixFold(payment.Creditor.Name, prefix) {
+		return false
+	}
+	if prefix := req.DebtorNamePrefix(); prefix != "" && !hasPrefixFold(payment.Debtor.Name, prefix) {
+		return false
+	}
+	return true
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix))
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func newMemoryIdempotencyStore() idempotencyStore {
+	return &memoryIdempotencyStore{}
+}
+
+type memoryIdempotencyStore struct{}
+
+func (s *memoryIdempotencyStore) Get(tx store.Tx, actor, key string) (*domain.IdempotencyKey, error) {
+	memTx := tx.(*memory.Tx)
+
+	v, ok := memTx.Get(memoryIdempotencyKeyTable, memoryIdempotencyKey(actor, key))
+	if !ok {
+		return nil, errors.Generic(errors.ErrCodeGenericNotFound, "unable to get idempotency key", "idempotency key not found")
+	}
+	record := v.(domain.IdempotencyKey)
+
+	return &record, nil
+}
+
+func (s *memoryIdempotencyStore) Insert(tx store.Tx, record *domain.IdempotencyKey) error {
+	memTx := tx.(*memory.Tx)
+
+	key := memoryIdempotencyKey(record.Actor, record.Key)
+	if _, ok := memTx.Get(memoryIdempotencyKeyTable, key); ok {
+		return errors.Generic(errors.ErrCodeGenericAlreadyExists, "unable to insert idempotency key", "idempotency key already exists")
+	}
+	memTx.Put(memoryIdempotencyKeyTable, key, *record)
+
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Delete(tx store.Tx, actor, key string) error {
+	memTx := tx.(*memory.Tx)
+
+	memTx.Delete(memoryIdempotencyKeyTable, memoryIdempotencyKey(actor, key))
+
+	return nil
+}
+
+// memoryIdempotencyKey joins the actor and the key with a line break, which
+// neither of the headers they are taken from can contain.
+func memoryIdempotencyKey(actor, key string) string {
+	return actor + "\n" + key
+}
+
+func newMemoryPaymentHistoryStore() paymentHistoryStore {
+	return &memoryPaymentHistoryStore{}
+}
+
+type memoryPaymentHistoryStore struct{}
+
+func (s *memoryPaymentHistoryStore) Find(tx store.Tx, paymentID domain.ID) ([]*domain.PaymentHistory, error) {
+	memTx := tx.(*memory.Tx)
+
+	var history []*domain.PaymentHistory
+	memTx.Scan(memoryPaymentHistoryTable, func(_ string, v interface{}) bool {
+		if entry := v.(domain.PaymentHistory); entry.PaymentID == paymentID {
+			history = append(history, &entry)
+		}
+		return true
+	})
+
+	return history, nil
+}
+
+// Insert keys the entries by their position in the payment history, so the
+// concurrent changes of the same payment conflict on commit.
+func (s *memoryPaymentHistoryStore) Insert(tx store.Tx, entry *domain.PaymentHistory) error {
+	memTx := tx.(*memory.Tx)
+
+	history, err := s.Find(tx, entry.PaymentID)
+	if err != nil {
+		return err
+	}
+	memTx.Put(memoryPaymentHistoryTable, fmt.Sprintf("%s/%06d", entry.PaymentID, len(history)), *entry)
+
+	return nil
+}
+
+func newMemoryPaymentEventStore() paymentEventStore {
+	return &memoryPaymentEventStore{}
+}
+
+// memoryPaymentEventStore numbers the events by a sequence the same way as
+// the SQL stores do.
+type memoryPaymentEventStore struct {
+	sequence int64
+}
+
+// Insert keys the events by their position among the events of the payment,
+// the same way as the payment history.
+func (s *memoryPaymentEventStore) Insert(tx store.Tx, event *domain.PaymentEvent) error {
+	memTx := tx.(*memory.Tx)
+
+	recorded := *event
+	recorded.Sequence = atomic.AddInt64(&s.sequence, 1)
+
+	var n int
+	prefix := event.PaymentID.String() + "/"
+	memTx.Scan(memoryPaymentEventTable, func(key string, _ interface{}) bool {
+		if strings.HasPrefix(key, prefix) {
+			n++
+		}
+		return true
+	})
+	memTx.Put(memoryPaymentEventTable, fmt.Sprintf("%s%06d", prefix, n), recorded)
+
+	return nil
+}
+
+// AssignPositions keys the positioned events by their position and marks
+// them by the position, both in tables of their own, so the positioning does
+// not conflict with the relay updating the events. Two assignments running at
+// once write the same positions, so only one of them commits.
+func (s *memoryPaymentEventStore) AssignPositions(tx store.Tx, limit int) (int, error) {
+	memTx := tx.(*memory.Tx)
+
+	last, _ := s.LastPosition(tx)
+
+	type unpositioned struct {
+		key      string
+		sequence int64
+	}
+	var events []unpositioned
+	memTx.Scan(memoryPaymentEventTable, func(key string, v interface{}) bool {
+		if _, ok := memTx.Get(memoryPaymentEventPositionTable, key); !ok {
+			events = append(events, unpositioned{key, v.(domain.PaymentEvent).Sequence})
+		}
+		return true
+	})
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].sequence < events[j].sequence
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	for _, event := range events {
+		last++
+		memTx.Put(memoryPaymentEventFeedTable, fmt.Sprintf("%020d", last), event.key)
+		memTx.Put(memoryPaymentEventPositionTable, event.key, last)
+	}
+
+	return len(events), nil
+}
+
+func (s *memoryPaymentEventStore) FindAfter(tx store.Tx, position int64, limit int) ([]*domain.PaymentEvent, error) {
+	memTx := tx.(*memory.Tx)
+
+	after := fmt.Sprintf("%020d", position)
+	var events []*domain.PaymentEvent
+	memTx.Scan(memoryPaymentEventFeedTable, func(key string, v interface{}) bool {
+		if key <= after {
+			return true
+		}
+		e, ok := memTx.Get(memoryPaymentEventTable, v.(string))
+		if !ok {
+			return true
+		}
+		event := e.(domain.PaymentEvent)
+		event.Position, _ = strconv.ParseInt(key, 10, 64)
+		events = append(events, &event)
+		return len(events) < limit
+	})
+
+	return events, nil
+}
+
+func (s *memoryPaymentEventStore) LastPosition(tx store.Tx) (int64, error) {
+	memTx := tx.(*memory.Tx)
+
+	var last int64
+	memTx.Scan(memoryPaymentEventFeedTable, func(key string, _ interface{}) bool {
+		last, _ = strconv.ParseInt(key, 10, 64)
+		return true
+	})
+
+	return last, nil
+}
+
+// ClaimPending returns the first pending event of each payment which is due,
+// ordered by the sequence the same way as in the SQL stores. There is nothing
+// to lock, the concurrent publications conflict on commit.
+func (s *memoryPaymentEventStore) ClaimPending(tx store.Tx, now time.Time, limit int) ([]*domain.PaymentEvent, error) {
+	memTx := tx.(*memory.Tx)
+
+	var events []*domain.PaymentEvent
+	blocked := make(map[domain.ID]bool)
+	memTx.Scan(memoryPaymentEventTable, func(_ string, v interface{}) bool {
+		event := v.(domain.PaymentEvent)
+		if event.Publication.Status != domain.PaymentEventPublicationPending || blocked[event.PaymentID] {
+			return true
+		}
+		blocked[event.PaymentID] = true
+		if next := event.Publication.NextAttemptAt; next == nil || !next.After(now) {
+			events = append(events, &event)
+		}
+		return true
+	})
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Sequence < events[j].Sequence
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+func (s *memoryPaymentEventStore) UpdatePublication(tx store.Tx, event *domain.PaymentEvent) error {
+	memTx := tx.(*memory.Tx)
+
+	var key string
+	memTx.Scan(memoryPaymentEventTable, func(k string, v interface{}) bool {
+		if v.(domain.PaymentEvent).ID == event.ID {
+			key = k
+			return false
+		}
+		return true
+	})
+	if key == "" {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update payment event publication", "payment event not found")
+	}
+	memTx.Put(memoryPaymentEventTable, key, *event)
+
+	return nil
+}
+
+func newMemoryWebhookSubscriptionStore() webhookSubscriptionStore {
+	return &memoryWebhookSubscriptionStore{}
+}
+
+type memoryWebhookSubscriptionStore struct{}
+
+func (s *memoryWebhookSubscriptionStore) Find(tx store.Tx) ([]*domain.WebhookSubscription, error) {
+	memTx := tx.(*memory.Tx)
+
+	subscriptions := []*domain.WebhookSubscription{}
+	memTx.Scan(memorySubscriptionTable, func(_ string, v interface{}) bool {
+		subscription := v.(domain.WebhookSubscription)
+		subscriptions = append(subscriptions, &subscription)
+		return true
+	})
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+
+	return subscriptions, nil
+}
+
+func (s *memoryWebhookSubscriptionStore) Get(tx store.Tx, id domain.ID) (*domain.WebhookSubscription, error) {
+	memTx := tx.(*memory.Tx)
+
+	v, ok := memTx.Get(memorySubscriptionTable, id.String())
+	if !ok {
+		return nil, errors.Generic(errors.ErrCodeGenericNotFound, "unable to get webhook subscription", "webhook subscription not found")
+	}
+	subscription := v.(domain.WebhookSubscription)
+
+	return &subscription, nil
+}
+
+func (s *memoryWebhookSubscriptionStore) Insert(tx store.Tx, subscription *domain.WebhookSubscription) error {
+	memTx := tx.(*memory.Tx)
+
+	_, exists := memTx.Get(memorySubscriptionTable, subscription.ID.String())
+	if _, deleted := memTx.Get(memoryDeletedSubscriptionTable, subscription.ID.String()); exists || deleted {
+		return errors.Generic(errors.ErrCodeGenericAlreadyExists, "unable to insert webhook subscription", "webhook subscription already exists")
+	}
+	memTx.Put(memorySubscriptionTable, subscription.ID.String(), *subscription)
+
+	return nil
+}
+
+func (s *memoryWebhookSubscriptionStore) Update(tx store.Tx, subscription *domain.WebhookSubscription) error {
+	memTx := tx.(*memory.Tx)
+
+	if _, ok := memTx.Get(memorySubscriptionTable, subscription.ID.String()); !ok {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update webhook subscription", "webhook subscription not found")
+	}
+	memTx.Put(memorySubscriptionTable, subscription.ID.String(), *subscription)
+
+	return nil
+}
+
+// Delete moves the subscription aside, so it is not found anymore while its
+// deliveries are kept and its id is not reused, the same way as in the SQL
+// stores.
+func (s *memoryWebhookSubscriptionStore) Delete(tx store.Tx, id domain.ID, deletedAt time.Time) error {
+	memTx := tx.(*memory.Tx)
+
+	v, ok := memTx.Get(memorySubscriptionTable, id.String())
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to delete webhook subscription", "webhook subscription not found")
+	}
+	subscription := v.(domain.WebhookSubscription)
+	subscription.Active = false
+	memTx.Delete(memorySubscriptionTable, id.String())
+	memTx.Put(memoryDeletedSubscriptionTable, id.String(), subscription)
+
+	return nil
+}
+
+func newMemoryWebhookDeliveryStore() webhookDeliveryStore {
+	return &memoryWebhookDeliveryStore{}
+}
+
+// memoryWebhookDeliveryStore keys the deliveries by a sequence, so they are
+// scanned in the order they were created.
+type memoryWebhookDeliveryStore struct {
+	sequence int64
+}
+
+func (s *memoryWebhookDeliveryStore) Count(tx store.Tx, req domain.WebhookDeliverySearchRequest) (uint, error) {
+	memTx := tx.(*memory.Tx)
+
+	var count uint
+	memTx.Scan(memoryDeliveryTable, func(_ string, v interface{}) bool {
+		if delivery := v.(domain.WebhookDelivery); s.matches(req, &delivery) {
+			count++
+		}
+		return true
+	})
+
+	return count, nil
+}
+
+func (s *memoryWebhookDeliveryStore) Find(tx store.Tx, req domain.WebhookDeliverySearchRequest) ([]*domain.WebhookDelivery, error) {
+	memTx := tx.(*memory.Tx)
+
+	deliveries := []*domain.WebhookDelivery{}
+	memTx.Scan(memoryDeliveryTable, func(_ string, v interface{}) bool {
+		if delivery := v.(domain.WebhookDelivery); s.matches(req, &delivery) {
+			deliveries = append(deliveries, &delivery)
+		}
+		return true
+	})
+
+	if pag := req.SearchPagination; pag != nil {
+		offset, limit := int(pag.Offset()), int(pag.Limit())
+		if offset > len(deliveries) {
+			offset = len(deliveries)
+		}
+		if offset+limit > len(deliveries) {
+			limit = len(deliveries) - offset
+		}
+		deliveries = deliveries[offset : offset+limit]
+	}
+
+	return deliveries, nil
+}
+
+func (s *memoryWebhookDeliveryStore) matches(req domain.WebhookDeliverySearchRequest, delivery *domain.WebhookDelivery) bool {
+	if ids := req.SubscriptionIDs(); len(ids) > 0 && !containsID(ids, delivery.SubscriptionID) {
+		return false
+	}
+	if ids := req.PaymentIDs(); len(ids) > 0 && !containsID(ids, delivery.PaymentID) {
+		return false
+	}
+	if types := req.EventTypes(); len(types) > 0 {
+		var found bool
+		for _, t := range types {
+			found = found || t == delivery.EventType
+		}
+		if !found {
+			return false
+		}
+	}
+	if statuses := req.Statuses(); len(statuses) > 0 {
+		var found bool
+		for _, status := range statuses {
+			found = found || status == delivery.Status
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *memoryWebhookDeliveryStore) Get(tx store.Tx, id domain.ID) (*domain.WebhookDelivery, error) {
+	delivery, _, ok := s.get(tx.(*memory.Tx), id)
+	if !ok {
+		return nil, errors.Generic(errors.ErrCodeGenericNotFound, "unable to get webhook delivery", "webhook delivery not found")
+	}
+	return delivery, nil
+}
+
+func (s *memoryWebhookDeliveryStore) get(memTx *memory.Tx, id domain.ID) (*domain.WebhookDelivery, string, bool) {
+	var (
+		found *domain.WebhookDelivery
+		key   string
+	)
+	memTx.Scan(memoryDeliveryTable, func(k string, v interface{}) bool {
+		if delivery := v.(domain.WebhookDelivery); delivery.ID == id {
+			found, key = &delivery, k
+			return false
+		}
+		return true
+	})
+	return found, key, found != nil
+}
+
+func (s *memoryWebhookDeliveryStore) Exists(tx store.Tx, subscriptionID, eventID domain.ID) (bool, error) {
+	memTx := tx.(*memory.Tx)
+
+	var exists bool
+	memTx.Scan(memoryDeliveryTable, func(_ string, v interface{}) bool {
+		d := v.(domain.WebhookDelivery)
+		exists = d.SubscriptionID == subscriptionID && d.EventID == eventID
+		return !exists
+	})
+
+	return exists, nil
+}
+
+// Insert rejects a second delivery of the same event to the subscription,
+// the same way as the unique key of the SQL stores.
+func (s *memoryWebhookDeliveryStore) Insert(tx store.Tx, delivery *domain.WebhookDelivery) error {
+	exists, _ := s.Exists(tx, delivery.SubscriptionID, delivery.EventID)
+	if exists {
+		return errors.Generic(errors.ErrCodeGenericAlreadyExists, "unable to insert webhook delivery", "event already delivered to the subscription")
+	}
+	tx.(*memory.Tx).Put(memoryDeliveryTable, fmt.Sprintf("%020d", atomic.AddInt64(&s.sequence, 1)), *delivery)
+
+	return nil
+}
+
+func (s *memoryWebhookDeliveryStore) Update(tx store.Tx, delivery *domain.WebhookDelivery) error {
+	memTx := tx.(*memory.Tx)
+
+	_, key, ok := s.get(memTx, delivery.ID)
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update webhook delivery", "webhook delivery not found")
+	}
+	memTx.Put(memoryDeliveryTable, key, *delivery)
+
+	return nil
+}
+
+// ClaimPending picks the oldest pending delivery which is the first pending
+// delivery of its payment to its subscription, there are no locked deliveries
+// to be skipped as the transactions are serialized on commit.
+func (s *memoryWebhookDeliveryStore) ClaimPending(tx store.Tx, now time.Time) (*domain.WebhookDelivery, error) {
+	memTx := tx.(*memory.Tx)
+
+	var claimed *domain.WebhookDelivery
+	blocked := make(map[[2]domain.ID]bool)
+	memTx.Scan(memoryDeliveryTable, func(_ string, v interface{}) bool {
+		delivery := v.(domain.WebhookDelivery)
+		if delivery.Status != domain.WebhookDeliveryStatusPending {
+			return true
+		}
+		key := [2]domain.ID{delivery.SubscriptionID, delivery.PaymentID}
+		if blocked[key] {
+			return true
+		}
+		blocked[key] = true
+		if next := delivery.NextAttemptAt; next != nil && next.After(now) {
+			return true
+		}
+		v, ok := memTx.Get(memorySubscriptionTable, delivery.SubscriptionID.String())
+		if !ok || !v.(domain.WebhookSubscription).Active {
+			return true
+		}
+		claimed = &delivery
+		return false
+	})
+	if claimed == nil {
+		return nil, errors.Generic(errors.ErrCodeGenericNotFound, "unable to claim webhook delivery", "no webhook delivery is pending")
+	}
+
+	return claimed, nil
+}
+
+func containsID(list []domain.ID, id domain.ID) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func newMemoryEnumStore() *memoryEnumStore {
+	return &memoryEnumStore{
+		enumMapping: map[domain.EnumName]string{
+			enumNameScheme:   "enum_scheme",
+			enumNameCountry:  "enum_country",
+			enumNameCurrency: "enum_currency",
+		},
+	}
+}
+
+type memoryEnumStore struct {
+	enumMapping map[domain.EnumName]string
+}
+
+func (s *memoryEnumStore) Exists(tx store.Tx, name domain.EnumName, code string) (bool, error) {
+	memTx := tx.(*memory.Tx)
+
+	tableName, ok := s.enumMapping[name]
+	if !ok {
+		return false, errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to select enumeration")
+	}
+
+	v, ok := memTx.Get(tableName, code)
+	if !ok {
+		return false, nil
+	}
+
+	return memoryEnum(v).IsActive(), nil
+}
+
+func (s *memoryEnumStore) Find(tx store.Tx, name domain.EnumName, req domain.EnumSearchRequest) ([]domain.Enum, error) {
+	memTx := tx.(*memory.Tx)
+
+	tableName, ok := s.enumMapping[name]
+	if !ok {
+		return nil, errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to select enumeration")
+	}
+
+	var enums []domain.Enum
+	memTx.Scan(tableName, func(code string, value interface{}) bool {
+		enum := memoryEnum(value)
+		if list := req.Codes(); len(list) > 0 && !containsString(list, code) {
+			return true
+		}
+		if prefix := req.NamePrefix(); prefix != "" && !hasPrefixFold(memoryEnumName(enum), prefix) {
+			return true
+		}
+		if active, ok := req.Active(); ok && enum.IsActive() != active {
+			return true
+		}
+		enums = append(enums, enum)
+		return true
+	})
+
+	return enums, nil
+}
+
+func (s *memoryEnumStore) GetCurrency(tx store.Tx, code string) (*domain.Currency, error) {
+	memTx := tx.(*memory.Tx)
+
+	v, ok := memTx.Get(s.enumMapping[enumNameCurrency], code)
+	if !ok {
+		return nil, errors.Generic(errors.ErrCodeGenericNotFound, "currency not found", "")
+	}
+	currency := v.(domain.Currency)
+
+	return &currency, nil
+}
+
+func (s *memoryEnumStore) Insert(tx store.Tx, name domain.EnumName, enum domain.Enum) error {
+	memTx := tx.(*memory.Tx)
+
+	tableName, ok := s.enumMapping[name]
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to insert enumeration")
+	}
+	if _, ok := memTx.Get(tableName, enum.GetID()); ok {
+		return errors.Generic(errors.ErrCodeGenericAlreadyExists, "unable to insert enum", "enum already exists")
+	}
+
+	memTx.Put(tableName, enum.GetID(), reflect.ValueOf(enum).Elem().Interface())
+
+	return nil
+}
+
+func (s *memoryEnumStore) Update(tx store.Tx, name domain.EnumName, enum domain.Enum) error {
+	memTx := tx.(*memory.Tx)
+
+	tableName, ok := s.enumMapping[name]
+	if !ok {
+		return errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to update enumeration")
+	}
+	if _, ok := memTx.Get(tableName, enum.GetID()); !ok {
+		return errors.Generic(errors.ErrCodeGenericNotFound, "unable to update enum", "enum not found")
+	}
+
+	memTx.Put(tableName, enum.GetID(), reflect.ValueOf(enum).Elem().Interface())
+
+	return nil
+}
+
+// memoryEnum returns a copy of the entry kept by value in the enumeration table.
+func memoryEnum(value interface{}) domain.Enum {
+	switch v := value.(type) {
+	case domain.Scheme:
+		return &v
+	case domain.Country:
+		return &v
+	case domain.Currency:
+		return &v
+	}
+	return nil
+}
+
+func memoryEnumName(enum domain.Enum) string {
+	switch e := enum.(type) {
+	case *domain.Scheme:
+		return e.Name
+	case *domain.Country:
+		return e.Name
+	case *domain.Currency:
+		return e.Name
+	}
+	return ""
+}
+
+// Seed populates the enumerations the same way the SQL migrations do.
+func (s *memoryEnumStore) Seed(tx store.Tx) error {
+	memTx := tx.(*memory.Tx)
+
+	for name, values := range memoryEnumValues {
+		tableName, ok := s.enumMapping[name]
+		if !ok {
+			return errors.Generic(errors.ErrCodeGenericInternal, "enum not found", "unable to seed enumeration")
+		}
+		for code, value := range values {
+			var enum interface{}
+			switch name {
+			case enumNameScheme:
+				enum = domain.Scheme{Code: code, Name: value, Active: true}
+			case enumNameCountry:
+				enum = domain.Country{Code: code, Name: value, Active: true}
+			}
+			memTx.Put(tableName, code, enum)
+		}
+	}
+	for _, currency := range memoryCurrencies {
+		currency.Active = true
+		memTx.Put(s.enumMapping[enumNameCurrency], currency.Code, currency)
+	}
+
+	return nil
+}
+
+var memoryEnumValues = map[domain.EnumName]map[string]string{
+	enumNameScheme: {
+		"SWIFT": "Society for Worldwide Interbank Financial Telecommunication Payment Scheme",
+		"SEPA":  "Single Euro Payments Area Payment Scheme",
+	},
+	enumNameCountry: {
+		"AT": "Austria",
+		"BE": "Belgium",
+		"BG": "Bulgaria",
+		"HR": "Croatia",
+		"CY": "Cyprus",
+		"CZ": "Czech Republic",
+		"DK": "Denmark",
+		"EE": "Estonia",
+		"FI": "Finland",
+		"FR": "France",
+		"DE": "Germany",
+		"GR": "Greece",
+		"HU": "Hungary",
+		"IE": "Republic of Ireland",
+		"IT": "Italy",
+		"LV": "Latvia",
+		"LT": "Lithuania",
+		"LU": "Luxembourg",
+		"MT": "Malta",
+		"NL": "Netherlands",
+		"PL": "Poland",
+		"PT": "Portugal",
+		"RO": "Romania",
+		"SK": "Slovakia",
+		"SI": "Slovenia",
+		"ES": "Spain",
+		"SE": "Sweden",
+		"GB": "United Kingdom",
+	},
+}
+
+var memoryCurrencies = []domain.Currency{
+	{Code: "BGN", Name: "Bulgarian lev", MinorUnits: 2},
+	{Code: "CZK", Name: "Czech koruna", MinorUnits: 2},
+	{Code: "DKK", Name: "Danish krone", MinorUnits: 2},
+	{Code: "EUR", Name: "Euro", MinorUnits: 2},
+	{Code: "GBP", Name: "Pound sterling", MinorUnits: 2},
+	{Code: "HRK", Name: "Croatian kuna", MinorUnits: 2},
+	{Code: "HUF", Name: "Hungarian forint", MinorUnits: 0},
+	{Code: "PLN", Name: "Polish złoty", MinorUnits: 2},
+	{Code: "RON", Name: "Romanian leu", MinorUnits: 2},
+	{Code: "SEK", Name: "Swedish krona", MinorUnits: 2},
+}
+
+func newMemoryCalendarStore() calendarStore {
+	return &memoryCalendarStore{}
+}
+
+// memoryCalendarStore serves the calendars seeded by the migrations of the
+// SQL stores, they are never changed so they are not kept in the database.
+type memoryCalendarStore struct{}
+
+func (s *memoryCalendarStore) Get(_ store.Tx, scheme string) (*domain.Calendar, error) {
+	c, ok := memoryCalendars[scheme]
+	if !ok {
+		return nil, errors.Generic(errors.ErrCodeGenericNotFound, "unable to get calendar", "calendar not found")
+	}
+
+	calendar, err := domain.NewCalendar(scheme, c.holidayCalendar, c.timeZone, c.cutOff)
+	if err != nil {
+		return nil, err
+	}
+	for date, name := range memoryHolidays[c.holidayCalendar] {
+		calendar.Holidays[domain.MustDateFrom(date)] = name
+	}
+
+	return calendar, nil
+}
+
+var memoryCalendars = map[string]struct{ holidayCalendar, timeZone, cutOff string }{
+	"SEPA":  {holidayCalendar: "TARGET2", timeZone: "Europe/Berlin", cutOff: "16:00"},
+	"SWIFT": {holidayCalendar: "GB", timeZone: "Europe/London", cutOff: "14:00"},
+}
+
+var memoryHolidays = map[string]map[string]string{
+	"TARGET2": {
+		"2019-01-01": "New Year's Day",
+		"2019-04-19": "Good Friday",
+		"2019-04-22": "Easter Monday",
+		"2019-05-01": "Labour Day",
+		"2019-12-25": "Christmas Day",
+		"2019-12-26": "Christmas Holiday",
+		"2020-01-01": "New Year's Day",
+		"2020-04-10": "Good Friday",
+		"2020-04-13": "Easter Monday",
+		"2020-05-01": "Labour Day",
+		"2020-12-25": "Christmas Day",
+		"2020-12-26": "Christmas Holiday",
+		"2021-01-01": "New Year's Day",
+		"2021-04-02": "Good Friday",
+		"2021-04-05": "Easter Monday",
+		"2021-05-01": "Labour Day",
+		"2021-12-25": "Christmas Day",
+		"2021-12-26": "Christmas Holiday",
+	},
+	"GB": {
+		"2019-01-01": "New Year's Day",
+		"2019-04-19": "Good Friday",
+		"2019-04-22": "Easter Monday",
+		"2019-05-06": "Early May bank holiday",
+		"2019-05-27": "Spring bank holiday",
+		"2019-08-26": "Summer bank holiday",
+		"2019-12-25": "Christmas Day",
+		"2019-12-26": "Boxing Day",
+		"2020-01-01": "New Year's Day",
+		"2020-04-10": "Good Friday",
+		"2020-04-13": "Easter Monday",
+		"2020-05-08": "Early May bank holiday",
+		"2020-05-25": "Spring bank holiday",
+		"2020-08-31": "Summer bank holiday",
+		"2020-12-25": "Christmas Day",
+		"2020-12-28": "Boxing Day",
+		"2021-01-01": "New Year's Day",
+		"2021-04-02": "Good Friday",
+		"2021-04-05": "Easter Monday",
+		"2021-05-03": "Early May bank holiday",
+		"2021-05-31": "Spring bank holiday",
+		"2021-08-30": "Summer bank holiday",
+		"2021-12-27": "Christmas Day",
+		"2021-12-28": "Boxing Day",
+	},
+}
@@ -0,0 +1,341 @@
+package payments
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+// paymentRule checks a single aspect of a payment and reports all the
+// violations found. An error is returned only if the check itself failed.
+type paymentRule func(tx store.Tx, payment *domain.Payment) ([]errors.Error, error)
+
+// paymentRules is the payment validation pipeline. The common rules apply to
+// every payment, the scheme rules only to the payments of the given scheme.
+// The schedule rules check the requested execution date, they apply only when
+// the date is requested, as a date which has passed since does not invalidate
+// the payment.
+type paymentRules struct {
+	common   []paymentRule
+	schemes  map[string][]paymentRule
+	schedule []paymentRule
+}
+
+func newPaymentRules(enumStore enumStore, clock func() time.Time) *paymentRules {
+	r := &paymentRules{schemes: make(map[string][]paymentRule)}
+
+	r.RegisterCommon(
+		enumRule(enumStore, enumNameScheme, "scheme", func(p *domain.Payment) string { return p.Scheme }),
+		enumRule(enumStore, enumNameCurrency, "amount/currency", func(p *domain.Payment) string { return p.Amount.Currency }),
+		amountRule(enumStore),
+		enumRule(enumStore, enumNameCountry, "debtor/address/country_code", func(p *domain.Payment) string { return p.Debtor.Address.CountryCode }),
+		enumRule(enumStore, enumNameCountry, "creditor/address/country_code", func(p *domain.Payment) string { return p.Creditor.Address.CountryCode }),
+		referenceRule,
+	)
+	r.RegisterSchedule(
+		executionDateRule(clock),
+	)
+	r.Register(domain.PaymentSchemeSEPA,
+		currencyRule("EUR"),
+		eeaCountryRule,
+		ibanRule(enumStore),
+		bicRule(debtorParty, false),
+		bicRule(creditorParty, false),
+		referenceLimitsRule(sepaReferenceLimits),
+		purposeCodeRule,
+	)
+	r.Register(domain.PaymentSchemeSWIFT,
+		bicRule(debtorParty, true),
+		bicRule(creditorParty, true),
+		referenceLimitsRule(swiftReferenceLimits),
+	)
+
+	return r
+}
+
+func (r *paymentRules) RegisterCommon(rules ...paymentRule) {
+	r.common = append(r.common, rules...)
+}
+
+func (r *paymentRules) Register(scheme string, rules ...paymentRule) {
+	r.schemes[scheme] = append(r.schemes[scheme], rules...)
+}
+
+func (r *paymentRules) RegisterSchedule(rules ...paymentRule) {
+	r.schedule = append(r.schedule, rules...)
+}
+
+// Validate runs all the rules applicable to the payment and returns all the
+// violations at once.
+func (r *paymentRules) Validate(tx store.Tx, payment *domain.Payment) error {
+	return r.validate(tx, payment, r.common, r.schemes[payment.Scheme], r.schedule)
+}
+
+// ValidateScheduled runs the rules applicable to the payment whose execution
+// date has been requested already, i.e. all but the schedule rules.
+func (r *paymentRules) ValidateScheduled(tx store.Tx, payment *domain.Payment) error {
+	return r.validate(tx, payment, r.common, r.schemes[payment.Scheme])
+}
+
+func (r *paymentRules) validate(tx store.Tx, payment *domain.Payment, pipeline ...[]paymentRule) error {
+	var violations errors.Multi
+	for _, rules := range pipeline {
+		for _, rule := range rules {
+			found, err := rule(tx, payment)
+			if err != nil {
+				return err
+			}
+			violations = append(violations, found...)
+		}
+	}
+	return violations.Err()
+}
+
+func violation(path, detail string) errors.Error {
+	return domain.PaymentViolation(domain.PaymentPointer(path), detail)
+}
+
+// paymentParty selects a party of the payment along with its path within
+// the payment document.
+type paymentParty struct {
+	path string
+	get  func(*domain.Payment) domain.PaymentParty
+}
+
+var (
+	debtorParty   = paymentParty{"debtor", func(p *domain.Payment) domain.PaymentParty { return p.Debtor }}
+	creditorParty = paymentParty{"creditor", func(p *domain.Payment) domain.PaymentParty { return p.Creditor }}
+)
+
+func enumRule(enumStore enumStore, name domain.EnumName, path string, value func(*domain.Payment) string) paymentRule {
+	return func(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+		code := value(payment)
+		ok, err := enumStore.Exists(tx, name, code)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return []errors.Error{violation(path, fmt.Sprintf("%s %q is not supported", strings.ToLower(string(name)), code))}, nil
+		}
+		return nil, nil
+	}
+}
+
+// amountRule checks the amount against the minor units and the limits of its
+// currency, an unsupported currency is reported by the enum rule already.
+func amountRule(enumStore enumStore) paymentRule {
+	return func(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+		currency, err := enumStore.GetCurrency(tx, payment.Amount.Currency)
+		if errors.Is(err, errors.ErrCodeGenericNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		err = currency.ValidateAmount(payment.Amount.Value)
+		if err != nil {
+			detail := err.Error()
+			if e, ok := err.(errors.Error); ok {
+				detail = e.Detail
+			}
+			return []errors.Error{violation("amount/value", detail)}, nil
+		}
+		return nil, nil
+	}
+}
+
+// executionDateRule checks the requested execution date not to be in the
+// past, the dates are compared in UTC.
+func executionDateRule(clock func() time.Time) paymentRule {
+	return func(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+		date := payment.RequestedExecutionDate
+		if date != nil && date.Before(domain.DateOf(clock().UTC())) {
+			return []errors.Error{violation("requested_execution_date", "requested execution date must not be in the past")}, nil
+		}
+		return nil, nil
+	}
+}
+
+func currencyRule(currencies ...string) paymentRule {
+	return func(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+		for _, c := range currencies {
+			if payment.Amount.Currency == c {
+				return nil, nil
+			}
+		}
+		return []errors.Error{violation("amount/currency", fmt.Sprintf(
+			"scheme %s supports only %s currency", payment.Scheme, strings.Join(currencies, ", "),
+		))}, nil
+	}
+}
+
+// eeaCountries are the countries of the European Economic Area.
+var eeaCountries = map[string]bool{
+	"AT": true, "BE": true, "BG": true, "HR": true, "CY": true, "CZ": true, "DK": true, "EE": true,
+	"FI": true, "FR": true, "DE": true, "GR": true, "HU": true, "IE": true, "IT": true, "LV": true,
+	"LT": true, "LU": true, "MT": true, "NL": true, "PL": true, "PT": true, "RO": true, "SK": true,
+	"SI": true, "ES": true, "SE": true, "IS": true, "LI": true, "NO": true,
+}
+
+func eeaCountryRule(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+	var violations []errors.Error
+	for _, party := range []paymentParty{debtorParty, creditorParty} {
+		if country := party.get(payment).Address.CountryCode; !eeaCountries[country] {
+			violations = append(violations, violation(party.path+"/address/country_code", fmt.Sprintf(
+				"scheme %s supports only EEA countries, %q is not one of them", payment.Scheme, country,
+			)))
+		}
+	}
+	return violations, nil
+}
+
+// ibanRule checks that both parties are identified by IBAN, and that the IBAN
+// country is the country of the party address or at least a supported country.
+func ibanRule(enumStore enumStore) paymentRule {
+	return func(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+		var violations []errors.Error
+		for _, party := range []paymentParty{debtorParty, creditorParty} {
+			path, p := party.path+"/account_number", party.get(payment)
+			err := domain.ValidateIBAN(p.AccountNumber)
+			if err != nil {
+				violations = append(violations, violation(path, err.Error()))
+				continue
+			}
+			country := domain.IBANCountryCode(p.AccountNumber)
+			if country == p.Address.CountryCode {
+				continue
+			}
+			ok, err := enumStore.Exists(tx, enumNameCountry, country)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				violations = append(violations, violation(path, fmt.Sprintf("IBAN country %s is not supported", country)))
+			}
+		}
+		return violations, nil
+	}
+}
+
+// bicRule checks the account provider code of the party to be a BIC, the
+// code may be left out unless it is required.
+func bicRule(party paymentParty, required bool) paymentRule {
+	return func(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+		code := party.get(payment).AccountProvider.Code
+		if code == "" && !required {
+			return nil, nil
+		}
+		err := domain.ValidateBIC(code)
+		if err != nil {
+			return []errors.Error{violation(party.path+"/account_provider/code", err.Error())}, nil
+		}
+		return nil, nil
+	}
+}
+
+// referenceRule checks the format of the references and of the remittance
+// information, their lengths are limited by the schemes.
+func referenceRule(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+	checks := []struct {
+		path     string
+		value    string
+		validate func(string) error
+	}{
+		{"end_to_end_reference", payment.EndToEndReference, domain.ValidateReference},
+		{"numeric_reference", payment.NumericReference, domain.ValidateNumericReference},
+		{"payment_purpose", payment.PaymentPurpose, domain.ValidateLatinText},
+		{"remittance_information/unstructured", payment.RemittanceInformation.Unstructured, domain.ValidateLatinText},
+		{"remittance_information/structured", payment.RemittanceInformation.Structured, domain.ValidateCreditorReference},
+	}
+	var violations []errors.Error
+	for _, c := range checks {
+		if c.value == "" {
+			continue
+		}
+		if err := c.validate(c.value); err != nil {
+			violations = append(violations, violation(c.path, err.Error()))
+		}
+	}
+	if ri := payment.RemittanceInformation; ri.Unstructured != "" && ri.Structured != "" {
+		violations = append(violations, violation("remittance_information",
+			"remittance information must be either structured or unstructured, not both"))
+	}
+	return violations, nil
+}
+
+// referenceLimits are the maximum lengths of the references and of the
+// remittance information the messages of a scheme can carry, a zero length
+// is not checked.
+type referenceLimits struct {
+	endToEndReference      int
+	numericReference       int
+	paymentPurpose         int
+	unstructuredRemittance int
+	structuredRemittance   bool
+}
+
+var (
+	// sepaReferenceLimits follow the SEPA credit transfer rulebook, the
+	// purpose is checked to be a purpose code instead.
+	sepaReferenceLimits = referenceLimits{
+		endToEndReference:      35,
+		numericReference:       35,
+		unstructuredRemittance: 140,
+		structuredRemittance:   true,
+	}
+	// swiftReferenceLimits follow the MT103 fields, the references go into
+	// the 16 character field 20 and the remittance into 4 lines of field 70.
+	swiftReferenceLimits = referenceLimits{
+		endToEndReference:      16,
+		numericReference:       16,
+		paymentPurpose:         35,
+		unstructuredRemittance: 140,
+	}
+)
+
+func referenceLimitsRule(limits referenceLimits) paymentRule {
+	return func(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+		checks := []struct {
+			path  string
+			value string
+			max   int
+		}{
+			{"end_to_end_reference", payment.EndToEndReference, limits.endToEndReference},
+			{"numeric_reference", payment.NumericReference, limits.numericReference},
+			{"payment_purpose", payment.PaymentPurpose, limits.paymentPurpose},
+			{"remittance_information/unstructured", payment.RemittanceInformation.Unstructured, limits.unstructuredRemittance},
+		}
+		var violations []errors.Error
+		for _, c := range checks {
+			if c.max > 0 && len(c.value) > c.max {
+				violations = append(violations, violation(c.path, fmt.Sprintf(
+					"scheme %s allows at most %d characters", payment.Scheme, c.max,
+				)))
+			}
+		}
+		if payment.RemittanceInformation.Structured != "" && !limits.structuredRemittance {
+			violations = append(violations, violation("remittance_information/structured", fmt.Sprintf(
+				"scheme %s supports only unstructured remittance information", payment.Scheme,
+			)))
+		}
+		return violations, nil
+	}
+}
+
+var purposeCodePattern = regexp.MustCompile(`^[A-Z]{4}$`)
+
+// purposeCodeRule checks the payment purpose to be an ISO 20022 purpose code,
+// e.g. SALA for salaries.
+func purposeCodeRule(tx store.Tx, payment *domain.Payment) ([]errors.Error, error) {
+	if payment.PaymentPurpose != "" && !purposeCodePattern.MatchString(payment.PaymentPurpose) {
+		return []errors.Error{violation("payment_purpose", fmt.Sprintf(
+			"scheme %s requires a 4 letter ISO 20022 purpose code", payment.Scheme,
+		))}, nil
+	}
+	return nil, nil
+}
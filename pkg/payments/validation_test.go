@@ -0,0 +1,267 @@
+package payments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/mock"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+func TestPaymentRules_Validate(t *testing.T) {
+	sepa := func(f func(*domain.Payment)) *domain.Payment {
+		p := &domain.Payment{
+			Scheme: domain.PaymentSchemeSEPA,
+			Amount: domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+			Debtor: domain.PaymentParty{
+				AccountNumber:   "DE89370400440532013000",
+				AccountProvider: domain.AccountProvider{Code: "DEUTDEFF"},
+				Address:         domain.Address{CountryCode: "DE"},
+			},
+			Creditor: domain.PaymentParty{
+				AccountNumber: "SK3112000000198742637541",
+				Address:       domain.Address{CountryCode: "SK"},
+			},
+		}
+		if f != nil {
+			f(p)
+		}
+		return p
+	}
+	swift := func(f func(*domain.Payment)) *domain.Payment {
+		p := &domain.Payment{
+			Scheme:   domain.PaymentSchemeSWIFT,
+			Amount:   domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "USD"},
+			Debtor:   domain.PaymentParty{AccountNumber: "0123456789", AccountProvider: domain.AccountProvider{Code: "BARCGB22"}, Address: domain.Address{CountryCode: "US"}},
+			Creditor: domain.PaymentParty{AccountNumber: "9876543210", AccountProvider: domain.AccountProvider{Code: "NWBKGB2L"}},
+		}
+		if f != nil {
+			f(p)
+		}
+		return p
+	}
+
+	testCases := []struct {
+		name     string
+		register func(*paymentRules)
+		in       *domain.Payment
+		pointers []string
+	}{
+		{
+			name: "Valid SEPA payment",
+			in:   sepa(nil),
+		},
+		{
+			name:     "SEPA payment in non-EUR currency",
+			in:       sepa(func(p *domain.Payment) { p.Amount.Currency = "USD" }),
+			pointers: []string{"/data/attributes/amount/currency"},
+		},
+		{
+			name:     "SEPA payment to non-EEA country",
+			in:       sepa(func(p *domain.Payment) { p.Creditor.Address.CountryCode = "US" }),
+			pointers: []string{"/data/attributes/creditor/address/country_code"},
+		},
+		{
+			name:     "SEPA payment with invalid IBAN",
+			in:       sepa(func(p *domain.Payment) { p.Debtor.AccountNumber = "DE89370400440532013001" }),
+			pointers: []string{"/data/attributes/debtor/account_number"},
+		},
+		{
+			name:     "SEPA payment with invalid BIC",
+			in:       sepa(func(p *domain.Payment) { p.Debtor.AccountProvider.Code = "DEUT" }),
+			pointers: []string{"/data/attributes/debtor/account_provider/code"},
+		},
+		{
+			name: "SEPA payment with multiple violations",
+			in: sepa(func(p *domain.Payment) {
+				p.Amount.Currency = "GBP"
+				p.Debtor.Address.CountryCode = "GB"
+				p.Creditor.AccountNumber = "SK3112000000198742637542"
+			}),
+			pointers: []string{
+				"/data/attributes/amount/currency",
+				"/data/attributes/debtor/address/country_code",
+				"/data/attributes/creditor/account_number",
+			},
+		},
+		{
+			name:     "SEPA payment with zero amount",
+			in:       sepa(func(p *domain.Payment) { p.Amount.Value = domain.MustDecimalFrom("0.00") }),
+			pointers: []string{"/data/attributes/amount/value"},
+		},
+		{
+			name:     "SEPA payment with too many fraction digits",
+			in:       sepa(func(p *domain.Payment) { p.Amount.Value = domain.MustDecimalFrom("100.123") }),
+			pointers: []string{"/data/attributes/amount/value"},
+		},
+		{
+			name:     "SEPA payment above the maximum amount",
+			in:       sepa(func(p *domain.Payment) { p.Amount.Value = domain.MustDecimalFrom("1000000.01") }),
+			pointers: []string{"/data/attributes/amount/value"},
+		},
+		{
+			name: "SWIFT payment with fractional amount in currency without minor units",
+			in: swift(func(p *domain.Payment) {
+				p.Amount = domain.Monetary{Value: domain.MustDecimalFrom("100.5"), Currency: "HUF"}
+			}),
+			pointers: []string{"/data/attributes/amount/value"},
+		},
+		{
+			name:     "SWIFT payment with negative amount",
+			in:       swift(func(p *domain.Payment) { p.Amount.Value = domain.MustDecimalFrom("-100.00") }),
+			pointers: []string{"/data/attributes/amount/value"},
+		},
+		{
+			name: "Valid SWIFT payment",
+			in:   swift(nil),
+		},
+		{
+			name:     "SWIFT payment without creditor BIC",
+			in:       swift(func(p *domain.Payment) { p.Creditor.AccountProvider.Code = "" }),
+			pointers: []string{"/data/attributes/creditor/account_provider/code"},
+		},
+		{
+			name:     "SWIFT payment without debtor BIC",
+			in:       swift(func(p *domain.Payment) { p.Debtor.AccountProvider.Code = "" }),
+			pointers: []string{"/data/attributes/debtor/account_provider/code"},
+		},
+		{
+			name:     "SWIFT payment with invalid debtor BIC",
+			in:       swift(func(p *domain.Payment) { p.Debtor.AccountProvider.Code = "invalid" }),
+			pointers: []string{"/data/attributes/debtor/account_provider/code"},
+		},
+		{
+			name: "Payment requested to be executed today",
+			in: sepa(func(p *domain.Payment) {
+				date := domain.MustDateFrom("2019-06-12")
+				p.RequestedExecutionDate = &date
+			}),
+		},
+		{
+			name: "Payment requested to be executed in the past",
+			in: sepa(func(p *domain.Payment) {
+				date := domain.MustDateFrom("2019-06-11")
+				p.RequestedExecutionDate = &date
+			}),
+			pointers: []string{"/data/attributes/requested_execution_date"},
+		},
+		{
+			name: "SEPA payment with references",
+			in: sepa(func(p *domain.Payment) {
+				p.EndToEndReference = "INV-2019/0042"
+				p.NumericReference = "1002001"
+				p.PaymentPurpose = "SUPP"
+				p.RemittanceInformation.Structured = "RF18539007547034"
+			}),
+		},
+		{
+			name: "SEPA payment with invalid references",
+			in: sepa(func(p *domain.Payment) {
+				p.EndToEndReference = "/INV-2019/0042"
+				p.NumericReference = "100-2001"
+				p.PaymentPurpose = "Supplies"
+				p.RemittanceInformation.Unstructured = "Invoice 42 – June"
+				p.RemittanceInformation.Structured = "RF19539007547034"
+			}),
+			pointers: []string{
+				"/data/attributes/end_to_end_reference",
+				"/data/attributes/numeric_reference",
+				"/data/attributes/remittance_information/unstructured",
+				"/data/attributes/remittance_information/structured",
+				"/data/attributes/remittance_information",
+				"/data/attributes/payment_purpose",
+			},
+		},
+		{
+			name: "SEPA payment with too long remittance information",
+			in: sepa(func(p *domain.Payment) {
+				p.EndToEndReference = strings.Repeat("E", 36)
+				p.RemittanceInformation.Unstructured = strings.Repeat("Invoice 42 ", 13)
+			}),
+			pointers: []string{
+				"/data/attributes/end_to_end_reference",
+				"/data/attributes/remittance_information/unstructured",
+			},
+		},
+		{
+			name: "SWIFT payment with references",
+			in: swift(func(p *domain.Payment) {
+				p.EndToEndReference = "INV-2019/0042"
+				p.PaymentPurpose = "Supplies for June"
+				p.RemittanceInformation.Unstructured = "Invoice 42"
+			}),
+		},
+		{
+			name: "SWIFT payment exceeding reference limits",
+			in: swift(func(p *domain.Payment) {
+				p.EndToEndReference = "INV-2019/0042/JUNE"
+				p.NumericReference = strings.Repeat("1", 17)
+				p.RemittanceInformation.Structured = "RF18539007547034"
+			}),
+			pointers: []string{
+				"/data/attributes/end_to_end_reference",
+				"/data/attributes/numeric_reference",
+				"/data/attributes/remittance_information/structured",
+			},
+		},
+		{
+			name: "Registered scheme rules",
+			register: func(r *paymentRules) {
+				r.Register("FPS", currencyRule("GBP"))
+			},
+			in: &domain.Payment{
+				Scheme: "FPS",
+				Amount: domain.Monetary{Value: domain.MustDecimalFrom("100.00"), Currency: "EUR"},
+			},
+			pointers: []string{"/data/attributes/amount/currency"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := newPaymentRules(&mock.EnumStore{
+				ExistsFn: func(store.Tx, domain.EnumName, string) (bool, error) { return true, nil },
+				GetCurrencyFn: func(_ store.Tx, code string) (*domain.Currency, error) {
+					switch code {
+					case "HUF":
+						return &domain.Currency{Code: code}, nil
+					case "EUR":
+						max := domain.MustDecimalFrom("1000000")
+						return &domain.Currency{Code: code, MinorUnits: 2, MaxAmount: &max}, nil
+					}
+					return &domain.Currency{Code: code, MinorUnits: 2}, nil
+				},
+			}, testClock)
+			if tc.register != nil {
+				tc.register(rules)
+			}
+
+			err := rules.Validate(nil, tc.in)
+			if len(tc.pointers) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, errors.ErrCodeGenericInvalidArgument) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if want, have := tc.pointers, violationPointers(err); !cmp.Equal(want, have) {
+				t.Fatalf("unexpected violations: %v", cmp.Diff(want, have))
+			}
+		})
+	}
+}
+
+func violationPointers(err error) []string {
+	var pointers []string
+	for _, v := range err.(errors.Multi) {
+		pointers = append(pointers, v.Extra[errors.ExtraPointer].(string))
+	}
+	return pointers
+}
@@ -0,0 +1,240 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/manyminds/api2go"
+	"github.com/manyminds/api2go/routing"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+)
+
+type webhookService interface {
+	Subscriptions(context.Context) ([]*domain.WebhookSubscription, error)
+	LoadSubscription(context.Context, domain.ID) (*domain.WebhookSubscription, error)
+	Subscribe(context.Context, *domain.WebhookSubscription) error
+	UpdateSubscription(context.Context, *domain.WebhookSubscription) error
+	Unsubscribe(context.Context, domain.ID) error
+	Deliveries(context.Context, domain.WebhookDeliverySearchRequest) ([]*domain.WebhookDelivery, uint, error)
+	LoadDelivery(context.Context, domain.ID) (*domain.WebhookDelivery, error)
+	Redeliver(context.Context, domain.ID) (*domain.WebhookDelivery, error)
+}
+
+// webhookSubscriptionResource exposes the endpoints the payment events are
+// pushed to, which can be managed by admins only.
+type webhookSubscriptionResource struct {
+	service webhookService
+}
+
+func newWebhookSubscriptionResource(service webhookService) webhookSubscriptionResource {
+	return webhookSubscriptionResource{service: service}
+}
+
+func (r webhookSubscriptionResource) FindOne(oid string, req api2go.Request) (api2go.Responder, error) {
+	id, err := domain.IDFrom(oid)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	subscription, err := r.service.LoadSubscription(req.PlainRequest.Context(), id)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	return resource.WrapObject(subscription, http.StatusOK), nil
+}
+
+func (r webhookSubscriptionResource) FindAll(req api2go.Request) (api2go.Responder, error) {
+	subscriptions, err := r.service.Subscriptions(req.PlainRequest.Context())
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	return resource.WrapArray(subscriptions, http.StatusOK), nil
+}
+
+func (r webhookSubscriptionResource) Create(obj interface{}, req api2go.Request) (api2go.Responder, error) {
+	subscription := obj.(*domain.WebhookSubscription)
+
+	err := subscription.Validate()
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	err = r.service.Subscribe(req.PlainRequest.Context(), subscription)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	return resource.WrapObject(subscription, http.StatusCreated), nil
+}
+
+func (r webhookSubscriptionResource) Update(obj interface{}, req api2go.Request) (api2go.Responder, error) {
+	subscription := obj.(*domain.WebhookSubscription)
+
+	err := subscription.Validate()
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	err = r.service.UpdateSubscription(req.PlainRequest.Context(), subscription)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	return resource.WrapObject(subscription, http.StatusOK), nil
+}
+
+func (r webhookSubscriptionResource) Delete(oid string, req api2go.Request) (api2go.Responder, error) {
+	id, err := domain.IDFrom(oid)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	err = r.service.Unsubscribe(req.PlainRequest.Context(), id)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	return resource.WrapObject(nil, http.StatusNoContent), nil
+}
+
+// webhookDeliveryResource exposes the log of the webhook deliveries, it is
+// read-only apart from the manual redelivery.
+type webhookDeliveryResource struct {
+	*resource.Generic
+	service webhookService
+}
+
+func newWebhookDeliveryResource(service webhookService) webhookDeliveryResource {
+	return webhookDeliveryResource{
+		Generic: &resource.Generic{ParamFunc: webhookDeliveryParamFunc},
+		service: service,
+	}
+}
+
+func (r webhookDeliveryResource) FindOne(oid string, req api2go.Request) (api2go.Responder, error) {
+	id, err := domain.IDFrom(oid)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	delivery, err := r.service.LoadDelivery(req.PlainRequest.Context(), id)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	return resource.WrapObject(delivery, http.StatusOK), nil
+}
+
+func (r webhookDeliveryResource) FindAll(req api2go.Request) (api2go.Responder, error) {
+	filter, err := r.ExtractSearchFilter(req.QueryParams)
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	deliveries, _, err := r.service.Deliveries(req.PlainRequest.Context(), domain.WebhookDeliverySearchRequest{
+		SearchFilter: filter,
+	})
+	if err != nil {
+		return nil, resource.WrapError(err)
+	}
+
+	return resource.WrapArray(deliveries, http.StatusOK), nil
+}
+
+func (r webhookDeliveryResource) PaginatedFindAll(req api2go.Request) (uint, api2go.Responder, error) {
+	filter, err := r.ExtractSearchFilter(req.QueryParams)
+	if err != nil {
+		return 0, nil, err
+	}
+	pagination, err := r.ExtractPagination(req.Pagination)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	deliveries, size, err := r.service.Deliveries(req.PlainRequest.Context(), domain.WebhookDeliverySearchRequest{
+		SearchFilter:     filter,
+		SearchPagination: pagination,
+	})
+	if err != nil {
+		return 0, nil, resource.WrapError(err)
+	}
+
+	return size, resource.WrapArray(deliveries, http.StatusOK), nil
+}
+
+func (r webhookDeliveryResource) redeliverHandler() routing.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, params map[string]string, _ map[string]interface{}) {
+		id, err := domain.IDFrom(params["id"])
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		delivery, err := r.service.Redeliver(req.Context(), id)
+		if err != nil {
+			resource.WriteError(w, jsonApiContentType, err)
+			return
+		}
+
+		resource.WriteObject(w, jsonApiContentType, delivery, http.StatusOK)
+	}
+}
+
+func webhookDeliveryParamFunc(key string, op resource.FilterOperator, values []string) (interface{}, error) {
+	switch {
+	case (key == "subscription_id" || key == "payment_id") && op == resource.FilterOperatorEq:
+		var ids []domain.ID
+		for i, s := range values {
+			id, err := domain.IDFrom(s)
+			if err != nil {
+				return nil, errors.Generic(
+					errors.ErrCodeGenericInvalidArgument,
+					err.Error(),
+					fmt.Sprintf("field %q: index %d: %q has not valid format", key, i, s),
+				)
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	case key == "event_type" && op == resource.FilterOperatorEq:
+		var types []domain.PaymentEventType
+		for i, s := range values {
+			t := domain.PaymentEventType(s)
+			if !t.IsValid() {
+				return nil, errors.Generic(
+					errors.ErrCodeGenericInvalidArgument,
+					"invalid filter parameter",
+					fmt.Sprintf("field %q: index %d: %q is not an event type", key, i, s),
+				)
+			}
+			types = append(types, t)
+		}
+		return types, nil
+	case key == "status" && op == resource.FilterOperatorEq:
+		var statuses []domain.WebhookDeliveryStatus
+		for i, s := range values {
+			status := domain.WebhookDeliveryStatus(s)
+			if !status.IsValid() {
+				return nil, errors.Generic(
+					errors.ErrCodeGenericInvalidArgument,
+					"invalid filter parameter",
+					fmt.Sprintf("field %q: index %d: %q is not a delivery status", key, i, s),
+				)
+			}
+			statuses = append(statuses, status)
+		}
+		return statuses, nil
+	default:
+		return nil, errors.Generic(
+			errors.ErrCodeGenericInvalidArgument,
+			"unsupported filter parameter",
+			resource.FilterKey(key, op),
+		)
+	}
+}
@@ -0,0 +1,365 @@
+package payments
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/domain"
+	"github.com/michaljemala/payments-sample/pkg/events"
+	"github.com/michaljemala/payments-sample/pkg/internal/auth"
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+	"github.com/michaljemala/payments-sample/pkg/internal/resource"
+	"github.com/michaljemala/payments-sample/pkg/internal/service"
+	"github.com/michaljemala/payments-sample/pkg/internal/store"
+)
+
+const webhookSecretSize = 32
+
+type defaultWebhookService struct {
+	*service.Generic
+
+	subscriptionStore webhookSubscriptionStore
+	deliveryStore     webhookDeliveryStore
+	sender            *events.Webhook
+	timeout           time.Duration
+	clock             func() time.Time
+}
+
+func newWebhookService(
+	txManager store.TxManager,
+	subscriptionStore webhookSubscriptionStore,
+	deliveryStore webhookDeliveryStore,
+	sender *events.Webhook,
+	timeout time.Duration,
+	clock func() time.Time,
+) *defaultWebhookService {
+	return &defaultWebhookService{
+		Generic:           &service.Generic{TxManager: txManager},
+		subscriptionStore: subscriptionStore,
+		deliveryStore:     deliveryStore,
+		sender:            sender,
+		timeout:           timeout,
+		clock:             clock,
+	}
+}
+
+func (s *defaultWebhookService) Subscriptions(ctx context.Context) (subscriptions []*domain.WebhookSubscription, err error) {
+	err = authorizeWebhooks(ctx, "unable to search webhook subscriptions")
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		subscriptions, err = s.subscriptionStore.Find(tx)
+		return err
+	})
+	for _, subscription := range subscriptions {
+		subscription.Secret = ""
+	}
+	return subscriptions, err
+}
+
+func (s *defaultWebhookService) LoadSubscription(ctx context.Context, id domain.ID) (subscription *domain.WebhookSubscription, err error) {
+	err = authorizeWebhooks(ctx, "unable to load webhook subscription")
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		subscription, err = s.subscriptionStore.Get(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+// Subscribe creates an active subscription. Unless the secret is given, a
+// random one is generated, it is the only time the secret is returned.
+func (s *defaultWebhookService) Subscribe(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	err := authorizeWebhooks(ctx, "unable to create webhook subscription")
+	if err != nil {
+		return err
+	}
+
+	if subscription.Secret == "" {
+		subscription.Secret, err = generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+	}
+	subscription.Active = true
+	subscription.CreatedAt = s.now()
+	subscription.UpdatedAt = subscription.CreatedAt
+
+	return s.WithTransaction(ctx, func(tx store.Tx) error {
+		return s.subscriptionStore.Insert(tx, subscription)
+	})
+}
+
+// UpdateSubscription keeps the current secret unless a new one is given,
+// the secret is not returned in either case.
+func (s *defaultWebhookService) UpdateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	err := authorizeWebhooks(ctx, "unable to update webhook subscription")
+	if err != nil {
+		return err
+	}
+
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		current, err := s.subscriptionStore.Get(tx, subscription.ID)
+		if err != nil {
+			return err
+		}
+		if subscription.Secret == "" {
+			subscription.Secret = current.Secret
+		}
+		subscription.CreatedAt = current.CreatedAt
+		subscription.UpdatedAt = s.now()
+		return s.subscriptionStore.Update(tx, subscription)
+	})
+	subscription.Secret = ""
+	return err
+}
+
+// Unsubscribe deletes the subscription, the log of its deliveries is kept.
+// The deliveries still pending end as dead letters.
+func (s *defaultWebhookService) Unsubscribe(ctx context.Context, id domain.ID) error {
+	err := authorizeWebhooks(ctx, "unable to delete webhook subscription")
+	if err != nil {
+		return err
+	}
+
+	return s.WithTransaction(ctx, func(tx store.Tx) error {
+		err := s.subscriptionStore.Delete(tx, id, s.now())
+		if err != nil {
+			return err
+		}
+		pending, err := s.deliveryStore.Find(tx, domain.WebhookDeliverySearchRequest{
+			SearchFilter: resource.SearchFilter{
+				"subscription_id": []domain.ID{id},
+				"status":          []domain.WebhookDeliveryStatus{domain.WebhookDeliveryStatusPending},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		for _, delivery := range pending {
+			delivery.Status = domain.WebhookDeliveryStatusDeadLetter
+			delivery.NextAttemptAt = nil
+			delivery.LastError = "webhook subscription deleted"
+			err = s.deliveryStore.Update(tx, delivery)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *defaultWebhookService) Deliveries(ctx context.Context, searchReq domain.WebhookDeliverySearchRequest) (deliveries []*domain.WebhookDelivery, size uint, err error) {
+	err = authorizeWebhooks(ctx, "unable to search webhook deliveries")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		deliveries, err = s.deliveryStore.Find(tx, searchReq)
+		if err != nil {
+			return err
+		}
+		if searchReq.SearchPagination != nil {
+			size, err = s.deliveryStore.Count(tx, searchReq)
+		}
+		return err
+	})
+	return deliveries, size, err
+}
+
+func (s *defaultWebhookService) LoadDelivery(ctx context.Context, id domain.ID) (delivery *domain.WebhookDelivery, err error) {
+	err = authorizeWebhooks(ctx, "unable to load webhook delivery")
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		delivery, err = s.deliveryStore.Get(tx, id)
+		return err
+	})
+	return delivery, err
+}
+
+// Redeliver schedules the delivery to be pushed again right away with all the
+// attempts available, e.g. once the receiver of a dead letter is fixed.
+func (s *defaultWebhookService) Redeliver(ctx context.Context, id domain.ID) (delivery *domain.WebhookDelivery, err error) {
+	err = authorizeWebhooks(ctx, "unable to redeliver webhook")
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		delivery, err = s.deliveryStore.Get(tx, id)
+		if err != nil {
+			return err
+		}
+		_, err = s.subscriptionStore.Get(tx, delivery.SubscriptionID)
+		if errors.Is(err, errors.ErrCodeGenericNotFound) {
+			return errors.Generic(
+				errors.ErrCodeGenericInvalidState,
+				"unable to redeliver webhook",
+				"webhook subscription has been deleted",
+			)
+		}
+		if err != nil {
+			return err
+		}
+		delivery.Status = domain.WebhookDeliveryStatusPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		delivery.ResponseStatus = 0
+		delivery.DeliveredAt = nil
+		return s.deliveryStore.Update(tx, delivery)
+	})
+	return delivery, err
+}
+
+// Publish schedules the deliveries of the event to the matching active
+// subscriptions, it is the sink the relay publishes the events to. The relay
+// may publish an event again, so the deliveries scheduled already are kept.
+func (s *defaultWebhookService) Publish(ctx context.Context, event *domain.PaymentEvent) error {
+	return s.WithTransaction(ctx, func(tx store.Tx) error {
+		subscriptions, err := s.subscriptionStore.Find(tx)
+		if err != nil {
+			return err
+		}
+		for _, subscription := range subscriptions {
+			if !subscription.Matches(event.Type) {
+				continue
+			}
+			exists, err := s.deliveryStore.Exists(tx, subscription.ID, event.ID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			delivery, err := domain.NewWebhookDelivery(subscription, event, event.OccurredAt)
+			if err != nil {
+				return err
+			}
+			err = s.deliveryStore.Insert(tx, delivery)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeliverPending pushes a single pending delivery and reports whether there
+// was any. The failed delivery is retried according to the policy, once it
+// runs out of the attempts it ends up as a dead letter. The deliveries of a
+// payment to a subscription are pushed in order, a failed one holds up the
+// following ones until it is delivered or ends as a dead letter.
+//
+// The delivery is claimed in a transaction of its own, which counts the
+// attempt and schedules the next one past the time the request may take, so
+// no other worker picks the delivery meanwhile and no lock is held while
+// waiting for the receiver. The result is recorded in another transaction.
+func (s *defaultWebhookService) DeliverPending(ctx context.Context, policy retryPolicy) (bool, error) {
+	var (
+		delivery     *domain.WebhookDelivery
+		subscription *domain.WebhookSubscription
+	)
+	err := s.WithTransaction(ctx, func(tx store.Tx) (err error) {
+		now := s.now()
+		delivery, err = s.deliveryStore.ClaimPending(tx, now)
+		if err != nil {
+			return err
+		}
+		subscription, err = s.subscriptionStore.Get(tx, delivery.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		next := now.Add(s.timeout + policy.delay(delivery.Attempts))
+		delivery.Attempts++
+		delivery.NextAttemptAt = &next
+		return s.deliveryStore.Update(tx, delivery)
+	})
+	if errors.Is(err, errors.ErrCodeGenericNotFound) && delivery == nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	status, failure := s.sender.Send(sendCtx, events.WebhookRequest{
+		ID:        delivery.ID.String(),
+		EventType: string(delivery.EventType),
+		URL:       subscription.TargetURL,
+		Secret:    subscription.Secret,
+		Body:      delivery.Payload,
+	}, s.now())
+	cancel()
+
+	err = s.WithTransaction(ctx, func(tx store.Tx) error {
+		current, err := s.deliveryStore.Get(tx, delivery.ID)
+		if err != nil {
+			return err
+		}
+		// The delivery has been redelivered meanwhile, the attempt is no
+		// longer the one to be recorded.
+		if current.Status != domain.WebhookDeliveryStatusPending || current.Attempts != delivery.Attempts {
+			return nil
+		}
+
+		now := s.now()
+		delivery.ResponseStatus = status
+		switch {
+		case failure == nil:
+			delivery.Status = domain.WebhookDeliveryStatusDelivered
+			delivery.NextAttemptAt = nil
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+		case delivery.Attempts >= policy.maxAttempts:
+			delivery.Status = domain.WebhookDeliveryStatusDeadLetter
+			delivery.NextAttemptAt = nil
+			delivery.LastError = failure.Error()
+		default:
+			next := now.Add(policy.delay(delivery.Attempts - 1))
+			delivery.NextAttemptAt = &next
+			delivery.LastError = failure.Error()
+		}
+		return s.deliveryStore.Update(tx, delivery)
+	})
+	return true, err
+}
+
+func (s *defaultWebhookService) now() time.Time {
+	return s.clock().UTC().Truncate(time.Microsecond)
+}
+
+func authorizeWebhooks(ctx context.Context, msg string) error {
+	if !auth.FromContext(ctx).HasRole(auth.RoleAdmin) {
+		return errors.Generic(
+			errors.ErrCodeGenericPermissionDenied,
+			msg,
+			"only admins can manage webhooks",
+		)
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errors.Generic(errors.ErrCodeGenericInternal, "unable to generate webhook secret", err.Error())
+	}
+	return hex.EncodeToString(b), nil
+}
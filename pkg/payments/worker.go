@@ -0,0 +1,105 @@
+package payments
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"time"
+)
+
+// The actors recorded in the history of the payments changed by the workers.
+const (
+	schedulerActor  = "scheduler"
+	dispatcherActor = "dispatcher"
+)
+
+// retryPolicy limits the execution attempts of a single payment, the delay
+// between them doubles with every failed attempt up to the maximal backoff.
+// The relay does not limit the attempts.
+type retryPolicy struct {
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+// delay returns how long to wait after the given number of previously failed
+// attempts has failed once more.
+func (p retryPolicy) delay(attempts int) time.Duration {
+	d := p.backoff
+	for i := 0; i < attempts && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.maxBackoff {
+		d = p.maxBackoff
+	}
+	return d
+}
+
+// paymentTask processes a single payment, or a batch of the payment events,
+// in its own transaction, it reports whether there was anything processed.
+type paymentTask func(context.Context, retryPolicy) (bool, error)
+
+// Worker processes the payments in the background, e.g. executes the ones
+// whose requested execution date has come.
+type Worker struct {
+	name     string
+	task     paymentTask
+	policy   retryPolicy
+	interval time.Duration
+	logger   *log.Logger
+}
+
+func newWorker(name string, task paymentTask, policy retryPolicy, interval time.Duration, logger *log.Logger) *Worker {
+	if logger == nil {
+		logger = log.New(ioutil.Discard, "", 0)
+	}
+	return &Worker{
+		name:     name,
+		task:     task,
+		policy:   policy,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run polls for the pending payments until the context is cancelled. The
+// payment being processed at the time of the cancellation is finished first.
+func (w *Worker) Run(ctx context.Context) {
+	w.logger.Printf("%s starting up: polling every %v", w.name, w.interval)
+	for {
+		w.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			w.logger.Printf("%s shutting down: %v", w.name, ctx.Err())
+			return
+		case <-time.After(w.interval):
+		}
+	}
+}
+
+// poll processes the pending payments one by one and returns how many of
+// them were processed.
+func (w *Worker) poll(ctx context.Context) int {
+	var n int
+	for ctx.Err() == nil {
+		processed, err := w.process()
+		if err != nil {
+			w.logger.Printf("%s: unable to process: %v", w.name, err)
+			return n
+		}
+		if !processed {
+			return n
+		}
+		n++
+	}
+	return n
+}
+
+// process runs the task detached from the cancellation of the worker, so the
+// payment in progress is always finished. The tasks limit their calls to the
+// remote parties themselves, so a timeout does not roll back the transaction
+// recording the failed attempt.
+func (w *Worker) process() (bool, error) {
+	return w.task(context.Background(), w.policy)
+}
@@ -0,0 +1,79 @@
+package payments
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/michaljemala/payments-sample/pkg/internal/errors"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 10, backoff: time.Minute, maxBackoff: 5 * time.Minute}
+
+	tests := []struct {
+		attempts int
+		delay    time.Duration
+	}{
+		{attempts: 0, delay: time.Minute},
+		{attempts: 1, delay: 2 * time.Minute},
+		{attempts: 2, delay: 4 * time.Minute},
+		{attempts: 3, delay: 5 * time.Minute},
+		{attempts: 100, delay: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if want, have := tt.delay, policy.delay(tt.attempts); want != have {
+			t.Errorf("unexpected delay after %d attempts: want %v, have %v", tt.attempts, want, have)
+		}
+	}
+}
+
+func TestWorker_Poll(t *testing.T) {
+	tests := []struct {
+		name      string
+		results   []error
+		cancel    bool
+		processed int
+	}{
+		{
+			name:      "Execute until nothing is due",
+			results:   []error{nil, nil, nil},
+			processed: 3,
+		},
+		{
+			name:      "Stop on error",
+			results:   []error{nil, errors.Generic(errors.ErrCodeGenericInternal, "unable to execute", ""), nil},
+			processed: 1,
+		},
+		{
+			name:      "Stop on cancellation",
+			results:   []error{nil, nil, nil},
+			cancel:    true,
+			processed: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			results := tt.results
+			task := func(context.Context, retryPolicy) (bool, error) {
+				if tt.cancel {
+					cancel()
+				}
+				if len(results) == 0 {
+					return false, nil
+				}
+				err := results[0]
+				results = results[1:]
+				return err == nil, err
+			}
+
+			w := newWorker("test", task, retryPolicy{}, time.Second, nil)
+			if want, have := tt.processed, w.poll(ctx); want != have {
+				t.Errorf("unexpected processed payments: want %d, have %d", want, have)
+			}
+		})
+	}
+}
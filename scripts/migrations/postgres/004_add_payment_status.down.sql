@@ -0,0 +1,2 @@
+ALTER TABLE payment DROP COLUMN status;
+DROP TABLE enum_payment_status;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE enum_payment_status
+(
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+INSERT INTO enum_payment_status (code, name)
+VALUES ('DRAFT', 'Draft'),
+       ('PENDING_APPROVAL', 'Pending approval'),
+       ('SUBMITTED', 'Submitted'),
+       ('ACCEPTED', 'Accepted'),
+       ('SETTLED', 'Settled'),
+       ('REJECTED', 'Rejected'),
+       ('CANCELLED', 'Cancelled'),
+       ('RETURNED', 'Returned');
+
+ALTER TABLE payment
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'DRAFT' REFERENCES enum_payment_status (code);
+CREATE INDEX idx_payment_status ON payment (status);
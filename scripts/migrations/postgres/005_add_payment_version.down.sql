@@ -0,0 +1 @@
+ALTER TABLE payment DROP COLUMN version;
\ No newline at end of file
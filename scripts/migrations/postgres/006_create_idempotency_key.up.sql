@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS idempotency_key
+(
+    key             TEXT PRIMARY KEY,
+    fingerprint     TEXT        NOT NULL,
+    resource_id     UUID        NOT NULL,
+    response_status INTEGER     NOT NULL,
+    response_body   BYTEA       NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    expires_at      TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX idx_idempotency_key_expires_at ON idempotency_key (expires_at);
@@ -0,0 +1,4 @@
+DROP INDEX idx_payment_creditor_name;
+DROP INDEX idx_payment_debtor_name;
+CREATE INDEX idx_payment_creditor_name ON payment (lower(creditor_name) text_pattern_ops);
+CREATE INDEX idx_payment_debtor_name ON payment (lower(debtor_name) text_pattern_ops);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payment_history;
+DROP FUNCTION IF EXISTS reject_payment_history_change();
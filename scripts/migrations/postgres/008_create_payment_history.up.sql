@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS payment_history
+(
+    sequence        BIGSERIAL PRIMARY KEY,
+    id              UUID        NOT NULL UNIQUE,
+    payment_id      UUID        NOT NULL,
+    operation       TEXT        NOT NULL,
+    actor           TEXT        NOT NULL,
+    request_id      TEXT        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    before_snapshot JSONB,
+    after_snapshot  JSONB
+);
+CREATE INDEX idx_payment_history_payment_id ON payment_history (payment_id);
+
+CREATE FUNCTION reject_payment_history_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'payment history is immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_payment_history_immutable
+    BEFORE UPDATE OR DELETE
+    ON payment_history
+    FOR EACH ROW
+EXECUTE PROCEDURE reject_payment_history_change();
@@ -0,0 +1,2 @@
+ALTER TABLE enum_currency DROP COLUMN max_amount;
+ALTER TABLE enum_currency DROP COLUMN minor_units;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE enum_currency
+    ADD COLUMN minor_units INTEGER NOT NULL DEFAULT 2;
+ALTER TABLE enum_currency
+    ADD COLUMN max_amount NUMERIC;
+
+-- The forint has no minor units in circulation, hence no fractional amounts are accepted.
+UPDATE enum_currency SET minor_units = 0 WHERE code = 'HUF';
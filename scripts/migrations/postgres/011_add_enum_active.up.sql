@@ -0,0 +1,6 @@
+ALTER TABLE enum_scheme
+    ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE enum_country
+    ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE enum_currency
+    ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,7 @@
+DROP INDEX idx_payment_requested_execution_date;
+DROP INDEX idx_payment_updated_at;
+DROP INDEX idx_payment_created_at;
+ALTER TABLE payment
+    DROP COLUMN requested_execution_date,
+    DROP COLUMN updated_at,
+    DROP COLUMN created_at;
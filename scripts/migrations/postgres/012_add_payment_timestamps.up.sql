@@ -0,0 +1,20 @@
+ALTER TABLE payment
+    ADD COLUMN created_at               TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at               TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN requested_execution_date DATE;
+
+UPDATE payment
+SET created_at = h.first_change,
+    updated_at = h.last_change
+FROM (SELECT payment_id, min(created_at) AS first_change, max(created_at) AS last_change
+      FROM payment_history
+      GROUP BY payment_id) h
+WHERE h.payment_id = payment.id;
+
+ALTER TABLE payment
+    ALTER COLUMN created_at DROP DEFAULT,
+    ALTER COLUMN updated_at DROP DEFAULT;
+
+CREATE INDEX idx_payment_created_at ON payment (created_at);
+CREATE INDEX idx_payment_updated_at ON payment (updated_at);
+CREATE INDEX idx_payment_requested_execution_date ON payment (requested_execution_date);
@@ -0,0 +1,5 @@
+DROP INDEX idx_payment_due;
+ALTER TABLE payment
+    DROP COLUMN execution_last_error,
+    DROP COLUMN execution_next_attempt_at,
+    DROP COLUMN execution_attempts;
@@ -0,0 +1,5 @@
+ALTER TABLE payment
+    DROP COLUMN value_date;
+
+DROP TABLE calendar_holiday;
+DROP TABLE scheme_calendar;
@@ -0,0 +1,66 @@
+CREATE TABLE scheme_calendar
+(
+    scheme           TEXT PRIMARY KEY REFERENCES enum_scheme (code),
+    holiday_calendar TEXT NOT NULL,
+    time_zone        TEXT NOT NULL,
+    cut_off          TEXT NOT NULL
+);
+
+CREATE TABLE calendar_holiday
+(
+    calendar TEXT NOT NULL,
+    date     DATE NOT NULL,
+    name     TEXT NOT NULL,
+    PRIMARY KEY (calendar, date)
+);
+
+INSERT INTO scheme_calendar (scheme, holiday_calendar, time_zone, cut_off)
+VALUES ('SEPA', 'TARGET2', 'Europe/Berlin', '16:00'),
+       ('SWIFT', 'GB', 'Europe/London', '14:00');
+
+INSERT INTO calendar_holiday (calendar, date, name)
+VALUES ('TARGET2', '2019-01-01', 'New Year''s Day'),
+       ('TARGET2', '2019-04-19', 'Good Friday'),
+       ('TARGET2', '2019-04-22', 'Easter Monday'),
+       ('TARGET2', '2019-05-01', 'Labour Day'),
+       ('TARGET2', '2019-12-25', 'Christmas Day'),
+       ('TARGET2', '2019-12-26', 'Christmas Holiday'),
+       ('TARGET2', '2020-01-01', 'New Year''s Day'),
+       ('TARGET2', '2020-04-10', 'Good Friday'),
+       ('TARGET2', '2020-04-13', 'Easter Monday'),
+       ('TARGET2', '2020-05-01', 'Labour Day'),
+       ('TARGET2', '2020-12-25', 'Christmas Day'),
+       ('TARGET2', '2020-12-26', 'Christmas Holiday'),
+       ('TARGET2', '2021-01-01', 'New Year''s Day'),
+       ('TARGET2', '2021-04-02', 'Good Friday'),
+       ('TARGET2', '2021-04-05', 'Easter Monday'),
+       ('TARGET2', '2021-05-01', 'Labour Day'),
+       ('TARGET2', '2021-12-25', 'Christmas Day'),
+       ('TARGET2', '2021-12-26', 'Christmas Holiday'),
+       ('GB', '2019-01-01', 'New Year''s Day'),
+       ('GB', '2019-04-19', 'Good Friday'),
+       ('GB', '2019-04-22', 'Easter Monday'),
+       ('GB', '2019-05-06', 'Early May bank holiday'),
+       ('GB', '2019-05-27', 'Spring bank holiday'),
+       ('GB', '2019-08-26', 'Summer bank holiday'),
+       ('GB', '2019-12-25', 'Christmas Day'),
+       ('GB', '2019-12-26', 'Boxing Day'),
+       ('GB', '2020-01-01', 'New Year''s Day'),
+       ('GB', '2020-04-10', 'Good Friday'),
+       ('GB', '2020-04-13', 'Easter Monday'),
+       ('GB', '2020-05-08', 'Early May bank holiday'),
+       ('GB', '2020-05-25', 'Spring bank holiday'),
+       ('GB', '2020-08-31', 'Summer bank holiday'),
+       ('GB', '2020-12-25', 'Christmas Day'),
+       ('GB', '2020-12-28', 'Boxing Day'),
+       ('GB', '2021-01-01', 'New Year''s Day'),
+       ('GB', '2021-04-02', 'Good Friday'),
+       ('GB', '2021-04-05', 'Easter Monday'),
+       ('GB', '2021-05-03', 'Early May bank holiday'),
+       ('GB', '2021-05-31', 'Spring bank holiday'),
+       ('GB', '2021-08-30', 'Summer bank holiday'),
+       ('GB', '2021-12-27', 'Christmas Day'),
+       ('GB', '2021-12-28', 'Boxing Day');
+
+ALTER TABLE payment
+    ADD COLUMN value_date DATE;
@@ -0,0 +1,4 @@
+DROP INDEX idx_payment_submitted;
+ALTER TABLE payment
+    DROP COLUMN rejection_reason,
+    DROP COLUMN scheme_reference;
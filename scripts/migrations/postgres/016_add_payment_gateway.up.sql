@@ -0,0 +1,5 @@
+ALTER TABLE payment
+    ADD COLUMN scheme_reference TEXT NOT NULL DEFAULT '',
+    ADD COLUMN rejection_reason TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_payment_submitted ON payment (updated_at)
+    WHERE status = 'SUBMITTED' AND deleted_at IS NULL;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS payment_event
+(
+    sequence                    BIGSERIAL PRIMARY KEY,
+    id                          UUID        NOT NULL UNIQUE,
+    type                        TEXT        NOT NULL,
+    payment_id                  UUID        NOT NULL,
+    occurred_at                 TIMESTAMPTZ NOT NULL,
+    payload                     JSONB       NOT NULL,
+    publication_attempts        INTEGER     NOT NULL DEFAULT 0,
+    publication_next_attempt_at TIMESTAMPTZ,
+    publication_last_error      TEXT        NOT NULL DEFAULT '',
+    published_at                TIMESTAMPTZ
+);
+CREATE INDEX idx_payment_event_pending ON payment_event (sequence)
+    WHERE published_at IS NULL;
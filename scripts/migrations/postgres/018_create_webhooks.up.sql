@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS webhook_subscription
+(
+    id          UUID PRIMARY KEY,
+    target_url  TEXT        NOT NULL,
+    event_types TEXT        NOT NULL DEFAULT '',
+    secret      TEXT        NOT NULL,
+    active      BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery
+(
+    sequence        BIGSERIAL PRIMARY KEY,
+    id              UUID        NOT NULL UNIQUE,
+    subscription_id UUID        NOT NULL REFERENCES webhook_subscription (id) ON DELETE CASCADE,
+    event_id        UUID        NOT NULL,
+    event_type      TEXT        NOT NULL,
+    payment_id      UUID        NOT NULL,
+    payload         JSONB       NOT NULL,
+    status          TEXT        NOT NULL,
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    response_status INTEGER     NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL,
+    delivered_at    TIMESTAMPTZ,
+    UNIQUE (subscription_id, event_id)
+);
+CREATE INDEX idx_webhook_delivery_pending ON webhook_delivery (created_at)
+    WHERE status = 'PENDING';
+CREATE INDEX idx_webhook_delivery_payment_id ON webhook_delivery (payment_id);
@@ -0,0 +1,7 @@
+DELETE FROM idempotency_key;
+ALTER TABLE idempotency_key
+    DROP CONSTRAINT idempotency_key_pkey;
+ALTER TABLE idempotency_key
+    DROP COLUMN actor;
+ALTER TABLE idempotency_key
+    ADD PRIMARY KEY (key);
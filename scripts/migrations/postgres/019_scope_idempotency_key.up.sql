@@ -0,0 +1,9 @@
+-- The keys expire within a day, the ones recorded before they were scoped to
+-- the actors are dropped rather than assigned to an actor.
+DELETE FROM idempotency_key;
+ALTER TABLE idempotency_key
+    ADD COLUMN actor TEXT NOT NULL;
+ALTER TABLE idempotency_key
+    DROP CONSTRAINT idempotency_key_pkey;
+ALTER TABLE idempotency_key
+    ADD PRIMARY KEY (actor, key);
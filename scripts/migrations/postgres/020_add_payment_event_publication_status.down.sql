@@ -0,0 +1,5 @@
+DROP INDEX idx_payment_event_pending;
+ALTER TABLE payment_event
+    DROP COLUMN publication_status;
+CREATE INDEX idx_payment_event_pending ON payment_event (sequence)
+    WHERE published_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX idx_webhook_delivery_pending;
+CREATE INDEX idx_webhook_delivery_pending ON webhook_delivery (created_at)
+    WHERE status = 'PENDING';
@@ -0,0 +1,10 @@
+DELETE FROM webhook_delivery
+WHERE subscription_id IN (SELECT id FROM webhook_subscription WHERE deleted_at IS NOT NULL);
+DELETE FROM webhook_subscription
+WHERE deleted_at IS NOT NULL;
+ALTER TABLE webhook_delivery
+    DROP CONSTRAINT webhook_delivery_subscription_id_fkey,
+    ADD CONSTRAINT webhook_delivery_subscription_id_fkey
+        FOREIGN KEY (subscription_id) REFERENCES webhook_subscription (id) ON DELETE CASCADE;
+ALTER TABLE webhook_subscription
+    DROP COLUMN deleted_at;
@@ -0,0 +1,8 @@
+-- A deleted subscription is only marked as deleted, so the log of its
+-- deliveries is kept.
+ALTER TABLE webhook_subscription
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE webhook_delivery
+    DROP CONSTRAINT webhook_delivery_subscription_id_fkey,
+    ADD CONSTRAINT webhook_delivery_subscription_id_fkey
+        FOREIGN KEY (subscription_id) REFERENCES webhook_subscription (id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS payment_event_feed;
+DROP INDEX idx_payment_event_unpositioned;
+DROP INDEX idx_payment_event_feed_position;
+ALTER TABLE payment_event
+    DROP COLUMN feed_position;
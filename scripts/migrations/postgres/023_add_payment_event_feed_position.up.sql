@@ -0,0 +1,21 @@
+-- The sequence of an event is assigned on insert, so the events are not
+-- committed in its order. The feed streams the events by their position,
+-- which is assigned in turns once the events are committed. The positions of
+-- the existing events are their sequences, so the clients of the feed resume
+-- where they stopped.
+ALTER TABLE payment_event
+    ADD COLUMN feed_position BIGINT;
+UPDATE payment_event
+SET feed_position = sequence;
+CREATE UNIQUE INDEX idx_payment_event_feed_position ON payment_event (feed_position);
+CREATE INDEX idx_payment_event_unpositioned ON payment_event (sequence)
+    WHERE feed_position IS NULL;
+
+CREATE TABLE IF NOT EXISTS payment_event_feed
+(
+    id       INTEGER PRIMARY KEY,
+    position BIGINT NOT NULL
+);
+INSERT INTO payment_event_feed (id, position)
+SELECT 1, COALESCE(MAX(sequence), 0)
+FROM payment_event;
@@ -0,0 +1,2 @@
+INSERT INTO enum_payment_status (code, name)
+VALUES ('UNCONFIRMED', 'Unconfirmed');
@@ -0,0 +1,3 @@
+-- The amounts are stored as numeric values, they are compared and indexed as
+-- they are.
+SELECT 1;
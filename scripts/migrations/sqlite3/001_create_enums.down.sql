@@ -0,0 +1,3 @@
+DROP TABLE enum_scheme;
+DROP TABLE enum_country;
+DROP TABLE enum_currency;
\ No newline at end of file
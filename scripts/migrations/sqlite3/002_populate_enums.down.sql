@@ -0,0 +1,3 @@
+DELETE FROM enum_scheme;
+DELETE FROM enum_country;
+DELETE FROM enum_currency;
\ No newline at end of file
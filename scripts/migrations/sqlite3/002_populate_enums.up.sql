@@ -0,0 +1,46 @@
+INSERT INTO enum_scheme (code, name)
+VALUES ('SWIFT', 'Society for Worldwide Interbank Financial Telecommunication Payment Scheme'),
+       ('SEPA', 'Single Euro Payments Area Payment Scheme');
+
+INSERT INTO enum_country (code, name)
+VALUES ('AT', 'Austria'),
+       ('BE', 'Belgium'),
+       ('BG', 'Bulgaria'),
+       ('HR', 'Croatia'),
+       ('CY', 'Cyprus'),
+       ('CZ', 'Czech Republic'),
+       ('DK', 'Denmark'),
+       ('EE', 'Estonia'),
+       ('FI', 'Finland'),
+       ('FR', 'France'),
+       ('DE', 'Germany'),
+       ('GR', 'Greece'),
+       ('HU', 'Hungary'),
+       ('IE', 'Republic of Ireland'),
+       ('IT', 'Italy'),
+       ('LV', 'Latvia'),
+       ('LT', 'Lithuania'),
+       ('LU', 'Luxembourg'),
+       ('MT', 'Malta'),
+       ('NL', 'Netherlands'),
+       ('PL', 'Poland'),
+       ('PT', 'Portugal'),
+       ('RO', 'Romania'),
+       ('SK', 'Slovakia'),
+       ('SI', 'Slovenia'),
+       ('ES', 'Spain'),
+       ('SE', 'Sweden'),
+       ('GB', 'United Kingdom');
+
+INSERT INTO enum_currency (code, name)
+VALUES ('BGN', 'Bulgarian lev'),
+       ('CZK', 'Czech koruna'),
+       ('DKK', 'Danish krone'),
+       ('EUR', 'Euro'),
+       ('GBP', 'Pound sterling'),
+       ('HRK', 'Croatian kuna'),
+       ('HUF', 'Hungarian forint'),
+       ('PLN', 'Polish złoty'),
+       ('RON', 'Romanian leu'),
+       ('SEK', 'Swedish krona');
+
@@ -0,0 +1 @@
+DROP TABLE payment;
\ No newline at end of file
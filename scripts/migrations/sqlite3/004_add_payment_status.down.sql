@@ -0,0 +1,3 @@
+DROP INDEX idx_payment_status;
+ALTER TABLE payment DROP COLUMN status;
+DROP TABLE enum_payment_status;
\ No newline at end of file
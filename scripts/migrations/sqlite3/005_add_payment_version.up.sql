@@ -0,0 +1,2 @@
+ALTER TABLE payment
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1 CHECK (version > 0);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS idempotency_key
+(
+    key             TEXT PRIMARY KEY,
+    fingerprint     TEXT        NOT NULL,
+    resource_id     TEXT        NOT NULL,
+    response_status INTEGER     NOT NULL,
+    response_body   BLOB        NOT NULL,
+    created_at      TIMESTAMP   NOT NULL,
+    expires_at      TIMESTAMP   NOT NULL
+);
+CREATE INDEX idx_idempotency_key_expires_at ON idempotency_key (expires_at);
@@ -0,0 +1,4 @@
+DROP INDEX idx_payment_creditor_name;
+DROP INDEX idx_payment_debtor_name;
+CREATE INDEX idx_payment_creditor_name ON payment (creditor_name COLLATE NOCASE);
+CREATE INDEX idx_payment_debtor_name ON payment (debtor_name COLLATE NOCASE);
\ No newline at end of file
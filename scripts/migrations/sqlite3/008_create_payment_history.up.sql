@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS payment_history
+(
+    sequence        INTEGER PRIMARY KEY AUTOINCREMENT,
+    id              TEXT      NOT NULL UNIQUE,
+    payment_id      TEXT      NOT NULL,
+    operation       TEXT      NOT NULL,
+    actor           TEXT      NOT NULL,
+    request_id      TEXT      NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    before_snapshot TEXT,
+    after_snapshot  TEXT
+);
+CREATE INDEX idx_payment_history_payment_id ON payment_history (payment_id);
+
+CREATE TRIGGER trg_payment_history_no_update
+    BEFORE UPDATE
+    ON payment_history
+BEGIN
+    SELECT RAISE(ABORT, 'payment history is immutable');
+END;
+
+CREATE TRIGGER trg_payment_history_no_delete
+    BEFORE DELETE
+    ON payment_history
+BEGIN
+    SELECT RAISE(ABORT, 'payment history is immutable');
+END;
@@ -0,0 +1,2 @@
+DROP INDEX idx_payment_deleted_at;
+ALTER TABLE payment DROP COLUMN deleted_at;
\ No newline at end of file
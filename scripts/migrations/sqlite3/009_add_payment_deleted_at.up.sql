@@ -0,0 +1,3 @@
+ALTER TABLE payment
+    ADD COLUMN deleted_at TIMESTAMP;
+CREATE INDEX idx_payment_deleted_at ON payment (deleted_at);
@@ -0,0 +1,3 @@
+ALTER TABLE enum_currency DROP COLUMN active;
+ALTER TABLE enum_country DROP COLUMN active;
+ALTER TABLE enum_scheme DROP COLUMN active;
\ No newline at end of file
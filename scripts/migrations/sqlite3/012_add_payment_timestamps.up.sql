@@ -0,0 +1,15 @@
+ALTER TABLE payment
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00+00:00';
+ALTER TABLE payment
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00+00:00';
+ALTER TABLE payment
+    ADD COLUMN requested_execution_date DATE;
+
+UPDATE payment
+SET created_at = (SELECT min(created_at) FROM payment_history WHERE payment_id = payment.id),
+    updated_at = (SELECT max(created_at) FROM payment_history WHERE payment_id = payment.id)
+WHERE EXISTS(SELECT 1 FROM payment_history WHERE payment_id = payment.id);
+
+CREATE INDEX idx_payment_created_at ON payment (created_at);
+CREATE INDEX idx_payment_updated_at ON payment (updated_at);
+CREATE INDEX idx_payment_requested_execution_date ON payment (requested_execution_date);
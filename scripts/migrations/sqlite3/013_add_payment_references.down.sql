@@ -0,0 +1,8 @@
+DROP INDEX idx_payment_remittance_structured;
+DROP INDEX idx_payment_numeric_reference;
+DROP INDEX idx_payment_end_to_end_reference;
+ALTER TABLE payment DROP COLUMN remittance_structured;
+ALTER TABLE payment DROP COLUMN remittance_unstructured;
+ALTER TABLE payment DROP COLUMN payment_purpose;
+ALTER TABLE payment DROP COLUMN numeric_reference;
+ALTER TABLE payment DROP COLUMN end_to_end_reference;
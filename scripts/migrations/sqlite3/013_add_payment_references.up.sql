@@ -0,0 +1,13 @@
+ALTER TABLE payment
+    ADD COLUMN end_to_end_reference TEXT NOT NULL DEFAULT '';
+ALTER TABLE payment
+    ADD COLUMN numeric_reference TEXT NOT NULL DEFAULT '';
+ALTER TABLE payment
+    ADD COLUMN payment_purpose TEXT NOT NULL DEFAULT '';
+ALTER TABLE payment
+    ADD COLUMN remittance_unstructured TEXT NOT NULL DEFAULT '';
+ALTER TABLE payment
+    ADD COLUMN remittance_structured TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_payment_end_to_end_reference ON payment (end_to_end_reference);
+CREATE INDEX idx_payment_numeric_reference ON payment (numeric_reference);
+CREATE INDEX idx_payment_remittance_structured ON payment (remittance_structured);
@@ -0,0 +1,4 @@
+DROP INDEX idx_payment_due;
+ALTER TABLE payment DROP COLUMN execution_last_error;
+ALTER TABLE payment DROP COLUMN execution_next_attempt_at;
+ALTER TABLE payment DROP COLUMN execution_attempts;
@@ -0,0 +1,8 @@
+ALTER TABLE payment
+    ADD COLUMN execution_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE payment
+    ADD COLUMN execution_next_attempt_at TIMESTAMP;
+ALTER TABLE payment
+    ADD COLUMN execution_last_error TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_payment_due ON payment (requested_execution_date)
+    WHERE status = 'DRAFT' AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX idx_payment_submitted;
+ALTER TABLE payment DROP COLUMN rejection_reason;
+ALTER TABLE payment DROP COLUMN scheme_reference;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS payment_event
+(
+    sequence                    INTEGER PRIMARY KEY AUTOINCREMENT,
+    id                          TEXT      NOT NULL UNIQUE,
+    type                        TEXT      NOT NULL,
+    payment_id                  TEXT      NOT NULL,
+    occurred_at                 TIMESTAMP NOT NULL,
+    payload                     TEXT      NOT NULL,
+    publication_attempts        INTEGER   NOT NULL DEFAULT 0,
+    publication_next_attempt_at TIMESTAMP,
+    publication_last_error      TEXT      NOT NULL DEFAULT '',
+    published_at                TIMESTAMP
+);
+CREATE INDEX idx_payment_event_pending ON payment_event (sequence)
+    WHERE published_at IS NULL;
@@ -0,0 +1,16 @@
+-- The keys expire within a day, the ones recorded before they were scoped to
+-- the actors are dropped rather than assigned to an actor.
+DROP TABLE idempotency_key;
+CREATE TABLE IF NOT EXISTS idempotency_key
+(
+    actor           TEXT        NOT NULL,
+    key             TEXT        NOT NULL,
+    fingerprint     TEXT        NOT NULL,
+    resource_id     TEXT        NOT NULL,
+    response_status INTEGER     NOT NULL,
+    response_body   BLOB        NOT NULL,
+    created_at      TIMESTAMP   NOT NULL,
+    expires_at      TIMESTAMP   NOT NULL,
+    PRIMARY KEY (actor, key)
+);
+CREATE INDEX idx_idempotency_key_expires_at ON idempotency_key (expires_at);
@@ -0,0 +1,10 @@
+-- An event which could not be published within the attempts ends as a dead
+-- letter, so it no longer holds up the following events of its payment.
+ALTER TABLE payment_event
+    ADD COLUMN publication_status TEXT NOT NULL DEFAULT 'PENDING';
+UPDATE payment_event
+SET publication_status = 'PUBLISHED'
+WHERE published_at IS NOT NULL;
+DROP INDEX idx_payment_event_pending;
+CREATE INDEX idx_payment_event_pending ON payment_event (payment_id, sequence)
+    WHERE publication_status = 'PENDING';
@@ -0,0 +1,5 @@
+-- The deliveries of a payment are pushed to a subscription in order, a
+-- pending delivery holds up the following ones.
+DROP INDEX idx_webhook_delivery_pending;
+CREATE INDEX idx_webhook_delivery_pending ON webhook_delivery (subscription_id, payment_id, sequence)
+    WHERE status = 'PENDING';
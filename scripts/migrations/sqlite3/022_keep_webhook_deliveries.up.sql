@@ -0,0 +1,45 @@
+-- A deleted subscription is only marked as deleted, so the log of its
+-- deliveries is kept. SQLite can not alter a foreign key, the table of the
+-- deliveries is copied without the cascade instead.
+ALTER TABLE webhook_subscription
+    ADD COLUMN deleted_at TIMESTAMP;
+CREATE TABLE webhook_delivery_new
+(
+    sequence        INTEGER PRIMARY KEY AUTOINCREMENT,
+    id              TEXT      NOT NULL UNIQUE,
+    subscription_id TEXT      NOT NULL REFERENCES webhook_subscription (id),
+    event_id        TEXT      NOT NULL,
+    event_type      TEXT      NOT NULL,
+    payment_id      TEXT      NOT NULL,
+    payload         TEXT      NOT NULL,
+    status          TEXT      NOT NULL,
+    attempts        INTEGER   NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    last_error      TEXT      NOT NULL DEFAULT '',
+    response_status INTEGER   NOT NULL DEFAULT 0,
+    created_at      TIMESTAMP NOT NULL,
+    delivered_at    TIMESTAMP,
+    UNIQUE (subscription_id, event_id)
+);
+INSERT INTO webhook_delivery_new
+SELECT sequence,
+       id,
+       subscription_id,
+       event_id,
+       event_type,
+       payment_id,
+       payload,
+       status,
+       attempts,
+       next_attempt_at,
+       last_error,
+       response_status,
+       created_at,
+       delivered_at
+FROM webhook_delivery;
+DROP TABLE webhook_delivery;
+ALTER TABLE webhook_delivery_new
+    RENAME TO webhook_delivery;
+CREATE INDEX idx_webhook_delivery_pending ON webhook_delivery (subscription_id, payment_id, sequence)
+    WHERE status = 'PENDING';
+CREATE INDEX idx_webhook_delivery_payment_id ON webhook_delivery (payment_id);
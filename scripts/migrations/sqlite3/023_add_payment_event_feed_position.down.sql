@@ -0,0 +1,34 @@
+-- SQLite can not drop a column, the table is copied without it instead.
+DROP TABLE IF EXISTS payment_event_feed;
+CREATE TABLE payment_event_old
+(
+    sequence                    INTEGER PRIMARY KEY AUTOINCREMENT,
+    id                          TEXT      NOT NULL UNIQUE,
+    type                        TEXT      NOT NULL,
+    payment_id                  TEXT      NOT NULL,
+    occurred_at                 TIMESTAMP NOT NULL,
+    payload                     TEXT      NOT NULL,
+    publication_attempts        INTEGER   NOT NULL DEFAULT 0,
+    publication_next_attempt_at TIMESTAMP,
+    publication_last_error      TEXT      NOT NULL DEFAULT '',
+    published_at                TIMESTAMP,
+    publication_status          TEXT      NOT NULL DEFAULT 'PENDING'
+);
+INSERT INTO payment_event_old
+SELECT sequence,
+       id,
+       type,
+       payment_id,
+       occurred_at,
+       payload,
+       publication_attempts,
+       publication_next_attempt_at,
+       publication_last_error,
+       published_at,
+       publication_status
+FROM payment_event;
+DROP TABLE payment_event;
+ALTER TABLE payment_event_old
+    RENAME TO payment_event;
+CREATE INDEX idx_payment_event_pending ON payment_event (payment_id, sequence)
+    WHERE publication_status = 'PENDING';
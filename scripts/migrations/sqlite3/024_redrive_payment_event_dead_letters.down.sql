@@ -0,0 +1,3 @@
+-- The events published again can not be told from the others, they are kept
+-- as they are.
+SELECT 1;
@@ -0,0 +1,6 @@
+-- The relay no longer gives up on the events, the dead letters are published
+-- again, the later events of their payments still pending wait for them.
+UPDATE payment_event
+SET publication_status          = 'PENDING',
+    publication_next_attempt_at = NULL
+WHERE publication_status = 'DEAD_LETTER';
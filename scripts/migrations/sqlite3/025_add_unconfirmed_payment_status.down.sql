@@ -0,0 +1,2 @@
+UPDATE payment SET status = 'SUBMITTED' WHERE status = 'UNCONFIRMED';
+DELETE FROM enum_payment_status WHERE code = 'UNCONFIRMED';
@@ -0,0 +1 @@
+DROP INDEX idx_payment_amount_key;
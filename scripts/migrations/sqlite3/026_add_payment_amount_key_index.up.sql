@@ -0,0 +1,8 @@
+-- The amounts are stored as text, they are compared by the keys the store
+-- builds of them, see sql.Dialect.DecimalKey. The expression must match the
+-- one of the queries for the index to be used.
+CREATE INDEX idx_payment_amount_key ON payment (
+    printf('%02d', instr(amount_value || '.', '.') - 1) ||
+    substr(amount_value, 1, instr(amount_value || '.', '.') - 1) ||
+    rtrim(rtrim(substr(amount_value, instr(amount_value || '.', '.')), '0'), '.')
+);